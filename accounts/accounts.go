@@ -0,0 +1,108 @@
+package accounts
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Twitch account a Discord user proved they own
+type Account struct {
+	DiscordUserID string    // Discord user ID
+	TwitchUserID  string    // Twitch user ID
+	TwitchLogin   string    // Twitch login
+	LinkedAt      time.Time // Time the account was linked
+}
+
+var (
+	mu    sync.RWMutex
+	links = make(map[string]*Account) // Map of Discord user IDs to their linked Twitch account
+)
+
+// Loads the linked accounts from disk
+func Load() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	err := utils.ReadGobFromDisk(utils.DataDir, "accounts", &links)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if links == nil {
+		links = make(map[string]*Account)
+	}
+
+	return err
+}
+
+// Links a Twitch account to a Discord user, replacing any account linked before
+func Link(discordUserID string, twitchUserID string, twitchLogin string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	links[discordUserID] = &Account{
+		DiscordUserID: discordUserID,
+		TwitchUserID:  twitchUserID,
+		TwitchLogin:   twitchLogin,
+		LinkedAt:      time.Now().UTC(),
+	}
+
+	return utils.WriteGobToDisk(utils.DataDir, "accounts", links)
+}
+
+// Forgets the Twitch account linked to a Discord user
+func Unlink(discordUserID string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if links[discordUserID] == nil {
+		return constants.ErrAccountNotLinked
+	}
+	delete(links, discordUserID)
+
+	return utils.WriteGobToDisk(utils.DataDir, "accounts", links)
+}
+
+// Returns the Twitch account linked to a Discord user
+func Get(discordUserID string) (Account, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if account := links[discordUserID]; account != nil {
+		return *account, true
+	}
+	return Account{}, false
+}
+
+// Returns the Discord user a Twitch account is linked to
+func GetByTwitchID(twitchUserID string) (Account, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, account := range links {
+		if account.TwitchUserID == twitchUserID {
+			return *account, true
+		}
+	}
+	return Account{}, false
+}
+
+// Returns every linked account ordered by Discord user ID
+func All() []Account {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	all := make([]Account, 0, len(links))
+	for _, account := range links {
+		all = append(all, *account)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].DiscordUserID < all[j].DiscordUserID })
+
+	return all
+}
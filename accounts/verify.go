@@ -0,0 +1,78 @@
+package accounts
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+// Link a Discord user started and has not completed yet
+type verification struct {
+	discordUserID string
+	expiry        time.Time
+	done          func(login string, err error)
+}
+
+var pending = make(map[string]*verification) // Map of verification states to the verification they identify
+
+// Starts verifying a Discord user's Twitch account. Returns the state that identifies the verification,
+// done is called once it is completed.
+func StartVerification(discordUserID string, done func(login string, err error)) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	state := hex.EncodeToString(b)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	prunePending()
+	pending[state] = &verification{
+		discordUserID: discordUserID,
+		expiry:        time.Now().Add(constants.AccountVerificationTime),
+		done:          done,
+	}
+
+	return state, nil
+}
+
+// Links the Twitch account that completed the verification identified by state
+func CompleteVerification(state string, twitchUserID string, twitchLogin string) error {
+	mu.Lock()
+	v := pending[state]
+	delete(pending, state)
+	mu.Unlock()
+
+	if v == nil || time.Now().After(v.expiry) {
+		return constants.ErrAuthorizationExpired
+	}
+
+	err := Link(v.discordUserID, twitchUserID, twitchLogin)
+	v.done(twitchLogin, err)
+
+	return err
+}
+
+// Fails the verification identified by state
+func FailVerification(state string, err error) {
+	mu.Lock()
+	v := pending[state]
+	delete(pending, state)
+	mu.Unlock()
+
+	if v != nil {
+		v.done("", err)
+	}
+}
+
+// Forgets verifications that were never completed. Must be called with mu held.
+func prunePending() {
+	for state, v := range pending {
+		if time.Now().After(v.expiry) {
+			delete(pending, state)
+		}
+	}
+}
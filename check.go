@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/config"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/spf13/cobra"
+)
+
+// Hosts the bot must be able to reach
+var checkHosts = []string{
+	"https://discord.com/api/v8/gateway",
+	"https://id.twitch.tv/oauth2/keys",
+	"https://api.twitch.tv/helix",
+}
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check the config, tokens, storage and network, print a report and exit",
+	Long: "Validates the config file, the Discord token, the Twitch credentials, that the data directory is writable " +
+		"and its files can be decrypted, and that Discord and Twitch can be reached. Exits with status 1 if any check fails.",
+	Args: cobra.NoArgs,
+	// Setup failures are reported by the check instead of stopping it
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+	Run: func(cmd *cobra.Command, args []string) {
+		os.Exit(runCheck())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+}
+
+// Checks everything the bot needs to start, prints a report and returns the exit code
+func runCheck() int {
+	failed := false
+	report := func(name string, err error) {
+		if err != nil {
+			failed = true
+			fmt.Printf("[FAIL] %v: %v\n", name, err)
+		} else {
+			fmt.Printf("[ OK ] %v\n", name)
+		}
+	}
+
+	report("Data directory "+dataDir+" is writable", utils.SetDataDir(dataDir))
+
+	if configPath == "" {
+		configPath = utils.DataDir + "/config.json"
+	}
+	configErr := config.Load(configPath)
+	if configErr == nil {
+		configErr = validateConfig(config.Settings)
+	}
+	report("Config file "+configPath, configErr)
+
+	if key := os.Getenv("DATA_ENCRYPTION_KEY"); key != "" {
+		utils.SetEncryptionKey(key)
+	} else {
+		utils.SetEncryptionKey(config.Settings.EncryptionKey)
+	}
+	report("Session data "+sessionName+" can be read", checkStorage())
+
+	report("HTTP client", configureHTTPClient())
+
+	for _, host := range checkHosts {
+		report("Network reaches "+host, checkReachable(host))
+	}
+
+	report("Discord token", checkDiscordToken())
+	for _, bot := range config.Settings.Bots {
+		report("Discord token of bot "+bot.Name, checkBotToken(bot.Token))
+	}
+	report("Twitch credentials", checkTwitchCredentials())
+
+	if failed {
+		fmt.Println("Check failed.")
+		return 1
+	}
+	fmt.Println("Check passed.")
+	return 0
+}
+
+// Returns an error for settings the bot would reject or misuse
+func validateConfig(c *config.Config) error {
+	if c.HTTPTimeout <= 0 {
+		return errors.New("http_timeout must be positive")
+	}
+	if c.PublicURL != "" {
+		if u, err := url.Parse(c.PublicURL); err != nil || u.Scheme != "https" {
+			return errors.New("public_url must be an https URL")
+		}
+	}
+	if c.EventSubSecret != "" && (len(c.EventSubSecret) < 10 || len(c.EventSubSecret) > 100) {
+		return errors.New("eventsub_secret must be 10 to 100 characters")
+	}
+	if c.EventSubSecret != "" && c.PublicURL == "" {
+		return errors.New("eventsub_secret is set but public_url is not")
+	}
+	for _, bot := range c.Bots {
+		if bot.Name == "" || bot.Token == "" {
+			return errors.New("every entry of bots needs a name and a token")
+		}
+	}
+	if (c.LiveFeed || c.Calendar || c.AdminToken != "" || c.EventSubSecret != "") && c.HTTPAddress == "" {
+		return errors.New("http_address must be set for the enabled HTTP endpoints")
+	}
+	return nil
+}
+
+// Reads the data files to make sure they are intact and can be decrypted
+func checkStorage() error {
+	names, err := utils.ListGobFiles(utils.DataDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for _, name := range names {
+		if err := utils.VerifyFileOnDisk(utils.DataDir, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns an error if host can't be reached. Any HTTP response counts as reachable.
+func checkReachable(host string) error {
+	resp, err := utils.HTTPClient.Get(host)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func checkDiscordToken() error {
+	t, err := resolveToken()
+	if err != nil {
+		return err
+	} else if t == "" {
+		return errors.New("no token given with -t, -p or BOT_TOKEN")
+	}
+
+	return checkBotToken(t)
+}
+
+func checkBotToken(t string) error {
+	dg, err := discordgo.New("Bot " + strings.TrimSpace(t))
+	if err != nil {
+		return err
+	}
+	dg.Client = utils.HTTPClient
+
+	user, err := dg.User("@me")
+	if err != nil {
+		return err
+	} else if !user.Bot {
+		return errors.New("token does not belong to a bot user")
+	}
+	return nil
+}
+
+func checkTwitchCredentials() error {
+	id, secret := os.Getenv("TWITCH_CLIENT_ID"), os.Getenv("TWITCH_CLIENT_SECRET")
+	if id == "" || secret == "" {
+		return errors.New("TWITCH_CLIENT_ID and TWITCH_CLIENT_SECRET must be set")
+	}
+
+	client, err := helix.NewClient(&helix.Options{
+		HTTPClient:   utils.HTTPClient,
+		ClientID:     id,
+		ClientSecret: secret,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.RequestAppAccessToken([]string{""})
+	if err != nil {
+		return err
+	} else if resp.Data.AccessToken == "" {
+		return fmt.Errorf("twitch rejected the credentials: %v", resp.ErrorMessage)
+	}
+
+	client.RevokeUserAccessToken(resp.Data.AccessToken)
+	return nil
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+)
+
+// Settings of the bot loaded from the config file
+type Config struct {
+	OwnerID           string `json:"owner_id"`            // Discord user ID of the bot owner
+	OperatorChannelID string `json:"operator_channel_id"` // Discord channel operator notices are sent to
+	UpdateCheck       bool   `json:"update_check"`        // Whether to periodically check GitHub for newer releases
+	EncryptionKey     string `json:"encryption_key"`      // Passphrase persisted data is encrypted with, DATA_ENCRYPTION_KEY takes precedence
+	HTTPAddress       string `json:"http_address"`        // Address the HTTP server listens on, disabled if empty
+	PublicURL         string `json:"public_url"`          // Public HTTPS URL the HTTP server is reachable at
+	EventSubSecret    string `json:"eventsub_secret"`     // Secret Twitch signs EventSub notifications with, 10 to 100 characters
+	LiveFeed          bool   `json:"live_feed"`           // Whether the HTTP server serves an Atom feed of live events
+	Calendar          bool   `json:"calendar"`            // Whether the HTTP server serves ICS calendars of scheduled streams
+	StatusPage        bool   `json:"status_page"`         // Whether the HTTP server serves an HTML page of monitored channels and whether they are live
+	StatusPageFile    string `json:"status_page_file"`    // File the HTML status page of every monitored channel is exported to, disabled if empty
+	LiveAPI           bool   `json:"live_api"`            // Whether the HTTP server serves the live status of guilds as JSON to holders of a guild's API token
+	EventSocket       bool   `json:"event_socket"`        // Whether the HTTP server streams live events of guilds over WebSockets to holders of a guild's API token
+	AnnounceAPI       bool   `json:"announce_api"`        // Whether the HTTP server accepts announcements for a guild's intake channel from holders of its API token
+	Metrics           bool   `json:"metrics"`             // Whether the HTTP server serves command metrics to Prometheus, requiring admin_token if it is set
+	AdminToken        string `json:"admin_token"`         // Bearer token required by admin HTTP endpoints, which are disabled if empty
+	HTTPTimeout       int    `json:"http_timeout"`        // Seconds before an outgoing HTTP request is abandoned
+	HTTPProxy         string `json:"http_proxy"`          // Proxy URL for outgoing HTTP requests, HTTP_PROXY and HTTPS_PROXY are used if empty
+	TLSCAFile         string `json:"tls_ca_file"`         // PEM file of an extra certificate authority to trust, e.g. of a corporate proxy
+	UserAgent         string `json:"user_agent"`          // User-Agent of outgoing HTTP requests
+	OTLPEndpoint      string `json:"otlp_endpoint"`       // host:port of the OTLP/HTTP collector traces are exported to, disabled if empty
+	OTLPInsecure      bool   `json:"otlp_insecure"`       // Whether traces are exported over plain HTTP
+	Bots              []Bot  `json:"bots"`                // Additional Discord bots sharing the Twitch session
+	NtfyURL           string `json:"ntfy_url"`            // ntfy server push notifications are published to, ntfy.sh if empty
+	PushoverToken     string `json:"pushover_token"`      // Pushover application token, Pushover notifications are disabled if empty
+	ControlSocket     string `json:"control_socket"`      // Path of the Unix socket accepting control commands, disabled if empty
+	SentryDSN         string `json:"sentry_dsn"`          // DSN of the Sentry project crashes are reported to, disabled if empty
+	ErrorReporting    bool   `json:"error_reporting"`     // Whether error logs are reported to Sentry and the error webhook
+	ErrorWebhookURL   string `json:"error_webhook_url"`   // URL error logs are posted to as JSON, disabled if empty
+	PresenceIntent    bool   `json:"presence_intent"`     // Whether to request the privileged presence intent to detect members streaming
+	InteractionOnly   bool   `json:"interaction_only"`    // Whether commands are only accepted as the slash command, for bots without the message content intent
+	LiveDebounce      int    `json:"live_debounce"`       // Seconds a stream must be live before it is announced, 90 if 0
+	OfflineDebounce   int    `json:"offline_debounce"`    // Seconds a stream must be offline before its message is ended, 90 if 0
+	ScriptsDir        string `json:"scripts_dir"`         // Directory of Starlark hook scripts, disabled if empty
+	UploadPreviews    bool   `json:"upload_previews"`     // Whether stream previews are uploaded with live messages instead of linked
+
+	TwitchResponseLog ResponseLog `json:"twitch_response_log"` // Logging of raw Twitch responses for debugging
+}
+
+// Logging of raw Twitch responses. Sensitive headers and tokens are always redacted.
+type ResponseLog struct {
+	Enabled  bool     `json:"enabled"`   // Whether Twitch responses are logged
+	Sample   int      `json:"sample"`    // Log the responses of 1 in this many polls, every poll if 0
+	MaxBytes int      `json:"max_bytes"` // Size each logged response is truncated to, 4096 if 0
+	Redact   []string `json:"redact"`    // Additional JSON fields and headers whose values are redacted
+}
+
+// An additional Discord bot, e.g. for a separate community
+type Bot struct {
+	Name  string `json:"name"`  // Name the bot is referred to by in logs
+	Token string `json:"token"` // Discord bot token
+}
+
+// Settings currently in use by the bot
+var Settings = defaults()
+
+// Path of the config file last loaded
+var loadedPath string
+
+func defaults() *Config {
+	return &Config{
+		UpdateCheck: true,
+		HTTPTimeout: 30,
+	}
+}
+
+// Loads the config file at path over the default settings.
+// A missing config file is not an error and leaves the defaults in place.
+func Load(path string) error {
+	c := defaults()
+	loadedPath = path
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		Settings = c
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := json.NewDecoder(file).Decode(c); err != nil {
+		return err
+	}
+
+	Settings = c
+	return nil
+}
+
+// Loads the config file again. Settings only read at startup, such as the HTTP server address, need a restart.
+func Reload() error {
+	return Load(loadedPath)
+}
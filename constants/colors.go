@@ -0,0 +1,9 @@
+package constants
+
+// Discord embed colors
+const (
+	DiscordLiveColor     = 0x00ff00
+	DiscordOfflineColor  = 0xff0000
+	DiscordRewardColor   = 0x9146ff
+	DiscordShoutoutColor = 0x9146ff
+)
@@ -0,0 +1,15 @@
+package constants
+
+// Discord limits
+const (
+	DiscordMaxPins             = 50   // Maximum number of pinned messages in a Discord channel
+	DiscordMaxMessageLength    = 2000 // Maximum length of a message's content
+	DiscordMaxEmbedFields      = 25   // Maximum number of fields in an embed
+	DiscordMaxFieldName        = 256  // Maximum length of an embed field name
+	DiscordMaxFieldValue       = 1024 // Maximum length of an embed field value
+	DiscordMaxEmbedTitle       = 256  // Maximum length of an embed title
+	DiscordMaxEmbedAuthor      = 256  // Maximum length of an embed author name
+	DiscordMaxEmbedDescription = 4096 // Maximum length of an embed description
+	DiscordMaxEmbedFooter      = 2048 // Maximum length of an embed footer
+	DiscordMaxEmbedTotal       = 6000 // Maximum length of the title, description, author, footer and fields of an embed together
+)
@@ -3,11 +3,99 @@ package constants
 import "errors"
 
 var (
-	ErrEmptyAccessToken = errors.New("access token retrieved is empty")
-	ErrInvalidToken     = errors.New("access token failed to validate or refresh")
+	ErrEmptyAccessToken     = errors.New("access token retrieved is empty")
+	ErrInvalidToken         = errors.New("access token failed to validate or refresh")
+	ErrTwitchQueryFailed    = errors.New("twitch query returned an error status")
+	ErrAuthorizationDenied  = errors.New("twitch user denied the authorization")
+	ErrAuthorizationExpired = errors.New("twitch authorization expired before it was completed")
+	ErrInvalidCredentials   = errors.New("twitch client id and secret were not accepted")
 )
 
 var (
-	ErrTwitchUserDoesNotExist = errors.New("twitch user does not exist")
-	ErrTwitchUserRegistered   = errors.New("twitch user is already registered to discord channel")
+	ErrTwitchUserDoesNotExist  = errors.New("twitch user does not exist")
+	ErrInvalidTwitchChannel    = errors.New("twitch channel is not a login, url or user id")
+	ErrTwitchUserRegistered    = errors.New("twitch user is already registered to discord channel")
+	ErrTwitchUserNotRegistered = errors.New("twitch user is not registered to discord channel")
+	ErrReminderTooFrequent     = errors.New("still live reminders cannot be sent that often")
+	ErrBroadcasterNotLinked    = errors.New("twitch broadcaster has not linked their account")
+	ErrStreamOffline           = errors.New("twitch stream is offline")
+	ErrEventSubDisabled        = errors.New("eventsub is not configured")
+	ErrRewardsNotConfigured    = errors.New("channel point redemptions are not posted for twitch channel")
+	ErrPollsNotConfigured      = errors.New("polls and predictions are not mirrored for twitch channel")
+	ErrGoalsNotConfigured      = errors.New("creator goals are not posted for twitch channel")
+	ErrSubRoleNotConfigured    = errors.New("no subscriber role is set for twitch channel and tier")
+	ErrAccountNotLinked        = errors.New("discord user has not linked a twitch account")
+	ErrUnknownPushService      = errors.New("push service must be ntfy or pushover")
+	ErrPushServiceDisabled     = errors.New("push service is not configured")
+	ErrPushNotConfigured       = errors.New("discord user has not set up push notifications")
+	ErrNotFollowing            = errors.New("discord user does not follow twitch channel")
+	ErrPushFailed              = errors.New("push service returned an error status")
+	ErrBanSyncNotConfigured    = errors.New("bans are not synced for twitch channel")
+	ErrBanDoesNotExist         = errors.New("synced ban does not exist in guild")
+	ErrBanReverted             = errors.New("synced ban was already reverted")
+)
+
+var (
+	ErrProfileExists       = errors.New("profile already exists in guild")
+	ErrAliasExists         = errors.New("alias already exists in guild")
+	ErrAliasDoesNotExist   = errors.New("alias does not exist in guild")
+	ErrAPITokenNotSet      = errors.New("no live status api token is set for guild")
+	ErrEmptyAnnouncement   = errors.New("announcement has neither content nor a title")
+	ErrAnnouncementTooLong = errors.New("announcement content is longer than discord allows")
+	ErrInvalidWatchTime    = errors.New("watch duration must be positive and at most a week")
+	ErrInvalidTimezone     = errors.New("timezone is not an iana timezone")
+	ErrInvalidHiatus       = errors.New("hiatus must be between 0 and 365 days")
+	ErrAliasIsChannel      = errors.New("alias is the name of the twitch channel")
+	ErrProfileDoesNotExist = errors.New("profile does not exist in guild")
+	ErrNothingToUndo       = errors.New("no removed registration can be restored")
+	ErrGameColorNotSet     = errors.New("no color is set for game in guild")
+	ErrInvalidTag          = errors.New("stream tags cannot be empty or contain spaces")
+	ErrTagRoleNotSet       = errors.New("no role is set for stream tag in guild")
+	ErrSquadTooSmall       = errors.New("a squad needs at least two twitch channels")
+	ErrSquadDoesNotExist   = errors.New("squad does not exist in guild")
+	ErrAvatarUnavailable   = errors.New("twitch logo could not be downloaded")
+	ErrPreviewUnavailable  = errors.New("stream preview could not be downloaded")
+	ErrPreviewTooLarge     = errors.New("stream preview exceeds the upload size limit")
+)
+
+var (
+	ErrUnknownFeature  = errors.New("feature does not exist")
+	ErrFeatureDisabled = errors.New("feature is not enabled in guild")
+)
+
+var (
+	ErrInvalidMuteDuration     = errors.New("mute duration is out of range")
+	ErrInvalidAnnounceDelay    = errors.New("announcement delay is out of range")
+	ErrInvalidPauseDuration    = errors.New("pause duration must not be negative")
+	ErrNotPaused               = errors.New("registration is not paused")
+	ErrTooManyTemplates        = errors.New("registration has too many templates")
+	ErrTemplateDoesNotExist    = errors.New("template does not exist")
+	ErrInvalidTemplateMode     = errors.New("template mode must be rotate or random")
+	ErrLookupRateLimited       = errors.New("too many twitch user lookups")
+	ErrUnknownEmbedField       = errors.New("unknown embed field")
+	ErrDuplicateEmbedField     = errors.New("embed field is listed twice")
+	ErrTooManyEmbedFields      = errors.New("too many embed fields")
+	ErrInvalidMutePolicy       = errors.New("mute policy must be queue or drop")
+	ErrNotMuted                = errors.New("guild is not muted")
+	ErrAlreadyFeatured         = errors.New("twitch channel is already in the featured rotation")
+	ErrNotFeatured             = errors.New("twitch channel is not in the featured rotation")
+	ErrNoRotation              = errors.New("guild has no featured rotation")
+	ErrInvalidRotationSchedule = errors.New("rotation schedule must be daily or weekly")
+	ErrGroupDoesNotExist       = errors.New("group has no registrations in guild")
+	ErrInvalidReportPeriod     = errors.New("report period must be weekly or monthly")
+	ErrReportsNotConfigured    = errors.New("stream reports are not set up in guild")
+	ErrInvalidDebounce         = errors.New("debounce is out of range")
+	ErrInvalidNameStyle        = errors.New("name style must be display, login or both")
+	ErrCategoryDoesNotExist    = errors.New("twitch category does not exist")
+	ErrInvalidPluginCommand    = errors.New("plugin command needs a single word name, a handler and a known permission")
+	ErrPluginCommandExists     = errors.New("plugin command is already registered")
+	ErrNoTwitchClient          = errors.New("twitch client could not be created")
+	ErrInvalidMaturePolicy     = errors.New("mature policy must be anywhere, nsfw-only or no-preview")
+	ErrOfflineTextTooLong      = errors.New("offline message is too long")
+	ErrInvalidImageURL         = errors.New("image must be an http or https url")
+)
+
+var (
+	ErrMissingEncryptionKey = errors.New("data is encrypted but no encryption key is set")
+	ErrCorruptedData        = errors.New("data could not be decrypted with the encryption key")
 )
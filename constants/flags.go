@@ -1,6 +1,5 @@
 package constants
 
 const (
-	Debug               = false // Must be true for any debugging to work
-	DebugTwitchResponse = false
+	Debug = false // Must be true for any debugging to work
 )
@@ -2,12 +2,37 @@ package constants
 
 // Path strings
 const (
-	DataPath = "data"
-	LogPath  = "logs"
+	DataPath         = "data"
+	LogPath          = "logs"
+	AnnounceLockPath = "announce-locks" // Directory in the data directory holding the announcement claims of every instance
 )
 
 // Control strings
 const (
 	ModRole       = "twitchbotmod"
 	CommandPrefix = "!twitch"
+	ConfirmEmoji  = "✅"
+
+	TwitchUnavailableMessage = "Twitch integration is currently unavailable. Please try again in a few minutes."
+	StorageUnhealthyMessage  = "The bot cannot save its data right now, so settings may not persist."
+)
+
+// Emojis moderators react with to pick one of a numbered list
+var NumberEmojis = []string{"1️⃣", "2️⃣", "3️⃣", "4️⃣", "5️⃣", "6️⃣", "7️⃣", "8️⃣", "9️⃣", "🔟"}
+
+// URL strings
+const (
+	GitHubLatestReleaseURL = "https://api.github.com/repos/samuel-mokhtar/DiscordTwitchBot/releases/latest"
+	TwitchDeviceURL        = "https://id.twitch.tv/oauth2/device"
+	TwitchTokenURL         = "https://id.twitch.tv/oauth2/token"
+	TwitchBansURL          = "https://api.twitch.tv/helix/moderation/bans"
+	TwitchShoutoutsURL     = "https://api.twitch.tv/helix/chat/shoutouts"
+	TwitchScheduleURL      = "https://api.twitch.tv/helix/schedule"
+	TwitchPreviewURL       = "https://static-cdn.jtvnw.net/previews-ttv/live_user_"
+	MultiTwitchURL         = "https://www.multitwitch.tv/"
+	TwitchGoalsURL         = "https://api.twitch.tv/helix/goals"
+	TwitchStreamsURL       = "https://api.twitch.tv/helix/streams"
+	TwitchFollowersURL     = "https://api.twitch.tv/helix/channels/followers"
+	NtfyURL                = "https://ntfy.sh"
+	PushoverMessagesURL    = "https://api.pushover.net/1/messages.json"
 )
@@ -9,4 +9,45 @@ const (
 	TwitchLiveMessageUpdateTime = time.Second * 30
 	TwitchThumbnailUpdateTime   = time.Minute * 5
 	TwitchGameUpdateTime        = time.Second * 60
+	UnregisterUndoTime          = time.Minute * 10
+	UpdateCheckInterval         = time.Hour * 24
+	PollUpdateInterval          = time.Second * 3
+	SubRoleSyncInterval         = time.Hour
+	AccountVerificationTime     = time.Minute * 10
+	MinReminderInterval         = time.Hour
+	MaxMuteDuration             = time.Hour * 24 * 7
+	ScheduleCacheTime           = time.Hour
+	SetupWizardTimeout          = time.Minute * 15
+	GoalUpdateInterval          = time.Minute * 5
+	MaxAnnounceDelay            = time.Hour
+	PurgeConfirmTimeout         = time.Minute * 2
+	ClearConfirmTimeout         = time.Minute * 2
+	UserLookupInterval          = time.Second
+	UserLookupMaxWait           = time.Second * 30
+	UserLookupCacheTime         = time.Minute * 10
+	GuildRemovalGracePeriod     = time.Hour * 24 * 30
+	OrphanGracePeriod           = time.Hour * 24 * 7
+	MetadataRefreshInterval     = time.Hour * 24
+	ChannelInfoRefreshTime      = time.Minute
+	RotationDay                 = time.Hour * 24
+	PollStallTimeout            = time.Minute * 5
+	ReportCheckInterval         = time.Hour
+	MaxDebounce                 = time.Hour
+	DiscoverReactionTimeout     = time.Minute * 10
+	ScriptTimeout               = time.Second
+	TwitchReconnectMinDelay     = time.Second * 30
+	TwitchReconnectMaxDelay     = time.Minute * 10
+	MaxSlowmodeWait             = time.Minute * 5
+	StatusPageExportInterval    = time.Minute
+	EventSocketPingInterval     = time.Second * 30
+	IntakeInterval              = time.Second
+	MaxWatchDuration            = time.Hour * 24 * 7
+	WatchExpiryInterval         = time.Minute
+	AnnounceLockAge             = time.Hour * 48
+	HelixCacheTime              = time.Minute * 5
+	StatsFlushInterval          = time.Minute
+	HTTPReadHeaderTimeout       = time.Second * 10
+	HTTPReadTimeout             = time.Second * 30
+	HTTPWriteTimeout            = time.Second * 30
+	HTTPIdleTimeout             = time.Minute * 2
 )
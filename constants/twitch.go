@@ -0,0 +1,39 @@
+package constants
+
+// Twitch query limits
+const (
+	TwitchQueryBatchSize          = 100 // Maximum number of user logins Twitch accepts per GetStreams request
+	TwitchQueryWorkers            = 4   // Default number of GetStreams batches issued concurrently
+	TwitchRateLimitThreshold      = 5   // Remaining rate limit points at which workers wait for the bucket to reset
+	TwitchMarkerDescriptionLength = 140 // Maximum length of a stream marker description
+	EventSubRecentMessages        = 500 // Number of EventSub message IDs remembered to drop retried notifications
+	ProgressBarLength             = 20  // Number of characters in poll and goal progress bars
+	MaxStreamReminders            = 6   // Maximum number of still live reminders posted during one stream
+	FeedEntries                   = 50  // Number of live events kept in the Atom feed
+	GuildOutcomeLogSize           = 50  // Number of notification outcomes kept per guild
+	MaxTemplates                  = 10  // Maximum number of templates a registration rotates through
+	UserLookupBurst               = 10  // Number of user lookups that can be issued at once before they are spaced out
+	OutageMinLiveChannels         = 3   // Live channels needed for a poll without streams to be a suspected outage
+	OutageConfirmations           = 3   // Consecutive polls without streams needed to end a suspected outage
+	FooterDescriptionLength       = 200 // Maximum length of the channel description shown in live embed footers
+	ReportTopGames                = 3   // Number of games listed for each channel in stream reports
+	DiscoverResults               = 10  // Maximum number of streams suggested by discover, one per number emoji
+	AdminStatsGuilds              = 5   // Number of slowest Discord servers listed by admin stats
+	AdminStatsCommands            = 10  // Number of most used commands listed by admin stats
+	SlowmodeRetries               = 3   // Times an announcement rejected by a channel's slowmode is retried
+	StorageFailureThreshold       = 3   // Consecutive failed writes of persisted data before the operator is alerted
+	EventSocketBuffer             = 16  // Events queued for an event socket before further events are dropped
+	IntakeQueueSize               = 50  // Announcements from the announce webhook waiting to be sent before further posts are refused
+	MaxOfflineText                = 500 // Maximum length of the custom message of an offline summary
+	MaxHiatusDays                 = 365 // Longest hiatus after which returning streams can be celebrated
+	StreakMinDays                 = 3   // Consecutive days with a stream before announcements celebrate the streak
+	StreakMinWeeks                = 3   // Consecutive weeks with a stream before announcements celebrate the streak
+	PollJitter                    = 0.1 // Fraction of a session's poll slot or a job's interval added at random to spread requests
+
+	ResponseLogMaxBytes = 4096    // Size logged Twitch responses are truncated to unless twitch_response_log sets another
+	PreviewMaxBytes     = 2 << 20 // Size of a stream preview above which it is linked instead of uploaded
+	HelixCacheEntries   = 2000    // Twitch lookup responses kept by the cache
+	EventSubMaxBytes    = 1 << 20 // Size of an EventSub notification above which it is refused
+	APIRequestMaxBytes  = 1 << 16 // Size of an API request body above which it is refused
+	ScriptMaxSteps      = 1000000 // Starlark steps a hook script can take before it is cancelled
+)
@@ -0,0 +1,135 @@
+package control
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Runs a control command with its arguments and returns the text written back to the client
+type HandlerFunc func(args []string) (string, error)
+
+var (
+	handlers = make(map[string]HandlerFunc)
+	listener net.Listener
+)
+
+// Time a client has to send its command
+const readTimeout = time.Second * 10
+
+// Registers a command of the control socket
+func Handle(command string, handler HandlerFunc) {
+	handlers[command] = handler
+}
+
+// Returns the registered commands in alphabetical order
+func Commands() []string {
+	commands := make([]string, 0, len(handlers))
+	for command := range handlers {
+		commands = append(commands, command)
+	}
+	sort.Strings(commands)
+	return commands
+}
+
+// Listens on a Unix socket at path and serves the registered commands in the background.
+// Each connection sends one line with a command and its arguments and receives the response.
+// Only the user running the bot can connect.
+func Start(path string) error {
+	// A socket left behind by a previous run that was killed would block listening
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		l.Close()
+		return err
+	}
+	listener = l
+
+	go func() {
+		utils.Log.Infof("Control socket listening on %v.\n", path)
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					utils.Log.WithError(err).Error("Control socket stopped.")
+				}
+				return
+			}
+			go serve(conn)
+		}
+	}()
+
+	return nil
+}
+
+// Stops listening and removes the socket
+func Stop() {
+	if listener != nil {
+		listener.Close()
+	}
+}
+
+func serve(conn net.Conn) {
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(readTimeout))
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && line == "" {
+		return
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		fmt.Fprintln(conn, "error: no command given, the commands are "+strings.Join(Commands(), ", "))
+		return
+	}
+
+	handler := handlers[fields[0]]
+	if handler == nil {
+		fmt.Fprintln(conn, "error: unknown command "+fields[0]+", the commands are "+strings.Join(Commands(), ", "))
+		return
+	}
+
+	utils.Log.WithField("command", fields[0]).Info("Control command received.")
+
+	response, err := handler(fields[1:])
+	if err != nil {
+		fmt.Fprintln(conn, "error: "+err.Error())
+		return
+	}
+	fmt.Fprintln(conn, strings.TrimRight(response, "\n"))
+}
+
+// Sends a command to the control socket at path and returns the response
+func Send(path string, command []string) (string, error) {
+	conn, err := net.DialTimeout("unix", path, readTimeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	if _, err := fmt.Fprintln(conn, strings.Join(command, " ")); err != nil {
+		return "", err
+	}
+
+	var response strings.Builder
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		response.WriteString(scanner.Text() + "\n")
+	}
+	return response.String(), scanner.Err()
+}
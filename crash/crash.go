@@ -0,0 +1,49 @@
+package crash
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/stats"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Time given to Sentry to deliver a crash report before processing continues
+const flushTimeout = time.Second * 2
+
+// Whether crashes are reported to Sentry
+var reporting bool
+
+// Reports recovered panics to the Sentry project of dsn. Until Start is called panics are only logged.
+func Start(dsn string, release string) error {
+	if err := sentry.Init(sentry.ClientOptions{Dsn: dsn, Release: release}); err != nil {
+		return err
+	}
+
+	reporting = true
+	return nil
+}
+
+// Recovers a panic of the calling goroutine, logs it with its stack trace and reports it.
+// Deferred at the start of event handlers and background jobs, operation names where it happened.
+func Recover(operation string) {
+	if r := recover(); r != nil {
+		Report(operation, r)
+	}
+}
+
+// Logs and reports a panic recovered by the caller, for deferred functions that need to know whether a panic happened.
+// It must be called from the deferred function so the stack trace still shows where the panic happened.
+func Report(operation string, r interface{}) {
+	utils.Log.WithField("operation", operation).WithField("stack", string(debug.Stack())).Error(fmt.Sprint("Recovered from panic: ", r))
+	stats.PanicRecovered()
+
+	if reporting {
+		hub := sentry.CurrentHub().Clone()
+		hub.Scope().SetTag("operation", operation)
+		hub.Recover(r)
+		hub.Flush(flushTimeout)
+	}
+}
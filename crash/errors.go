@@ -0,0 +1,141 @@
+package crash
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Number of error reports waiting to be sent before new ones are dropped
+const reportQueueSize = 100
+
+// Log fields reported as searchable tags and the names of their tags
+var contextTags = []struct{ field, tag string }{
+	{"server_id", "guild"},
+	{"channel_id", "channel"},
+	{"twitch_channel", "twitch_channel"},
+	{"operation", "operation"},
+}
+
+// An error level log entry sent to the error webhook
+type errorReport struct {
+	Content string            `json:"content"` // Summary shown by Discord webhooks
+	Text    string            `json:"text"`    // Summary shown by Slack webhooks
+	Level   string            `json:"level"`
+	Message string            `json:"message"`
+	Time    time.Time         `json:"time"`
+	Fields  map[string]string `json:"fields"`
+}
+
+// Reports error level logs to Sentry and an error webhook
+type errorHook struct {
+	sentry     bool
+	webhookURL string
+	reports    chan *logrus.Entry
+}
+
+// Reports every error level log with its context fields to Sentry, when crash reporting was
+// started, and as JSON to webhookURL if it is not empty. Reports are sent in the background.
+func ReportErrors(webhookURL string) {
+	hook := &errorHook{
+		sentry:     reporting,
+		webhookURL: webhookURL,
+		reports:    make(chan *logrus.Entry, reportQueueSize),
+	}
+	go hook.send()
+
+	utils.Log.AddHook(hook)
+}
+
+func (h *errorHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel}
+}
+
+func (h *errorHook) Fire(entry *logrus.Entry) error {
+	// The process exits after fatal logs, so they are sent right away
+	if entry.Level <= logrus.FatalLevel {
+		h.report(entry)
+		return nil
+	}
+
+	select {
+	case h.reports <- entry:
+	default:
+	}
+	return nil
+}
+
+func (h *errorHook) send() {
+	for entry := range h.reports {
+		h.report(entry)
+	}
+}
+
+func (h *errorHook) report(entry *logrus.Entry) {
+	fields := make(map[string]string, len(entry.Data))
+	for key, value := range entry.Data {
+		fields[key] = fmt.Sprint(value)
+	}
+
+	// Recovered panics are already reported to Sentry with their stack trace
+	if _, panicked := fields["stack"]; h.sentry && !panicked {
+		event := sentry.NewEvent()
+		event.Level = sentry.Level(entry.Level.String())
+		event.Message = entry.Message
+		event.Timestamp = entry.Time
+		for key, value := range fields {
+			event.Extra[key] = value
+		}
+		for _, c := range contextTags {
+			if value := fields[c.field]; value != "" {
+				event.Tags[c.tag] = value
+				delete(event.Extra, c.field)
+			}
+		}
+		sentry.CaptureEvent(event)
+		if entry.Level <= logrus.FatalLevel {
+			sentry.Flush(flushTimeout)
+		}
+	}
+
+	if h.webhookURL != "" {
+		summary := entry.Message
+		for _, c := range contextTags {
+			if value := fields[c.field]; value != "" {
+				summary += " " + c.tag + "=" + value
+			}
+		}
+		if err := fields[logrus.ErrorKey]; err != "" {
+			summary += " error=" + err
+		}
+
+		body, err := json.Marshal(errorReport{
+			Content: summary,
+			Text:    summary,
+			Level:   entry.Level.String(),
+			Message: entry.Message,
+			Time:    entry.Time,
+			Fields:  fields,
+		})
+		if err != nil {
+			return
+		}
+
+		// Failures are logged below error level so they are not reported again
+		resp, err := utils.HTTPClient.Post(h.webhookURL, "application/json", bytes.NewReader(body))
+		if err != nil {
+			utils.Log.WithField("error", err).Warn("Failed to send error report.")
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusBadRequest {
+			utils.Log.WithField("StatusCode", resp.StatusCode).Warn("Error webhook rejected error report.")
+		}
+	}
+}
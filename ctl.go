@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/config"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/control"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/features"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/stats"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/spf13/cobra"
+)
+
+var socketPath string
+
+var ctlCmd = &cobra.Command{
+	Use:   "ctl <command> [arguments]",
+	Short: "Send a command to a running bot through its control socket",
+	Long: "Sends a command to the control socket of a running bot and prints the response. " +
+		"The commands are status, reload, force-poll and dump-state.",
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := socketPath
+		if path == "" {
+			path = config.Settings.ControlSocket
+		}
+		if path == "" {
+			fmt.Fprintln(os.Stderr, "No control socket is configured. Set control_socket in the config file or pass --socket.")
+			os.Exit(1)
+		}
+
+		response, err := control.Send(path, args)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "The control socket could not be reached:", err)
+			os.Exit(1)
+		}
+		fmt.Print(response)
+		if strings.HasPrefix(response, "error: ") {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	ctlCmd.Flags().StringVar(&socketPath, "socket", "", "Path to the control socket (default control_socket of the config file)")
+	rootCmd.AddCommand(ctlCmd)
+}
+
+// Serves the control commands of a running bot on the configured Unix socket
+func startControlSocket(ts *twitch.Session) {
+	control.Handle("status", func(args []string) (string, error) {
+		snapshot := ts.Snapshot()
+
+		live := 0
+		for _, channel := range snapshot.Channels {
+			if channel.Live {
+				live++
+			}
+		}
+		connected := 0
+		for _, guild := range snapshot.Guilds {
+			if guild.Status == "connected" {
+				connected++
+			}
+		}
+		twitchStatus := "disconnected"
+		if snapshot.Connected {
+			twitchStatus = "connected"
+		}
+
+		return fmt.Sprintf("twitch: %v\nuptime: %v\nguilds: %v of %v connected\ntwitch channels: %v\nlive: %v\nrate limit remaining: %v",
+			twitchStatus, stats.SessionUptime().Round(time.Second), connected, len(snapshot.Guilds), len(snapshot.Channels), live, snapshot.RateLimit.Remaining), nil
+	})
+	control.Handle("reload", func(args []string) (string, error) {
+		if err := config.Reload(); err != nil {
+			return "", errors.New("the config file could not be loaded: " + err.Error())
+		}
+		if err := features.Load(); err != nil {
+			return "", errors.New("the feature flags could not be loaded: " + err.Error())
+		}
+		return "The config file and feature flags were reloaded. Settings only read at startup need a restart.", nil
+	})
+	control.Handle("force-poll", func(args []string) (string, error) {
+		ts.PollNow()
+		return "Twitch will be polled now.", nil
+	})
+	control.Handle("dump-state", func(args []string) (string, error) {
+		snapshot := ts.Snapshot()
+
+		state, err := json.MarshalIndent(snapshot, "", "  ")
+		return string(state), err
+	})
+
+	if err := control.Start(config.Settings.ControlSocket); err != nil {
+		utils.Log.WithError(err).Error("Control socket could not be started.")
+	}
+}
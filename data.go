@@ -0,0 +1,439 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/accounts"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/config"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	overwrite bool   // Whether import replaces existing data files
+	oldKey    string // Encryption passphrase data files were written with before migrating
+	dryRun    bool   // Whether migrate only verifies the converted data without switching over to it
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export <archive.tar.gz>",
+	Short: "Export every data file into a decrypted archive",
+	Long: "Writes every data file of the data directory into a gzipped tar archive. Encrypted files are decrypted, " +
+		"so the archive can be imported by an instance with a different encryption key. Keep the archive private.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exportData(args[0])
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <archive.tar.gz>",
+	Short: "Import data files from an archive created by export",
+	Long:  "Writes the data files of an archive created by export into the data directory, encrypted with the current key.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return importData(args[0])
+	},
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the data files of a session to the current format and encryption key",
+	Long: "Rewrites every data file with the current encryption key, decrypting them with --old-encryption-key " +
+		"if the key changed, then loads and saves the session so older data is converted to the current format. " +
+		"The conversion is done in a copy of the data directory, which replaces it only if the Twitch channels, " +
+		"registrations, server settings and linked accounts were all carried over. The previous directory is kept as a backup.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return migrateData()
+	},
+}
+
+func init() {
+	importCmd.Flags().BoolVar(&overwrite, "overwrite", false, "Replace data files that already exist")
+	migrateCmd.Flags().StringVar(&oldKey, "old-encryption-key", "", "Passphrase the data files are currently encrypted with")
+	migrateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Verify the converted data without replacing the data directory")
+
+	rootCmd.AddCommand(exportCmd, importCmd, migrateCmd)
+}
+
+func exportData(archivePath string) error {
+	names, err := utils.ListGobFiles(utils.DataDir)
+	if err != nil {
+		return err
+	}
+	logs, err := utils.ListLogFiles(utils.DataDir)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(archivePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	archive := tar.NewWriter(gz)
+	add := func(name string, data []byte) error {
+		if err := archive.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(data))}); err != nil {
+			return err
+		}
+		_, err := archive.Write(data)
+		return err
+	}
+
+	for _, name := range names {
+		data, err := utils.ReadFileFromDisk(utils.DataDir, name)
+		if err != nil {
+			return err
+		}
+		if err := add(name+".gob", data); err != nil {
+			return err
+		}
+		fmt.Printf("Exported %v\n", name)
+	}
+
+	// Log files are exported with one decrypted line per line
+	for _, name := range logs {
+		lines, err := utils.ReadLinesFromDisk(utils.DataDir, name)
+		if err != nil {
+			return err
+		}
+		if err := add(name+".log", joinLines(lines)); err != nil {
+			return err
+		}
+		fmt.Printf("Exported %v\n", name)
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func importData(archivePath string) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	archive := tar.NewReader(gz)
+
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		// Only plain data and log files are accepted so entries can't escape the data directory
+		name := header.Name
+		ext := path.Ext(name)
+		if header.Typeflag != tar.TypeReg || path.Base(name) != name || (ext != ".gob" && ext != ".log") {
+			return fmt.Errorf("%v is not a data file", name)
+		}
+
+		if _, err := os.Stat(utils.DataDir + "/" + name); err == nil && !overwrite {
+			return fmt.Errorf("%v already exists, use --overwrite to replace it", strings.TrimSuffix(name, ext))
+		}
+		name = strings.TrimSuffix(name, ext)
+
+		data, err := io.ReadAll(archive)
+		if err != nil {
+			return err
+		}
+		if ext == ".log" {
+			err = utils.WriteLinesToDisk(utils.DataDir, name, splitLines(data))
+		} else {
+			err = utils.WriteFileToDisk(utils.DataDir, name, data)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Imported %v\n", name)
+	}
+}
+
+func migrateData() error {
+	names, err := utils.ListGobFiles(utils.DataDir)
+	if err != nil {
+		return err
+	}
+
+	// Read everything before writing so a wrong key leaves the files untouched
+	if oldKey != "" {
+		utils.SetEncryptionKey(oldKey)
+	}
+	contents := make(map[string][]byte)
+	for _, name := range names {
+		if contents[name], err = utils.ReadFileFromDisk(utils.DataDir, name); err != nil {
+			return err
+		}
+	}
+	logs, err := readLogs(utils.DataDir)
+	if err != nil {
+		return err
+	}
+	before, err := countRows(contents)
+	if err != nil {
+		return fmt.Errorf("data files could not be read: %w", err)
+	}
+	before.logLines = countLines(logs)
+
+	// Data is converted in a copy of the data directory, which only replaces it once nothing was lost
+	dataDir := filepath.Clean(utils.DataDir)
+	staging := dataDir + ".migrating"
+	if err := os.RemoveAll(staging); err != nil {
+		return err
+	}
+	if err := copyDir(dataDir, staging); err != nil {
+		return err
+	}
+	after, err := convertData(staging, contents, logs)
+	if err != nil {
+		os.RemoveAll(staging)
+		return fmt.Errorf("%w, the data directory was left untouched", err)
+	}
+
+	fmt.Printf("Twitch channels: %v before, %v after\n", before.channels, after.channels)
+	fmt.Printf("Registrations: %v before, %v after\n", before.registrations, after.registrations)
+	fmt.Printf("Discord servers with settings: %v before, %v after\n", before.guilds, after.guilds)
+	fmt.Printf("Linked accounts: %v before, %v after\n", before.accounts, after.accounts)
+	fmt.Printf("Log lines: %v before, %v after\n", before.logLines, after.logLines)
+	if before != after {
+		os.RemoveAll(staging)
+		return errors.New("converted data does not match the original, the data directory was left untouched")
+	}
+
+	if dryRun {
+		fmt.Println("Converted data matches the original. The data directory was left untouched since this is a dry run.")
+		return os.RemoveAll(staging)
+	}
+
+	backup := dataDir + ".pre-migrate-" + time.Now().Format("20060102-150405")
+	if err := os.Rename(dataDir, backup); err != nil {
+		os.RemoveAll(staging)
+		return err
+	}
+	if err := os.Rename(staging, dataDir); err != nil {
+		os.Rename(backup, dataDir)
+		return err
+	}
+	utils.DataDir = dataDir
+	fmt.Printf("Migrated session %v. The previous data directory was kept as %v.\n", sessionName, backup)
+
+	return nil
+}
+
+// Rewrites the data and log files in a staging directory with the current encryption key, converts them to the
+// current format by loading and closing the session, and counts the rows of the result
+func convertData(staging string, contents map[string][]byte, logs map[string][][]byte) (rowCounts, error) {
+	if key := os.Getenv("DATA_ENCRYPTION_KEY"); key != "" {
+		utils.SetEncryptionKey(key)
+	} else {
+		utils.SetEncryptionKey(config.Settings.EncryptionKey)
+	}
+
+	dataDir := utils.DataDir
+	defer func() { utils.DataDir = dataDir }()
+	if err := utils.SetDataDir(staging); err != nil {
+		return rowCounts{}, err
+	}
+
+	for name, data := range contents {
+		if err := utils.WriteFileToDisk(staging, name, data); err != nil {
+			return rowCounts{}, err
+		}
+	}
+	for name, lines := range logs {
+		if err := utils.WriteLinesToDisk(staging, name, lines); err != nil {
+			return rowCounts{}, err
+		}
+	}
+	fmt.Printf("Rewrote %v data files and %v log files.\n", len(contents), len(logs))
+
+	// Loading and closing the session converts older data to the current format, without connecting to Twitch
+	if err := accounts.Load(); err != nil {
+		return rowCounts{}, err
+	}
+	ts, err := twitch.Load(sessionName)
+	if err != nil {
+		return rowCounts{}, err
+	}
+	if err := ts.Close(); err != nil {
+		return rowCounts{}, err
+	}
+
+	names, err := utils.ListGobFiles(staging)
+	if err != nil {
+		return rowCounts{}, err
+	}
+	converted := make(map[string][]byte)
+	for _, name := range names {
+		if converted[name], err = utils.ReadFileFromDisk(staging, name); err != nil {
+			return rowCounts{}, err
+		}
+	}
+	counts, err := countRows(converted)
+	if err != nil {
+		return counts, err
+	}
+
+	// The log files must decrypt with the current key
+	convertedLogs, err := readLogs(staging)
+	if err != nil {
+		return counts, err
+	}
+	counts.logLines = countLines(convertedLogs)
+	return counts, nil
+}
+
+// Returns the decrypted lines of every log file in a directory, e.g. the stream event log, by file name
+func readLogs(dir string) (map[string][][]byte, error) {
+	names, err := utils.ListLogFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := make(map[string][][]byte)
+	for _, name := range names {
+		if logs[name], err = utils.ReadLinesFromDisk(dir, name); err != nil {
+			return nil, err
+		}
+	}
+	return logs, nil
+}
+
+func countLines(logs map[string][][]byte) int {
+	n := 0
+	for _, lines := range logs {
+		n += len(lines)
+	}
+	return n
+}
+
+// Joins the lines of a log file for an export archive
+func joinLines(lines [][]byte) []byte {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// Splits the contents of a log file in an export archive into its lines
+func splitLines(data []byte) [][]byte {
+	lines := [][]byte{}
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Rows of the session's data compared before and after a migration
+type rowCounts struct {
+	channels      int // Monitored Twitch channels
+	registrations int // Discord channels notified of a Twitch channel
+	guilds        int // Discord servers with settings
+	accounts      int // Discord users with a linked Twitch account
+	logLines      int // Lines of the log files, e.g. stream events
+}
+
+// Counts the rows of the session's data files. Only fields every version of the files has are decoded,
+// since gob ignores the rest, so files written by any version of the bot can be counted.
+func countRows(files map[string][]byte) (rowCounts, error) {
+	var counts rowCounts
+	decode := func(name string, o interface{}) error {
+		if files[name] == nil {
+			return nil
+		}
+		if err := gob.NewDecoder(bytes.NewReader(files[name])).Decode(o); err != nil {
+			return fmt.Errorf("%v: %w", name, err)
+		}
+		return nil
+	}
+
+	var channels map[string]*struct {
+		DiscordChannels map[string][]*struct{ ChannelID string }
+	}
+	if err := decode(sessionName, &channels); err != nil {
+		return counts, err
+	}
+	counts.channels = len(channels)
+	for _, tci := range channels {
+		for _, dcs := range tci.DiscordChannels {
+			counts.registrations += len(dcs)
+		}
+	}
+
+	var guilds map[string]*struct {
+		Profiles map[string]*struct{ Template string }
+	}
+	if err := decode(sessionName+"_guilds", &guilds); err != nil {
+		return counts, err
+	}
+	counts.guilds = len(guilds)
+
+	// Accounts linked before they were shared between sessions are moved into the account store
+	linked := make(map[string]bool)
+	for _, name := range []string{"accounts", sessionName + "_accounts"} {
+		var accounts map[string]*struct{ TwitchUserID string }
+		if err := decode(name, &accounts); err != nil {
+			return counts, err
+		}
+		for discordUserID := range accounts {
+			linked[discordUserID] = true
+		}
+	}
+	counts.accounts = len(linked)
+
+	return counts, nil
+}
+
+// Copies the files of a directory and its subdirectories
+func copyDir(from string, to string) error {
+	return filepath.Walk(from, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+
+		if info.IsDir() {
+			return os.MkdirAll(target, 0700)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, info.Mode().Perm())
+	})
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Sets up an empty data directory for session with a stream event written with the encryption key old
+func newMigrationDir(t *testing.T, old string) string {
+	t.Helper()
+
+	dataDir := utils.DataDir
+	t.Cleanup(func() {
+		utils.DataDir = dataDir
+		utils.SetEncryptionKey("")
+	})
+
+	dir := filepath.Join(t.TempDir(), "data")
+	if err := utils.SetDataDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	sessionName = "session1"
+
+	utils.SetEncryptionKey(old)
+	ts, _ := twitch.Load(sessionName)
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := utils.AppendLineToDisk(dir, sessionName+"_events", []byte(`{"login":"shroud"}`)); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestMigrateReencryptsEventLog(t *testing.T) {
+	dir := newMigrationDir(t, "old passphrase")
+
+	// The migration must not need Twitch credentials
+	t.Setenv("TWITCH_CLIENT_ID", "")
+	t.Setenv("DATA_ENCRYPTION_KEY", "new passphrase")
+	oldKey = "old passphrase"
+	defer func() { oldKey = "" }()
+
+	if err := migrateData(); err != nil {
+		t.Fatal(err)
+	}
+
+	utils.SetEncryptionKey("new passphrase")
+	lines, err := utils.ReadLinesFromDisk(dir, sessionName+"_events")
+	if err != nil {
+		t.Fatalf("event log could not be read with the new key: %v", err)
+	}
+	if len(lines) != 1 || string(lines[0]) != `{"login":"shroud"}` {
+		t.Errorf("got event log %q", lines)
+	}
+}
+
+func TestExportImportEventLog(t *testing.T) {
+	dir := newMigrationDir(t, "old passphrase")
+	archive := filepath.Join(t.TempDir(), "export.tar.gz")
+	if err := exportData(archive); err != nil {
+		t.Fatal(err)
+	}
+
+	// The archive is imported by an instance with another key
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := utils.SetDataDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	utils.SetEncryptionKey("new passphrase")
+	if err := importData(archive); err != nil {
+		t.Fatal(err)
+	}
+
+	lines, err := utils.ReadLinesFromDisk(dir, sessionName+"_events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 1 || string(lines[0]) != `{"login":"shroud"}` {
+		t.Errorf("got event log %q", lines)
+	}
+}
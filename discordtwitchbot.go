@@ -1,90 +1,318 @@
 package main
 
 import (
-	"flag"
+	"context"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
+	_ "time/tzdata" // Embeds the timezone database so guild timezones work on hosts without one
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/accounts"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/config"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/control"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/crash"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/features"
 	"github.com/samuel-mokhtar/DiscordTwitchBot/handlers"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/helixcache"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/plugins"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/push"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/stats"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/systemd"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/tracing"
 	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
 	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/version"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/web"
+	"github.com/spf13/cobra"
 )
 
 // Variables used for command line parameters
 var (
-	token     string
-	tokenPath string
+	token        string
+	tokenPath    string
+	configPath   string
+	dataDir      string
+	sessionName  string
+	queryWorkers int
 )
 
+// Flushes remaining traces on shutdown
+var shutdownTracing = func(context.Context) error { return nil }
+
+var rootCmd = &cobra.Command{
+	Use:               "discordtwitchbot",
+	Short:             "Discord bot announcing Twitch streams",
+	Long:              "Discord bot announcing Twitch streams. Runs the bot when no command is given.",
+	PersistentPreRun:  setup,
+	Run:               runBot,
+	SilenceUsage:      true,
+	DisableAutoGenTag: true,
+}
+
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run the bot",
+	Args:  cobra.NoArgs,
+	Run:   runBot,
+}
+
 func init() {
-	flag.StringVar(&token, "t", "", "Bot Token")
-	flag.StringVar(&tokenPath, "p", "", "Path to Bot Token")
-	flag.Parse()
-
-	// We process the most important flag to receive a token
-	// The flags listed in order of importance are
-	// t > p
-	// If no flags are set the Bot loads token from environment variable BOT_TOKEN
-	if len(token) > 0 {
+	flags := rootCmd.PersistentFlags()
+	flags.StringVarP(&token, "token", "t", "", "Bot Token")
+	flags.StringVarP(&tokenPath, "token-file", "p", "", "Path to Bot Token")
+	flags.StringVarP(&configPath, "config", "c", "", "Path to config file (default <data-dir>/config.json)")
+	flags.StringVar(&dataDir, "data-dir", constants.DataPath, "Directory persisted data is stored in")
+	flags.StringVar(&sessionName, "session", "session1", "Name of the Twitch session, used to name its data files")
+	rootCmd.Flags().IntVarP(&queryWorkers, "workers", "w", constants.TwitchQueryWorkers, "Number of Twitch query batches issued concurrently")
+	runCmd.Flags().IntVarP(&queryWorkers, "workers", "w", constants.TwitchQueryWorkers, "Number of Twitch query batches issued concurrently")
+
+	rootCmd.AddCommand(runCmd)
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// Prepares the data directory, config, encryption, HTTP client and tracing used by every command
+func setup(cmd *cobra.Command, args []string) {
+	if err := utils.SetDataDir(dataDir); err != nil {
+		utils.Log.WithError(err).Fatal("Data directory could not be used")
+	}
+
+	if configPath == "" {
+		configPath = utils.DataDir + "/config.json"
+	}
+	if err := config.Load(configPath); err != nil {
+		utils.Log.WithError(err).Fatal("Config file could not be read")
+	}
+
+	// Encrypt persisted data if a key is set in the environment variable DATA_ENCRYPTION_KEY or the config file
+	if key := os.Getenv("DATA_ENCRYPTION_KEY"); key != "" {
+		utils.SetEncryptionKey(key)
+	} else {
+		utils.SetEncryptionKey(config.Settings.EncryptionKey)
+	}
+
+	// Configure outgoing HTTP requests
+	if err := configureHTTPClient(); err != nil {
+		utils.Log.WithError(err).Fatal("HTTP client could not be configured.")
+	}
+
+	// Export traces of the poll loop, storage and outgoing requests
+	if config.Settings.OTLPEndpoint != "" {
+		shutdown, err := tracing.Start(config.Settings.OTLPEndpoint, config.Settings.OTLPInsecure, version.Version)
+		if err != nil {
+			utils.Log.WithError(err).Fatal("Tracing could not be started.")
+		}
+		shutdownTracing = shutdown
+	}
+	// Lookups answered from the cache are neither counted as Twitch requests nor traced
+	utils.HTTPClient.Transport = helixcache.Transport(stats.Transport(tracing.Transport(utils.HTTPClient.Transport)))
 
+	// Report panics recovered in handlers and background jobs
+	if config.Settings.SentryDSN != "" {
+		if err := crash.Start(config.Settings.SentryDSN, version.Version); err != nil {
+			utils.Log.WithError(err).Error("Crash reporting could not be started.")
+		}
+	}
+	// Report error logs with their guild, channel and Twitch channel
+	if config.Settings.ErrorReporting {
+		crash.ReportErrors(config.Settings.ErrorWebhookURL)
+	}
+}
+
+// Configures the shared HTTP client from the config
+func configureHTTPClient() error {
+	userAgent := config.Settings.UserAgent
+	if userAgent == "" {
+		userAgent = "DiscordTwitchBot/" + version.Version
+	}
+	return utils.ConfigureHTTPClient(time.Duration(config.Settings.HTTPTimeout)*time.Second,
+		config.Settings.HTTPProxy, config.Settings.TLSCAFile, userAgent)
+}
+
+// We process the most important flag to receive a token
+// The flags listed in order of importance are
+// t > p
+// If no flags are set the Bot loads token from environment variable BOT_TOKEN
+func resolveToken() (string, error) {
+	if len(token) > 0 {
+		return token, nil
 	} else if len(tokenPath) > 0 {
 		rawToken, err := os.ReadFile(tokenPath)
 		if err != nil {
-			utils.Log.WithError(err).Fatal("Token file could not be read")
+			return "", err
 		}
-		token = string(rawToken)
-	} else {
-		utils.Log.Warning("No Flags specified. Loading bot token from the environment variable BOT_TOKEN.")
-		token = os.Getenv("BOT_TOKEN")
+		return string(rawToken), nil
 	}
+
+	utils.Log.Warning("No Flags specified. Loading bot token from the environment variable BOT_TOKEN.")
+	return os.Getenv("BOT_TOKEN"), nil
 }
 
-func main() {
-	// Create a new Discord session using the provided bot token.
-	dg, errDiscord := discordgo.New("Bot " + token)
-	if errDiscord != nil {
-		utils.Log.WithError(errDiscord).Fatal("Discord session could not be created.")
+// Runs the bot until a termination signal is received
+func runBot(cmd *cobra.Command, args []string) {
+	token, err := resolveToken()
+	if err != nil {
+		utils.Log.WithError(err).Fatal("Token file could not be read")
+	}
+
+	// Data that can't be read would be overwritten with empty data by the next write, e.g. with a wrong encryption key
+	if err := checkStorage(); err != nil {
+		utils.Log.WithError(err).Fatal("Data files could not be read")
+	}
+
+	// Load feature flags
+	if err := features.Load(); err != nil {
+		utils.Log.WithError(err).Error("Feature flags could not be loaded.")
+	}
+
+	// Load linked accounts
+	if err := accounts.Load(); err != nil {
+		utils.Log.WithError(err).Error("Linked accounts could not be loaded.")
+	}
+
+	// Load push subscriptions
+	if err := push.Load(); err != nil {
+		utils.Log.WithError(err).Error("Push subscriptions could not be loaded.")
 	}
 
 	// Create a new Twitch session with client id, secret, and a path to saved data
-	ts, errTwitch := twitch.New(os.Getenv("TWITCH_CLIENT_ID"), os.Getenv("TWITCH_CLIENT_SECRET"), "session1")
+	ts, errTwitch := twitch.New(os.Getenv("TWITCH_CLIENT_ID"), os.Getenv("TWITCH_CLIENT_SECRET"), sessionName)
+	// Unreadable data was caught by checkStorage, so the bot runs without Twitch until it can connect
 	if errTwitch != nil {
 		utils.Log.WithError(errTwitch).Error("Twitch session could not be created.")
 	}
+	ts.SetQueryWorkers(queryWorkers)
+	ts.SetDebounce(time.Duration(config.Settings.LiveDebounce)*time.Second, time.Duration(config.Settings.OfflineDebounce)*time.Second)
 
 	utils.Log.Info("Bot is starting up.")
 
-	// Register event handlers
-	dg.AddHandler(handlers.GuildCreate)
-	dg.AddHandler(handlers.GuildDelete)
-	dg.AddHandler(handlers.MessageCreate)
-
-	dg.Identify.Intents = discordgo.IntentsGuilds | discordgo.IntentsGuildMessages
+	// Load lifetime statistics
+	if err := stats.Load(); err != nil {
+		utils.Log.WithError(err).Error("Bot statistics could not be loaded.")
+	}
 
 	// Open a websocket connection to Discord and begin listening.
-	errDiscord = dg.Open()
+	dg, errDiscord := openDiscord(token)
 	if errDiscord != nil {
 		utils.Log.WithError(errDiscord).Fatal("Could not establish connection to Discord.")
 	}
 
+	// Alert the operator when persisted data stops being saved
+	utils.OnStorageHealthChange(func(healthy bool, err error) {
+		if healthy {
+			utils.NotifyOperator(dg, "Data is being saved again.")
+		} else {
+			utils.NotifyOperator(dg, constants.StorageUnhealthyMessage+" Last error: "+err.Error())
+		}
+	})
+
 	// Open a connection to twitch
 	utils.Log.Info("Establishing connection to Twitch.")
 	errTwitch = ts.GetAuthToken()
 	if errTwitch != nil {
-		utils.Log.WithError(errTwitch).Error("Could not establish connection to Twitch.")
+		utils.Log.WithError(errTwitch).Error("Could not establish connection to Twitch. Retrying in the background.")
+	}
+
+	// Check GitHub for newer releases
+	if config.Settings.UpdateCheck {
+		go version.WatchReleases(func(release string) {
+			utils.NotifyOperator(dg, "A newer release of the bot is available: "+release+" (running "+version.Version+").")
+		})
+	}
+
+	// Receive EventSub notifications through the HTTP server
+	if config.Settings.PublicURL != "" && config.Settings.EventSubSecret != "" {
+		web.Handle("/eventsub", ts.EnableEventSub(strings.TrimSuffix(config.Settings.PublicURL, "/")+"/eventsub", config.Settings.EventSubSecret, dg))
+	}
+	// Link accounts through the Twitch authorization page
+	if config.Settings.PublicURL != "" {
+		web.Handle("/link", ts.EnableAccountLinking(strings.TrimSuffix(config.Settings.PublicURL, "/")+"/link"))
+	}
+	// Serve go-live events as an Atom feed
+	if config.Settings.LiveFeed {
+		web.Handle("/feed", ts.FeedHandler(strings.TrimSuffix(config.Settings.PublicURL, "/")+"/feed"))
+	}
+	// Serve stream schedules as calendars
+	if config.Settings.Calendar {
+		web.Handle("/calendar.ics", ts.CalendarHandler())
+	}
+	// Serve who's live as an embeddable HTML page
+	if config.Settings.StatusPage {
+		web.Handle("/status", ts.StatusPageHandler())
+	}
+	// Serve the live status of guilds as JSON
+	if config.Settings.LiveAPI {
+		web.Handle("/api/live", ts.LiveStatusHandler())
+	}
+	// Stream live events to overlays
+	if config.Settings.EventSocket {
+		web.Handle("/api/events", ts.EventSocketHandler())
+	}
+	// Accept announcements from external systems
+	if config.Settings.AnnounceAPI {
+		web.Handle("/api/announce", ts.AnnounceHandler())
+	}
+	// Serve command metrics to Prometheus
+	if config.Settings.Metrics {
+		web.Handle("/metrics", stats.MetricsHandler(config.Settings.AdminToken))
+	}
+	// Admin endpoints
+	if config.Settings.AdminToken != "" {
+		web.Handle("/admin/credentials", ts.CredentialsHandler(config.Settings.AdminToken))
+		web.Handle("/admin/state", ts.StateHandler(config.Settings.AdminToken))
+	}
+	if config.Settings.HTTPAddress != "" {
+		web.Start(config.Settings.HTTPAddress)
 	}
 
 	// Start monitoring Twitch
-	go twitch.StartMonitoring(ts, dg)
+	twitch.StartMonitoring(ts, dg)
+
+	// Accept commands from the shell
+	if config.Settings.ControlSocket != "" {
+		startControlSocket(ts)
+	}
+
+	// Connect the additional bots, which share the Twitch session of the main bot
+	var bots []*discordgo.Session
+	for _, bot := range config.Settings.Bots {
+		bs, err := openDiscord(bot.Token)
+		if err != nil {
+			utils.Log.WithError(err).WithField("bot", bot.Name).Error("Could not establish connection to Discord.")
+			continue
+		}
+		twitch.AttachDiscord(ts, bs)
+		bots = append(bots, bs)
+	}
+
+	// Mark guilds the bots were removed from while offline, unless a bot is missing and its guilds would look removed
+	if len(bots) == len(config.Settings.Bots) {
+		twitch.Reconcile(ts, append([]*discordgo.Session{dg}, bots...)...)
+	} else {
+		utils.Log.Warn("Not every bot is connected. Guild membership is not reconciled.")
+	}
+
+	// Let systemd know the bot started and restart it if polling stalls
+	systemd.Ready("Monitoring Twitch")
+	go systemd.Watchdog(ts.Polling)
 
 	// Wait here until CTRL-C or other term signal is received.
-	utils.Log.Info("Bot is now running.")
+	utils.Log.WithField("instance_id", twitch.InstanceID).Info("Bot is now running.")
 	sc := make(chan os.Signal, 1)
 	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
 	<-sc
 
+	systemd.Stopping()
+	control.Stop()
+
 	// Cleanly shut down the Twitch session
 	utils.Log.Info("Twitch session is shutting down.")
 	ts.Close()
@@ -92,6 +320,57 @@ func main() {
 	// Cleanly close down the Discord session.
 	utils.Log.Info("Bot is shutting down.")
 	dg.Close()
+	for _, bs := range bots {
+		bs.Close()
+	}
+
+	// Persist lifetime statistics
+	if err := stats.Save(); err != nil {
+		utils.Log.WithError(err).Error("Bot statistics could not be saved.")
+	}
+
+	if err := shutdownTracing(context.Background()); err != nil {
+		utils.Log.WithError(err).Error("Traces could not be flushed.")
+	}
 
 	utils.Log.Info("Bot has shutdown.")
 }
+
+// Creates a Discord session for a bot token, registers the event handlers and connects to Discord
+func openDiscord(token string) (*discordgo.Session, error) {
+	dg, err := discordgo.New("Bot " + token)
+	if err != nil {
+		return nil, err
+	}
+	dg.Client = utils.HTTPClient
+
+	// Register event handlers
+	dg.AddHandler(handlers.Ready)
+	dg.AddHandler(handlers.Resumed)
+	dg.AddHandler(handlers.Disconnect)
+	dg.AddHandler(handlers.GuildCreate)
+	dg.AddHandler(handlers.GuildDelete)
+	dg.AddHandler(handlers.ChannelUpdate)
+	dg.AddHandler(handlers.MessageCreate)
+	dg.AddHandler(handlers.GuildBanAdd)
+	dg.AddHandler(handlers.MessageReactionAdd)
+	for _, handler := range plugins.Handlers() {
+		dg.AddHandler(handler)
+	}
+
+	dg.Identify.Intents = discordgo.IntentsGuilds | discordgo.IntentsGuildMessages | discordgo.IntentsGuildBans | discordgo.IntentsGuildMessageReactions | discordgo.IntentsDirectMessages
+
+	// Members streaming on Twitch can be detected from their presence, which is a privileged intent
+	if config.Settings.PresenceIntent {
+		dg.AddHandler(handlers.PresenceUpdate)
+		dg.Identify.Intents |= discordgo.IntentsGuildPresences
+	}
+
+	// Commands are run through the slash command instead of messages, so guild messages are not needed
+	if config.Settings.InteractionOnly {
+		dg.AddHandler(handlers.InteractionCreate)
+		dg.Identify.Intents &^= discordgo.IntentsGuildMessages
+	}
+
+	return dg, dg.Open()
+}
@@ -0,0 +1,127 @@
+package features
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Experimental features that must be enabled before their subsystem activates
+const (
+	EventSub = "eventsub"
+)
+
+var known = []string{EventSub}
+
+type flags struct {
+	Global map[string]bool            // Map of feature to whether it is enabled everywhere
+	Guilds map[string]map[string]bool // Map of Discord guild IDs to per guild overrides
+}
+
+var (
+	mu    sync.RWMutex
+	state = flags{
+		Global: make(map[string]bool),
+		Guilds: make(map[string]map[string]bool),
+	}
+)
+
+// Loads the feature flags from disk, replacing the flags in memory
+func Load() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	state = flags{
+		Global: make(map[string]bool),
+		Guilds: make(map[string]map[string]bool),
+	}
+	err := utils.ReadGobFromDisk(utils.DataDir, "features", &state)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if state.Global == nil {
+		state.Global = make(map[string]bool)
+	}
+	if state.Guilds == nil {
+		state.Guilds = make(map[string]map[string]bool)
+	}
+
+	return err
+}
+
+// Returns the names of every feature that can be toggled
+func Known() []string {
+	names := append([]string{}, known...)
+	sort.Strings(names)
+
+	return names
+}
+
+// Returns whether a feature can be toggled
+func IsKnown(feature string) bool {
+	for _, k := range known {
+		if k == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns whether a feature is enabled in a guild. A guild override takes precedence over the global flag.
+func Enabled(feature string, guildID string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if enabled, ok := state.Guilds[guildID][feature]; ok {
+		return enabled
+	}
+	return state.Global[feature]
+}
+
+// Returns whether a feature is enabled globally
+func EnabledGlobally(feature string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return state.Global[feature]
+}
+
+// Enables or disables a feature in a guild, or globally if guildID is empty
+func Set(feature string, guildID string, enabled bool) error {
+	if !IsKnown(feature) {
+		return constants.ErrUnknownFeature
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if guildID == "" {
+		state.Global[feature] = enabled
+	} else {
+		if state.Guilds[guildID] == nil {
+			state.Guilds[guildID] = make(map[string]bool)
+		}
+		state.Guilds[guildID][feature] = enabled
+	}
+
+	return utils.WriteGobToDisk(utils.DataDir, "features", state)
+}
+
+// Deletes the overrides of a guild. Returns the number of overrides deleted.
+func ForgetGuild(guildID string) (int, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	n := len(state.Guilds[guildID])
+	if n == 0 {
+		return 0, nil
+	}
+
+	delete(state.Guilds, guildID)
+	return n, utils.WriteGobToDisk(utils.DataDir, "features", state)
+}
@@ -4,11 +4,17 @@ go 1.16
 
 require (
 	github.com/bwmarrin/discordgo v0.23.2
-	github.com/gorilla/websocket v1.4.2 // indirect
+	github.com/getsentry/sentry-go v0.10.0
+	github.com/gorilla/websocket v1.4.2
 	github.com/nicklaw5/helix v1.13.1
 	github.com/sirupsen/logrus v1.8.1
 	github.com/snowzach/rotatefilehook v0.0.0-20180327172521-2f64f265f58c
-	golang.org/x/crypto v0.0.0-20210421170649-83a5a9bb288b // indirect
-	golang.org/x/sys v0.0.0-20210502180810-71e4cd670f79 // indirect
+	github.com/spf13/cobra v1.1.3
+	go.opentelemetry.io/otel v1.0.0
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp v1.0.0
+	go.opentelemetry.io/otel/sdk v1.0.0
+	go.opentelemetry.io/otel/trace v1.0.0
+	go.starlark.net v0.0.0-20230302034142-4b1e35fe2254
+	golang.org/x/crypto v0.0.0-20210421170649-83a5a9bb288b
 	gopkg.in/natefinch/lumberjack.v2 v2.0.0 // indirect
 )
@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/crash"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+)
+
+func ChannelUpdate(s *discordgo.Session, event *discordgo.ChannelUpdate) {
+	defer crash.Recover("channel_update")
+
+	if t := twitch.GetSession(s); t != nil {
+		t.UpdateChannelName(event.GuildID, event.ID, event.Name)
+	}
+}
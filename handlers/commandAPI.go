@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/config"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandAPI(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	t := twitch.GetSession(s)
+
+	if !config.Settings.LiveAPI && !config.Settings.EventSocket && !config.Settings.AnnounceAPI {
+		sendTemporaryMessage(s, m.ChannelID, "None of the live status API, the event socket and the announce webhook is enabled by the bot operator.")
+		return
+	}
+
+	if len(c) == 1 {
+		switch c[0] {
+		case "token":
+			token, err := t.NewAPIToken(m.GuildID)
+			if err != nil {
+				utils.Log.WithError(err).Error("Failed to create live status API token.")
+				sendTemporaryMessage(s, m.ChannelID, "Error creating an API token.")
+				return
+			}
+
+			utils.Log.WithFields(logrus.Fields{
+				"user":      m.Author.Username,
+				"server_id": m.GuildID}).Info("Succeeded in creating live status API token.")
+
+			content := "The live status API token of this Discord server is `" + token + "`. Any previous token no longer works."
+			if config.Settings.PublicURL != "" {
+				content += "\n" + strings.TrimSuffix(config.Settings.PublicURL, "/") + "/api/live?guild=" + m.GuildID + "&token=" + token
+			}
+			if sendDirectMessage(s, m.Author.ID, content) != nil {
+				sendTemporaryMessage(s, m.ChannelID, "A new API token was created but I couldn't send it to you. Allow direct messages from server members and try again.")
+				return
+			}
+			sendTemporaryMessage(s, m.ChannelID, "A new API token was sent to you in a direct message.")
+			return
+		case "revoke":
+			if err := t.RevokeAPIToken(m.GuildID); errors.Is(err, constants.ErrAPITokenNotSet) {
+				sendTemporaryMessage(s, m.ChannelID, "This Discord server has no API token.")
+				return
+			}
+
+			utils.Log.WithFields(logrus.Fields{
+				"user":      m.Author.Username,
+				"server_id": m.GuildID}).Info("Succeeded in revoking live status API token.")
+
+			sendTemporaryMessage(s, m.ChannelID, "The API token of this Discord server was revoked.")
+			return
+		case "intake":
+			t.SetIntakeChannel(m.GuildID, m.ChannelID)
+
+			utils.Log.WithFields(logrus.Fields{
+				"user":       m.Author.Username,
+				"channel_id": m.ChannelID,
+				"server_id":  m.GuildID}).Info("Succeeded in setting intake channel.")
+
+			sendTemporaryMessage(s, m.ChannelID, "Announcements posted to the announce webhook will be sent to this channel.")
+			return
+		}
+	} else if len(c) == 2 && c[0] == "intake" && c[1] == "off" {
+		t.SetIntakeChannel(m.GuildID, "")
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"server_id": m.GuildID}).Info("Succeeded in disabling intake channel.")
+
+		sendTemporaryMessage(s, m.ChannelID, "Announcements posted to the announce webhook will be refused.")
+		return
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" api token\n"+
+		constants.CommandPrefix+" api revoke\n"+
+		constants.CommandPrefix+" api intake [off]")
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/stats"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/version"
+)
+
+func commandAbout(s *discordgo.Session, m *discordgo.MessageCreate) {
+	c := stats.Get()
+
+	aboutEmbed := &discordgo.MessageEmbed{
+		Title: "Discord Twitch Bot",
+		URL:   "https://github.com/samuel-mokhtar/DiscordTwitchBot",
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Version", Value: version.Version, Inline: true},
+			{Name: "Commit", Value: version.Commit, Inline: true},
+			{Name: "Go", Value: runtime.Version(), Inline: true},
+			{Name: "discordgo", Value: version.ModuleVersion("github.com/bwmarrin/discordgo"), Inline: true},
+			{Name: "helix", Value: version.ModuleVersion("github.com/nicklaw5/helix"), Inline: true},
+			{Name: "Uptime", Value: formatLongDuration(stats.SessionUptime()), Inline: true},
+			{Name: "Total uptime", Value: formatLongDuration(c.Uptime), Inline: true},
+			{Name: "Restarts", Value: fmt.Sprint(c.Restarts), Inline: true},
+			{Name: "Announcements sent", Value: fmt.Sprint(c.AnnouncementsSent), Inline: true},
+			{Name: "Commands processed", Value: fmt.Sprint(c.CommandsProcessed), Inline: true},
+			{Name: "Panics recovered", Value: fmt.Sprint(c.PanicsRecovered), Inline: true},
+		},
+	}
+
+	if release := version.NewerRelease(); release != "" {
+		aboutEmbed.Description = "A newer release " + release + " is available."
+	}
+
+	_, err := s.ChannelMessageSendEmbed(m.ChannelID, aboutEmbed)
+	if err != nil {
+		utils.Log.WithError(err).Error("Failed to send message to Discord.")
+	}
+}
+
+// Formats durations that can span several days
+func formatLongDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	h := d / time.Hour
+	d -= h * time.Hour
+	m := d / time.Minute
+
+	return fmt.Sprintf("%dd %dh %dm", days, h, m)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/accounts"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandAccount(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	if len(c) == 1 {
+		switch c[0] {
+		case "link":
+			t := twitch.GetSession(s)
+
+			done := func(login string, err error) {
+				if err != nil {
+					utils.Log.WithFields(logrus.Fields{
+						"user":  m.Author.Username,
+						"error": err}).Info("Failed to link account.")
+
+					sendDirectMessage(s, m.Author.ID, "Your Twitch account could not be linked: "+err.Error()+".")
+					return
+				}
+
+				utils.Log.WithFields(logrus.Fields{
+					"user":           m.Author.Username,
+					"twitch_channel": login}).Info("Succeeded in linking account.")
+
+				sendDirectMessage(s, m.Author.ID, "Your Twitch account "+login+" is now linked.")
+			}
+
+			// Prefer the Twitch authorization page when the HTTP server can receive the redirect
+			var instructions string
+			if url, ok, err := t.AccountLinkURL(m.Author.ID, done); ok {
+				if err != nil {
+					utils.Log.WithError(err).Error("Failed to start account verification.")
+					sendTemporaryMessage(s, m.ChannelID, "Error linking account.")
+					return
+				}
+				instructions = "To link your Twitch account open " + url
+			} else {
+				dc, err := t.LinkAccount(m.Author.ID, done)
+				if err != nil {
+					utils.Log.WithError(err).Error("Failed to start Twitch authorization.")
+					sendTemporaryMessage(s, m.ChannelID, "Error linking account. Connection to twitch may be down.")
+					return
+				}
+				instructions = "To link your Twitch account go to " + dc.VerificationURI + " and enter the code **" + dc.UserCode + "**."
+			}
+
+			if err := sendDirectMessage(s, m.Author.ID, instructions); err != nil {
+				sendTemporaryMessage(s, m.ChannelID, "I couldn't send you a direct message. Please allow direct messages from this server and try again.")
+			} else {
+				sendTemporaryMessage(s, m.ChannelID, "Check your direct messages to finish linking your Twitch account.")
+			}
+			return
+		case "unlink":
+			if err := accounts.Unlink(m.Author.ID); err != nil {
+				sendTemporaryMessage(s, m.ChannelID, "You have not linked a Twitch account.")
+				return
+			}
+
+			sendTemporaryMessage(s, m.ChannelID, "Your Twitch account is no longer linked.")
+			return
+		case "show":
+			if account, ok := accounts.Get(m.Author.ID); ok {
+				sendTemporaryMessage(s, m.ChannelID, "Your linked Twitch account is "+account.TwitchLogin+".")
+			} else {
+				sendTemporaryMessage(s, m.ChannelID, "You have not linked a Twitch account.")
+			}
+			return
+		default:
+		}
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" account [link/unlink/show]")
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandAlias(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	t := twitch.GetSession(s)
+
+	if len(c) == 1 && c[0] == "list" {
+		aliases := t.GetAliases(m.GuildID)
+
+		names := make([]string, 0, len(aliases))
+		for alias := range aliases {
+			names = append(names, alias)
+		}
+		sort.Strings(names)
+
+		listFields := []*discordgo.MessageEmbedField{}
+		for _, alias := range names {
+			listFields = append(listFields, &discordgo.MessageEmbedField{
+				Name:   alias,
+				Value:  aliases[alias],
+				Inline: true,
+			})
+		}
+
+		listEmbed := &discordgo.MessageEmbed{
+			Title:  "This Discord server has the aliases",
+			Fields: listFields,
+		}
+
+		if _, err := s.ChannelMessageSendEmbed(m.ChannelID, listEmbed); err != nil {
+			utils.Log.WithError(err).Error("Failed to send message to Discord.")
+		}
+		return
+	} else if len(c) == 2 && c[0] == "remove" {
+		alias := strings.ToLower(c[1])
+
+		if err := t.RemoveAlias(m.GuildID, alias); err != nil {
+			sendTemporaryMessage(s, m.ChannelID, "The alias "+alias+" does not exist.")
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"alias":     alias,
+			"server_id": m.GuildID}).Info("Succeeded in removing alias.")
+
+		sendTemporaryMessage(s, m.ChannelID, "The alias "+alias+" was removed.")
+		return
+	} else if len(c) == 3 && c[0] == "add" {
+		alias, twitchChannel := strings.ToLower(c[1]), strings.ToLower(c[2])
+
+		if err := t.AddAlias(m.GuildID, alias, twitchChannel); err != nil {
+			utils.Log.WithFields(logrus.Fields{
+				"user":           m.Author.Username,
+				"alias":          alias,
+				"twitch_channel": twitchChannel,
+				"server_id":      m.GuildID,
+				"error":          err}).Info("Failed to add alias.")
+
+			if errors.Is(err, constants.ErrAliasExists) {
+				sendTemporaryMessage(s, m.ChannelID, "The alias "+alias+" already exists.")
+			} else {
+				sendTemporaryMessage(s, m.ChannelID, "An alias must differ from the Twitch channel name.")
+			}
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":           m.Author.Username,
+			"alias":          alias,
+			"twitch_channel": twitchChannel,
+			"server_id":      m.GuildID}).Info("Succeeded in adding alias.")
+
+		sendTemporaryMessage(s, m.ChannelID, alias+" can now be used instead of "+twitchChannel+" in commands.")
+		return
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" alias add <Alias> <Twitch Channel>\n"+
+		constants.CommandPrefix+" alias remove <Alias>\n"+
+		constants.CommandPrefix+" alias list")
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandBans(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	if len(c) == 1 && c[0] == "log" {
+		t := twitch.GetSession(s)
+		records := t.GetBanLog(m.GuildID, 10)
+
+		if len(records) == 0 {
+			sendTemporaryMessage(s, m.ChannelID, "No bans have been synced in this Discord server.")
+			return
+		}
+
+		lines := []string{}
+		for _, r := range records {
+			direction := "Discord to Twitch"
+			if r.ToDiscord {
+				direction = "Twitch to Discord"
+			}
+			line := fmt.Sprintf("#%v %v (<@%v>) on %v, %v, approved by %v", r.ID, r.TwitchLogin, r.DiscordUserID, r.Login, direction, r.ApprovedBy)
+			if r.Reverted {
+				line += ", reverted"
+			}
+			lines = append(lines, line)
+		}
+
+		sendTemporaryMessage(s, m.ChannelID, "Synced bans:\n"+strings.Join(lines, "\n"))
+		return
+	} else if len(c) == 2 {
+		switch c[0] {
+		case "off":
+			t := twitch.GetSession(s)
+			login := resolveTwitchChannel(s, m.GuildID, c[1])
+
+			if err := t.DisableBanSync(login, m.GuildID); err != nil {
+				sendTemporaryMessage(s, m.ChannelID, login+"'s bans are not being synced.")
+				return
+			}
+
+			sendTemporaryMessage(s, m.ChannelID, login+"'s bans will no longer be synced.")
+			return
+		case "revert":
+			t := twitch.GetSession(s)
+			id, err := strconv.Atoi(strings.TrimPrefix(c[1], "#"))
+			if err != nil {
+				break
+			}
+
+			record, err := t.RevertBan(s, m.GuildID, id)
+			if err != nil {
+				utils.Log.WithFields(logrus.Fields{
+					"user":       m.Author.Username,
+					"channel_id": m.ChannelID,
+					"server_id":  m.GuildID,
+					"error":      err}).Info("Failed to revert synced ban.")
+
+				if errors.Is(err, constants.ErrBanDoesNotExist) {
+					sendTemporaryMessage(s, m.ChannelID, "There is no synced ban #"+c[1]+".")
+				} else if errors.Is(err, constants.ErrBanReverted) {
+					sendTemporaryMessage(s, m.ChannelID, "That ban was already reverted.")
+				} else {
+					sendTemporaryMessage(s, m.ChannelID, "Error reverting the ban. The bot or broadcaster may be missing permissions.")
+				}
+				return
+			}
+
+			utils.Log.WithFields(logrus.Fields{
+				"user":           m.Author.Username,
+				"twitch_channel": record.Login,
+				"channel_id":     m.ChannelID,
+				"server_id":      m.GuildID}).Info("Succeeded in reverting synced ban.")
+
+			sendTemporaryMessage(s, m.ChannelID, fmt.Sprintf("Ban #%v was reverted.", record.ID))
+			return
+		default:
+		}
+	} else if len(c) == 3 && c[0] == "sync" {
+		t := twitch.GetSession(s)
+		login := resolveTwitchChannel(s, m.GuildID, c[1])
+
+		var toDiscord, toTwitch bool
+		switch c[2] {
+		case "discord":
+			toDiscord = true
+		case "twitch":
+			toTwitch = true
+		case "both":
+			toDiscord, toTwitch = true, true
+		}
+
+		if toDiscord || toTwitch {
+			if err := t.SetBanSync(login, m.GuildID, m.ChannelID, toDiscord, toTwitch); err != nil {
+				utils.Log.WithFields(logrus.Fields{
+					"user":           m.Author.Username,
+					"twitch_channel": login,
+					"channel_id":     m.ChannelID,
+					"server_id":      m.GuildID,
+					"error":          err}).Info("Failed to set ban sync.")
+
+				if errors.Is(err, constants.ErrFeatureDisabled) {
+					sendTemporaryMessage(s, m.ChannelID, "The eventsub feature is not enabled in this Discord server.")
+				} else if errors.Is(err, constants.ErrBroadcasterNotLinked) {
+					sendTemporaryMessage(s, m.ChannelID, login+" has not linked their Twitch account to this Discord server.")
+				} else if errors.Is(err, constants.ErrEventSubDisabled) {
+					sendTemporaryMessage(s, m.ChannelID, "EventSub is not configured for this bot.")
+				} else {
+					sendTemporaryMessage(s, m.ChannelID, "Error subscribing to bans. Connection to twitch may be down.")
+				}
+				return
+			}
+
+			utils.Log.WithFields(logrus.Fields{
+				"user":           m.Author.Username,
+				"twitch_channel": login,
+				"channel_id":     m.ChannelID,
+				"server_id":      m.GuildID}).Info("Succeeded in setting ban sync.")
+
+			sendTemporaryMessage(s, m.ChannelID, "Bans of linked accounts on "+login+"'s channel will be synced. Moderators confirm each ban in this Discord channel.")
+			return
+		}
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" bans sync <Twitch Channel> [discord/twitch/both]\n"+
+		constants.CommandPrefix+" bans off <Twitch Channel>\n"+
+		constants.CommandPrefix+" bans revert <Ban number>\n"+
+		constants.CommandPrefix+" bans log")
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandBroadcaster(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	if len(c) == 1 {
+		switch c[0] {
+		case "link":
+			t := twitch.GetSession(s)
+
+			dc, err := t.LinkBroadcaster(m.Author.ID, m.GuildID, func(login string, err error) {
+				if err != nil {
+					utils.Log.WithFields(logrus.Fields{
+						"user":      m.Author.Username,
+						"server_id": m.GuildID,
+						"error":     err}).Info("Failed to link broadcaster.")
+
+					sendDirectMessage(s, m.Author.ID, "Your Twitch account could not be linked: "+err.Error()+".")
+					return
+				}
+
+				utils.Log.WithFields(logrus.Fields{
+					"user":           m.Author.Username,
+					"twitch_channel": login,
+					"server_id":      m.GuildID}).Info("Succeeded in linking broadcaster.")
+
+				sendDirectMessage(s, m.Author.ID, "Your Twitch account "+login+" is now linked.")
+			})
+			if err != nil {
+				utils.Log.WithError(err).Error("Failed to start Twitch authorization.")
+				sendTemporaryMessage(s, m.ChannelID, "Error linking account. Connection to twitch may be down.")
+				return
+			}
+
+			if err := sendDirectMessage(s, m.Author.ID, "To link your Twitch account go to "+dc.VerificationURI+
+				" and enter the code **"+dc.UserCode+"**."); err != nil {
+				sendTemporaryMessage(s, m.ChannelID, "I couldn't send you a direct message. Please allow direct messages from this server and try again.")
+			} else {
+				sendTemporaryMessage(s, m.ChannelID, "Check your direct messages to finish linking your Twitch account.")
+			}
+			return
+		case "list":
+			t := twitch.GetSession(s)
+			broadcasters := t.GetLinkedBroadcasters(m.GuildID)
+
+			if len(broadcasters) == 0 {
+				sendTemporaryMessage(s, m.ChannelID, "No broadcasters are linked to this Discord server.")
+			} else {
+				sendTemporaryMessage(s, m.ChannelID, "Linked broadcasters: "+strings.Join(broadcasters, ", "))
+			}
+			return
+		default:
+		}
+	} else if len(c) == 2 {
+		switch c[0] {
+		case "unlink":
+			if !isUserMod(s, m.GuildID, m.Member) {
+				utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+				return
+			}
+
+			t := twitch.GetSession(s)
+			login := resolveTwitchChannel(s, m.GuildID, c[1])
+
+			if err := t.UnlinkBroadcaster(login, m.GuildID); err != nil {
+				sendTemporaryMessage(s, m.ChannelID, login+" is not linked to this Discord server.")
+				return
+			}
+
+			utils.Log.WithFields(logrus.Fields{
+				"user":           m.Author.Username,
+				"twitch_channel": login,
+				"server_id":      m.GuildID}).Info("Succeeded in unlinking broadcaster.")
+
+			sendTemporaryMessage(s, m.ChannelID, login+" was successfully unlinked from this Discord server.")
+			return
+		default:
+		}
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" broadcaster link\n"+
+		constants.CommandPrefix+" broadcaster list\n"+
+		constants.CommandPrefix+" broadcaster unlink <Twitch Channel>")
+}
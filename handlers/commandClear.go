@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Removal of every registration of a channel or guild waiting for the administrator who requested it to react
+type pendingClear struct {
+	userID    string
+	guildID   string
+	channelID string // Discord channel whose registrations are removed, empty for the whole guild
+	expiresAt time.Time
+}
+
+var pendingClears = struct {
+	sync.Mutex
+	m map[string]*pendingClear // Map of confirmation message ID to the removal it confirms
+}{m: make(map[string]*pendingClear)}
+
+// Asks an administrator to confirm removing every registration of the current channel, or of the guild if guildWide is set
+func requestClear(s *discordgo.Session, m *discordgo.MessageCreate, guildWide bool) {
+	if !isUserAdmin(s, m.ChannelID, m.Author) {
+		utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+		sendTemporaryMessage(s, m.ChannelID, "Only administrators of this Discord server can clear registrations.")
+		return
+	}
+
+	channelID, scope := m.ChannelID, "this channel"
+	if guildWide {
+		channelID, scope = "", "this Discord server"
+	}
+
+	count := 0
+	for _, r := range twitch.GetSession(s).GetGuildRegistrations(m.GuildID) {
+		if channelID == "" || r.DiscordChannelID == channelID {
+			count++
+		}
+	}
+	if count == 0 {
+		sendTemporaryMessage(s, m.ChannelID, "There are no registrations in "+scope+".")
+		return
+	}
+
+	msg, err := s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("React with %v within %v to remove the %v registrations of %v. "+
+		"They can be restored one at a time with `%v undo`.", constants.ConfirmEmoji, formatLongDuration(constants.ClearConfirmTimeout), count, scope, constants.CommandPrefix))
+	if err != nil {
+		utils.Log.WithError(err).Error("Failed to send message to Discord.")
+		return
+	}
+
+	pendingClears.Lock()
+	pendingClears.m[msg.ID] = &pendingClear{userID: m.Author.ID, guildID: m.GuildID, channelID: channelID, expiresAt: time.Now().Add(constants.ClearConfirmTimeout)}
+	pendingClears.Unlock()
+
+	s.MessageReactionAdd(m.ChannelID, msg.ID, constants.ConfirmEmoji)
+
+	go func() {
+		deleteBotMessageWithDelay(s, msg, constants.ClearConfirmTimeout)
+
+		pendingClears.Lock()
+		delete(pendingClears.m, msg.ID)
+		pendingClears.Unlock()
+	}()
+}
+
+// Removes the registrations of a confirmed clear. Returns whether the reaction was on a clear confirmation message.
+func confirmClear(s *discordgo.Session, r *discordgo.MessageReactionAdd, member *discordgo.Member) bool {
+	pendingClears.Lock()
+	pc := pendingClears.m[r.MessageID]
+	if pc != nil && pc.userID == r.UserID {
+		delete(pendingClears.m, r.MessageID)
+	}
+	pendingClears.Unlock()
+
+	if pc == nil {
+		return false
+	}
+	// Only the administrator who asked can confirm
+	if pc.userID != r.UserID || time.Now().After(pc.expiresAt) {
+		return true
+	}
+
+	removed := twitch.GetSession(s).ClearRegistrations(pc.guildID, pc.channelID)
+
+	utils.Log.WithFields(logrus.Fields{
+		"user":       member.User.Username,
+		"channel_id": pc.channelID,
+		"server_id":  pc.guildID}).Info(fmt.Sprintf("Succeeded in clearing %v registrations.", removed))
+
+	sendTemporaryMessage(s, r.ChannelID, fmt.Sprintf("Removed %v registrations. Use `%v undo` to restore them one at a time.", removed, constants.CommandPrefix))
+	return true
+}
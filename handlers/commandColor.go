@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandColor(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	t := twitch.GetSession(s)
+
+	if len(c) == 1 && c[0] == "list" {
+		colors := t.GetGameColors(m.GuildID)
+
+		games := make([]string, 0, len(colors))
+		for game := range colors {
+			games = append(games, game)
+		}
+		sort.Strings(games)
+
+		listFields := []*discordgo.MessageEmbedField{}
+		for _, game := range games {
+			listFields = append(listFields, &discordgo.MessageEmbedField{
+				Name:   game,
+				Value:  fmt.Sprintf("#%06x", colors[game]),
+				Inline: true,
+			})
+		}
+
+		listEmbed := &discordgo.MessageEmbed{
+			Title:  "This Discord server colors live messages of the games",
+			Fields: listFields,
+		}
+
+		if _, err := s.ChannelMessageSendEmbed(m.ChannelID, listEmbed); err != nil {
+			utils.Log.WithError(err).Error("Failed to send message to Discord.")
+		}
+		return
+	} else if len(c) == 3 && c[0] == "channel" {
+		twitchChannel := resolveTwitchChannel(s, m.GuildID, c[1])
+
+		color := 0
+		if c[2] == "auto" {
+			color = twitch.ColorAuto
+		} else if c[2] != "off" {
+			var err error
+			if color, err = parseHexColor(c[2]); err != nil {
+				sendTemporaryMessage(s, m.ChannelID, err.Error()+".")
+				return
+			}
+		}
+
+		if err := t.SetChannelColor(twitchChannel, m.GuildID, m.ChannelID, color); err != nil {
+			utils.Log.WithFields(logrus.Fields{
+				"user":           m.Author.Username,
+				"twitch_channel": twitchChannel,
+				"channel_id":     m.ChannelID,
+				"server_id":      m.GuildID,
+				"error":          err}).Info("Failed to set live message color.")
+
+			if errors.Is(err, constants.ErrTwitchUserNotRegistered) {
+				sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is not added to this Discord channel.")
+			} else {
+				sendTemporaryMessage(s, m.ChannelID, "The colors of "+twitchChannel+"'s Twitch logo could not be sampled.")
+			}
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":           m.Author.Username,
+			"twitch_channel": twitchChannel,
+			"channel_id":     m.ChannelID,
+			"server_id":      m.GuildID}).Info("Succeeded in setting live message color.")
+
+		switch color {
+		case 0:
+			sendTemporaryMessage(s, m.ChannelID, "Live messages of "+twitchChannel+" use the default color.")
+		case twitch.ColorAuto:
+			sendTemporaryMessage(s, m.ChannelID, "Live messages of "+twitchChannel+" use the main color of their Twitch logo.")
+		default:
+			sendTemporaryMessage(s, m.ChannelID, fmt.Sprintf("Live messages of %v use the color #%06x.", twitchChannel, color))
+		}
+		return
+	} else if len(c) == 3 && c[0] == "game" {
+		game := c[1]
+
+		color := 0
+		if c[2] != "off" {
+			var err error
+			if color, err = parseHexColor(c[2]); err != nil {
+				sendTemporaryMessage(s, m.ChannelID, err.Error()+".")
+				return
+			}
+		}
+
+		if err := t.SetGameColor(m.GuildID, game, color); err != nil {
+			sendTemporaryMessage(s, m.ChannelID, "No color is set for "+game+".")
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"game":      game,
+			"server_id": m.GuildID}).Info("Succeeded in setting game color.")
+
+		if color == 0 {
+			sendTemporaryMessage(s, m.ChannelID, "Live messages no longer have a color for "+game+".")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, fmt.Sprintf("Live messages use the color #%06x while %v is played.", color, game))
+		}
+		return
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" color channel <Twitch Channel> <Hex color/auto/off>\n"+
+		constants.CommandPrefix+" color game \"<Game>\" <Hex color/off>\n"+
+		constants.CommandPrefix+" color list")
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandCredentials(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	if len(c) != 2 {
+		sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+			constants.CommandPrefix+" credentials <Client ID> <Client Secret>")
+		return
+	}
+
+	// The secret must not stay visible in the channel
+	s.ChannelMessageDelete(m.ChannelID, m.ID)
+
+	t := twitch.GetSession(s)
+	if err := t.RotateCredentials(c[0], c[1]); err != nil {
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"server_id": m.GuildID,
+			"error":     err}).Info("Failed to rotate Twitch credentials.")
+
+		sendTemporaryMessage(s, m.ChannelID, "The new Twitch credentials could not be verified. The previous credentials are still in use.")
+		return
+	}
+
+	utils.Log.WithFields(logrus.Fields{
+		"user":      m.Author.Username,
+		"server_id": m.GuildID}).Info("Succeeded in rotating Twitch credentials.")
+
+	sendTemporaryMessage(s, m.ChannelID, "The bot now uses the new Twitch credentials. Update TWITCH_CLIENT_ID and TWITCH_CLIENT_SECRET before the next restart.")
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+)
+
+func commandDebug(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	if len(c) >= 1 && len(c) <= 2 && c[0] == "last" {
+		limit := 10
+		if len(c) == 2 {
+			n, err := strconv.Atoi(c[1])
+			if err != nil || n < 1 || n > constants.GuildOutcomeLogSize {
+				sendTemporaryMessage(s, m.ChannelID, fmt.Sprintf("The number of outcomes must be between 1 and %v.", constants.GuildOutcomeLogSize))
+				return
+			}
+			limit = n
+		}
+
+		t := twitch.GetSession(s)
+		outcomes := t.GetOutcomes(m.GuildID, limit)
+		loc := t.GetTimezone(m.GuildID)
+
+		if len(outcomes) == 0 {
+			sendTemporaryMessage(s, m.ChannelID, "No notifications have been attempted in this Discord server since the bot started.")
+			return
+		}
+
+		lines := []string{}
+		for _, o := range outcomes {
+			line := fmt.Sprintf("`%v` %v %v in <#%v>: %v", o.Time.In(loc).Format("Jan 2 15:04 MST"), o.TwitchChannel, o.Kind, o.DiscordChannelID, o.Result)
+			if o.Error != "" {
+				line += " (" + o.Error + ")"
+			}
+			lines = append(lines, line)
+		}
+
+		sendTemporaryMessage(s, m.ChannelID, "Recent notifications:\n"+strings.Join(lines, "\n"))
+		return
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" debug last [Number]")
+}
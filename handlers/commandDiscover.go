@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Suggestions of a discover message moderators can add by reacting with their number
+type pendingDiscovery struct {
+	guildID   string
+	channelID string
+	logins    []string
+	expiresAt time.Time
+}
+
+var pendingDiscoveries = struct {
+	sync.Mutex
+	m map[string]*pendingDiscovery // Map of Discord message ID to the suggestions it lists
+}{m: make(map[string]*pendingDiscovery)}
+
+// Suggests live channels of a Twitch category to add to the current channel
+func commandDiscover(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	if len(c) == 0 {
+		sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+			constants.CommandPrefix+" discover <Category> [Minimum viewers]")
+		return
+	}
+
+	minViewers := 0
+	if len(c) > 1 {
+		if n, err := strconv.Atoi(c[len(c)-1]); err == nil && n >= 0 {
+			minViewers = n
+			c = c[:len(c)-1]
+		}
+	}
+	category := strings.Join(c, " ")
+
+	game, suggestions, err := twitch.GetSession(s).Discover(m.GuildID, category, minViewers)
+	if err != nil {
+		utils.Log.WithFields(logrus.Fields{
+			"user":       m.Author.Username,
+			"category":   category,
+			"channel_id": m.ChannelID,
+			"server_id":  m.GuildID,
+			"error":      err}).Info("Failed to discover streams.")
+
+		if errors.Is(err, constants.ErrCategoryDoesNotExist) {
+			sendTemporaryMessage(s, m.ChannelID, "The Twitch category "+category+" does not exist. Use its full name as shown on Twitch.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, "Error searching streams. Connection to twitch may be down.")
+		}
+		return
+	}
+	if len(suggestions) == 0 {
+		sendTemporaryMessage(s, m.ChannelID, "No other channels are live in "+game+fmt.Sprintf(" with at least %v viewers.", minViewers))
+		return
+	}
+
+	lines := []string{}
+	logins := []string{}
+	for i, suggestion := range suggestions {
+		lines = append(lines, fmt.Sprintf("%v **[%v](https://www.twitch.tv/%v)** (%v viewers)\n%v", constants.NumberEmojis[i],
+			utils.SanitizeText(suggestion.DisplayName), suggestion.Login, suggestion.Viewers, utils.SanitizeText(suggestion.Title)))
+		logins = append(logins, suggestion.Login)
+	}
+
+	msg, err := s.ChannelMessageSendEmbed(m.ChannelID, &discordgo.MessageEmbed{
+		Title:       "Live in " + game,
+		Description: strings.Join(lines, "\n\n"),
+		Color:       constants.DiscordLiveColor,
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Moderators can react with a number to add that channel to this Discord channel."},
+	})
+	if err != nil {
+		utils.Log.WithError(err).Error("Failed to send message to Discord.")
+		return
+	}
+
+	pendingDiscoveries.Lock()
+	pendingDiscoveries.m[msg.ID] = &pendingDiscovery{guildID: m.GuildID, channelID: m.ChannelID, logins: logins, expiresAt: time.Now().Add(constants.DiscoverReactionTimeout)}
+	pendingDiscoveries.Unlock()
+
+	for i := range logins {
+		s.MessageReactionAdd(m.ChannelID, msg.ID, constants.NumberEmojis[i])
+	}
+
+	go func() {
+		deleteBotMessageWithDelay(s, msg, constants.DiscoverReactionTimeout)
+
+		pendingDiscoveries.Lock()
+		delete(pendingDiscoveries.m, msg.ID)
+		pendingDiscoveries.Unlock()
+	}()
+}
+
+// Adds the channel a moderator picked from a discover message that has not expired
+func pickDiscovery(s *discordgo.Session, r *discordgo.MessageReactionAdd, member *discordgo.Member, pick int) {
+	pendingDiscoveries.Lock()
+	pd := pendingDiscoveries.m[r.MessageID]
+	pendingDiscoveries.Unlock()
+
+	if pd == nil || time.Now().After(pd.expiresAt) || pick >= len(pd.logins) {
+		return
+	}
+
+	t := twitch.GetSession(s)
+	if t == nil {
+		sendTemporaryMessage(s, r.ChannelID, constants.TwitchUnavailableMessage)
+		return
+	}
+	login := pd.logins[pick]
+	if err := t.RegisterChannel(login, pd.guildID, pd.channelID, getChannelName(s, pd.channelID)); err != nil {
+		utils.Log.WithFields(logrus.Fields{
+			"user":           member.User.Username,
+			"twitch_channel": login,
+			"channel_id":     pd.channelID,
+			"server_id":      pd.guildID,
+			"error":          err}).Info("Failed to register discovered channel.")
+
+		if errors.Is(err, constants.ErrTwitchUserRegistered) {
+			sendTemporaryMessage(s, pd.channelID, login+"'s Twitch channel is already added to this Discord channel.")
+		} else {
+			sendTemporaryMessage(s, pd.channelID, "Error registering channel. Connection to twitch may be down.")
+		}
+		return
+	}
+
+	utils.Log.WithFields(logrus.Fields{
+		"user":           member.User.Username,
+		"twitch_channel": login,
+		"channel_id":     pd.channelID,
+		"server_id":      pd.guildID}).Info("Succeeded in registering discovered channel.")
+
+	sendTemporaryMessage(s, pd.channelID, login+"'s Twitch channel successfully added to this Discord channel.")
+}
+
+// Returns the position of a number emoji, or -1 if the emoji is not one
+func numberEmojiIndex(emoji string) int {
+	for i, e := range constants.NumberEmojis {
+		if e == emoji {
+			return i
+		}
+	}
+	return -1
+}
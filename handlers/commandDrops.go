@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandDrops(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	t := twitch.GetSession(s)
+
+	if len(c) == 0 {
+		if t.GetDropsOnly(m.GuildID) {
+			sendTemporaryMessage(s, m.ChannelID, "Only streams with Drops enabled are announced.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, "Every stream is announced, whether or not Drops are enabled.")
+		}
+		return
+	} else if len(c) == 1 && (c[0] == "on" || c[0] == "off") {
+		t.SetDropsOnly(m.GuildID, c[0] == "on")
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"server_id": m.GuildID}).Info("Succeeded in setting drops filter.")
+
+		if c[0] == "on" {
+			sendTemporaryMessage(s, m.ChannelID, "Only streams with Drops enabled will be announced.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, "Every stream will be announced, whether or not Drops are enabled.")
+		}
+		return
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" drops [on/off]")
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandEmbed(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	t := twitch.GetSession(s)
+
+	if len(c) == 1 && c[0] == "fields" {
+		if layout := t.GetEmbedLayout(m.GuildID); len(layout) > 0 {
+			sendTemporaryMessage(s, m.ChannelID, "Live messages show the fields "+strings.Join(layout, ", ")+".")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, "Live messages show the default fields.")
+		}
+		return
+	} else if len(c) == 2 && c[0] == "fields" {
+		var fields []string
+		if c[1] != "reset" {
+			for _, field := range strings.Split(c[1], ",") {
+				if field = strings.ToLower(strings.TrimSpace(field)); field != "" {
+					fields = append(fields, field)
+				}
+			}
+		}
+
+		if err := t.SetEmbedLayout(m.GuildID, fields); err != nil {
+			utils.Log.WithFields(logrus.Fields{
+				"user":      m.Author.Username,
+				"server_id": m.GuildID,
+				"error":     err}).Info("Failed to set embed layout.")
+
+			if errors.Is(err, constants.ErrTooManyEmbedFields) {
+				sendTemporaryMessage(s, m.ChannelID, "Live messages can show up to 25 fields.")
+			} else {
+				sendTemporaryMessage(s, m.ChannelID, "Invalid embed layout: "+err.Error()+". The fields are "+strings.Join(twitch.EmbedFields, ", ")+".")
+			}
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"server_id": m.GuildID}).Info("Succeeded in setting embed layout.")
+
+		if len(fields) == 0 {
+			sendTemporaryMessage(s, m.ChannelID, "Live messages will show the default fields.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, "Live messages will show the fields "+strings.Join(fields, ", ")+".")
+		}
+		return
+	} else if len(c) == 2 && c[0] == "info" && (c[1] == "on" || c[1] == "off") {
+		t.SetChannelInfoFooter(m.GuildID, c[1] == "on")
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"server_id": m.GuildID}).Info("Succeeded in setting channel info footer.")
+
+		if c[1] == "on" {
+			sendTemporaryMessage(s, m.ChannelID, "Live messages will show the channel description and follower count.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, "Live messages will no longer show the channel description and follower count.")
+		}
+		return
+	} else if len(c) == 2 && c[0] == "names" {
+		if err := t.SetNameStyle(m.GuildID, c[1]); err != nil {
+			sendTemporaryMessage(s, m.ChannelID, "Twitch channels can be named by their display, login or both names.")
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"server_id": m.GuildID}).Info("Succeeded in setting name style.")
+
+		switch c[1] {
+		case twitch.NamesLogin:
+			sendTemporaryMessage(s, m.ChannelID, "Twitch channels with a localized display name will be named by their login.")
+		case twitch.NamesBoth:
+			sendTemporaryMessage(s, m.ChannelID, "Twitch channels with a localized display name will be named by their display name followed by their login.")
+		default:
+			sendTemporaryMessage(s, m.ChannelID, "Twitch channels will be named by their display name.")
+		}
+		return
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" embed fields\n"+
+		constants.CommandPrefix+" embed fields \"<Field>, <Field>\"\n"+
+		constants.CommandPrefix+" embed fields reset\n"+
+		constants.CommandPrefix+" embed info <on/off>\n"+
+		constants.CommandPrefix+" embed names <display/login/both>\n"+
+		"The fields are "+strings.Join(twitch.EmbedFields, ", ")+".")
+}
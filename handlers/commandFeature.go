@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/features"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandFeature(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	c, options := parseOptions(c)
+
+	if len(c) == 1 {
+		switch c[0] {
+		case "list":
+			listFields := []*discordgo.MessageEmbedField{}
+
+			for _, feature := range features.Known() {
+				listFields = append(listFields, &discordgo.MessageEmbedField{
+					Name: feature,
+					Value: "Global: " + enabledString(features.EnabledGlobally(feature)) +
+						"\nThis server: " + enabledString(features.Enabled(feature, m.GuildID)),
+					Inline: true,
+				})
+			}
+
+			listEmbed := &discordgo.MessageEmbed{
+				Title:  "Feature flags",
+				Fields: listFields,
+			}
+
+			_, err := s.ChannelMessageSendEmbed(m.ChannelID, listEmbed)
+			if err != nil {
+				utils.Log.WithError(err).Error("Failed to send message to Discord.")
+			}
+			return
+		default:
+		}
+	} else if len(c) == 2 {
+		switch c[0] {
+		case "enable", "disable":
+			feature := strings.ToLower(c[1])
+			enabled := c[0] == "enable"
+
+			guildID := m.GuildID
+			scope := "this server"
+			if _, global := options["global"]; global {
+				guildID = ""
+				scope = "every server"
+			} else if options["guild"] != "" {
+				guildID = options["guild"]
+				scope = "server " + guildID
+			}
+
+			if err := features.Set(feature, guildID, enabled); err != nil {
+				utils.Log.WithFields(logrus.Fields{
+					"user":      m.Author.Username,
+					"feature":   feature,
+					"server_id": guildID,
+					"error":     err}).Info("Failed to set feature flag.")
+
+				sendTemporaryMessage(s, m.ChannelID, "The feature "+feature+" could not be "+c[0]+"d. Known features are "+strings.Join(features.Known(), ", ")+".")
+				return
+			}
+
+			utils.Log.WithFields(logrus.Fields{
+				"user":      m.Author.Username,
+				"feature":   feature,
+				"enabled":   enabled,
+				"server_id": guildID}).Info("Succeeded in setting feature flag.")
+
+			sendTemporaryMessage(s, m.ChannelID, "The feature "+feature+" is now "+enabledString(enabled)+" for "+scope+".")
+			return
+		default:
+		}
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" feature list\n"+
+		constants.CommandPrefix+" feature [enable/disable] <Feature> [--global/--guild <Server ID>]")
+}
+
+func enabledString(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
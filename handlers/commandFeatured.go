@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandFeatured(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	t := twitch.GetSession(s)
+
+	if len(c) == 0 {
+		channels, schedule, featured := t.GetRotation(m.GuildID)
+		if len(channels) == 0 {
+			sendTemporaryMessage(s, m.ChannelID, "This Discord server has no featured rotation, every stream is announced with its mention.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, "The featured streamer is "+featured+". The rotation "+strings.Join(channels, ", ")+" changes "+schedule+".")
+		}
+		return
+	} else if len(c) == 1 && c[0] == "off" {
+		if err := t.ClearRotation(m.GuildID); err != nil {
+			sendTemporaryMessage(s, m.ChannelID, "This Discord server has no featured rotation.")
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"server_id": m.GuildID}).Info("Succeeded in clearing featured rotation.")
+
+		sendTemporaryMessage(s, m.ChannelID, "The featured rotation was removed, every stream will be announced with its mention.")
+		return
+	} else if len(c) == 2 && c[0] == "add" {
+		twitchChannel := resolveTwitchChannel(s, m.GuildID, c[1])
+
+		if err := t.AddFeatured(m.GuildID, twitchChannel); err != nil {
+			utils.Log.WithFields(logrus.Fields{
+				"user":           m.Author.Username,
+				"twitch_channel": twitchChannel,
+				"server_id":      m.GuildID,
+				"error":          err}).Info("Failed to add featured streamer.")
+
+			if errors.Is(err, constants.ErrAlreadyFeatured) {
+				sendTemporaryMessage(s, m.ChannelID, twitchChannel+" is already in the featured rotation.")
+			} else {
+				sendTemporaryMessage(s, m.ChannelID, twitchChannel+" must be added to this Discord server first.")
+			}
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":           m.Author.Username,
+			"twitch_channel": twitchChannel,
+			"server_id":      m.GuildID}).Info("Succeeded in adding featured streamer.")
+
+		sendTemporaryMessage(s, m.ChannelID, twitchChannel+" was added to the featured rotation. Other streams are announced without a mention.")
+		return
+	} else if len(c) == 2 && c[0] == "remove" {
+		twitchChannel := resolveTwitchChannel(s, m.GuildID, c[1])
+
+		if err := t.RemoveFeatured(m.GuildID, twitchChannel); err != nil {
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+" is not in the featured rotation.")
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":           m.Author.Username,
+			"twitch_channel": twitchChannel,
+			"server_id":      m.GuildID}).Info("Succeeded in removing featured streamer.")
+
+		sendTemporaryMessage(s, m.ChannelID, twitchChannel+" was removed from the featured rotation.")
+		return
+	} else if len(c) == 2 && c[0] == "schedule" {
+		schedule := strings.ToLower(c[1])
+
+		if err := t.SetRotationSchedule(m.GuildID, schedule); err != nil {
+			if errors.Is(err, constants.ErrNoRotation) {
+				sendTemporaryMessage(s, m.ChannelID, "This Discord server has no featured rotation.")
+			} else {
+				sendTemporaryMessage(s, m.ChannelID, "The featured streamer can change daily or weekly.")
+			}
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"schedule":  schedule,
+			"server_id": m.GuildID}).Info("Succeeded in setting featured rotation schedule.")
+
+		sendTemporaryMessage(s, m.ChannelID, "The featured streamer will change "+schedule+".")
+		return
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" featured\n"+
+		constants.CommandPrefix+" featured add <Twitch Channel>\n"+
+		constants.CommandPrefix+" featured remove <Twitch Channel>\n"+
+		constants.CommandPrefix+" featured schedule <daily/weekly>\n"+
+		constants.CommandPrefix+" featured off")
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// A purge of a guild's data waiting for the user who requested it to confirm
+type pendingPurge struct {
+	guildID   string
+	expiresAt time.Time
+}
+
+var pendingPurges = struct {
+	sync.Mutex
+	m map[string]*pendingPurge // Map of Discord user ID to the purge they requested
+}{m: make(map[string]*pendingPurge)}
+
+// Deletes every piece of data stored for the current guild. Only guild administrators can use it.
+func commandForget(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	if len(c) == 0 {
+		requestPurge(s, m, m.GuildID, constants.CommandPrefix+" forget confirm")
+		return
+	} else if len(c) == 1 && c[0] == "confirm" {
+		confirmPurge(s, m, m.GuildID)
+		return
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" forget\n"+
+		constants.CommandPrefix+" forget confirm")
+}
+
+// Owner commands for administering every guild
+func commandAdmin(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	if len(c) == 2 && c[0] == "purge-guild" {
+		requestPurge(s, m, c[1], constants.CommandPrefix+" admin purge-guild "+c[1]+" confirm")
+		return
+	} else if len(c) == 3 && c[0] == "purge-guild" && c[2] == "confirm" {
+		confirmPurge(s, m, c[1])
+		return
+	} else if len(c) == 1 && c[0] == "stats" {
+		sendAdminStats(s, m)
+		return
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" admin purge-guild <Discord Server ID> [confirm]\n"+
+		constants.CommandPrefix+" admin stats")
+}
+
+// Asks the user to confirm a purge with the given command before it times out
+func requestPurge(s *discordgo.Session, m *discordgo.MessageCreate, guildID string, confirmCommand string) {
+	pendingPurges.Lock()
+	pendingPurges.m[m.Author.ID] = &pendingPurge{guildID: guildID, expiresAt: time.Now().Add(constants.PurgeConfirmTimeout)}
+	pendingPurges.Unlock()
+
+	sendTemporaryMessage(s, m.ChannelID, "This irrevocably deletes every registration, setting, linked broadcaster and history of the Discord server "+guildID+
+		". Run `"+confirmCommand+"` within "+formatLongDuration(constants.PurgeConfirmTimeout)+" to continue.")
+}
+
+// Purges a guild's data if the user requested it and the request has not timed out
+func confirmPurge(s *discordgo.Session, m *discordgo.MessageCreate, guildID string) {
+	pendingPurges.Lock()
+	p := pendingPurges.m[m.Author.ID]
+	delete(pendingPurges.m, m.Author.ID)
+	pendingPurges.Unlock()
+
+	if p == nil || p.guildID != guildID || time.Now().After(p.expiresAt) {
+		sendTemporaryMessage(s, m.ChannelID, "There is no purge of this Discord server to confirm. Request it again first.")
+		return
+	}
+
+	setupWizards.Lock()
+	for userID, w := range setupWizards.m {
+		if w.guildID == guildID {
+			delete(setupWizards.m, userID)
+		}
+	}
+	setupWizards.Unlock()
+
+	report, err := twitch.GetSession(s).PurgeGuild(guildID)
+	if err != nil {
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"server_id": guildID,
+			"error":     err}).Error("Failed to purge guild data.")
+	} else {
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"server_id": guildID}).Info("Succeeded in purging guild data.")
+	}
+
+	lines := []string{
+		fmt.Sprintf("Registrations: %v", report.Registrations),
+		fmt.Sprintf("Twitch channels no longer monitored: %v", report.TwitchChannels),
+		fmt.Sprintf("Server settings, profiles and ban log: %v", yesNo(report.Settings)),
+		fmt.Sprintf("Notification outcomes: %v", report.Outcomes),
+		fmt.Sprintf("Linked broadcasters: %v", report.Broadcasters),
+		fmt.Sprintf("Pending ban confirmations: %v", report.PendingBans),
+		fmt.Sprintf("Live feed entries: %v", report.FeedEvents),
+		fmt.Sprintf("Event log entries: %v", report.StreamEvents),
+		fmt.Sprintf("Feature flag overrides: %v", report.FeatureOverrides),
+		fmt.Sprintf("Command metrics: %v", report.CommandMetrics),
+		fmt.Sprintf("Server presence record: %v", yesNo(report.Presence)),
+		fmt.Sprintf("Announcement claims: %v", report.AnnouncementClaims),
+	}
+
+	reportEmbed := &discordgo.MessageEmbed{
+		Title:       "Deleted the stored data of Discord server " + guildID,
+		Description: strings.Join(lines, "\n"),
+	}
+	if err != nil {
+		reportEmbed.Footer = &discordgo.MessageEmbedFooter{Text: "Some data could not be deleted: " + err.Error()}
+	}
+
+	if _, err := s.ChannelMessageSendEmbed(m.ChannelID, reportEmbed); err != nil {
+		utils.Log.WithError(err).Error("Failed to send message to Discord.")
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandGoals(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	if len(c) == 2 {
+		switch c[0] {
+		case "channel":
+			t := twitch.GetSession(s)
+			login := resolveTwitchChannel(s, m.GuildID, c[1])
+
+			if err := t.SetGoalChannel(login, m.GuildID, m.ChannelID); err != nil {
+				utils.Log.WithFields(logrus.Fields{
+					"user":           m.Author.Username,
+					"twitch_channel": login,
+					"channel_id":     m.ChannelID,
+					"server_id":      m.GuildID,
+					"error":          err}).Info("Failed to set goal channel.")
+
+				if errors.Is(err, constants.ErrBroadcasterNotLinked) {
+					sendTemporaryMessage(s, m.ChannelID, login+" has not linked their Twitch account to this Discord server.")
+				} else {
+					sendTemporaryMessage(s, m.ChannelID, "Error authorizing as "+login+". Connection to twitch may be down.")
+				}
+				return
+			}
+
+			utils.Log.WithFields(logrus.Fields{
+				"user":           m.Author.Username,
+				"twitch_channel": login,
+				"channel_id":     m.ChannelID,
+				"server_id":      m.GuildID}).Info("Succeeded in setting goal channel.")
+
+			sendTemporaryMessage(s, m.ChannelID, "The progress of "+login+"'s goals will be pinned in this Discord channel within "+constants.GoalUpdateInterval.String()+".")
+			return
+		case "off":
+			t := twitch.GetSession(s)
+			login := resolveTwitchChannel(s, m.GuildID, c[1])
+
+			if err := t.DisableGoals(login, m.GuildID); err != nil {
+				sendTemporaryMessage(s, m.ChannelID, login+"'s goals are not being posted.")
+				return
+			}
+
+			sendTemporaryMessage(s, m.ChannelID, login+"'s goals will no longer be posted.")
+			return
+		default:
+		}
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" goals [channel/off] <Twitch Channel>")
+}
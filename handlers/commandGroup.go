@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandGroup(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	t := twitch.GetSession(s)
+
+	if len(c) == 1 && c[0] == "list" {
+		groups := t.GetGroups(m.GuildID)
+		if len(groups) == 0 {
+			sendTemporaryMessage(s, m.ChannelID, "No registration in this Discord server is tagged with a group.")
+			return
+		}
+
+		names := make([]string, 0, len(groups))
+		for name := range groups {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		listFields := []*discordgo.MessageEmbedField{}
+		for _, name := range names {
+			listFields = append(listFields, &discordgo.MessageEmbedField{
+				Name:   name,
+				Value:  strings.Join(groups[name], ", "),
+				Inline: false,
+			})
+		}
+
+		listEmbed := &discordgo.MessageEmbed{
+			Title:  "This Discord server has the groups",
+			Fields: listFields,
+		}
+
+		if _, err := s.ChannelMessageSendEmbed(m.ChannelID, listEmbed); err != nil {
+			utils.Log.WithError(err).Error("Failed to send message to Discord.")
+		}
+		return
+	} else if (len(c) == 2 || len(c) == 3) && c[0] == "pause" {
+		group := strings.ToLower(c[1])
+
+		var duration time.Duration
+		if len(c) == 3 {
+			d, err := time.ParseDuration(c[2])
+			if err != nil || d <= 0 {
+				sendTemporaryMessage(s, m.ChannelID, "The duration is written like 72h.")
+				return
+			}
+			duration = d
+		}
+
+		if err := t.PauseGroup(m.GuildID, group, duration); err != nil {
+			sendTemporaryMessage(s, m.ChannelID, "No registration in this Discord server is tagged with the group "+group+".")
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"group":     group,
+			"server_id": m.GuildID}).Info("Succeeded in pausing group.")
+
+		if duration == 0 {
+			sendTemporaryMessage(s, m.ChannelID, "Announcements of the group "+group+" are paused until "+constants.CommandPrefix+" group resume "+group+" is used.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, "Announcements of the group "+group+" are paused for "+formatLongDuration(duration)+".")
+		}
+		return
+	} else if len(c) == 2 && c[0] == "resume" {
+		group := strings.ToLower(c[1])
+
+		if err := t.ResumeGroup(m.GuildID, group); err != nil {
+			sendTemporaryMessage(s, m.ChannelID, "No registration in this Discord server is tagged with the group "+group+".")
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"group":     group,
+			"server_id": m.GuildID}).Info("Succeeded in resuming group.")
+
+		sendTemporaryMessage(s, m.ChannelID, "Announcements of the group "+group+" are resumed.")
+		return
+	} else if len(c) == 3 && c[0] == "template" {
+		group := strings.ToLower(c[1])
+		template := c[2]
+		if template == "clear" {
+			template = ""
+		}
+
+		if err := t.SetGroupTemplate(m.GuildID, group, template); err != nil {
+			sendTemporaryMessage(s, m.ChannelID, "No registration in this Discord server is tagged with the group "+group+".")
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"group":     group,
+			"server_id": m.GuildID}).Info("Succeeded in setting group template.")
+
+		if template == "" {
+			sendTemporaryMessage(s, m.ChannelID, "The registrations of the group "+group+" will use the template of their profile.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, "The registrations of the group "+group+" will be announced with the template:\n"+template)
+		}
+		return
+	} else if len(c) == 2 && c[0] == "digest" {
+		group := strings.ToLower(c[1])
+
+		members, err := t.GroupDigest(m.GuildID, group)
+		if err != nil {
+			sendTemporaryMessage(s, m.ChannelID, "No registration in this Discord server is tagged with the group "+group+".")
+			return
+		}
+
+		live := 0
+		lines := []string{}
+		for _, member := range members {
+			var status string
+			if member.Live {
+				live++
+				status = "🔴 live for " + formatUptime(member.Uptime)
+				if member.Game != "" {
+					status += " playing " + utils.SanitizeText(member.Game)
+				}
+			} else if !member.LastLive.IsZero() {
+				status = fmt.Sprintf("offline, last live <t:%d:R>", member.LastLive.Unix())
+			} else {
+				status = "offline"
+			}
+			if member.Paused {
+				status += ", paused"
+			}
+			lines = append(lines, fmt.Sprintf("**[%v](https://www.twitch.tv/%v)** in <#%v>: %v", utils.SanitizeText(member.DisplayName), member.TwitchChannel, member.DiscordChannelID, status))
+		}
+
+		digestEmbed := &discordgo.MessageEmbed{
+			Title:       fmt.Sprintf("Group %v: %v of %v live", group, live, len(members)),
+			Description: strings.Join(lines, "\n"),
+			Color:       constants.DiscordLiveColor,
+		}
+		if len(digestEmbed.Description) > constants.DiscordMaxEmbedDescription {
+			digestEmbed.Description = digestEmbed.Description[:constants.DiscordMaxEmbedDescription-3] + "..."
+		}
+
+		if _, err := s.ChannelMessageSendEmbed(m.ChannelID, digestEmbed); err != nil {
+			utils.Log.WithError(err).Error("Failed to send message to Discord.")
+		}
+		return
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" group list\n"+
+		constants.CommandPrefix+" group digest <Group>\n"+
+		constants.CommandPrefix+" group pause <Group> [Duration, e.g. 72h]\n"+
+		constants.CommandPrefix+" group resume <Group>\n"+
+		constants.CommandPrefix+" group template <Group> <\"Text\"/clear>\n"+
+		"Registrations are tagged with "+constants.CommandPrefix+" channel group <Twitch Channel> <Group/none> or "+constants.CommandPrefix+" channel add <Twitch Channel> --group <Group>")
+}
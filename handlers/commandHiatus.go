@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandHiatus(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	t := twitch.GetSession(s)
+
+	if len(c) == 0 {
+		if days := t.GetHiatusDays(m.GuildID); days > 0 {
+			sendTemporaryMessage(s, m.ChannelID, fmt.Sprintf("Streams after a break of at least %v days are announced as a return.", days))
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, "Returning streams are announced like any other stream.")
+		}
+		return
+	} else if len(c) == 1 {
+		days, err := strconv.Atoi(c[0])
+		if c[0] == "off" {
+			days, err = 0, nil
+		}
+
+		if err == nil && t.SetHiatusDays(m.GuildID, days) == nil {
+			utils.Log.WithFields(logrus.Fields{
+				"user":      m.Author.Username,
+				"days":      days,
+				"server_id": m.GuildID}).Info("Succeeded in setting hiatus badge.")
+
+			if days > 0 {
+				sendTemporaryMessage(s, m.ChannelID, fmt.Sprintf("Streams after a break of at least %v days are now announced as a return.", days))
+			} else {
+				sendTemporaryMessage(s, m.ChannelID, "Returning streams are no longer announced differently.")
+			}
+			return
+		}
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" hiatus [Days/off]")
+}
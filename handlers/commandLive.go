@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Lists the live Twitch channels of the Discord server. Like other replies it is deleted after a short delay.
+func commandLive(s *discordgo.Session, m *discordgo.MessageCreate) {
+	live := twitch.GetSession(s).GetLiveChannels(m.GuildID)
+	if len(live) == 0 {
+		sendTemporaryMessage(s, m.ChannelID, "No Twitch channel of this Discord server is live right now.")
+		return
+	}
+
+	lines := []string{}
+	for _, channel := range live {
+		line := fmt.Sprintf("**[%v](https://www.twitch.tv/%v)** for %v", utils.SanitizeText(channel.DisplayName), channel.TwitchChannel, formatUptime(channel.Uptime))
+		if channel.Game != "" {
+			line += " playing " + utils.SanitizeText(channel.Game)
+		}
+		lines = append(lines, line+fmt.Sprintf(" (%v viewers)\n%v", channel.Viewers, utils.SanitizeText(channel.Title)))
+	}
+
+	description := ""
+	for _, line := range lines {
+		// Embed descriptions are limited in length
+		if len(description)+len(line)+2 > constants.DiscordMaxEmbedDescription {
+			description += "…"
+			break
+		}
+		description += line + "\n\n"
+	}
+
+	liveEmbed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("%v live now", len(live)),
+		Description: strings.TrimSpace(description),
+		Color:       constants.DiscordLiveColor,
+	}
+
+	msg, err := s.ChannelMessageSendEmbed(m.ChannelID, liveEmbed)
+	if err != nil {
+		utils.Log.WithError(err).Error("Failed to send message to Discord.")
+		return
+	}
+	go deleteBotMessageWithDelay(s, msg, constants.DiscordMessageDeleteDelay)
+}
+
+// Formats the uptime of a stream like 2h 5m
+func formatUptime(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h := d / time.Hour
+	m := (d - h*time.Hour) / time.Minute
+
+	if h == 0 {
+		return fmt.Sprintf("%dm", m)
+	}
+	return fmt.Sprintf("%dh %dm", h, m)
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandLiveRole(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	t := twitch.GetSession(s)
+
+	if len(c) == 0 {
+		if roleID := t.GetLiveRole(m.GuildID); roleID != "" {
+			sendTemporaryMessage(s, m.ChannelID, "Members are given <@&"+roleID+"> while their linked Twitch channel is live.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, "No live role is set in this Discord server.")
+		}
+		return
+	} else if len(c) == 1 && c[0] == "off" {
+		t.SetLiveRole(s, m.GuildID, "")
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"server_id": m.GuildID}).Info("Succeeded in disabling live role.")
+
+		sendTemporaryMessage(s, m.ChannelID, "Members will no longer be given a role while they are live.")
+		return
+	} else if len(c) == 1 && len(m.MentionRoles) == 1 {
+		t.SetLiveRole(s, m.GuildID, m.MentionRoles[0])
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"role_id":   m.MentionRoles[0],
+			"server_id": m.GuildID}).Info("Succeeded in setting live role.")
+
+		sendTemporaryMessage(s, m.ChannelID, "Members will be given <@&"+m.MentionRoles[0]+"> while their linked Twitch channel is live.")
+		return
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" liverole\n"+
+		constants.CommandPrefix+" liverole <@Role>\n"+
+		constants.CommandPrefix+" liverole off")
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandMarker(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	c, options := parseOptions(c)
+	t := twitch.GetSession(s)
+
+	// Default to the only linked broadcaster when no channel is given
+	login := strings.ToLower(options["channel"])
+	if login == "" {
+		if broadcasters := t.GetLinkedBroadcasters(m.GuildID); len(broadcasters) == 1 {
+			login = broadcasters[0]
+		}
+	}
+
+	if login == "" {
+		sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+			constants.CommandPrefix+" marker [--channel <Twitch Channel>] <Description>")
+		return
+	}
+
+	description := []rune(strings.Join(c, " "))
+	if len(description) > constants.TwitchMarkerDescriptionLength {
+		description = description[:constants.TwitchMarkerDescriptionLength]
+	}
+
+	marker, err := t.CreateStreamMarker(login, m.GuildID, string(description))
+	if err != nil {
+		utils.Log.WithFields(logrus.Fields{
+			"user":           m.Author.Username,
+			"twitch_channel": login,
+			"server_id":      m.GuildID,
+			"error":          err}).Info("Failed to create stream marker.")
+
+		if errors.Is(err, constants.ErrBroadcasterNotLinked) {
+			sendTemporaryMessage(s, m.ChannelID, login+" has not linked their Twitch account to this Discord server.")
+		} else if errors.Is(err, constants.ErrStreamOffline) {
+			sendTemporaryMessage(s, m.ChannelID, login+" is not live.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, "Error creating stream marker. Connection to twitch may be down.")
+		}
+		return
+	}
+
+	utils.Log.WithFields(logrus.Fields{
+		"user":           m.Author.Username,
+		"twitch_channel": login,
+		"server_id":      m.GuildID}).Info("Succeeded in creating stream marker.")
+
+	sendTemporaryMessage(s, m.ChannelID, "Marker created on "+login+"'s stream at "+formatSeconds(marker.PositionSeconds)+".")
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Descriptions of how mature streams are announced in channels not marked NSFW
+var maturePolicies = map[string]string{
+	twitch.MatureAnywhere:  "Mature streams are announced in every channel.",
+	twitch.MatureNSFWOnly:  "Mature streams are only announced in channels marked NSFW.",
+	twitch.MatureNoPreview: "Mature streams are announced without their preview image in channels not marked NSFW.",
+}
+
+func commandMature(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	t := twitch.GetSession(s)
+
+	if len(c) == 0 {
+		sendTemporaryMessage(s, m.ChannelID, maturePolicies[t.GetMaturePolicy(m.GuildID)])
+		return
+	} else if len(c) == 1 {
+		if err := t.SetMaturePolicy(m.GuildID, c[0]); err == nil {
+			utils.Log.WithFields(logrus.Fields{
+				"user":      m.Author.Username,
+				"server_id": m.GuildID}).Info("Succeeded in setting mature stream policy.")
+
+			sendTemporaryMessage(s, m.ChannelID, maturePolicies[c[0]])
+			return
+		}
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" mature [anywhere/nsfw-only/no-preview]")
+}
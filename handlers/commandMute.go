@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandMute(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	c, options := parseOptions(c)
+	t := twitch.GetSession(s)
+
+	if len(c) == 0 {
+		if until, policy := t.GetMute(m.GuildID); !until.IsZero() {
+			sendTemporaryMessage(s, m.ChannelID, "Announcements are muted for another "+formatLongDuration(time.Until(until))+
+				" and will be "+mutePolicyDescription(policy)+".")
+			return
+		}
+	} else if len(c) == 1 {
+		duration, err := time.ParseDuration(c[0])
+		policy := options["policy"]
+		if policy == "" {
+			policy = twitch.MuteQueue
+		}
+
+		if err == nil {
+			if err := t.Mute(m.GuildID, duration, policy); err != nil {
+				utils.Log.WithFields(logrus.Fields{
+					"user":      m.Author.Username,
+					"server_id": m.GuildID,
+					"error":     err}).Info("Failed to mute announcements.")
+
+				if errors.Is(err, constants.ErrInvalidMuteDuration) {
+					sendTemporaryMessage(s, m.ChannelID, "Announcements can be muted for up to "+formatLongDuration(constants.MaxMuteDuration)+".")
+				} else {
+					sendTemporaryMessage(s, m.ChannelID, "The mute policy must be queue or drop.")
+				}
+				return
+			}
+
+			utils.Log.WithFields(logrus.Fields{
+				"user":      m.Author.Username,
+				"server_id": m.GuildID}).Info("Succeeded in muting announcements.")
+
+			sendTemporaryMessage(s, m.ChannelID, "Announcements are muted for "+formatLongDuration(duration)+
+				" and will be "+mutePolicyDescription(policy)+". Use "+constants.CommandPrefix+" unmute to end the mute early.")
+			return
+		}
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" mute <Duration, e.g. 2h> [--policy queue/drop]\n"+
+		constants.CommandPrefix+" unmute")
+}
+
+func commandUnmute(s *discordgo.Session, m *discordgo.MessageCreate) {
+	t := twitch.GetSession(s)
+
+	if err := t.Unmute(m.GuildID); err != nil {
+		sendTemporaryMessage(s, m.ChannelID, "Announcements are not muted.")
+		return
+	}
+
+	utils.Log.WithFields(logrus.Fields{
+		"user":      m.Author.Username,
+		"server_id": m.GuildID}).Info("Succeeded in unmuting announcements.")
+
+	sendTemporaryMessage(s, m.ChannelID, "Announcements are no longer muted.")
+}
+
+// Describes what happens to announcements while muted
+func mutePolicyDescription(policy string) string {
+	if policy == twitch.MuteDrop {
+		return "dropped"
+	}
+	return "sent when the mute ends if the stream is still live"
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/accounts"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandOffline(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	if len(c) < 1 || len(c) > 3 {
+		sendOfflineUsage(s, m)
+		return
+	}
+
+	t := twitch.GetSession(s)
+	twitchChannel := resolveTwitchChannel(s, m.GuildID, c[0])
+
+	// Streamers can word the end of their own streams once they linked their Twitch account
+	if account, ok := accounts.Get(m.Author.ID); !isUserMod(s, m.GuildID, m.Member) && (!ok || !strings.EqualFold(account.TwitchLogin, twitchChannel)) {
+		utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+		return
+	}
+
+	if len(c) == 1 {
+		text, image, err := t.GetOffline(twitchChannel, m.GuildID, m.ChannelID)
+		if err != nil {
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is not added to this Discord channel.")
+			return
+		}
+
+		if text == "" {
+			text = "the stream summary"
+		} else {
+			text = "\"" + text + "\""
+		}
+		if image == "" {
+			image = "the Twitch offline banner"
+		} else {
+			image = "<" + image + ">"
+		}
+		sendTemporaryMessage(s, m.ChannelID, fmt.Sprintf("When %v's stream ends its live message shows %v with %v.", twitchChannel, text, image))
+		return
+	}
+
+	if len(c) == 2 {
+		sendOfflineUsage(s, m)
+		return
+	}
+
+	value := c[2]
+	if value == "off" {
+		value = ""
+	}
+
+	var err error
+	switch c[1] {
+	case "message":
+		err = t.SetOfflineText(twitchChannel, m.GuildID, m.ChannelID, value)
+	case "image":
+		err = t.SetOfflineImage(twitchChannel, m.GuildID, m.ChannelID, value)
+	default:
+		sendOfflineUsage(s, m)
+		return
+	}
+	if err != nil {
+		utils.Log.WithFields(logrus.Fields{
+			"user":           m.Author.Username,
+			"twitch_channel": twitchChannel,
+			"channel_id":     m.ChannelID,
+			"server_id":      m.GuildID,
+			"error":          err}).Info("Failed to set offline " + c[1] + ".")
+
+		if errors.Is(err, constants.ErrOfflineTextTooLong) {
+			sendTemporaryMessage(s, m.ChannelID, fmt.Sprintf("Offline messages can be up to %v characters long.", constants.MaxOfflineText))
+		} else if errors.Is(err, constants.ErrInvalidImageURL) {
+			sendTemporaryMessage(s, m.ChannelID, "The image must be an http or https link.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is not added to this Discord channel.")
+		}
+		return
+	}
+
+	utils.Log.WithFields(logrus.Fields{
+		"user":           m.Author.Username,
+		"twitch_channel": twitchChannel,
+		"channel_id":     m.ChannelID,
+		"server_id":      m.GuildID}).Info("Succeeded in setting offline " + c[1] + ".")
+
+	if value == "" {
+		sendTemporaryMessage(s, m.ChannelID, "The default offline "+c[1]+" is used again for "+twitchChannel+".")
+	} else {
+		sendTemporaryMessage(s, m.ChannelID, "The offline "+c[1]+" of "+twitchChannel+" was set.")
+	}
+}
+
+func sendOfflineUsage(s *discordgo.Session, m *discordgo.MessageCreate) {
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" offline <Channel>\n"+
+		constants.CommandPrefix+" offline <Channel> message \"<Text>\"/off\n"+
+		constants.CommandPrefix+" offline <Channel> image <Image URL>/off")
+}
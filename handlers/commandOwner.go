@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/config"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/features"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/plugins"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/stats"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Commands the bot owner can issue in a direct message
+var ownerCommands = map[string]bool{
+	"status":      true,
+	"guilds":      true,
+	"broadcast":   true,
+	"reload":      true,
+	"credentials": true,
+	"admin":       true,
+}
+
+// Commands that do not use the Twitch session and keep working while it is unavailable
+var offlineCommands = map[string]bool{
+	"about":   true,
+	"feature": true,
+	"status":  true,
+	"reload":  true,
+}
+
+// Returns whether a command needs the Twitch session. Plugin commands decide for themselves.
+func needsTwitch(command string) bool {
+	if offlineCommands[command] {
+		return false
+	}
+	_, plugin := plugins.LookupCommand(command)
+	return !plugin
+}
+
+func commandStatus(s *discordgo.Session, m *discordgo.MessageCreate) {
+	t := twitch.GetSession(s)
+	if t == nil {
+		statusEmbed := &discordgo.MessageEmbed{
+			Title: "Bot status",
+			Fields: []*discordgo.MessageEmbedField{
+				{Name: "Twitch", Value: "Unavailable, reconnecting in the background", Inline: true},
+				{Name: "Uptime", Value: formatLongDuration(stats.SessionUptime()), Inline: true},
+			},
+		}
+		if _, err := s.ChannelMessageSendEmbed(m.ChannelID, statusEmbed); err != nil {
+			utils.Log.WithError(err).Error("Failed to send message to Discord.")
+		}
+		return
+	}
+
+	snapshot := t.Snapshot()
+
+	live := 0
+	for _, channel := range snapshot.Channels {
+		if channel.Live {
+			live++
+		}
+	}
+	connected := 0
+	for _, guild := range snapshot.Guilds {
+		if guild.Status == "connected" {
+			connected++
+		}
+	}
+
+	statusEmbed := &discordgo.MessageEmbed{
+		Title: "Bot status",
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Twitch", Value: connectionStatus(snapshot.Connected), Inline: true},
+			{Name: "Uptime", Value: formatLongDuration(stats.SessionUptime()), Inline: true},
+			{Name: "Guilds", Value: fmt.Sprintf("%v of %v connected", connected, len(snapshot.Guilds)), Inline: true},
+			{Name: "Twitch channels", Value: fmt.Sprint(len(snapshot.Channels)), Inline: true},
+			{Name: "Live", Value: fmt.Sprint(live), Inline: true},
+			{Name: "Rate limit remaining", Value: fmt.Sprint(snapshot.RateLimit.Remaining), Inline: true},
+			{Name: "Storage", Value: storageStatus(), Inline: true},
+		},
+	}
+
+	if _, err := s.ChannelMessageSendEmbed(m.ChannelID, statusEmbed); err != nil {
+		utils.Log.WithError(err).Error("Failed to send message to Discord.")
+	}
+}
+
+func commandGuilds(s *discordgo.Session, m *discordgo.MessageCreate) {
+	guilds := twitch.GetSession(s).GetGuilds(s)
+	if len(guilds) == 0 {
+		sendTemporaryMessage(s, m.ChannelID, "The bot has not joined any Discord servers.")
+		return
+	}
+
+	lines := []string{}
+	for _, guild := range guilds {
+		lines = append(lines, fmt.Sprintf("**%v** (%v): %v registrations, %v", guild.Name, guild.ID, guild.Registrations, connectionStatus(guild.Connected)))
+	}
+
+	guildsEmbed := &discordgo.MessageEmbed{
+		Title:       "The bot has joined the following Discord servers",
+		Description: strings.Join(lines, "\n"),
+	}
+
+	if _, err := s.ChannelMessageSendEmbed(m.ChannelID, guildsEmbed); err != nil {
+		utils.Log.WithError(err).Error("Failed to send message to Discord.")
+	}
+}
+
+func commandBroadcast(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	if len(c) == 0 {
+		sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+			constants.CommandPrefix+" broadcast <Message>")
+		return
+	}
+
+	sent, failed := twitch.GetSession(s).Broadcast(s, strings.Join(c, " "))
+
+	utils.Log.WithFields(logrus.Fields{
+		"user":   m.Author.Username,
+		"sent":   sent,
+		"failed": failed}).Info("Succeeded in broadcasting message.")
+
+	sendTemporaryMessage(s, m.ChannelID, fmt.Sprintf("The message was sent to %v Discord channels, %v failed.", sent, failed))
+}
+
+func commandReload(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if err := config.Reload(); err != nil {
+		utils.Log.WithFields(logrus.Fields{
+			"user":  m.Author.Username,
+			"error": err}).Info("Failed to reload config file.")
+
+		sendTemporaryMessage(s, m.ChannelID, "The config file could not be loaded: "+err.Error())
+		return
+	}
+	if err := features.Load(); err != nil {
+		utils.Log.WithFields(logrus.Fields{
+			"user":  m.Author.Username,
+			"error": err}).Info("Failed to reload feature flags.")
+
+		sendTemporaryMessage(s, m.ChannelID, "The feature flags could not be loaded: "+err.Error())
+		return
+	}
+
+	utils.Log.WithFields(logrus.Fields{
+		"user": m.Author.Username}).Info("Succeeded in reloading config file.")
+
+	sendTemporaryMessage(s, m.ChannelID, "The config file and feature flags were reloaded. Settings only read at startup, such as the HTTP server, need a restart.")
+}
+
+// Summarizes announcements, API errors, delivery times and Twitch quota usage
+func sendAdminStats(s *discordgo.Session, m *discordgo.MessageCreate) {
+	days := []string{}
+	for _, day := range stats.DailyAnnouncements() {
+		days = append(days, fmt.Sprintf("%v: %v", day.Day.Format("Jan 2"), day.Count))
+	}
+	if len(days) == 0 {
+		days = append(days, "None yet")
+	}
+
+	usage := stats.GetUsage()
+	twitchErrors := fmt.Sprintf("%v of %v requests failed (%v)", usage.TwitchErrors, usage.TwitchRequests, percentage(usage.TwitchErrors, usage.TwitchRequests))
+	if usage.TwitchRateLimited > 0 {
+		twitchErrors += fmt.Sprintf(", %v rate limited", usage.TwitchRateLimited)
+	}
+	discordErrors := fmt.Sprintf("%v of %v notifications failed (%v)", usage.DiscordFailed, usage.DiscordSent+usage.DiscordFailed, percentage(usage.DiscordFailed, usage.DiscordSent+usage.DiscordFailed))
+
+	quota := "Unknown until the first Twitch request"
+	if usage.RateLimit > 0 {
+		quota = fmt.Sprintf("%v of %v points remaining, lowest %v", usage.RateLimitRemaining, usage.RateLimit, usage.RateLimitLowest)
+	}
+
+	cacheLookups := usage.CacheHits + usage.CacheRevalidated + usage.CacheMisses
+	cache := fmt.Sprintf("%v of %v lookups answered from the cache (%v), %v of them revalidated",
+		usage.CacheHits+usage.CacheRevalidated, cacheLookups, percentage(usage.CacheHits+usage.CacheRevalidated, cacheLookups), usage.CacheRevalidated)
+
+	slowest := []string{}
+	for _, d := range twitch.GetSession(s).SlowestDeliveries(constants.AdminStatsGuilds) {
+		name := d.GuildID
+		if guild, err := s.State.Guild(d.GuildID); err == nil {
+			name = guild.Name
+		}
+		slowest = append(slowest, fmt.Sprintf("**%v**: %v average, %v slowest over %v announcements",
+			name, d.Average.Round(time.Millisecond), d.Slowest.Round(time.Millisecond), d.Announcements))
+	}
+	if len(slowest) == 0 {
+		slowest = append(slowest, "No announcements since the bot started")
+	}
+
+	commands := []string{}
+	for i, c := range stats.CommandUsages() {
+		if i == constants.AdminStatsCommands {
+			break
+		}
+		commands = append(commands, fmt.Sprintf("**%v**: %v runs, %v failed, %v average, %v slowest",
+			c.Command, c.Invocations, c.Failures, c.Average.Round(time.Millisecond), c.Slowest.Round(time.Millisecond)))
+	}
+	if len(commands) == 0 {
+		commands = append(commands, "No commands since the bot started")
+	}
+
+	statsEmbed := &discordgo.MessageEmbed{
+		Title: "Bot statistics",
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Announcements per day (UTC)", Value: strings.Join(days, "\n")},
+			{Name: "Twitch API errors since start", Value: twitchErrors},
+			{Name: "Discord errors since start", Value: discordErrors},
+			{Name: "Twitch rate limit", Value: quota},
+			{Name: "Twitch lookup cache", Value: cache},
+			{Name: "Slowest servers to deliver to", Value: strings.Join(slowest, "\n")},
+			{Name: "Most used commands since start", Value: strings.Join(commands, "\n")},
+		},
+	}
+
+	if _, err := s.ChannelMessageSendEmbed(m.ChannelID, statsEmbed); err != nil {
+		utils.Log.WithError(err).Error("Failed to send message to Discord.")
+	}
+}
+
+// Formats part of a total as a percentage
+func percentage(part int, total int) string {
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)*100/float64(total))
+}
+
+func storageStatus() string {
+	if utils.StorageHealthy() {
+		return "Saving"
+	}
+	return "Writes failing"
+}
+
+func connectionStatus(connected bool) string {
+	if connected {
+		return "connected"
+	}
+	return "disconnected"
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandPerms(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	if len(c) == 1 && c[0] == "check" {
+		checked, problems, err := twitch.GetSession(s).CheckPermissions(s, m.GuildID)
+		if err != nil {
+			utils.Log.WithError(err).WithFields(logrus.Fields{"server_id": m.GuildID}).Error("Failed to check permissions.")
+			sendTemporaryMessage(s, m.ChannelID, "Error checking permissions. Connection to Discord may be down.")
+			return
+		}
+
+		if checked == 0 {
+			sendTemporaryMessage(s, m.ChannelID, "No Twitch channels are added to this Discord server.")
+			return
+		}
+		if len(problems) == 0 {
+			sendTemporaryMessage(s, m.ChannelID, fmt.Sprintf("I have every permission I need in the %v Discord channels Twitch channels are added to.", checked))
+			return
+		}
+
+		lines := []string{}
+		for _, p := range problems {
+			line := fmt.Sprintf("<#%v> (%v): ", p.DiscordChannelID, strings.Join(p.TwitchChannels, ", "))
+			if p.Err != nil {
+				line += "the channel could not be found, it may have been deleted"
+			} else {
+				line += "missing " + strings.Join(p.Missing, ", ")
+			}
+			lines = append(lines, line)
+		}
+
+		content := fmt.Sprintf("I am missing permissions in %v of the %v Discord channels Twitch channels are added to:\n", len(problems), checked)
+		for i, line := range lines {
+			// Problems that do not fit in one message are summarized
+			rest := fmt.Sprintf("\n...and %v more.", len(lines)-i)
+			if len([]rune(content+line+"\n"+rest)) > constants.DiscordMaxMessageLength-100 {
+				content += rest
+				break
+			}
+			content += line + "\n"
+		}
+
+		sendTemporaryMessage(s, m.ChannelID, strings.TrimSuffix(content, "\n"))
+		return
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" perms check")
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/crash"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/plugins"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/scripts"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Runs a command registered by a plugin if the user has the permission it requires
+func runPluginCommand(s *discordgo.Session, m *discordgo.MessageCreate, command plugins.Command, args []string) {
+	defer crash.Recover("plugin_command_" + command.Name)
+
+	allowed := true
+	switch command.Permission {
+	case plugins.Moderator:
+		go deleteUserMessageWithDelay(s, m, time.Second)
+		allowed = isUserMod(s, m.GuildID, m.Member)
+	case plugins.Owner:
+		go deleteUserMessageWithDelay(s, m, time.Second)
+		allowed = isUserOwner(m.Author)
+	}
+
+	if !allowed {
+		utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+		return
+	}
+
+	command.Run(s, m, args)
+}
+
+// Passes a command the bot does not know to the on_command script. Returns whether the script handled it.
+func runScriptCommand(s *discordgo.Session, m *discordgo.MessageCreate, c []string) bool {
+	if m.GuildID == "" || !scripts.Has(scripts.OnCommand) {
+		return false
+	}
+
+	result, err := scripts.RunCommand(scripts.Command{
+		GuildID:   m.GuildID,
+		ChannelID: m.ChannelID,
+		UserID:    m.Author.ID,
+		Username:  m.Author.Username,
+		Moderator: isUserMod(s, m.GuildID, m.Member),
+		Name:      c[0],
+		Args:      c[1:],
+	})
+	if err != nil {
+		utils.Log.WithFields(logrus.Fields{
+			"user":       m.Author.Username,
+			"command":    loggedCommand(m.Content),
+			"channel_id": m.ChannelID,
+			"server_id":  m.GuildID,
+			"error":      err}).Error("Failed to run command script.")
+		return false
+	}
+	if !result.Handled {
+		return false
+	}
+
+	if result.Reply != "" {
+		if _, err := s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
+			Content:         result.Reply,
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+		}); err != nil {
+			utils.Log.WithError(err).Error("Failed to send message to Discord.")
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandPolls(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	if len(c) == 2 {
+		switch c[0] {
+		case "channel":
+			t := twitch.GetSession(s)
+			login := resolveTwitchChannel(s, m.GuildID, c[1])
+
+			if err := t.SetPollChannel(login, m.GuildID, m.ChannelID); err != nil {
+				utils.Log.WithFields(logrus.Fields{
+					"user":           m.Author.Username,
+					"twitch_channel": login,
+					"channel_id":     m.ChannelID,
+					"server_id":      m.GuildID,
+					"error":          err}).Info("Failed to set poll channel.")
+
+				if errors.Is(err, constants.ErrFeatureDisabled) {
+					sendTemporaryMessage(s, m.ChannelID, "The eventsub feature is not enabled in this Discord server.")
+				} else if errors.Is(err, constants.ErrBroadcasterNotLinked) {
+					sendTemporaryMessage(s, m.ChannelID, login+" has not linked their Twitch account to this Discord server.")
+				} else if errors.Is(err, constants.ErrEventSubDisabled) {
+					sendTemporaryMessage(s, m.ChannelID, "EventSub is not configured for this bot.")
+				} else {
+					sendTemporaryMessage(s, m.ChannelID, "Error subscribing to polls and predictions. Connection to twitch may be down.")
+				}
+				return
+			}
+
+			utils.Log.WithFields(logrus.Fields{
+				"user":           m.Author.Username,
+				"twitch_channel": login,
+				"channel_id":     m.ChannelID,
+				"server_id":      m.GuildID}).Info("Succeeded in setting poll channel.")
+
+			sendTemporaryMessage(s, m.ChannelID, login+"'s polls and predictions will be mirrored in this Discord channel.")
+			return
+		case "off":
+			t := twitch.GetSession(s)
+			login := resolveTwitchChannel(s, m.GuildID, c[1])
+
+			if err := t.DisablePolls(login, m.GuildID); err != nil {
+				sendTemporaryMessage(s, m.ChannelID, login+"'s polls and predictions are not being mirrored.")
+				return
+			}
+
+			sendTemporaryMessage(s, m.ChannelID, login+"'s polls and predictions will no longer be mirrored.")
+			return
+		default:
+		}
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" polls [channel/off] <Twitch Channel>")
+}
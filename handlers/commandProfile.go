@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+var roleMentionRegex = regexp.MustCompile(`^<@&\d+>$`)
+
+func commandProfile(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	c, options := parseOptions(c)
+
+	if len(c) == 1 {
+		switch c[0] {
+		case "list":
+			t := twitch.GetSession(s)
+			profiles := t.GetProfiles(m.GuildID)
+
+			names := make([]string, 0, len(profiles))
+			for name := range profiles {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			listFields := []*discordgo.MessageEmbedField{}
+			for _, name := range names {
+				listFields = append(listFields, &discordgo.MessageEmbedField{
+					Name:   name,
+					Value:  describeProfile(profiles[name]),
+					Inline: false,
+				})
+			}
+
+			listEmbed := &discordgo.MessageEmbed{
+				Title:  "This Discord server has the profiles",
+				Fields: listFields,
+			}
+
+			_, err := s.ChannelMessageSendEmbed(m.ChannelID, listEmbed)
+			if err != nil {
+				utils.Log.WithError(err).Error("Failed to send message to Discord.")
+			}
+			return
+		default:
+		}
+	} else if len(c) == 2 {
+		switch c[0] {
+		case "create":
+			t := twitch.GetSession(s)
+			name := strings.ToLower(c[1])
+
+			p, err := profileFromOptions(options)
+			if err != nil {
+				sendTemporaryMessage(s, m.ChannelID, "Invalid profile option: "+err.Error())
+				return
+			}
+
+			if err := t.CreateProfile(m.GuildID, name, p); err != nil {
+				utils.Log.WithFields(logrus.Fields{
+					"user":      m.Author.Username,
+					"profile":   name,
+					"server_id": m.GuildID,
+					"error":     err}).Info("Failed to create profile.")
+
+				if errors.Is(err, constants.ErrProfileExists) {
+					sendTemporaryMessage(s, m.ChannelID, "The profile "+name+" already exists.")
+				} else {
+					sendTemporaryMessage(s, m.ChannelID, "Error creating profile.")
+				}
+				return
+			}
+
+			utils.Log.WithFields(logrus.Fields{
+				"user":      m.Author.Username,
+				"profile":   name,
+				"server_id": m.GuildID}).Info("Succeeded in creating profile.")
+
+			sendTemporaryMessage(s, m.ChannelID, "The profile "+name+" was successfully created.")
+			return
+		case "delete":
+			t := twitch.GetSession(s)
+			name := strings.ToLower(c[1])
+
+			if err := t.DeleteProfile(m.GuildID, name); err != nil {
+				sendTemporaryMessage(s, m.ChannelID, "The profile "+name+" does not exist.")
+				return
+			}
+
+			utils.Log.WithFields(logrus.Fields{
+				"user":      m.Author.Username,
+				"profile":   name,
+				"server_id": m.GuildID}).Info("Succeeded in deleting profile.")
+
+			sendTemporaryMessage(s, m.ChannelID, "The profile "+name+" was successfully deleted.")
+			return
+		default:
+		}
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" profile list\n"+
+		constants.CommandPrefix+" profile create <Profile> [--template \"<Text>\"] [--mention <@Role/@everyone/@here>] [--games \"<Game>, <Game>\"] [--color <Hex color>]\n"+
+		constants.CommandPrefix+" profile delete <Profile>")
+}
+
+// Builds a profile from the options of a profile create command
+func profileFromOptions(options map[string]string) (twitch.Profile, error) {
+	p := twitch.Profile{
+		Template: options["template"],
+	}
+
+	if mention := options["mention"]; mention != "" {
+		if mention != "@everyone" && mention != "@here" && !roleMentionRegex.MatchString(mention) {
+			return p, fmt.Errorf("%v is not a role mention, @everyone or @here", mention)
+		}
+		p.Mention = mention
+	}
+
+	if games := options["games"]; games != "" {
+		for _, game := range strings.Split(games, ",") {
+			if game = strings.TrimSpace(game); game != "" {
+				p.Games = append(p.Games, game)
+			}
+		}
+	}
+
+	if color := options["color"]; color != "" {
+		c, err := parseHexColor(color)
+		if err != nil {
+			return p, err
+		}
+		p.Color = c
+	}
+
+	return p, nil
+}
+
+// Returns a readable summary of a profile's settings
+func describeProfile(p twitch.Profile) string {
+	description := ""
+
+	if p.Template != "" {
+		description += "**Template:** " + p.Template + "\n"
+	}
+	if p.Mention != "" {
+		description += "**Mention:** " + p.Mention + "\n"
+	}
+	if len(p.Games) > 0 {
+		description += "**Games:** " + strings.Join(p.Games, ", ") + "\n"
+	}
+	if p.Color != 0 {
+		description += fmt.Sprintf("**Color:** #%06x\n", p.Color)
+	}
+	if description == "" {
+		description = "Default settings"
+	}
+
+	return description
+}
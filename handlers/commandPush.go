@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/push"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Lets members receive mobile push notifications when Twitch channels go live
+func commandPush(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	if len(c) == 0 {
+		if sub, ok := push.Get(m.Author.ID); ok {
+			channels := "no Twitch channels"
+			if len(sub.Channels) > 0 {
+				channels = strings.Join(sub.Channels, ", ")
+			}
+			sendTemporaryMessage(s, m.ChannelID, m.Author.Mention()+" receives "+sub.Service+" notifications about "+channels+".")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, m.Author.Mention()+" has not set up push notifications.")
+		}
+		return
+	} else if len(c) == 1 && c[0] == "off" {
+		if err := push.Disable(m.Author.ID); err != nil {
+			sendTemporaryMessage(s, m.ChannelID, m.Author.Mention()+" has not set up push notifications.")
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"server_id": m.GuildID}).Info("Succeeded in disabling push notifications.")
+
+		sendTemporaryMessage(s, m.ChannelID, "Push notifications to "+m.Author.Mention()+" are turned off.")
+		return
+	} else if len(c) == 2 && (c[0] == push.Ntfy || c[0] == push.Pushover) {
+		if err := push.SetTarget(m.Author.ID, c[0], c[1]); err != nil {
+			utils.Log.WithFields(logrus.Fields{
+				"user":      m.Author.Username,
+				"server_id": m.GuildID,
+				"error":     err}).Info("Failed to set up push notifications.")
+
+			if errors.Is(err, constants.ErrPushServiceDisabled) {
+				sendTemporaryMessage(s, m.ChannelID, "Pushover notifications are not set up on this bot.")
+			} else {
+				sendTemporaryMessage(s, m.ChannelID, "Error setting up push notifications.")
+			}
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"server_id": m.GuildID}).Info("Succeeded in setting up push notifications.")
+
+		sendTemporaryMessage(s, m.ChannelID, m.Author.Mention()+" will receive "+c[0]+" notifications. Use "+constants.CommandPrefix+" push follow <Twitch Channel> to choose the streams.")
+		return
+	} else if len(c) == 2 && c[0] == "follow" {
+		twitchChannel := resolveTwitchChannel(s, m.GuildID, c[1])
+		if !twitch.GetSession(s).IsMonitored(twitchChannel) {
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is not monitored by the bot.")
+			return
+		}
+
+		if err := push.Follow(m.Author.ID, twitchChannel); err != nil {
+			sendTemporaryMessage(s, m.ChannelID, m.Author.Mention()+" has to set up push notifications first.")
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":           m.Author.Username,
+			"twitch_channel": twitchChannel,
+			"server_id":      m.GuildID}).Info("Succeeded in following channel.")
+
+		sendTemporaryMessage(s, m.ChannelID, m.Author.Mention()+" will be notified when "+twitchChannel+" goes live.")
+		return
+	} else if len(c) == 2 && c[0] == "unfollow" {
+		twitchChannel := resolveTwitchChannel(s, m.GuildID, c[1])
+
+		if err := push.Unfollow(m.Author.ID, twitchChannel); err != nil {
+			if errors.Is(err, constants.ErrNotFollowing) {
+				sendTemporaryMessage(s, m.ChannelID, m.Author.Mention()+" does not follow "+twitchChannel+".")
+			} else {
+				sendTemporaryMessage(s, m.ChannelID, m.Author.Mention()+" has not set up push notifications.")
+			}
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":           m.Author.Username,
+			"twitch_channel": twitchChannel,
+			"server_id":      m.GuildID}).Info("Succeeded in unfollowing channel.")
+
+		sendTemporaryMessage(s, m.ChannelID, m.Author.Mention()+" will no longer be notified when "+twitchChannel+" goes live.")
+		return
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" push\n"+
+		constants.CommandPrefix+" push ntfy <Topic or topic URL>\n"+
+		constants.CommandPrefix+" push pushover <User key>\n"+
+		constants.CommandPrefix+" push follow <Twitch Channel>\n"+
+		constants.CommandPrefix+" push unfollow <Twitch Channel>\n"+
+		constants.CommandPrefix+" push off")
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandReport(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	t := twitch.GetSession(s)
+
+	if len(c) == 0 {
+		if channelID, period := t.GetReports(m.GuildID); channelID != "" {
+			sendTemporaryMessage(s, m.ChannelID, "A "+period+" stream report is posted in <#"+channelID+">.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, "No stream reports are posted in this Discord server.")
+		}
+		return
+	} else if len(c) == 1 && (c[0] == twitch.ReportWeekly || c[0] == twitch.ReportMonthly) {
+		if err := t.SetReports(m.GuildID, m.ChannelID, c[0]); err != nil {
+			sendTemporaryMessage(s, m.ChannelID, "Reports can be posted weekly or monthly.")
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":       m.Author.Username,
+			"period":     c[0],
+			"channel_id": m.ChannelID,
+			"server_id":  m.GuildID}).Info("Succeeded in setting up stream reports.")
+
+		sendTemporaryMessage(s, m.ChannelID, "A "+c[0]+" report of the streams of this Discord server's Twitch channels will be posted in this channel.")
+		return
+	} else if len(c) == 1 && c[0] == "off" {
+		if err := t.DisableReports(m.GuildID); err != nil {
+			sendTemporaryMessage(s, m.ChannelID, "No stream reports are posted in this Discord server.")
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"server_id": m.GuildID}).Info("Succeeded in disabling stream reports.")
+
+		sendTemporaryMessage(s, m.ChannelID, "Stream reports will no longer be posted.")
+		return
+	} else if (len(c) == 1 || len(c) == 2) && c[0] == "now" {
+		period := twitch.ReportWeekly
+		if len(c) == 2 {
+			period = c[1]
+		}
+
+		if err := t.PostReport(s, m.GuildID, m.ChannelID, period); err != nil {
+			utils.Log.WithFields(logrus.Fields{
+				"user":       m.Author.Username,
+				"channel_id": m.ChannelID,
+				"server_id":  m.GuildID,
+				"error":      err}).Info("Failed to post stream report.")
+
+			sendTemporaryMessage(s, m.ChannelID, "The report could not be posted: "+err.Error()+".")
+		}
+		return
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" report\n"+
+		constants.CommandPrefix+" report <weekly/monthly>\n"+
+		constants.CommandPrefix+" report now [weekly/monthly]\n"+
+		constants.CommandPrefix+" report off")
+}
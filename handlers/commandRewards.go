@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandRewards(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	if len(c) == 2 {
+		switch c[0] {
+		case "channel":
+			t := twitch.GetSession(s)
+			login := resolveTwitchChannel(s, m.GuildID, c[1])
+
+			if err := t.SetRewardChannel(login, m.GuildID, m.ChannelID); err != nil {
+				utils.Log.WithFields(logrus.Fields{
+					"user":           m.Author.Username,
+					"twitch_channel": login,
+					"channel_id":     m.ChannelID,
+					"server_id":      m.GuildID,
+					"error":          err}).Info("Failed to set reward channel.")
+
+				if errors.Is(err, constants.ErrFeatureDisabled) {
+					sendTemporaryMessage(s, m.ChannelID, "The eventsub feature is not enabled in this Discord server.")
+				} else if errors.Is(err, constants.ErrBroadcasterNotLinked) {
+					sendTemporaryMessage(s, m.ChannelID, login+" has not linked their Twitch account to this Discord server.")
+				} else if errors.Is(err, constants.ErrEventSubDisabled) {
+					sendTemporaryMessage(s, m.ChannelID, "EventSub is not configured for this bot.")
+				} else {
+					sendTemporaryMessage(s, m.ChannelID, "Error subscribing to channel point redemptions. Connection to twitch may be down.")
+				}
+				return
+			}
+
+			utils.Log.WithFields(logrus.Fields{
+				"user":           m.Author.Username,
+				"twitch_channel": login,
+				"channel_id":     m.ChannelID,
+				"server_id":      m.GuildID}).Info("Succeeded in setting reward channel.")
+
+			sendTemporaryMessage(s, m.ChannelID, "Enabled rewards redeemed on "+login+"'s channel will be posted in this Discord channel.")
+			return
+		case "off":
+			t := twitch.GetSession(s)
+			login := resolveTwitchChannel(s, m.GuildID, c[1])
+
+			if err := t.DisableRewards(login, m.GuildID); err != nil {
+				sendTemporaryMessage(s, m.ChannelID, "Rewards redeemed on "+login+"'s channel are not being posted.")
+				return
+			}
+
+			sendTemporaryMessage(s, m.ChannelID, "Rewards redeemed on "+login+"'s channel will no longer be posted.")
+			return
+		case "list":
+			t := twitch.GetSession(s)
+			login := resolveTwitchChannel(s, m.GuildID, c[1])
+
+			channelID, rewards, err := t.GetRewardSettings(login, m.GuildID)
+			if err != nil {
+				sendTemporaryMessage(s, m.ChannelID, "Rewards redeemed on "+login+"'s channel are not being posted.")
+				return
+			}
+
+			names := make([]string, 0, len(rewards))
+			for name := range rewards {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			listFields := []*discordgo.MessageEmbedField{}
+			for _, name := range names {
+				listFields = append(listFields, &discordgo.MessageEmbedField{
+					Name:   name,
+					Value:  enabledString(rewards[name]),
+					Inline: true,
+				})
+			}
+
+			listEmbed := &discordgo.MessageEmbed{
+				Title:       login + "'s rewards",
+				Description: "Redemptions are posted in <#" + channelID + ">",
+				Fields:      listFields,
+			}
+
+			_, err = s.ChannelMessageSendEmbed(m.ChannelID, listEmbed)
+			if err != nil {
+				utils.Log.WithError(err).Error("Failed to send message to Discord.")
+			}
+			return
+		default:
+		}
+	} else if len(c) == 3 {
+		switch c[0] {
+		case "enable", "disable":
+			t := twitch.GetSession(s)
+			login := resolveTwitchChannel(s, m.GuildID, c[1])
+
+			if err := t.SetRewardEnabled(login, m.GuildID, c[2], c[0] == "enable"); err != nil {
+				sendTemporaryMessage(s, m.ChannelID, "Use "+constants.CommandPrefix+" rewards channel "+login+" first to choose where redemptions are posted.")
+				return
+			}
+
+			sendTemporaryMessage(s, m.ChannelID, "Posting redemptions of "+c[2]+" is now "+enabledString(c[0] == "enable")+".")
+			return
+		default:
+		}
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" rewards [channel/off/list] <Twitch Channel>\n"+
+		constants.CommandPrefix+" rewards [enable/disable] <Twitch Channel> \"<Reward>\"")
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Steps of the setup wizard
+const (
+	setupStepChannel = iota
+	setupStepStreamers
+	setupStepMention
+)
+
+// Name of the profile the setup wizard stores the mention role in
+const setupProfile = "setup"
+
+// Progress of a user through the setup wizard, which is answered in direct messages
+type setupWizard struct {
+	guildID        string
+	step           int
+	channelID      string
+	twitchChannels []string
+	lastActivity   time.Time
+}
+
+var setupWizards = struct {
+	sync.Mutex
+	m map[string]*setupWizard // Map of Discord user ID to their wizard
+}{m: make(map[string]*setupWizard)}
+
+func commandSetup(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	if len(c) != 0 {
+		sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+constants.CommandPrefix+" setup")
+		return
+	}
+
+	setupWizards.Lock()
+	setupWizards.m[m.Author.ID] = &setupWizard{guildID: m.GuildID, lastActivity: time.Now()}
+	setupWizards.Unlock()
+
+	if err := sendDirectMessage(s, m.Author.ID, "Let's set up stream announcements. Reply `cancel` at any time to stop.\n\n"+
+		"**1/3** Which channel should streams be announced in? Reply with the name of the channel."); err != nil {
+		setupWizards.Lock()
+		delete(setupWizards.m, m.Author.ID)
+		setupWizards.Unlock()
+
+		sendTemporaryMessage(s, m.ChannelID, "The setup could not be started. Allow direct messages from server members and try again.")
+		return
+	}
+
+	utils.Log.WithFields(logrus.Fields{
+		"user":      m.Author.Username,
+		"server_id": m.GuildID}).Info("Succeeded in starting setup wizard.")
+
+	sendTemporaryMessage(s, m.ChannelID, "I sent you a direct message to continue the setup.")
+}
+
+// Handles a direct message answering the setup wizard. Returns false if the user has no wizard in progress.
+func handleSetupReply(s *discordgo.Session, m *discordgo.MessageCreate) bool {
+	setupWizards.Lock()
+	defer setupWizards.Unlock()
+
+	w := setupWizards.m[m.Author.ID]
+	if w == nil {
+		return false
+	} else if time.Since(w.lastActivity) > constants.SetupWizardTimeout {
+		delete(setupWizards.m, m.Author.ID)
+		return false
+	}
+	w.lastActivity = time.Now()
+
+	reply := strings.TrimSpace(m.Content)
+	if strings.EqualFold(reply, "cancel") {
+		delete(setupWizards.m, m.Author.ID)
+		s.ChannelMessageSend(m.ChannelID, "Setup cancelled. Anything added so far is kept.")
+		return true
+	}
+
+	switch w.step {
+	case setupStepChannel:
+		channel := findGuildChannel(s, w.guildID, reply)
+		if channel == nil {
+			s.ChannelMessageSend(m.ChannelID, "I couldn't find a text channel called "+reply+" in the server. Reply with the name of the channel.")
+			return true
+		}
+
+		w.channelID = channel.ID
+		w.step = setupStepStreamers
+		s.ChannelMessageSend(m.ChannelID, "Streams will be announced in #"+channel.Name+".\n\n"+
+			"**2/3** Which Twitch channels should be announced? Reply with their names separated by commas.")
+	case setupStepStreamers:
+		t := twitch.GetSession(s)
+		if t == nil {
+			s.ChannelMessageSend(m.ChannelID, constants.TwitchUnavailableMessage)
+			return true
+		}
+		var results []string
+
+		for _, name := range strings.FieldsFunc(reply, func(r rune) bool { return r == ',' || r == ' ' }) {
+			twitchChannel := strings.ToLower(name)
+
+			err := t.RegisterChannel(twitchChannel, w.guildID, w.channelID, getChannelName(s, w.channelID))
+			if err == nil || errors.Is(err, constants.ErrTwitchUserRegistered) {
+				w.twitchChannels = append(w.twitchChannels, twitchChannel)
+				results = append(results, twitchChannel+" was added.")
+			} else if errors.Is(err, constants.ErrTwitchUserDoesNotExist) {
+				results = append(results, "The Twitch channel "+twitchChannel+" does not exist.")
+			} else if errors.Is(err, constants.ErrLookupRateLimited) {
+				results = append(results, twitchChannel+" could not be added because too many Twitch channels are being added. Try again in a minute.")
+			} else {
+				results = append(results, twitchChannel+" could not be added. Connection to twitch may be down.")
+			}
+		}
+
+		if len(w.twitchChannels) == 0 {
+			s.ChannelMessageSend(m.ChannelID, strings.Join(append(results, "Reply with the names of the Twitch channels to announce."), "\n"))
+			return true
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":            m.Author.Username,
+			"twitch_channels": w.twitchChannels,
+			"channel_id":      w.channelID,
+			"server_id":       w.guildID}).Info("Succeeded in registering channels with the setup wizard.")
+
+		w.step = setupStepMention
+		s.ChannelMessageSend(m.ChannelID, strings.Join(results, "\n")+"\n\n"+
+			"**3/3** Which role should be mentioned in announcements? Reply with the name of a role, `everyone`, `here` or `none`.")
+	case setupStepMention:
+		mention := ""
+		switch strings.ToLower(strings.TrimPrefix(reply, "@")) {
+		case "none":
+		case "everyone":
+			mention = "@everyone"
+		case "here":
+			mention = "@here"
+		default:
+			role := findGuildRole(s, w.guildID, reply)
+			if role == nil {
+				s.ChannelMessageSend(m.ChannelID, "I couldn't find a role called "+reply+" in the server. Reply with the name of a role, `everyone`, `here` or `none`.")
+				return true
+			}
+			mention = role.Mention()
+		}
+
+		t := twitch.GetSession(s)
+		if t == nil {
+			s.ChannelMessageSend(m.ChannelID, constants.TwitchUnavailableMessage)
+			return true
+		}
+		if mention != "" {
+			t.SaveProfile(w.guildID, setupProfile, twitch.Profile{Mention: mention})
+			for _, twitchChannel := range w.twitchChannels {
+				if err := t.SetChannelProfile(twitchChannel, w.guildID, w.channelID, setupProfile); err != nil {
+					utils.Log.WithError(err).Error("Failed to apply profile to channel.")
+				}
+			}
+		}
+
+		delete(setupWizards.m, m.Author.ID)
+
+		if preview, err := t.PreviewLiveMessage(w.twitchChannels[0], w.guildID, w.channelID); err == nil {
+			preview.Content = strings.TrimSpace("This is how announcements will look:\n" + preview.Content)
+			if _, err := s.ChannelMessageSendComplex(m.ChannelID, preview); err != nil {
+				utils.Log.WithError(err).Error("Failed to send direct message to Discord.")
+			}
+		}
+
+		s.ChannelMessageSend(m.ChannelID, "Setup is complete! Use `"+constants.CommandPrefix+" channel add <Twitch Channel>` in a channel to announce more streams there, "+
+			"and `"+constants.CommandPrefix+" profile` to change the mention and message of the "+setupProfile+" profile.")
+	}
+
+	return true
+}
+
+// Finds a text channel of a guild by name, mention or ID
+func findGuildChannel(s *discordgo.Session, guildID string, name string) *discordgo.Channel {
+	name = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(name, "<#"), "#"), ">")
+
+	channels, err := s.GuildChannels(guildID)
+	if err != nil {
+		utils.Log.WithError(err).Error("Failed to get channels from guild.")
+		return nil
+	}
+
+	for _, channel := range channels {
+		if channel.Type == discordgo.ChannelTypeGuildText && (channel.ID == name || strings.EqualFold(channel.Name, name)) {
+			return channel
+		}
+	}
+
+	return nil
+}
+
+// Finds a role of a guild by name, mention or ID
+func findGuildRole(s *discordgo.Session, guildID string, name string) *discordgo.Role {
+	name = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(name, "<@&"), "@"), ">")
+
+	roles, err := s.GuildRoles(guildID)
+	if err != nil {
+		utils.Log.WithError(err).Error("Failed to get roles from guild.")
+		return nil
+	}
+
+	for _, role := range roles {
+		if role.ID == name || strings.EqualFold(role.Name, name) {
+			return role
+		}
+	}
+
+	return nil
+}
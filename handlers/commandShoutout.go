@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandShoutout(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	c, options := parseOptions(c)
+
+	if len(c) != 1 {
+		sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+			constants.CommandPrefix+" shoutout <Twitch Channel> [--from <Linked Twitch Channel>]")
+		return
+	}
+
+	t := twitch.GetSession(s)
+	login := resolveTwitchChannel(s, m.GuildID, c[0])
+
+	profile, err := t.GetChannelProfile(login)
+	if err != nil {
+		utils.Log.WithFields(logrus.Fields{
+			"user":           m.Author.Username,
+			"twitch_channel": login,
+			"server_id":      m.GuildID,
+			"error":          err}).Info("Failed to get channel profile.")
+
+		if errors.Is(err, constants.ErrTwitchUserDoesNotExist) {
+			sendTemporaryMessage(s, m.ChannelID, login+" does not exist on Twitch.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, "Error getting channel. Connection to twitch may be down.")
+		}
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Go check out " + utils.SanitizeText(profile.DisplayName) + "!",
+		URL:         "https://www.twitch.tv/" + profile.Login,
+		Description: utils.SanitizeText(profile.Description),
+		Color:       constants.DiscordShoutoutColor,
+		Thumbnail:   &discordgo.MessageEmbedThumbnail{URL: profile.ProfileImageURL},
+	}
+	if profile.GameName != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Last seen playing", Value: utils.SanitizeText(profile.GameName), Inline: true})
+	}
+	if profile.Title != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Last stream", Value: utils.SanitizeText(profile.Title), Inline: true})
+	}
+
+	if _, err := s.ChannelMessageSendEmbed(m.ChannelID, embed); err != nil {
+		utils.Log.WithError(err).Error("Error sending Discord message.")
+		return
+	}
+
+	// Also shout them out on the stream of a linked broadcaster
+	if from := strings.ToLower(options["from"]); from != "" {
+		if err := t.SendShoutout(from, m.GuildID, login); err != nil {
+			utils.Log.WithFields(logrus.Fields{
+				"user":           m.Author.Username,
+				"twitch_channel": from,
+				"server_id":      m.GuildID,
+				"error":          err}).Info("Failed to send Twitch shoutout.")
+
+			if errors.Is(err, constants.ErrBroadcasterNotLinked) {
+				sendTemporaryMessage(s, m.ChannelID, from+" has not linked their Twitch account to this Discord server.")
+			} else {
+				sendTemporaryMessage(s, m.ChannelID, "Error sending the shoutout on Twitch. "+from+" must be live and may need to link again.")
+			}
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":           m.Author.Username,
+			"twitch_channel": from,
+			"server_id":      m.GuildID}).Info("Succeeded in sending Twitch shoutout.")
+	}
+}
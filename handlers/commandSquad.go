@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandSquad(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	t := twitch.GetSession(s)
+
+	if len(c) == 1 && c[0] == "list" {
+		squads := t.GetSquads(m.GuildID)
+
+		names := make([]string, 0, len(squads))
+		for name := range squads {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		listFields := []*discordgo.MessageEmbedField{}
+		for _, name := range names {
+			listFields = append(listFields, &discordgo.MessageEmbedField{
+				Name:   name,
+				Value:  strings.Join(squads[name], ", "),
+				Inline: false,
+			})
+		}
+
+		listEmbed := &discordgo.MessageEmbed{
+			Title:  "This Discord server has the squads",
+			Fields: listFields,
+		}
+
+		if _, err := s.ChannelMessageSendEmbed(m.ChannelID, listEmbed); err != nil {
+			utils.Log.WithError(err).Error("Failed to send message to Discord.")
+		}
+		return
+	} else if len(c) == 2 && c[0] == "remove" {
+		name := strings.ToLower(c[1])
+
+		if err := t.RemoveSquad(m.GuildID, name); err != nil {
+			sendTemporaryMessage(s, m.ChannelID, "The squad "+name+" does not exist.")
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"squad":     name,
+			"server_id": m.GuildID}).Info("Succeeded in removing squad.")
+
+		sendTemporaryMessage(s, m.ChannelID, "The squad "+name+" was removed.")
+		return
+	} else if len(c) >= 4 && c[0] == "set" {
+		name := strings.ToLower(c[1])
+
+		twitchChannels := make([]string, 0, len(c)-2)
+		for _, twitchChannel := range c[2:] {
+			twitchChannels = append(twitchChannels, resolveTwitchChannel(s, m.GuildID, twitchChannel))
+		}
+
+		if err := t.SetSquad(m.GuildID, name, twitchChannels); err != nil {
+			utils.Log.WithFields(logrus.Fields{
+				"user":            m.Author.Username,
+				"squad":           name,
+				"twitch_channels": twitchChannels,
+				"server_id":       m.GuildID,
+				"error":           err}).Info("Failed to set squad.")
+
+			if errors.Is(err, constants.ErrTwitchUserNotRegistered) {
+				sendTemporaryMessage(s, m.ChannelID, "Every Twitch channel of a squad must be added to this Discord server.")
+			} else {
+				sendTemporaryMessage(s, m.ChannelID, "A squad needs at least two Twitch channels.")
+			}
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":            m.Author.Username,
+			"squad":           name,
+			"twitch_channels": twitchChannels,
+			"server_id":       m.GuildID}).Info("Succeeded in setting squad.")
+
+		sendTemporaryMessage(s, m.ChannelID, "When members of the squad "+name+" stream the same game, they are announced together in the channels they are added to.")
+		return
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" squad set <Squad> <Twitch Channel> <Twitch Channel> [...]\n"+
+		constants.CommandPrefix+" squad remove <Squad>\n"+
+		constants.CommandPrefix+" squad list")
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandStreaks(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	t := twitch.GetSession(s)
+
+	if len(c) == 0 {
+		if t.GetStreaks(m.GuildID) {
+			sendTemporaryMessage(s, m.ChannelID, "Announcements celebrate streaks and stream milestones.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, "Announcements don't celebrate streaks and stream milestones.")
+		}
+		return
+	} else if len(c) == 1 && (c[0] == "on" || c[0] == "off") {
+		t.SetStreaks(m.GuildID, c[0] == "on")
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"streaks":   c[0],
+			"server_id": m.GuildID}).Info("Succeeded in setting streaks.")
+
+		if c[0] == "on" {
+			sendTemporaryMessage(s, m.ChannelID, "Announcements now celebrate streaks and stream milestones.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, "Announcements no longer celebrate streaks and stream milestones.")
+		}
+		return
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" streaks [on/off]")
+}
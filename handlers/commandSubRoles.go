@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+var subTiers = map[string]string{
+	"1":   twitch.Tier1,
+	"2":   twitch.Tier2,
+	"3":   twitch.Tier3,
+	"any": twitch.TierAny,
+}
+
+func commandSubRoles(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	if len(c) == 1 && c[0] == "list" {
+		t := twitch.GetSession(s)
+		roles := t.GetSubRoles(m.GuildID)
+
+		if len(roles) == 0 {
+			sendTemporaryMessage(s, m.ChannelID, "No subscriber roles are set in this Discord server.")
+			return
+		}
+
+		lines := []string{}
+		for login, tiers := range roles {
+			for tier, roleID := range tiers {
+				lines = append(lines, login+" "+tierName(tier)+": <@&"+roleID+">")
+			}
+		}
+		sort.Strings(lines)
+
+		sendTemporaryMessage(s, m.ChannelID, "Subscriber roles:\n"+strings.Join(lines, "\n"))
+		return
+	} else if len(c) == 4 && c[0] == "set" && len(m.MentionRoles) == 1 {
+		t := twitch.GetSession(s)
+		login := resolveTwitchChannel(s, m.GuildID, c[1])
+		tier, ok := subTiers[strings.ToLower(c[2])]
+
+		if ok {
+			if err := t.SetSubRole(login, m.GuildID, tier, m.MentionRoles[0]); err != nil {
+				utils.Log.WithFields(logrus.Fields{
+					"user":           m.Author.Username,
+					"twitch_channel": login,
+					"channel_id":     m.ChannelID,
+					"server_id":      m.GuildID,
+					"error":          err}).Info("Failed to set subscriber role.")
+
+				if errors.Is(err, constants.ErrBroadcasterNotLinked) {
+					sendTemporaryMessage(s, m.ChannelID, login+" has not linked their Twitch account to this Discord server.")
+				} else {
+					sendTemporaryMessage(s, m.ChannelID, "Error setting subscriber role. Connection to twitch may be down.")
+				}
+				return
+			}
+
+			utils.Log.WithFields(logrus.Fields{
+				"user":           m.Author.Username,
+				"twitch_channel": login,
+				"channel_id":     m.ChannelID,
+				"server_id":      m.GuildID}).Info("Succeeded in setting subscriber role.")
+
+			sendTemporaryMessage(s, m.ChannelID, "Linked "+tierName(tier)+" subscribers of "+login+" will be given <@&"+m.MentionRoles[0]+">.")
+			return
+		}
+	} else if len(c) == 3 && c[0] == "remove" {
+		t := twitch.GetSession(s)
+		login := resolveTwitchChannel(s, m.GuildID, c[1])
+		tier, ok := subTiers[strings.ToLower(c[2])]
+
+		if ok {
+			if err := t.RemoveSubRole(login, m.GuildID, tier); err != nil {
+				sendTemporaryMessage(s, m.ChannelID, "No role is set for "+tierName(tier)+" subscribers of "+login+".")
+				return
+			}
+
+			sendTemporaryMessage(s, m.ChannelID, tierName(tier)+" subscribers of "+login+" will no longer be given a role.")
+			return
+		}
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" subroles set <Twitch Channel> [1/2/3/any] <@Role>\n"+
+		constants.CommandPrefix+" subroles remove <Twitch Channel> [1/2/3/any]\n"+
+		constants.CommandPrefix+" subroles list")
+}
+
+// Returns a readable name for a subscription tier
+func tierName(tier string) string {
+	switch tier {
+	case twitch.Tier1:
+		return "Tier 1"
+	case twitch.Tier2:
+		return "Tier 2"
+	case twitch.Tier3:
+		return "Tier 3"
+	default:
+		return "All"
+	}
+}
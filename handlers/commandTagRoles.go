@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandTagRoles(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	t := twitch.GetSession(s)
+
+	if len(c) == 1 && c[0] == "list" {
+		roles := t.GetTagRoles(m.GuildID)
+
+		if len(roles) == 0 {
+			sendTemporaryMessage(s, m.ChannelID, "No stream tags mention a role in this Discord server.")
+			return
+		}
+
+		lines := []string{}
+		for tag, roleID := range roles {
+			lines = append(lines, tag+": <@&"+roleID+">")
+		}
+		sort.Strings(lines)
+
+		sendTemporaryMessage(s, m.ChannelID, "Roles mentioned by stream tag:\n"+strings.Join(lines, "\n"))
+		return
+	} else if len(c) == 3 && c[0] == "set" && len(m.MentionRoles) == 1 {
+		if err := t.SetTagRole(m.GuildID, c[1], m.MentionRoles[0]); errors.Is(err, constants.ErrInvalidTag) {
+			sendTemporaryMessage(s, m.ChannelID, "Stream tags are a single word, such as Speedrun.")
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"tag":       c[1],
+			"role_id":   m.MentionRoles[0],
+			"server_id": m.GuildID}).Info("Succeeded in setting tag role.")
+
+		sendTemporaryMessage(s, m.ChannelID, "Streams tagged "+c[1]+" will mention <@&"+m.MentionRoles[0]+"> when they are announced.")
+		return
+	} else if len(c) == 2 && c[0] == "remove" {
+		if err := t.RemoveTagRole(m.GuildID, c[1]); errors.Is(err, constants.ErrTagRoleNotSet) {
+			sendTemporaryMessage(s, m.ChannelID, "No role is mentioned for streams tagged "+c[1]+".")
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"tag":       c[1],
+			"server_id": m.GuildID}).Info("Succeeded in removing tag role.")
+
+		sendTemporaryMessage(s, m.ChannelID, "Streams tagged "+c[1]+" will no longer mention a role.")
+		return
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" tagroles set <Tag> <@Role>\n"+
+		constants.CommandPrefix+" tagroles remove <Tag>\n"+
+		constants.CommandPrefix+" tagroles list")
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandTemplate(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	t := twitch.GetSession(s)
+
+	if len(c) == 2 && c[0] == "list" {
+		twitchChannel := resolveTwitchChannel(s, m.GuildID, c[1])
+
+		templates, mode, err := t.GetTemplates(twitchChannel, m.GuildID, m.ChannelID)
+		if err != nil {
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is not added to this Discord channel.")
+			return
+		} else if len(templates) == 0 {
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+" has no templates of its own and uses the template of its profile.")
+			return
+		}
+
+		listFields := []*discordgo.MessageEmbedField{}
+		for i, template := range templates {
+			listFields = append(listFields, &discordgo.MessageEmbedField{
+				Name:   fmt.Sprint(i + 1),
+				Value:  template,
+				Inline: false,
+			})
+		}
+
+		listEmbed := &discordgo.MessageEmbed{
+			Title:  twitchChannel + " has the templates",
+			Fields: listFields,
+			Footer: &discordgo.MessageEmbedFooter{Text: "Templates are picked in " + mode + " mode"},
+		}
+
+		if _, err := s.ChannelMessageSendEmbed(m.ChannelID, listEmbed); err != nil {
+			utils.Log.WithError(err).Error("Failed to send message to Discord.")
+		}
+		return
+	} else if len(c) == 3 && c[0] == "add" {
+		twitchChannel := resolveTwitchChannel(s, m.GuildID, c[1])
+
+		if err := t.AddTemplate(twitchChannel, m.GuildID, m.ChannelID, c[2]); err != nil {
+			utils.Log.WithFields(logrus.Fields{
+				"user":           m.Author.Username,
+				"twitch_channel": twitchChannel,
+				"channel_id":     m.ChannelID,
+				"server_id":      m.GuildID,
+				"error":          err}).Info("Failed to add template.")
+
+			if errors.Is(err, constants.ErrTooManyTemplates) {
+				sendTemporaryMessage(s, m.ChannelID, fmt.Sprintf("A registration can have up to %v templates.", constants.MaxTemplates))
+			} else {
+				sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is not added to this Discord channel.")
+			}
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":           m.Author.Username,
+			"twitch_channel": twitchChannel,
+			"channel_id":     m.ChannelID,
+			"server_id":      m.GuildID}).Info("Succeeded in adding template.")
+
+		sendTemporaryMessage(s, m.ChannelID, "The template was added to "+twitchChannel+".")
+		return
+	} else if len(c) == 3 && c[0] == "remove" {
+		twitchChannel := resolveTwitchChannel(s, m.GuildID, c[1])
+
+		if n, err := strconv.Atoi(c[2]); err == nil {
+			if err := t.RemoveTemplate(twitchChannel, m.GuildID, m.ChannelID, n); err != nil {
+				if errors.Is(err, constants.ErrTemplateDoesNotExist) {
+					sendTemporaryMessage(s, m.ChannelID, twitchChannel+" has no template "+c[2]+".")
+				} else {
+					sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is not added to this Discord channel.")
+				}
+				return
+			}
+
+			utils.Log.WithFields(logrus.Fields{
+				"user":           m.Author.Username,
+				"twitch_channel": twitchChannel,
+				"channel_id":     m.ChannelID,
+				"server_id":      m.GuildID}).Info("Succeeded in removing template.")
+
+			sendTemporaryMessage(s, m.ChannelID, "Template "+c[2]+" was removed from "+twitchChannel+".")
+			return
+		}
+	} else if len(c) == 3 && c[0] == "mode" && (c[2] == twitch.TemplateRotate || c[2] == twitch.TemplateRandom) {
+		twitchChannel := resolveTwitchChannel(s, m.GuildID, c[1])
+
+		if err := t.SetTemplateMode(twitchChannel, m.GuildID, m.ChannelID, c[2]); err != nil {
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is not added to this Discord channel.")
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":           m.Author.Username,
+			"twitch_channel": twitchChannel,
+			"channel_id":     m.ChannelID,
+			"server_id":      m.GuildID}).Info("Succeeded in setting template mode.")
+
+		if c[2] == twitch.TemplateRandom {
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s announcements will use a random template.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s announcements will use its templates in order.")
+		}
+		return
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" template list <Twitch Channel>\n"+
+		constants.CommandPrefix+" template add <Twitch Channel> \"<Text>\"\n"+
+		constants.CommandPrefix+" template remove <Twitch Channel> <Number>\n"+
+		constants.CommandPrefix+" template mode <Twitch Channel> <rotate/random>")
+}
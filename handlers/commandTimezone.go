@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandTimezone(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	t := twitch.GetSession(s)
+
+	if len(c) == 0 {
+		sendTemporaryMessage(s, m.ChannelID, "Times are shown in "+t.GetTimezone(m.GuildID).String()+".")
+		return
+	} else if len(c) == 2 && c[0] == "set" {
+		if err := t.SetTimezone(m.GuildID, c[1]); err != nil {
+			sendTemporaryMessage(s, m.ChannelID, c[1]+" is not a timezone. Use a name from the IANA timezone database such as Europe/Paris or America/New_York.")
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"timezone":  c[1],
+			"server_id": m.GuildID}).Info("Succeeded in setting timezone.")
+
+		sendTemporaryMessage(s, m.ChannelID, "Times are now shown in "+t.GetTimezone(m.GuildID).String()+" and reports follow its weeks and months.")
+		return
+	} else if len(c) == 1 && c[0] == "reset" {
+		t.SetTimezone(m.GuildID, "")
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"server_id": m.GuildID}).Info("Succeeded in resetting timezone.")
+
+		sendTemporaryMessage(s, m.ChannelID, "Times are now shown in UTC.")
+		return
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" timezone\n"+
+		constants.CommandPrefix+" timezone set <Timezone, e.g. Europe/Paris>\n"+
+		constants.CommandPrefix+" timezone reset")
+}
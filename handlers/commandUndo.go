@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandUndo(s *discordgo.Session, m *discordgo.MessageCreate) {
+	t := twitch.GetSession(s)
+
+	twitchChannel, channelID, err := t.UndoUnregister(m.GuildID)
+	if err != nil {
+		utils.Log.WithFields(logrus.Fields{
+			"user":      m.Author.Username,
+			"server_id": m.GuildID,
+			"error":     err}).Info("Failed to restore channel.")
+
+		if errors.Is(err, constants.ErrTwitchUserRegistered) {
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel has already been added back to <#"+channelID+">.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, "There is no removed Twitch channel to restore.")
+		}
+		return
+	}
+
+	utils.Log.WithFields(logrus.Fields{
+		"user":           m.Author.Username,
+		"twitch_channel": twitchChannel,
+		"channel_id":     channelID,
+		"server_id":      m.GuildID}).Info("Succeeded in restoring channel.")
+
+	sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel successfully restored to <#"+channelID+">.")
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func commandWatch(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	// "for" reads naturally but is optional, e.g. watch shroud for 6h
+	if len(c) == 3 && c[1] == "for" {
+		c = []string{c[0], c[2]}
+	}
+
+	if len(c) == 2 {
+		if duration, err := time.ParseDuration(c[1]); err == nil {
+			twitchChannel, ok := resolveTwitchInput(s, m, c[0])
+			if !ok {
+				return
+			}
+			watchChannel(s, m, twitchChannel, duration)
+			return
+		}
+	}
+
+	sendTemporaryMessage(s, m.ChannelID, "Proper usage is:\n"+
+		constants.CommandPrefix+" watch <Channel> for <Duration, e.g. 6h>")
+}
+
+// Monitors a Twitch channel in the Discord channel the command was sent in until the duration has passed
+func watchChannel(s *discordgo.Session, m *discordgo.MessageCreate, twitchChannel string, duration time.Duration) {
+	t := twitch.GetSession(s)
+
+	until, err := t.WatchChannel(twitchChannel, m.GuildID, m.ChannelID, getChannelName(s, m.ChannelID), duration)
+	if err != nil {
+		utils.Log.WithFields(logrus.Fields{
+			"user":           m.Author.Username,
+			"twitch_channel": twitchChannel,
+			"channel_id":     m.ChannelID,
+			"server_id":      m.GuildID,
+			"error":          err}).Info("Failed to watch channel.")
+
+		if errors.Is(err, constants.ErrInvalidWatchTime) {
+			sendTemporaryMessage(s, m.ChannelID, "Twitch channels can be watched for up to "+formatLongDuration(constants.MaxWatchDuration)+".")
+		} else if errors.Is(err, constants.ErrTwitchUserDoesNotExist) {
+			sendTemporaryMessage(s, m.ChannelID, "The Twitch channel "+twitchChannel+" does not exist.")
+		} else if errors.Is(err, constants.ErrTwitchUserRegistered) {
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is already added to this Discord channel for good.")
+		} else if errors.Is(err, constants.ErrLookupRateLimited) {
+			sendTemporaryMessage(s, m.ChannelID, "Too many Twitch channels are being added right now. Try again in a minute.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, "Error registering channel. Connection to twitch may be down.")
+		}
+		return
+	}
+
+	utils.Log.WithFields(logrus.Fields{
+		"user":           m.Author.Username,
+		"twitch_channel": twitchChannel,
+		"channel_id":     m.ChannelID,
+		"server_id":      m.GuildID,
+		"until":          until}).Info("Succeeded in watching channel.")
+
+	// Streams that are already live can be announced without waiting for the next poll
+	guildID, channelID := m.GuildID, m.ChannelID
+	go func() {
+		if _, err := t.CheckRegisteredChannel(s, twitchChannel, guildID, channelID); err != nil {
+			utils.Log.WithError(err).Error("Failed to check if registered channel is live.")
+		}
+	}()
+
+	sendTemporaryMessage(s, m.ChannelID, fmt.Sprintf("%v's Twitch channel is watched in this Discord channel until <t:%d:f>, then it is removed. "+
+		"Use %v add %v to keep it.", twitchChannel, until.Unix(), constants.CommandPrefix, twitchChannel))
+}
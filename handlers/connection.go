@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/crash"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// discordgo reconnects on its own after the gateway connection drops
+func Disconnect(s *discordgo.Session, event *discordgo.Disconnect) {
+	defer crash.Recover("disconnect")
+
+	utils.Log.Warn("Disconnected from Discord. Reconnecting.")
+}
+
+func Resumed(s *discordgo.Session, event *discordgo.Resumed) {
+	defer crash.Recover("resumed")
+
+	utils.Log.Info("Resumed connection to Discord.")
+	twitch.Resume(s)
+}
+
+// Discord sends Ready when the bot starts and whenever it reconnects with a new gateway session instead of resuming
+func Ready(s *discordgo.Session, event *discordgo.Ready) {
+	defer crash.Recover("ready")
+
+	utils.Log.WithField("session_id", event.SessionID).Info("Connected to Discord as ", event.User.Username, ".")
+	twitch.Resume(s)
+}
@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/crash"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+)
+
+func GuildBanAdd(s *discordgo.Session, event *discordgo.GuildBanAdd) {
+	defer crash.Recover("guild_ban_add")
+
+	if t := twitch.GetSession(s); t != nil {
+		t.HandleDiscordBan(s, event.GuildID, event.User)
+	}
+}
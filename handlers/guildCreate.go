@@ -2,11 +2,14 @@ package handlers
 
 import (
 	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/crash"
 	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
 	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
 )
 
 func GuildCreate(s *discordgo.Session, event *discordgo.GuildCreate) {
+	defer crash.Recover("guild_create")
+
 	if event.Guild.Unavailable {
 		utils.Log.Debugf("Guild %v is unavailable.\n", event.ID)
 		twitch.SetGuildUnavailable(event.ID)
@@ -14,5 +17,15 @@ func GuildCreate(s *discordgo.Session, event *discordgo.GuildCreate) {
 	}
 
 	utils.Log.Debugf("Connected to guild %v.\n", event.ID)
-	twitch.SetGuildActive(event.ID)
+	twitch.SetGuildActive(s, event.ID)
+	syncSlashCommands(s, event.ID)
+
+	// Presences are only sent with the presence intent
+	if t := twitch.GetSession(s); t != nil {
+		for _, presence := range event.Guild.Presences {
+			if presence.User != nil {
+				t.HandlePresence(s, event.ID, presence.User.ID, presence.Activities)
+			}
+		}
+	}
 }
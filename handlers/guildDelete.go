@@ -2,11 +2,14 @@ package handlers
 
 import (
 	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/crash"
 	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
 	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
 )
 
 func GuildDelete(s *discordgo.Session, event *discordgo.GuildDelete) {
+	defer crash.Recover("guild_delete")
+
 	if event.Guild.Unavailable {
 		utils.Log.Debugf("Guild %v is unavailable.\n", event.ID)
 		twitch.SetGuildUnavailable(event.ID)
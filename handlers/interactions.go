@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/config"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/crash"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Interaction types and callback types of the Discord API
+const (
+	interactionApplicationCommand = 2
+	interactionResponseMessage    = 4
+	messageFlagEphemeral          = 64
+)
+
+// The slash command running any bot command, e.g. /twitch command:add shroud
+var slashCommands = []map[string]interface{}{{
+	"name":        "twitch",
+	"description": "Run a bot command",
+	"options": []map[string]interface{}{{
+		"type":        3,
+		"name":        "command",
+		"description": "The command to run, e.g. add <Channel> or help",
+		"required":    true,
+	}},
+}}
+
+// Slash command interaction sent by Discord. The version of discordgo used has no interaction support.
+type interaction struct {
+	ID        string            `json:"id"`
+	Type      int               `json:"type"`
+	Token     string            `json:"token"`
+	GuildID   string            `json:"guild_id"`
+	ChannelID string            `json:"channel_id"`
+	Member    *discordgo.Member `json:"member"`
+	Data      struct {
+		Name    string `json:"name"`
+		Options []struct {
+			Name  string      `json:"name"`
+			Value interface{} `json:"value"`
+		} `json:"options"`
+	} `json:"data"`
+}
+
+// Guilds whose slash commands were registered or removed since the bot started
+var slashCommandGuilds sync.Map
+
+// Runs slash commands as if their text was sent as a prefixed command
+func InteractionCreate(s *discordgo.Session, e *discordgo.Event) {
+	if e.Type != "INTERACTION_CREATE" {
+		return
+	}
+	defer crash.Recover("interaction_create")
+
+	var i interaction
+	if err := json.Unmarshal(e.RawData, &i); err != nil {
+		utils.Log.WithError(err).Error("Failed to decode interaction.")
+		return
+	}
+	if i.Type != interactionApplicationCommand || i.Data.Name != "twitch" || i.GuildID == "" || i.Member == nil || i.Member.User == nil {
+		return
+	}
+
+	text := ""
+	for _, option := range i.Data.Options {
+		if value, ok := option.Value.(string); ok && option.Name == "command" {
+			text = strings.TrimSpace(value)
+		}
+	}
+
+	// Discord shows the command failed unless it is answered within 3 seconds, so it is answered before running
+	_, err := s.RequestWithBucketID("POST", discordgo.EndpointAPI+"interactions/"+i.ID+"/"+i.Token+"/callback", map[string]interface{}{
+		"type": interactionResponseMessage,
+		"data": map[string]interface{}{
+			"content": "Running `" + strings.ReplaceAll(text, "`", "") + "`.",
+			"flags":   messageFlagEphemeral,
+		},
+	}, discordgo.EndpointAPI+"interactions/")
+	if err != nil {
+		utils.Log.WithError(err).Error("Failed to respond to interaction.")
+	}
+
+	i.Member.GuildID = i.GuildID
+	runCommand(s, &discordgo.MessageCreate{Message: &discordgo.Message{
+		Content:   constants.CommandPrefix + " " + text,
+		ChannelID: i.ChannelID,
+		GuildID:   i.GuildID,
+		Author:    i.Member.User,
+		Member:    i.Member,
+	}})
+}
+
+// Registers the slash commands in a guild in interaction only mode, or removes them otherwise.
+// It is done once per guild since the bot started rather than on every reconnect.
+func syncSlashCommands(s *discordgo.Session, guildID string) {
+	if _, done := slashCommandGuilds.LoadOrStore(guildID, true); done {
+		return
+	}
+
+	commands := slashCommands
+	if !config.Settings.InteractionOnly {
+		commands = []map[string]interface{}{}
+	}
+
+	endpoint := discordgo.EndpointApplication(s.State.User.ID) + "/guilds/" + guildID + "/commands"
+	if _, err := s.RequestWithBucketID("PUT", endpoint, commands, endpoint); err != nil {
+		slashCommandGuilds.Delete(guildID)
+
+		// Bots invited without the applications.commands scope cannot have commands, which only matters in interaction only mode
+		entry := utils.Log.WithError(err).WithFields(logrus.Fields{"server_id": guildID})
+		if config.Settings.InteractionOnly {
+			entry.Error("Failed to register slash commands.")
+		} else {
+			entry.Debug("Failed to remove slash commands.")
+		}
+	}
+}
@@ -3,32 +3,82 @@ package handlers
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/config"
 	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/crash"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/plugins"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/stats"
 	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
 	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
 	"github.com/sirupsen/logrus"
 )
 
 func MessageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
+	defer crash.Recover("message_create")
+
 	if m.Author.ID == s.State.User.ID {
 		return
 	}
 
+	// Direct messages answer the setup wizard
+	if m.GuildID == "" && handleSetupReply(s, m) {
+		return
+	}
+
+	// Commands are only accepted as slash commands in interaction only mode
+	if config.Settings.InteractionOnly {
+		return
+	}
+
+	runCommand(s, m)
+}
+
+// Runs the command of a message. Slash commands are run as a message with the text of the command.
+func runCommand(s *discordgo.Session, m *discordgo.MessageCreate) {
 	if strings.HasPrefix(strings.ToLower(m.Content), constants.CommandPrefix) {
 
 		utils.Log.WithFields(logrus.Fields{
 			"user":       m.Author.Username,
-			"command":    m.Content,
+			"command":    loggedCommand(m.Content),
 			"channel_id": m.ChannelID,
 			"server_id":  m.GuildID}).Info("Command recieved.")
+		stats.CommandProcessed()
 
-		commandParams := strings.Split(m.Content, " ")[1:]
+		commandParams := splitCommand(m.Content)[1:]
 
 		if len(commandParams) > 0 {
+			// Direct messages only accept the owner's administrative commands
+			if m.GuildID == "" && (!isUserOwner(m.Author) || !ownerCommands[commandParams[0]]) {
+				return
+			}
+
+			// Handling time and outcome of the command. Commands nothing handles are recorded as unknown.
+			start, command, failed := time.Now(), commandParams[0], false
+			defer func() {
+				r := recover()
+				stats.CommandHandled(command, m.GuildID, time.Since(start), failed || r != nil)
+				if r != nil {
+					crash.Report("message_create", r)
+				}
+			}()
+
+			// Commands that need Twitch are answered with a notice while the session is unavailable
+			if twitch.GetSession(s) == nil && needsTwitch(commandParams[0]) {
+				failed = true
+				utils.Log.WithFields(logrus.Fields{
+					"user":       m.Author.Username,
+					"command":    loggedCommand(m.Content),
+					"channel_id": m.ChannelID,
+					"server_id":  m.GuildID}).Warn("Command received while Twitch is unavailable.")
+				sendTemporaryMessage(s, m.ChannelID, constants.TwitchUnavailableMessage)
+				return
+			}
+
 			switch commandParams[0] {
 			case "channel":
 				go deleteUserMessageWithDelay(s, m, time.Second)
@@ -39,25 +89,457 @@ func MessageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
 					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
 					return
 				}
+			case "profile":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandProfile(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "feature":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserOwner(m.Author) {
+					commandFeature(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "credentials":
+				if isUserOwner(m.Author) {
+					commandCredentials(s, m, commandParams[1:])
+					return
+				} else {
+					go deleteUserMessageWithDelay(s, m, time.Second)
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "status":
+				if isUserOwner(m.Author) {
+					commandStatus(s, m)
+					return
+				} else {
+					go deleteUserMessageWithDelay(s, m, time.Second)
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "guilds":
+				if isUserOwner(m.Author) {
+					commandGuilds(s, m)
+					return
+				} else {
+					go deleteUserMessageWithDelay(s, m, time.Second)
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "broadcast":
+				if isUserOwner(m.Author) {
+					commandBroadcast(s, m, commandParams[1:])
+					return
+				} else {
+					go deleteUserMessageWithDelay(s, m, time.Second)
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "reload":
+				if isUserOwner(m.Author) {
+					commandReload(s, m)
+					return
+				} else {
+					go deleteUserMessageWithDelay(s, m, time.Second)
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "admin":
+				if isUserOwner(m.Author) {
+					commandAdmin(s, m, commandParams[1:])
+					return
+				} else {
+					go deleteUserMessageWithDelay(s, m, time.Second)
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "forget":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserAdmin(s, m.ChannelID, m.Author) {
+					commandForget(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "broadcaster":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				commandBroadcaster(s, m, commandParams[1:])
+				return
+			case "marker":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandMarker(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "rewards":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandRewards(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "polls":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandPolls(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "subroles":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandSubRoles(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "bans":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandBans(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "shoutout":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandShoutout(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "mute":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandMute(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "template":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandTemplate(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "embed":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandEmbed(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "liverole":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandLiveRole(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "discover":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandDiscover(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "mature":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandMature(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "api":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandAPI(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "timezone":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandTimezone(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "hiatus":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandHiatus(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "streaks":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandStreaks(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "tagroles":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandTagRoles(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "watch":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandWatch(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "perms":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandPerms(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "report":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandReport(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "group":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandGroup(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "featured":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandFeatured(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "drops":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandDrops(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "unmute":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandUnmute(s, m)
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "debug":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandDebug(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "color":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandColor(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "setup":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandSetup(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "squad":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandSquad(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "goals":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandGoals(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "alias":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandAlias(s, m, commandParams[1:])
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
+			case "push":
+				// The topic or user key must not stay visible in the channel
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				commandPush(s, m, commandParams[1:])
+				return
+			case "offline":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				commandOffline(s, m, commandParams[1:])
+				return
+			case "account":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				commandAccount(s, m, commandParams[1:])
+				return
+			case "about":
+				commandAbout(s, m)
+				return
+			case "live":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				commandLive(s, m)
+				return
+			case "undo":
+				go deleteUserMessageWithDelay(s, m, time.Second)
+				if isUserMod(s, m.GuildID, m.Member) {
+					commandUndo(s, m)
+					return
+				} else {
+					utils.Log.Info("User ", m.Author.Username, " tried to issue a command without proper permissions.")
+					return
+				}
 			}
+
+			if command, ok := plugins.LookupCommand(commandParams[0]); ok {
+				runPluginCommand(s, m, command, commandParams[1:])
+				return
+			}
+			if runScriptCommand(s, m, commandParams) {
+				return
+			}
+			command = "unknown"
 		}
 
 		utils.Log.WithFields(logrus.Fields{
 			"user":       m.Author.Username,
-			"command":    m.Content,
+			"command":    loggedCommand(m.Content),
 			"channel_id": m.ChannelID,
 			"server_id":  m.GuildID}).Info("Invalid command.")
 	}
 }
 
 func commandChannel(s *discordgo.Session, m *discordgo.MessageCreate, c []string) {
+	c, options := parseOptions(c)
+
 	if len(c) == 1 {
 		switch c[0] {
 		case "list":
 			t := twitch.GetSession(s)
-			mChannels := t.GetMonitoredChannels(m.ChannelID)
 			listFields := []*discordgo.MessageEmbedField{}
 
+			if _, all := options["all"]; all {
+				for _, r := range t.GetGuildRegistrations(m.GuildID) {
+					// Repair registrations made before channel names were stored
+					if r.DiscordChannelName == "" {
+						if r.DiscordChannelName = getChannelName(s, r.DiscordChannelID); r.DiscordChannelName != "" {
+							t.UpdateChannelName(m.GuildID, r.DiscordChannelID, r.DiscordChannelName)
+						}
+					}
+
+					channel := "#" + r.DiscordChannelName
+					if r.DiscordChannelName == "" {
+						channel = "<#" + r.DiscordChannelID + ">"
+					}
+					if !r.WatchUntil.IsZero() {
+						channel += fmt.Sprintf(" until <t:%d:f>", r.WatchUntil.Unix())
+					}
+
+					listFields = append(listFields, &discordgo.MessageEmbedField{
+						Name:   r.DisplayName,
+						Value:  channel,
+						Inline: true,
+					})
+				}
+
+				listEmbed := &discordgo.MessageEmbed{
+					Title:  "This Discord server is monitoring",
+					Fields: listFields,
+				}
+
+				_, err := s.ChannelMessageSendEmbed(m.ChannelID, listEmbed)
+				if err != nil {
+					utils.Log.WithError(err).Error("Failed to send message to Discord.")
+				}
+				return
+			}
+
+			mChannels := t.GetMonitoredChannels(m.ChannelID)
+
 			for i, channel := range mChannels {
 				listField := &discordgo.MessageEmbedField{
 					Name:   "Channel " + fmt.Sprint(i+1),
@@ -78,100 +560,430 @@ func commandChannel(s *discordgo.Session, m *discordgo.MessageCreate, c []string
 				utils.Log.WithError(err).Error("Failed to send message to Discord.")
 			}
 			return
+		case "clear":
+			requestClear(s, m, false)
+			return
 		default:
 		}
+	} else if len(c) == 2 && c[0] == "clear" && c[1] == "server" {
+		requestClear(s, m, true)
+		return
 	} else if len(c) == 2 {
 		switch c[0] {
 		case "add":
+			profile := options["profile"]
+			if profile != "" && !twitch.GetSession(s).HasProfile(m.GuildID, profile) {
+				sendTemporaryMessage(s, m.ChannelID, "The profile "+profile+" does not exist.")
+				return
+			}
+
+			twitchChannel, ok := resolveTwitchInput(s, m, c[1])
+			if !ok {
+				return
+			}
+
+			addChannel(s, m, twitchChannel, m.ChannelID, options)
+			return
+		case "remove":
 			t := twitch.GetSession(s)
-			twitchChannel := strings.ToLower(c[1])
+			twitchChannel, ok := resolveTwitchInput(s, m, c[1])
+			if !ok {
+				return
+			}
 
-			if err := t.RegisterChannel(twitchChannel, m.GuildID, m.ChannelID); err != nil {
+			if t.UnregisterChannel(twitchChannel, m.GuildID, m.ChannelID) {
 				utils.Log.WithFields(logrus.Fields{
 					"user":           m.Author.Username,
 					"twitch_channel": twitchChannel,
 					"channel_id":     m.ChannelID,
-					"server_id":      m.GuildID,
-					"error":          err}).Info("Failed to register channel.")
-
-				if errors.Is(err, constants.ErrTwitchUserDoesNotExist) {
-					m, err := s.ChannelMessageSend(m.ChannelID, "The Twitch channel "+twitchChannel+" does not exist.")
-					if err != nil {
-						utils.Log.WithError(err).Error("Failed to send message to Discord.")
-					} else {
-						go deleteBotMessageWithDelay(s, m, constants.DiscordMessageDeleteDelay)
-					}
-				} else if errors.Is(err, constants.ErrTwitchUserRegistered) {
-					m, err := s.ChannelMessageSend(m.ChannelID, twitchChannel+"'s Twitch channel is already added to this Discord channel.")
-					if err != nil {
-						utils.Log.WithError(err).Error("Failed to send message to Discord.")
-					} else {
-						go deleteBotMessageWithDelay(s, m, constants.DiscordMessageDeleteDelay)
-					}
+					"server_id":      m.GuildID}).Info("Succeeded in unregistering channel.")
+
+				m, err := s.ChannelMessageSend(m.ChannelID, twitchChannel+"'s Twitch channel successfully removed from this Discord channel. Use "+constants.CommandPrefix+" undo within "+constants.UnregisterUndoTime.String()+" to restore it.")
+				if err != nil {
+					utils.Log.WithError(err).Error("Failed to send message to Discord.")
 				} else {
-					m, err := s.ChannelMessageSend(m.ChannelID, "Error registering channel. Connection to twitch may be down.")
-					if err != nil {
-						utils.Log.WithError(err).Error("Failed to send message to Discord.")
-					} else {
-						go deleteBotMessageWithDelay(s, m, constants.DiscordMessageDeleteDelay)
-					}
+					deleteBotMessageWithDelay(s, m, constants.DiscordMessageDeleteDelay)
 				}
 			} else {
 				utils.Log.WithFields(logrus.Fields{
 					"user":           m.Author.Username,
 					"twitch_channel": twitchChannel,
 					"channel_id":     m.ChannelID,
-					"server_id":      m.GuildID}).Info("Succeeded in registering channel.")
+					"server_id":      m.GuildID}).Info("Failed to unregister channel.")
 
-				m, err := s.ChannelMessageSend(m.ChannelID, twitchChannel+"'s Twitch channel successfully added to this Discord channel.")
+				m, err := s.ChannelMessageSend(m.ChannelID, twitchChannel+"'s Twitch channel is not added to this Discord channel.")
 				if err != nil {
 					utils.Log.WithError(err).Error("Failed to send message to Discord.")
 				} else {
 					go deleteBotMessageWithDelay(s, m, constants.DiscordMessageDeleteDelay)
 				}
+
 			}
 			return
-		case "remove":
+		case "pause":
+			pauseChannel(s, m, resolveTwitchChannel(s, m.GuildID, c[1]), 0)
+			return
+		case "refresh":
 			t := twitch.GetSession(s)
-			twitchChannel := strings.ToLower(c[1])
+			twitchChannel := resolveTwitchChannel(s, m.GuildID, c[1])
 
-			if t.UnregisterChannel(twitchChannel, m.GuildID, m.ChannelID) {
+			displayName, err := t.RefreshChannel(twitchChannel)
+			if err != nil {
 				utils.Log.WithFields(logrus.Fields{
 					"user":           m.Author.Username,
 					"twitch_channel": twitchChannel,
 					"channel_id":     m.ChannelID,
-					"server_id":      m.GuildID}).Info("Succeeded in unregistering channel.")
+					"server_id":      m.GuildID,
+					"error":          err}).Info("Failed to refresh channel.")
 
-				m, err := s.ChannelMessageSend(m.ChannelID, twitchChannel+"'s Twitch channel successfully removed from this Discord channel.")
-				if err != nil {
-					utils.Log.WithError(err).Error("Failed to send message to Discord.")
+				if errors.Is(err, constants.ErrTwitchUserNotRegistered) {
+					sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is not monitored by the bot.")
+				} else if errors.Is(err, constants.ErrTwitchUserDoesNotExist) {
+					sendTemporaryMessage(s, m.ChannelID, "The Twitch channel "+twitchChannel+" no longer exists.")
 				} else {
-					deleteBotMessageWithDelay(s, m, constants.DiscordMessageDeleteDelay)
+					sendTemporaryMessage(s, m.ChannelID, "Error refreshing channel. Connection to twitch may be down.")
 				}
+				return
+			}
+
+			utils.Log.WithFields(logrus.Fields{
+				"user":           m.Author.Username,
+				"twitch_channel": twitchChannel,
+				"channel_id":     m.ChannelID,
+				"server_id":      m.GuildID}).Info("Succeeded in refreshing channel.")
+
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s display name, logo, offline banner and description were refreshed. It is now shown as "+displayName+".")
+			return
+		case "resume":
+			t := twitch.GetSession(s)
+			twitchChannel := resolveTwitchChannel(s, m.GuildID, c[1])
+
+			if err := t.ResumeChannel(twitchChannel, m.GuildID, m.ChannelID); err != nil {
+				if errors.Is(err, constants.ErrNotPaused) {
+					sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s announcements are not paused.")
+				} else {
+					sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is not added to this Discord channel.")
+				}
+				return
+			}
+
+			utils.Log.WithFields(logrus.Fields{
+				"user":           m.Author.Username,
+				"twitch_channel": twitchChannel,
+				"channel_id":     m.ChannelID,
+				"server_id":      m.GuildID}).Info("Succeeded in resuming channel.")
+
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s announcements are resumed.")
+			return
+		default:
+		}
+	} else if len(c) >= 3 && c[0] == "add" {
+		var channels []*discordgo.Channel
+		for _, name := range c[2:] {
+			channel := findGuildChannel(s, m.GuildID, name)
+			if channel == nil {
+				sendTemporaryMessage(s, m.ChannelID, "I couldn't find a text channel called "+name+" in this Discord server.")
+				return
+			}
+			channels = append(channels, channel)
+		}
+
+		profile := options["profile"]
+		if profile != "" && !twitch.GetSession(s).HasProfile(m.GuildID, profile) {
+			sendTemporaryMessage(s, m.ChannelID, "The profile "+profile+" does not exist.")
+			return
+		}
+
+		twitchChannel, ok := resolveTwitchInput(s, m, c[1])
+		if !ok {
+			return
+		}
+		for _, channel := range channels {
+			addChannel(s, m, twitchChannel, channel.ID, options)
+		}
+		return
+	} else if len(c) == 3 && c[0] == "copy" {
+		t := twitch.GetSession(s)
+		twitchChannel := resolveTwitchChannel(s, m.GuildID, c[1])
+
+		channel := findGuildChannel(s, m.GuildID, c[2])
+		if channel == nil {
+			sendTemporaryMessage(s, m.ChannelID, "I couldn't find a text channel called "+c[2]+" in this Discord server.")
+			return
+		}
+
+		if err := t.CopyRegistration(twitchChannel, m.GuildID, m.ChannelID, channel.ID, channel.Name); err != nil {
+			utils.Log.WithFields(logrus.Fields{
+				"user":           m.Author.Username,
+				"twitch_channel": twitchChannel,
+				"channel_id":     m.ChannelID,
+				"server_id":      m.GuildID,
+				"error":          err}).Info("Failed to copy registration.")
+
+			if errors.Is(err, constants.ErrTwitchUserRegistered) {
+				sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is already added to <#"+channel.ID+">.")
 			} else {
+				sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is not added to this Discord channel.")
+			}
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":           m.Author.Username,
+			"twitch_channel": twitchChannel,
+			"channel_id":     channel.ID,
+			"server_id":      m.GuildID}).Info("Succeeded in copying registration.")
+
+		sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel was added to <#"+channel.ID+"> with the settings of this Discord channel.")
+		return
+	} else if len(c) == 3 && c[0] == "pause" {
+		if duration, err := time.ParseDuration(c[2]); err == nil && duration > 0 {
+			pauseChannel(s, m, resolveTwitchChannel(s, m.GuildID, c[1]), duration)
+			return
+		}
+	} else if len(c) == 3 && c[0] == "remind" {
+		t := twitch.GetSession(s)
+		twitchChannel := resolveTwitchChannel(s, m.GuildID, c[1])
+
+		hours, err := strconv.Atoi(c[2])
+		if c[2] == "off" || (err == nil && hours > 0) {
+			if err := t.SetReminderInterval(twitchChannel, m.GuildID, m.ChannelID, time.Duration(hours)*time.Hour); err != nil {
 				utils.Log.WithFields(logrus.Fields{
 					"user":           m.Author.Username,
 					"twitch_channel": twitchChannel,
 					"channel_id":     m.ChannelID,
-					"server_id":      m.GuildID}).Info("Failed to unregister channel.")
+					"server_id":      m.GuildID,
+					"error":          err}).Info("Failed to set still live reminders.")
 
-				m, err := s.ChannelMessageSend(m.ChannelID, twitchChannel+"'s Twitch channel is not added to this Discord channel.")
-				if err != nil {
-					utils.Log.WithError(err).Error("Failed to send message to Discord.")
+				if errors.Is(err, constants.ErrReminderTooFrequent) {
+					sendTemporaryMessage(s, m.ChannelID, "Reminders can be sent at most every "+constants.MinReminderInterval.String()+".")
 				} else {
-					go deleteBotMessageWithDelay(s, m, constants.DiscordMessageDeleteDelay)
+					sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is not added to this Discord channel.")
 				}
+				return
+			}
 
+			if hours == 0 {
+				sendTemporaryMessage(s, m.ChannelID, "Still live reminders for "+twitchChannel+" are turned off.")
+			} else {
+				sendTemporaryMessage(s, m.ChannelID, fmt.Sprintf("A reminder will be posted every %vh while %v is live, up to %v times per stream.",
+					hours, twitchChannel, constants.MaxStreamReminders))
 			}
 			return
-		default:
+		}
+	} else if len(c) == 3 && c[0] == "group" {
+		t := twitch.GetSession(s)
+		twitchChannel := resolveTwitchChannel(s, m.GuildID, c[1])
+
+		group := strings.ToLower(c[2])
+		if group == "none" {
+			group = ""
+		}
+
+		if err := t.SetChannelGroup(twitchChannel, m.GuildID, m.ChannelID, group); err != nil {
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is not added to this Discord channel.")
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":           m.Author.Username,
+			"twitch_channel": twitchChannel,
+			"group":          group,
+			"channel_id":     m.ChannelID,
+			"server_id":      m.GuildID}).Info("Succeeded in setting channel group.")
+
+		if group == "" {
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+" is no longer in a group.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+" is now in the group "+group+".")
+		}
+		return
+	} else if len(c) == 3 && c[0] == "pin" && (c[2] == "on" || c[2] == "off") {
+		t := twitch.GetSession(s)
+		twitchChannel := resolveTwitchChannel(s, m.GuildID, c[1])
+
+		if err := t.SetChannelPin(twitchChannel, m.GuildID, m.ChannelID, c[2] == "on"); err != nil {
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is not added to this Discord channel.")
+			return
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"user":           m.Author.Username,
+			"twitch_channel": twitchChannel,
+			"channel_id":     m.ChannelID,
+			"server_id":      m.GuildID}).Info("Succeeded in setting live message pinning.")
+
+		if c[2] == "on" {
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s live messages will be pinned while the stream is live.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s live messages will no longer be pinned.")
+		}
+		return
+	} else if len(c) == 3 && c[0] == "delay" {
+		t := twitch.GetSession(s)
+		twitchChannel := resolveTwitchChannel(s, m.GuildID, c[1])
+
+		minutes, err := strconv.Atoi(c[2])
+		if c[2] == "off" || (err == nil && minutes >= 0) {
+			if err := t.SetAnnounceDelay(twitchChannel, m.GuildID, m.ChannelID, time.Duration(minutes)*time.Minute); err != nil {
+				utils.Log.WithFields(logrus.Fields{
+					"user":           m.Author.Username,
+					"twitch_channel": twitchChannel,
+					"channel_id":     m.ChannelID,
+					"server_id":      m.GuildID,
+					"error":          err}).Info("Failed to set announcement delay.")
+
+				if errors.Is(err, constants.ErrInvalidAnnounceDelay) {
+					sendTemporaryMessage(s, m.ChannelID, "Announcements can be delayed by at most "+constants.MaxAnnounceDelay.String()+".")
+				} else {
+					sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is not added to this Discord channel.")
+				}
+				return
+			}
+
+			if minutes == 0 {
+				sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s streams will be announced right away.")
+			} else {
+				sendTemporaryMessage(s, m.ChannelID, fmt.Sprintf("%v's streams will be announced %v minutes after they start.", twitchChannel, minutes))
+			}
+			return
+		}
+	} else if len(c) == 4 && c[0] == "debounce" {
+		live, liveErr := parseDebounce(c[2])
+		offline, offlineErr := parseDebounce(c[3])
+		if liveErr == nil && offlineErr == nil {
+			t := twitch.GetSession(s)
+			twitchChannel := resolveTwitchChannel(s, m.GuildID, c[1])
+
+			if err := t.SetChannelDebounce(twitchChannel, m.GuildID, m.ChannelID, live, offline); err != nil {
+				utils.Log.WithFields(logrus.Fields{
+					"user":           m.Author.Username,
+					"twitch_channel": twitchChannel,
+					"channel_id":     m.ChannelID,
+					"server_id":      m.GuildID,
+					"error":          err}).Info("Failed to set debounce.")
+
+				if errors.Is(err, constants.ErrInvalidDebounce) {
+					sendTemporaryMessage(s, m.ChannelID, "Debounces can be at most "+constants.MaxDebounce.String()+".")
+				} else {
+					sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is not added to this Discord channel.")
+				}
+				return
+			}
+
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s streams will be announced after they are live for "+describeDebounce(live)+
+				" and ended after they are offline for "+describeDebounce(offline)+".")
+			return
 		}
 	}
 
-	mes, err := s.ChannelMessageSend(m.ChannelID, "Proper usage is:\n"+constants.CommandPrefix+" channel list\n"+constants.CommandPrefix+" channel [add/remove] <Twitch Channel>")
+	mes, err := s.ChannelMessageSend(m.ChannelID, "Proper usage is:\n"+constants.CommandPrefix+" channel list [--all]\n"+constants.CommandPrefix+" channel add <Twitch Channel> [#Discord Channel...] [--profile <Profile>] [--group <Group>]\n"+constants.CommandPrefix+" channel copy <Twitch Channel> <#Discord Channel>\n"+constants.CommandPrefix+" channel remove <Twitch Channel>\n"+constants.CommandPrefix+" channel clear [server]\n"+constants.CommandPrefix+" channel remind <Twitch Channel> <Hours/off>\n"+constants.CommandPrefix+" channel delay <Twitch Channel> <Minutes/off>\n"+constants.CommandPrefix+" channel debounce <Twitch Channel> <Live, e.g. 30s/default> <Offline, e.g. 10m/default>\n"+constants.CommandPrefix+" channel pin <Twitch Channel> <on/off>\n"+constants.CommandPrefix+" channel pause <Twitch Channel> [Duration, e.g. 72h]\n"+constants.CommandPrefix+" channel resume <Twitch Channel>\n"+constants.CommandPrefix+" channel refresh <Twitch Channel>\n"+constants.CommandPrefix+" channel group <Twitch Channel> <Group/none>")
 	if err != nil {
 		utils.Log.WithError(err).Error("Failed to send message to Discord.")
 	} else {
 		go deleteBotMessageWithDelay(s, mes, constants.DiscordMessageDeleteDelay)
 	}
 }
+
+// Pauses announcements of a registration in the current channel, until it is resumed if duration is 0
+func pauseChannel(s *discordgo.Session, m *discordgo.MessageCreate, twitchChannel string, duration time.Duration) {
+	t := twitch.GetSession(s)
+
+	if err := t.PauseChannel(twitchChannel, m.GuildID, m.ChannelID, duration); err != nil {
+		sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is not added to this Discord channel.")
+		return
+	}
+
+	utils.Log.WithFields(logrus.Fields{
+		"user":           m.Author.Username,
+		"twitch_channel": twitchChannel,
+		"channel_id":     m.ChannelID,
+		"server_id":      m.GuildID}).Info("Succeeded in pausing channel.")
+
+	if duration == 0 {
+		sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s announcements are paused until "+constants.CommandPrefix+" channel resume "+twitchChannel+" is used.")
+	} else {
+		sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s announcements are paused for "+formatLongDuration(duration)+".")
+	}
+}
+
+// Parses the debounce argument of a command. "default" is returned as 0.
+func parseDebounce(arg string) (time.Duration, error) {
+	if arg == "default" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(arg)
+	if err == nil && d <= 0 {
+		err = constants.ErrInvalidDebounce
+	}
+	return d, err
+}
+
+// Describes the debounce of a registration, 0 meaning the default
+func describeDebounce(d time.Duration) string {
+	if d == 0 {
+		return "the default time"
+	}
+	return d.String()
+}
+
+// Registers a Twitch channel to the Discord channel channelID, which may differ from the channel the command was sent in
+func addChannel(s *discordgo.Session, m *discordgo.MessageCreate, twitchChannel string, channelID string, options map[string]string) {
+	t := twitch.GetSession(s)
+	target := describeChannel(m, channelID)
+
+	if err := t.RegisterChannel(twitchChannel, m.GuildID, channelID, getChannelName(s, channelID)); err != nil {
+		utils.Log.WithFields(logrus.Fields{
+			"user":           m.Author.Username,
+			"twitch_channel": twitchChannel,
+			"channel_id":     channelID,
+			"server_id":      m.GuildID,
+			"error":          err}).Info("Failed to register channel.")
+
+		if errors.Is(err, constants.ErrTwitchUserDoesNotExist) {
+			sendTemporaryMessage(s, m.ChannelID, "The Twitch channel "+twitchChannel+" does not exist.")
+		} else if errors.Is(err, constants.ErrTwitchUserRegistered) {
+			sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel is already added to "+target+".")
+		} else if errors.Is(err, constants.ErrLookupRateLimited) {
+			sendTemporaryMessage(s, m.ChannelID, "Too many Twitch channels are being added right now. Try again in a minute.")
+		} else {
+			sendTemporaryMessage(s, m.ChannelID, "Error registering channel. Connection to twitch may be down.")
+		}
+		return
+	}
+
+	utils.Log.WithFields(logrus.Fields{
+		"user":           m.Author.Username,
+		"twitch_channel": twitchChannel,
+		"channel_id":     channelID,
+		"server_id":      m.GuildID}).Info("Succeeded in registering channel.")
+
+	if profile := options["profile"]; profile != "" {
+		if err := t.SetChannelProfile(twitchChannel, m.GuildID, channelID, profile); err != nil {
+			utils.Log.WithError(err).Error("Failed to apply profile to channel.")
+		}
+	}
+	if group := options["group"]; group != "" {
+		if err := t.SetChannelGroup(twitchChannel, m.GuildID, channelID, group); err != nil {
+			utils.Log.WithError(err).Error("Failed to tag channel with group.")
+		}
+	}
+
+	// Streams that are already live can be announced without waiting for the next poll
+	guildID := m.GuildID
+	go func() {
+		if _, err := t.CheckRegisteredChannel(s, twitchChannel, guildID, channelID); err != nil {
+			utils.Log.WithError(err).Error("Failed to check if registered channel is live.")
+		}
+	}()
+
+	sendTemporaryMessage(s, m.ChannelID, twitchChannel+"'s Twitch channel successfully added to "+target+".")
+}
+
+// Returns how replies refer to a Discord channel, which is usually the one the command was sent in
+func describeChannel(m *discordgo.MessageCreate, channelID string) string {
+	if channelID == m.ChannelID {
+		return "this Discord channel"
+	}
+	return "<#" + channelID + ">"
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/crash"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func MessageReactionAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	defer crash.Recover("message_reaction_add")
+
+	pick := numberEmojiIndex(r.Emoji.Name)
+	if r.UserID == s.State.User.ID || (r.Emoji.Name != constants.ConfirmEmoji && pick < 0) || r.GuildID == "" {
+		return
+	}
+
+	// Only moderators can confirm synced bans, announcements and clears or add discovered channels
+	member, err := s.GuildMember(r.GuildID, r.UserID)
+	if err != nil || !isUserMod(s, r.GuildID, member) {
+		return
+	}
+
+	if pick >= 0 {
+		pickDiscovery(s, r, member, pick)
+		return
+	}
+	if confirmClear(s, r, member) {
+		return
+	}
+
+	t := twitch.GetSession(s)
+	if t == nil {
+		return
+	}
+
+	if handled, err := t.ConfirmAnnouncement(s, r.MessageID); handled {
+		if err != nil {
+			utils.Log.WithFields(logrus.Fields{
+				"user":       member.User.Username,
+				"channel_id": r.ChannelID,
+				"server_id":  r.GuildID,
+				"error":      err}).Info("Failed to announce stream.")
+		}
+		return
+	}
+
+	if handled, err := t.ConfirmBan(s, r.MessageID, member.User.Username); handled && err != nil {
+		utils.Log.WithFields(logrus.Fields{
+			"user":       member.User.Username,
+			"channel_id": r.ChannelID,
+			"server_id":  r.GuildID,
+			"error":      err}).Info("Failed to apply synced ban.")
+
+		sendTemporaryMessage(s, r.ChannelID, "Error applying the ban. The bot or broadcaster may be missing permissions.")
+	}
+}
@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/crash"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+)
+
+func PresenceUpdate(s *discordgo.Session, event *discordgo.PresenceUpdate) {
+	defer crash.Recover("presence_update")
+
+	if t := twitch.GetSession(s); t != nil && event.User != nil {
+		t.HandlePresence(s, event.GuildID, event.User.ID, event.Activities)
+	}
+}
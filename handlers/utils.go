@@ -1,15 +1,37 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/config"
 	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
 	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
 	"github.com/sirupsen/logrus"
 )
 
+// Returns whether the user is the bot owner set in the config file
+func isUserOwner(user *discordgo.User) bool {
+	return config.Settings.OwnerID != "" && user.ID == config.Settings.OwnerID
+}
+
+// Returns whether the user has the Administrator permission in the channel's guild
+func isUserAdmin(ds *discordgo.Session, channelID string, user *discordgo.User) bool {
+	perms, err := ds.UserChannelPermissions(user.ID, channelID)
+	if err != nil {
+		utils.Log.WithFields(logrus.Fields{"error": err}).Error("Failed to get permissions of user.")
+		return false
+	}
+
+	return perms&discordgo.PermissionAdministrator != 0
+}
+
 func isUserMod(ds *discordgo.Session, guildID string, user *discordgo.Member) bool {
 	modID := getModRoleID(ds, guildID)
 
@@ -50,8 +72,170 @@ func deleteBotMessageWithDelay(s *discordgo.Session, m *discordgo.Message, t tim
 }
 
 func deleteUserMessageWithDelay(s *discordgo.Session, m *discordgo.MessageCreate, t time.Duration) {
+	// Slash commands have no message to delete
+	if m.ID == "" {
+		return
+	}
+
 	time.Sleep(t)
 	if err := s.ChannelMessageDelete(m.ChannelID, m.ID); err != nil {
 		utils.Log.WithError(err).Error("Failed to delete Discord message.")
 	}
 }
+
+// Returns the name of a Discord channel, or an empty string if the channel can't be found
+func getChannelName(s *discordgo.Session, channelID string) string {
+	if channel, err := s.State.Channel(channelID); err == nil {
+		return channel.Name
+	}
+
+	channel, err := s.Channel(channelID)
+	if err != nil {
+		utils.Log.WithError(err).Error("Failed to get channel from Discord.")
+		return ""
+	}
+
+	return channel.Name
+}
+
+// Sends a message to a Discord channel and deletes it after DiscordMessageDeleteDelay
+func sendTemporaryMessage(s *discordgo.Session, channelID string, content string) {
+	if !utils.StorageHealthy() {
+		content += "\n⚠️ " + constants.StorageUnhealthyMessage
+	}
+
+	m, err := s.ChannelMessageSend(channelID, content)
+	if err != nil {
+		utils.Log.WithError(err).Error("Failed to send message to Discord.")
+	} else {
+		go deleteBotMessageWithDelay(s, m, constants.DiscordMessageDeleteDelay)
+	}
+}
+
+// Sends a direct message to a Discord user
+func sendDirectMessage(s *discordgo.Session, userID string, content string) error {
+	channel, err := s.UserChannelCreate(userID)
+	if err != nil {
+		utils.Log.WithError(err).Error("Failed to open direct message channel.")
+		return err
+	}
+
+	if _, err := s.ChannelMessageSend(channel.ID, content); err != nil {
+		utils.Log.WithError(err).Error("Failed to send direct message to Discord.")
+		return err
+	}
+
+	return nil
+}
+
+// Formats a number of seconds as h:mm:ss
+func formatSeconds(seconds int) string {
+	return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+// Commands whose arguments are secrets, which are left out of the logs
+var secretCommands = map[string]bool{"credentials": true}
+
+// Returns a command as it is logged, with the arguments of commands taking secrets redacted
+func loggedCommand(content string) string {
+	params := splitCommand(content)
+	if len(params) > 2 && secretCommands[strings.ToLower(params[1])] {
+		return strings.Join(params[:2], " ") + " [redacted]"
+	}
+	return content
+}
+
+// Splits a command on whitespace, keeping text surrounded by double quotes together
+func splitCommand(content string) []string {
+	var (
+		params   []string
+		current  strings.Builder
+		inQuotes bool
+		inParam  bool
+	)
+
+	for _, r := range content {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			inParam = true
+		case unicode.IsSpace(r) && !inQuotes:
+			if inParam {
+				params = append(params, current.String())
+				current.Reset()
+				inParam = false
+			}
+		default:
+			current.WriteRune(r)
+			inParam = true
+		}
+	}
+
+	if inParam {
+		params = append(params, current.String())
+	}
+
+	return params
+}
+
+// Separates --name value options from the positional parameters of a command
+func parseOptions(params []string) ([]string, map[string]string) {
+	positional := []string{}
+	options := make(map[string]string)
+
+	for i := 0; i < len(params); i++ {
+		if strings.HasPrefix(params[i], "--") {
+			name := strings.ToLower(strings.TrimPrefix(params[i], "--"))
+			if i+1 < len(params) && !strings.HasPrefix(params[i+1], "--") {
+				options[name] = params[i+1]
+				i++
+			} else {
+				options[name] = ""
+			}
+		} else {
+			positional = append(positional, params[i])
+		}
+	}
+
+	return positional, options
+}
+
+// Returns the twitch channel a command parameter refers to, resolving the guild's aliases
+func resolveTwitchChannel(s *discordgo.Session, guildID string, name string) string {
+	return twitch.GetSession(s).ResolveAlias(guildID, name)
+}
+
+// Returns the twitch channel an add or remove command refers to, accepting aliases, twitch.tv URLs and numeric user IDs.
+// Replies with the reason and returns false if the channel cannot be resolved.
+func resolveTwitchInput(s *discordgo.Session, m *discordgo.MessageCreate, name string) (string, bool) {
+	twitchChannel, err := twitch.GetSession(s).ResolveChannel(resolveTwitchChannel(s, m.GuildID, name))
+	if err == nil {
+		return twitchChannel, true
+	}
+
+	utils.Log.WithFields(logrus.Fields{
+		"user":       m.Author.Username,
+		"input":      name,
+		"channel_id": m.ChannelID,
+		"server_id":  m.GuildID,
+		"error":      err}).Info("Failed to resolve twitch channel.")
+
+	if errors.Is(err, constants.ErrInvalidTwitchChannel) {
+		sendTemporaryMessage(s, m.ChannelID, name+" is not a Twitch channel. Use a login such as shroud, a link such as https://twitch.tv/shroud or a numeric user ID.")
+	} else if errors.Is(err, constants.ErrLookupRateLimited) {
+		sendTemporaryMessage(s, m.ChannelID, "Too many Twitch channels are being looked up right now. Try again in a minute.")
+	} else {
+		sendTemporaryMessage(s, m.ChannelID, "Error looking up the Twitch user ID "+name+". Connection to twitch may be down.")
+	}
+	return "", false
+}
+
+// Parses a color written as hex, with or without a leading #
+func parseHexColor(color string) (int, error) {
+	c, err := strconv.ParseInt(strings.TrimPrefix(color, "#"), 16, 32)
+	if err != nil || c < 0 || c > 0xffffff {
+		return 0, fmt.Errorf("%v is not a hex color", color)
+	}
+
+	return int(c), nil
+}
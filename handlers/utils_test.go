@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := map[string][]string{
+		"!twitch add shroud":                      {"!twitch", "add", "shroud"},
+		"  !twitch   add\tshroud ":                {"!twitch", "add", "shroud"},
+		`!twitch offline shroud message "Bye!"`:   {"!twitch", "offline", "shroud", "message", "Bye!"},
+		`!twitch template add "Live now: {name}"`: {"!twitch", "template", "add", "Live now: {name}"},
+		"": nil,
+	}
+	for content, want := range tests {
+		if got := splitCommand(content); !reflect.DeepEqual(got, want) {
+			t.Errorf("splitCommand(%q) = %q, want %q", content, got, want)
+		}
+	}
+}
+
+func TestLoggedCommand(t *testing.T) {
+	tests := map[string]string{
+		"!twitch credentials abc123 s3cr3t":    "!twitch credentials [redacted]",
+		"!twitch Credentials abc123 s3cr3t":    "!twitch Credentials [redacted]",
+		`!twitch credentials "abc 123" s3cr3t`: "!twitch credentials [redacted]",
+		"!twitch credentials":                  "!twitch credentials",
+		"!twitch add shroud":                   "!twitch add shroud",
+	}
+	for content, want := range tests {
+		if got := loggedCommand(content); got != want {
+			t.Errorf("loggedCommand(%q) = %q, want %q", content, got, want)
+		}
+	}
+}
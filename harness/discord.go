@@ -0,0 +1,104 @@
+package harness
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Message the bot sent to the fake Discord server
+type Message struct {
+	ID        string
+	ChannelID string
+	Content   string
+	Embed     *discordgo.MessageEmbed
+	Edits     int // Number of times the bot edited the message
+}
+
+// Fake Discord REST API recording the messages the bot sends and edits. Endpoints it does not know answer with an empty object.
+type FakeDiscord struct {
+	mu       sync.Mutex
+	messages []*Message
+	nextID   int
+}
+
+func NewFakeDiscord() *FakeDiscord {
+	return &FakeDiscord{}
+}
+
+// Returns copies of the messages sent to a Discord channel, oldest first
+func (f *FakeDiscord) Messages(channelID string) []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	messages := []Message{}
+	for _, m := range f.messages {
+		if m.ChannelID == channelID {
+			messages = append(messages, *m)
+		}
+	}
+	return messages
+}
+
+func (f *FakeDiscord) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// Paths look like /api/v8/channels/<Channel ID>/messages/<Message ID>
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 4 || parts[2] != "channels" {
+		writeJSON(w, map[string]interface{}{})
+		return
+	}
+	channelID := parts[3]
+
+	switch {
+	case len(parts) == 4 && r.Method == http.MethodGet:
+		writeJSON(w, &discordgo.Channel{ID: channelID, Type: discordgo.ChannelTypeGuildText})
+	case len(parts) == 5 && parts[4] == "messages" && r.Method == http.MethodPost:
+		var data discordgo.MessageSend
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.nextID++
+		m := &Message{ID: fakeID(f.nextID), ChannelID: channelID, Content: data.Content, Embed: data.Embed}
+		f.messages = append(f.messages, m)
+		writeJSON(w, m.discordMessage())
+	case len(parts) == 6 && parts[4] == "messages" && r.Method == http.MethodPatch:
+		var data discordgo.MessageEdit
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, m := range f.messages {
+			if m.ID == parts[5] {
+				if data.Content != nil {
+					m.Content = *data.Content
+				}
+				if data.Embed != nil {
+					m.Embed = data.Embed
+				}
+				m.Edits++
+				writeJSON(w, m.discordMessage())
+				return
+			}
+		}
+		http.Error(w, `{"message": "Unknown Message", "code": 10008}`, http.StatusNotFound)
+	default:
+		writeJSON(w, map[string]interface{}{})
+	}
+}
+
+func (m *Message) discordMessage() *discordgo.Message {
+	dm := &discordgo.Message{ID: m.ID, ChannelID: m.ChannelID, Content: m.Content}
+	if m.Embed != nil {
+		dm.Embeds = []*discordgo.MessageEmbed{m.Embed}
+	}
+	return dm
+}
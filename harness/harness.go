@@ -0,0 +1,123 @@
+package harness
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// IDs of the Discord guild and bot the harness runs with
+const (
+	GuildID = "200001"
+	BotID   = "200002"
+)
+
+// Bot running against fake Twitch and Discord servers, for exercising the register, poll, announce and
+// offline flow end to end without credentials or network access
+type Harness struct {
+	Twitch  *FakeTwitch
+	Discord *FakeDiscord
+	Session *twitch.Session    // Twitch session of the bot
+	Bot     *discordgo.Session // Discord session of the bot, which never opens a gateway connection
+
+	previousClient *http.Client
+}
+
+// Routes requests to Twitch and Discord to the fake servers without opening any sockets
+type transport struct {
+	twitch  http.Handler
+	discord http.Handler
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	var handler http.Handler
+	switch r.URL.Hostname() {
+	case "api.twitch.tv", "id.twitch.tv":
+		handler = t.twitch
+	case "discord.com":
+		handler = t.discord
+	default:
+		return nil, errors.New("harness has no fake server for " + r.URL.Hostname())
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	return rec.Result(), nil
+}
+
+// Starts a bot storing its data in dataDir against fresh fake servers. Streams are announced and ended
+// after being live or offline for a millisecond. Only one harness can run at a time.
+func New(dataDir string) (*Harness, error) {
+	if err := utils.SetDataDir(dataDir); err != nil {
+		return nil, err
+	}
+
+	h := &Harness{
+		Twitch:         NewFakeTwitch(),
+		Discord:        NewFakeDiscord(),
+		previousClient: utils.HTTPClient,
+	}
+	client := &http.Client{Transport: &transport{twitch: h.Twitch, discord: h.Discord}, Timeout: time.Second * 5}
+	utils.HTTPClient = client
+
+	ts, err := twitch.New("fake-client-id", "fake-client-secret", "harness")
+	if err != nil {
+		utils.HTTPClient = h.previousClient
+		return nil, err
+	}
+	ts.SetDebounce(time.Millisecond, time.Millisecond)
+	if err := ts.GetAuthToken(); err != nil {
+		utils.HTTPClient = h.previousClient
+		return nil, err
+	}
+	h.Session = ts
+
+	dg, err := discordgo.New("Bot fake-token")
+	if err != nil {
+		utils.HTTPClient = h.previousClient
+		return nil, err
+	}
+	dg.Client = client
+	dg.State.User = &discordgo.User{ID: BotID, Username: "harness", Bot: true}
+	dg.State.GuildAdd(&discordgo.Guild{ID: GuildID, Name: "Harness"})
+	h.Bot = dg
+
+	twitch.SetGuildActive(dg, GuildID)
+	twitch.StartMonitoring(ts, dg)
+
+	return h, nil
+}
+
+// Registers a Twitch channel added to the fake Twitch server to a Discord channel of the harness guild
+func (h *Harness) Register(login string, channelID string) error {
+	return h.Session.RegisterChannel(login, GuildID, channelID, "channel-"+channelID)
+}
+
+// Removes a registration added with Register. Returns false if it did not exist.
+func (h *Harness) Unregister(login string, channelID string) bool {
+	return h.Session.UnregisterChannel(login, GuildID, channelID)
+}
+
+// Polls Twitch until done returns true, failing once timeout passes
+func (h *Harness) WaitFor(done func() bool, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for !done() {
+		if time.Now().After(deadline) {
+			return errors.New("timed out")
+		}
+		h.Session.PollNow()
+		time.Sleep(time.Millisecond * 50)
+	}
+	return nil
+}
+
+// Stops the bot and restores the HTTP client it replaced
+func (h *Harness) Close() {
+	h.Session.Close()
+	utils.HTTPClient = h.previousClient
+}
@@ -0,0 +1,162 @@
+package harness
+
+import (
+	"testing"
+	"time"
+)
+
+const timeout = time.Second * 10
+
+// Starts a harness with a temporary data directory, closed when the test ends
+func start(t *testing.T, dataDir string) *Harness {
+	t.Helper()
+
+	h, err := New(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(h.Close)
+	return h
+}
+
+// Returns whether the only message of a Discord channel was turned into an offline summary
+func ended(h *Harness, channelID string, liveAuthor string) bool {
+	messages := h.Discord.Messages(channelID)
+	return len(messages) == 1 && messages[0].Edits > 0 && messages[0].Embed != nil &&
+		messages[0].Embed.Author != nil && messages[0].Embed.Author.Name != liveAuthor
+}
+
+func TestAnnounceAndEnd(t *testing.T) {
+	h := start(t, t.TempDir())
+
+	h.Twitch.AddUser("streamer", "Streamer")
+	if err := h.Register("streamer", "300001"); err != nil {
+		t.Fatal(err)
+	}
+
+	h.Twitch.GoLive("streamer", "Test stream", "Just Chatting")
+	if err := h.WaitFor(func() bool { return len(h.Discord.Messages("300001")) == 1 }, timeout); err != nil {
+		t.Fatal("live stream was not announced: ", err)
+	}
+	live := h.Discord.Messages("300001")[0]
+	if live.Embed == nil || live.Embed.Author == nil || live.Embed.Author.Name != "Streamer is live!" {
+		t.Fatalf("unexpected live message %+v", live)
+	}
+	if live.Embed.Title != "Test stream" {
+		t.Errorf("live message has title %q", live.Embed.Title)
+	}
+
+	h.Twitch.GoOffline("streamer")
+	if err := h.WaitFor(func() bool { return ended(h, "300001", "Streamer is live!") }, timeout); err != nil {
+		t.Fatal("offline stream did not end the live message: ", err)
+	}
+}
+
+func TestAnnounceInEveryChannel(t *testing.T) {
+	h := start(t, t.TempDir())
+
+	h.Twitch.AddUser("streamer", "Streamer")
+	for _, channelID := range []string{"300001", "300002"} {
+		if err := h.Register("streamer", channelID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h.Twitch.GoLive("streamer", "Test stream", "Just Chatting")
+	if err := h.WaitFor(func() bool {
+		return len(h.Discord.Messages("300001")) == 1 && len(h.Discord.Messages("300002")) == 1
+	}, timeout); err != nil {
+		t.Fatal("live stream was not announced in both Discord channels: ", err)
+	}
+}
+
+func TestUnregisteredChannelIsNotAnnounced(t *testing.T) {
+	h := start(t, t.TempDir())
+
+	h.Twitch.AddUser("streamer", "Streamer")
+	h.Twitch.AddUser("other", "Other")
+	for _, login := range []string{"streamer", "other"} {
+		if err := h.Register(login, "300001"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !h.Unregister("streamer", "300001") {
+		t.Fatal("channel was not unregistered")
+	}
+
+	// The channel still registered shows when the poll has run
+	h.Twitch.GoLive("streamer", "Test stream", "Just Chatting")
+	h.Twitch.GoLive("other", "Other stream", "Just Chatting")
+	if err := h.WaitFor(func() bool { return len(h.Discord.Messages("300001")) > 0 }, timeout); err != nil {
+		t.Fatal("registered channel was not announced: ", err)
+	}
+	for _, m := range h.Discord.Messages("300001") {
+		if m.Embed != nil && m.Embed.Author != nil && m.Embed.Author.Name == "Streamer is live!" {
+			t.Error("unregistered channel was announced")
+		}
+	}
+}
+
+func TestRegistrationSurvivesRestart(t *testing.T) {
+	dataDir := t.TempDir()
+
+	h, err := New(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Twitch.AddUser("streamer", "Streamer")
+	if err := h.Register("streamer", "300001"); err != nil {
+		h.Close()
+		t.Fatal(err)
+	}
+	h.Close()
+
+	// The restarted bot may poll before the account is added and post an orphan notice, so only the announcement counts
+	h = start(t, dataDir)
+	h.Twitch.AddUser("streamer", "Streamer")
+	h.Twitch.GoLive("streamer", "Test stream", "Just Chatting")
+	announced := func() bool {
+		for _, m := range h.Discord.Messages("300001") {
+			if m.Embed != nil {
+				return true
+			}
+		}
+		return false
+	}
+	if err := h.WaitFor(announced, timeout); err != nil {
+		t.Fatal("registration was lost on restart: ", err)
+	}
+}
+
+func TestRegistrationsChangeWhilePolling(t *testing.T) {
+	h := start(t, t.TempDir())
+
+	logins := []string{"first", "second", "third", "fourth", "fifth"}
+	for _, login := range logins {
+		h.Twitch.AddUser(login, login)
+		h.Twitch.GoLive(login, "Test stream", "Just Chatting")
+	}
+
+	if err := h.Register(logins[0], "300001"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Registrations are added and removed while the poll iterates over them
+	for i := 0; i < 20; i++ {
+		for _, login := range logins[1:] {
+			if err := h.Register(login, "300001"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		h.Session.PollNow()
+		for _, login := range logins[1:] {
+			if !h.Unregister(login, "300001") {
+				t.Fatalf("%v was not unregistered", login)
+			}
+		}
+	}
+
+	if err := h.WaitFor(func() bool { return len(h.Discord.Messages("300001")) > 0 }, timeout); err != nil {
+		t.Fatal("registered channel was not announced: ", err)
+	}
+}
@@ -0,0 +1,116 @@
+package harness
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nicklaw5/helix"
+)
+
+// Fake Helix API and Twitch authentication server. Endpoints it does not know answer with no data.
+type FakeTwitch struct {
+	mu      sync.Mutex
+	users   map[string]helix.User   // Map of logins to their user
+	streams map[string]helix.Stream // Map of logins to their live stream
+	nextID  int
+}
+
+func NewFakeTwitch() *FakeTwitch {
+	return &FakeTwitch{
+		users:   make(map[string]helix.User),
+		streams: make(map[string]helix.Stream),
+	}
+}
+
+// Adds a Twitch channel that can be registered
+func (f *FakeTwitch) AddUser(login string, displayName string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.nextID++
+	f.users[login] = helix.User{
+		ID:              fakeID(f.nextID),
+		Login:           login,
+		DisplayName:     displayName,
+		ProfileImageURL: "https://static-cdn.jtvnw.net/jtv_user_pictures/" + login + ".png",
+	}
+}
+
+// Starts a stream of a Twitch channel added with AddUser
+func (f *FakeTwitch) GoLive(login string, title string, game string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	user := f.users[login]
+	f.nextID++
+	f.streams[login] = helix.Stream{
+		ID:           fakeID(f.nextID),
+		UserID:       user.ID,
+		UserLogin:    login,
+		UserName:     user.DisplayName,
+		GameName:     game,
+		Type:         "live",
+		Title:        title,
+		ViewerCount:  1,
+		StartedAt:    time.Now().UTC(),
+		ThumbnailURL: "https://static-cdn.jtvnw.net/previews-ttv/live_user_" + login + "-{width}x{height}.jpg",
+	}
+}
+
+// Ends the stream of a Twitch channel
+func (f *FakeTwitch) GoOffline(login string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.streams, login)
+}
+
+func (f *FakeTwitch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Ratelimit-Limit", "800")
+	w.Header().Set("Ratelimit-Remaining", "799")
+	w.Header().Set("Ratelimit-Reset", "0")
+
+	switch strings.TrimSuffix(r.URL.Path, "/") {
+	case "/oauth2/token":
+		writeJSON(w, map[string]interface{}{"access_token": "fake-app-token", "expires_in": 3600, "token_type": "bearer"})
+	case "/oauth2/validate":
+		writeJSON(w, map[string]interface{}{"client_id": "fake-client-id", "expires_in": 3600})
+	case "/helix/users":
+		users := []helix.User{}
+		for _, login := range r.URL.Query()["login"] {
+			if user, ok := f.users[strings.ToLower(login)]; ok {
+				users = append(users, user)
+			}
+		}
+		writeJSON(w, map[string]interface{}{"data": users})
+	case "/helix/streams":
+		streams := []helix.Stream{}
+		for _, login := range r.URL.Query()["user_login"] {
+			if stream, ok := f.streams[strings.ToLower(login)]; ok {
+				streams = append(streams, stream)
+			}
+		}
+		writeJSON(w, map[string]interface{}{"data": streams, "pagination": map[string]string{}})
+	default:
+		writeJSON(w, map[string]interface{}{"data": []interface{}{}})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Returns the nth ID handed out by a fake server
+func fakeID(n int) string {
+	return strconv.Itoa(100000 + n)
+}
@@ -0,0 +1,203 @@
+// Package helixcache caches the responses of Twitch Helix lookups that rarely change, such as users, games and
+// channel information, so repeated registrations and embed builds do not spend rate limit points on them.
+package helixcache
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/stats"
+)
+
+// Paths of the Helix endpoints whose responses are cached
+var cachedPaths = map[string]bool{
+	"/helix/users":    true,
+	"/helix/games":    true,
+	"/helix/channels": true,
+}
+
+type entry struct {
+	status  int
+	header  http.Header
+	body    []byte
+	etag    string    // ETag Twitch sent, used to revalidate the entry once it expires
+	expires time.Time // Time the entry must be revalidated or fetched again
+	stored  time.Time // Time the entry was fetched or last revalidated
+}
+
+var (
+	mu         sync.Mutex
+	transports []*transport // Every caching transport, so entries can be forgotten in each of them
+)
+
+type transport struct {
+	base    http.RoundTripper
+	mu      sync.Mutex
+	entries map[string]*entry // Map of request URL and authorization to its cached response
+}
+
+// Wraps an HTTP transport to cache GET requests to cached Helix endpoints. Responses are kept for their
+// Cache-Control max-age, or HelixCacheTime if Twitch sends none, and revalidated with If-None-Match when Twitch
+// sent an ETag. Lookups are counted as cache hits, revalidations or misses in the stats.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	t := &transport{base: base, entries: make(map[string]*entry)}
+	transports = append(transports, t)
+	return t
+}
+
+// Forgets the cached responses of requests with a query parameter set to value, e.g. the lookups of a login
+// that must be fetched again after a rebrand
+func Forget(param string, value string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, t := range transports {
+		t.mu.Lock()
+		for key := range t.entries {
+			u, err := url.Parse(strings.SplitN(key, " ", 2)[0])
+			if err != nil {
+				continue
+			}
+			for _, v := range u.Query()[param] {
+				if strings.EqualFold(v, value) {
+					delete(t.entries, key)
+				}
+			}
+		}
+		t.mu.Unlock()
+	}
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Method != http.MethodGet || r.URL.Hostname() != "api.twitch.tv" || !cachedPaths[r.URL.Path] || r.Header.Get("Cache-Control") == "no-cache" {
+		return t.base.RoundTrip(r)
+	}
+
+	// Responses can depend on the token, e.g. the email of the authorized user
+	key := r.URL.String() + " " + r.Header.Get("Authorization")
+
+	t.mu.Lock()
+	cached := t.entries[key]
+	t.mu.Unlock()
+
+	if cached != nil && time.Now().Before(cached.expires) {
+		stats.CacheLookup(stats.CacheHit)
+		return cached.response(r), nil
+	}
+
+	if cached != nil && cached.etag != "" {
+		r = r.Clone(r.Context())
+		r.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := t.base.RoundTrip(r)
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		resp.Body.Close()
+		t.mu.Lock()
+		cached.expires, cached.stored = expiry(resp.Header), time.Now()
+		t.mu.Unlock()
+		stats.CacheLookup(stats.CacheRevalidated)
+		return cached.response(r), nil
+	}
+	stats.CacheLookup(stats.CacheMiss)
+
+	if resp.StatusCode != http.StatusOK || noStore(resp.Header) {
+		return resp, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	t.store(key, &entry{
+		status:  resp.StatusCode,
+		header:  resp.Header.Clone(),
+		body:    body,
+		etag:    resp.Header.Get("ETag"),
+		expires: expiry(resp.Header),
+		stored:  time.Now(),
+	})
+
+	return resp, nil
+}
+
+// Stores an entry, making room by dropping expired entries that cannot be revalidated and then the oldest entries
+func (t *transport) store(key string, e *entry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.entries) >= constants.HelixCacheEntries {
+		now := time.Now()
+		for k, old := range t.entries {
+			if old.etag == "" && now.After(old.expires) {
+				delete(t.entries, k)
+			}
+		}
+	}
+	for len(t.entries) >= constants.HelixCacheEntries {
+		oldestKey := ""
+		for k, old := range t.entries {
+			if oldestKey == "" || old.stored.Before(t.entries[oldestKey].stored) {
+				oldestKey = k
+			}
+		}
+		delete(t.entries, oldestKey)
+	}
+
+	t.entries[key] = e
+}
+
+func (e *entry) response(r *http.Request) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(e.status) + " " + http.StatusText(e.status),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       r,
+	}
+}
+
+// Returns when a response expires according to its Cache-Control max-age, or after HelixCacheTime if it has none.
+// A response with no-cache is stored but revalidated every time.
+func expiry(header http.Header) time.Time {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "no-cache" {
+			return time.Now()
+		}
+		if strings.HasPrefix(directive, "max-age=") {
+			if seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil {
+				return time.Now().Add(time.Duration(seconds) * time.Second)
+			}
+		}
+	}
+	return time.Now().Add(constants.HelixCacheTime)
+}
+
+func noStore(header http.Header) bool {
+	return strings.Contains(header.Get("Cache-Control"), "no-store")
+}
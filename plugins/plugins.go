@@ -0,0 +1,144 @@
+package plugins
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/crash"
+)
+
+// Who can issue a plugin command
+const (
+	Everyone  = iota // Any member of the Discord server
+	Moderator        // Members allowed to manage the bot's registrations
+	Owner            // The bot owner set by owner_id
+)
+
+// Custom command added to the command router
+type Command struct {
+	Name       string // First word after the command prefix, in lowercase
+	Permission int    // Everyone, Moderator or Owner
+	// Handles the command. Args are the words following the command name.
+	Run func(s *discordgo.Session, m *discordgo.MessageCreate, args []string)
+}
+
+// Stream of a monitored Twitch channel that went live
+type LiveEvent struct {
+	TwitchID    string    // ID of the Twitch channel
+	Login       string    // Login of the Twitch channel
+	DisplayName string    // Display name of the Twitch channel
+	Title       string    // Title of the stream
+	Game        string    // Category of the stream
+	StartTime   time.Time // Time the stream started
+}
+
+// Stream of a monitored Twitch channel that ended
+type OfflineEvent struct {
+	TwitchID    string    // ID of the Twitch channel
+	Login       string    // Login of the Twitch channel
+	DisplayName string    // Display name of the Twitch channel
+	StartTime   time.Time // Time the stream started
+	EndTime     time.Time // Time the stream ended
+}
+
+var (
+	mu               sync.RWMutex
+	commands         = make(map[string]Command) // Map of command names to their plugin command
+	liveListeners    []func(LiveEvent)
+	offlineListeners []func(OfflineEvent)
+	handlers         []interface{} // discordgo event handlers added to every bot
+)
+
+// Adds a command to the command router. Built-in commands take precedence over plugin commands of the same name.
+// Commands should be registered before the bot connects to Discord, for example in an init function.
+func RegisterCommand(c Command) error {
+	name := strings.ToLower(c.Name)
+	if name == "" || strings.ContainsAny(name, " \t\n") || c.Run == nil {
+		return constants.ErrInvalidPluginCommand
+	}
+	if c.Permission < Everyone || c.Permission > Owner {
+		return constants.ErrInvalidPluginCommand
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, exists := commands[name]; exists {
+		return constants.ErrPluginCommandExists
+	}
+	c.Name = name
+	commands[name] = c
+
+	return nil
+}
+
+// Returns the plugin command of a name, if one is registered
+func LookupCommand(name string) (Command, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	c, ok := commands[strings.ToLower(name)]
+	return c, ok
+}
+
+// Calls a listener every time a monitored stream is announced as live
+func OnLive(listener func(LiveEvent)) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	liveListeners = append(liveListeners, listener)
+}
+
+// Calls a listener every time a monitored stream is confirmed offline
+func OnOffline(listener func(OfflineEvent)) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	offlineListeners = append(offlineListeners, listener)
+}
+
+// Adds a discordgo event handler to every bot, for events the bot does not expose itself.
+// Handlers must be added before the bot connects to Discord.
+func AddHandler(handler interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	handlers = append(handlers, handler)
+}
+
+// Returns the discordgo event handlers added by plugins
+func Handlers() []interface{} {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return append([]interface{}{}, handlers...)
+}
+
+// Notifies the live listeners of a stream, each in its own goroutine
+func Live(e LiveEvent) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, listener := range liveListeners {
+		go func(listener func(LiveEvent)) {
+			defer crash.Recover("plugin_live_listener")
+			listener(e)
+		}(listener)
+	}
+}
+
+// Notifies the offline listeners of a stream, each in its own goroutine
+func Offline(e OfflineEvent) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, listener := range offlineListeners {
+		go func(listener func(OfflineEvent)) {
+			defer crash.Recover("plugin_offline_listener")
+			listener(e)
+		}(listener)
+	}
+}
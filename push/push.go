@@ -0,0 +1,231 @@
+package push
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/config"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Push services notifications can be sent through
+const (
+	Ntfy     = "ntfy"
+	Pushover = "pushover"
+)
+
+// Subscription of a Discord user to mobile push notifications of go-live events
+type Subscription struct {
+	Service  string   // Push service, Ntfy or Pushover
+	Target   string   // ntfy topic or URL, or Pushover user key
+	Channels []string // Twitch channels the user is notified about
+}
+
+var (
+	mu            sync.RWMutex
+	subscriptions = make(map[string]*Subscription) // Map of Discord user IDs to their subscription
+)
+
+// Loads the push subscriptions from disk
+func Load() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	err := utils.ReadGobFromDisk(utils.DataDir, "push", &subscriptions)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if subscriptions == nil {
+		subscriptions = make(map[string]*Subscription)
+	}
+
+	return err
+}
+
+func save() error {
+	return utils.WriteGobToDisk(utils.DataDir, "push", subscriptions)
+}
+
+// Sets where a Discord user receives push notifications, keeping the channels they follow
+func SetTarget(discordUserID string, service string, target string) error {
+	if service != Ntfy && service != Pushover {
+		return constants.ErrUnknownPushService
+	}
+	if service == Pushover && config.Settings.PushoverToken == "" {
+		return constants.ErrPushServiceDisabled
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	sub := subscriptions[discordUserID]
+	if sub == nil {
+		sub = &Subscription{}
+		subscriptions[discordUserID] = sub
+	}
+	sub.Service = service
+	sub.Target = target
+
+	return save()
+}
+
+// Stops push notifications to a Discord user and forgets the channels they follow
+func Disable(discordUserID string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if subscriptions[discordUserID] == nil {
+		return constants.ErrPushNotConfigured
+	}
+	delete(subscriptions, discordUserID)
+
+	return save()
+}
+
+// Notifies a Discord user when a Twitch channel goes live
+func Follow(discordUserID string, login string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	sub := subscriptions[discordUserID]
+	if sub == nil {
+		return constants.ErrPushNotConfigured
+	}
+	for _, channel := range sub.Channels {
+		if channel == login {
+			return nil
+		}
+	}
+	sub.Channels = append(sub.Channels, login)
+	sort.Strings(sub.Channels)
+
+	return save()
+}
+
+// Stops notifying a Discord user when a Twitch channel goes live
+func Unfollow(discordUserID string, login string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	sub := subscriptions[discordUserID]
+	if sub == nil {
+		return constants.ErrPushNotConfigured
+	}
+	for i, channel := range sub.Channels {
+		if channel == login {
+			sub.Channels = append(sub.Channels[:i], sub.Channels[i+1:]...)
+			return save()
+		}
+	}
+
+	return constants.ErrNotFollowing
+}
+
+// Returns the push subscription of a Discord user
+func Get(discordUserID string) (Subscription, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if sub := subscriptions[discordUserID]; sub != nil {
+		s := *sub
+		s.Channels = append([]string{}, sub.Channels...)
+		return s, true
+	}
+	return Subscription{}, false
+}
+
+// Sends a go-live push notification to every user following a Twitch channel
+func NotifyLive(login string, displayName string, title string, game string) {
+	mu.RLock()
+	var targets []Subscription
+	for _, sub := range subscriptions {
+		for _, channel := range sub.Channels {
+			if channel == login {
+				targets = append(targets, *sub)
+				break
+			}
+		}
+	}
+	mu.RUnlock()
+
+	heading := displayName + " is live"
+	if game != "" {
+		heading += " playing " + game
+	}
+	link := "https://www.twitch.tv/" + login
+
+	for _, sub := range targets {
+		var err error
+		switch sub.Service {
+		case Ntfy:
+			err = sendNtfy(sub.Target, heading, title, link)
+		case Pushover:
+			err = sendPushover(sub.Target, heading, title, link)
+		}
+		if err != nil {
+			utils.Log.WithError(err).WithField("service", sub.Service).Error("Error sending push notification.")
+		}
+	}
+}
+
+// Publishes a message to an ntfy topic. Topics given as a URL are published to that server instead of the configured one.
+func sendNtfy(topic string, heading string, message string, link string) error {
+	topicURL := topic
+	if !strings.HasPrefix(topic, "https://") && !strings.HasPrefix(topic, "http://") {
+		server := config.Settings.NtfyURL
+		if server == "" {
+			server = constants.NtfyURL
+		}
+		topicURL = strings.TrimSuffix(server, "/") + "/" + url.PathEscape(topic)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, topicURL, strings.NewReader(message))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Title", heading)
+	req.Header.Set("Click", link)
+	req.Header.Set("Tags", "red_circle")
+
+	return send(req)
+}
+
+// Sends a message to a Pushover user through the configured application token
+func sendPushover(userKey string, heading string, message string, link string) error {
+	form := url.Values{
+		"token":   {config.Settings.PushoverToken},
+		"user":    {userKey},
+		"title":   {heading},
+		"message": {message},
+		"url":     {link},
+	}
+
+	req, err := http.NewRequest(http.MethodPost, constants.PushoverMessagesURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return send(req)
+}
+
+func send(req *http.Request) error {
+	resp, err := utils.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %v", constants.ErrPushFailed, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	replayChannel string        // Twitch channel the replay is limited to
+	replaySince   time.Duration // How far back events are replayed
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Rebuild announcement state from the event log of a session",
+	Long: "Reads the stream events the bot recorded for the session and replays them through the monitor's rules. " +
+		"Prints the streams, announcements and time live of every Twitch channel, and events that would cause missing or repeated announcements.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		events, err := twitch.ReadEventLog(sessionName)
+		if err != nil {
+			return err
+		}
+
+		filtered := events[:0]
+		for _, e := range events {
+			if replayChannel != "" && e.Login != strings.ToLower(replayChannel) {
+				continue
+			}
+			if replaySince > 0 && time.Since(e.Time) > replaySince {
+				continue
+			}
+			filtered = append(filtered, e)
+		}
+		fmt.Printf("Replaying %v events.\n\n", len(filtered))
+
+		channels := twitch.Replay(filtered)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "CHANNEL\tLIVE\tSTREAMS\tANNOUNCEMENTS\tTIME LIVE\tANOMALIES")
+		for _, c := range channels {
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", c.Login, c.Live, c.Streams, c.Announcements, c.LiveTime.Round(time.Minute), len(c.Anomalies))
+		}
+		w.Flush()
+
+		for _, c := range channels {
+			for _, anomaly := range c.Anomalies {
+				fmt.Printf("%v: %v\n", c.Login, anomaly)
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	replayCmd.Flags().StringVar(&replayChannel, "channel", "", "Only replay events of this Twitch channel")
+	replayCmd.Flags().DurationVar(&replaySince, "since", 0, "Only replay events this recent, e.g. 72h")
+
+	rootCmd.AddCommand(replayCmd)
+}
@@ -0,0 +1,330 @@
+package scripts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/config"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"go.starlark.net/starlark"
+)
+
+// Hooks a script can implement, named after the Starlark function implementing them
+const (
+	OnLive    = "on_live"
+	OnOffline = "on_offline"
+	OnCommand = "on_command"
+)
+
+// Extension of the Starlark scripts loaded from the scripts directory
+const scriptExt = ".star"
+
+// Announcement passed to the on_live and on_offline hooks
+type Announcement struct {
+	Hook             string
+	GuildID          string
+	ChannelID        string
+	TwitchLogin      string
+	DisplayName      string
+	Title            string
+	Game             string
+	Content          string
+	EmbedTitle       string
+	EmbedDescription string
+}
+
+// Changes a hook makes to an announcement. Fields left out keep their value.
+type AnnouncementResult struct {
+	Suppress         bool    // Whether to skip the announcement
+	Content          *string // Message content to send instead
+	EmbedTitle       *string // Embed title to use instead
+	EmbedDescription *string // Embed description to use instead
+}
+
+// Command passed to the on_command hook
+type Command struct {
+	Hook      string
+	GuildID   string
+	ChannelID string
+	UserID    string
+	Username  string
+	Moderator bool
+	Name      string
+	Args      []string
+}
+
+// Response of the on_command hook
+type CommandResult struct {
+	Handled bool   // Whether the script handled the command
+	Reply   string // Message sent back to the channel, if any
+}
+
+// Script file loaded from the scripts directory, reloaded once the file changes
+type script struct {
+	modTime time.Time
+	size    int64
+	globals starlark.StringDict // Frozen globals of the script, nil if it failed to load
+}
+
+var loaded = struct {
+	sync.Mutex
+	dir     string
+	scripts map[string]*script // Map of file name to the script loaded from it
+}{scripts: make(map[string]*script)}
+
+// Runs the on_live or on_offline hook for an announcement. The result is empty if no script implements the hook.
+func RunAnnouncement(a Announcement) (AnnouncementResult, error) {
+	var result AnnouncementResult
+
+	value, err := run(a.Hook, event{
+		"hook":              starlark.String(a.Hook),
+		"guild_id":          starlark.String(a.GuildID),
+		"channel_id":        starlark.String(a.ChannelID),
+		"twitch_login":      starlark.String(a.TwitchLogin),
+		"display_name":      starlark.String(a.DisplayName),
+		"title":             starlark.String(a.Title),
+		"game":              starlark.String(a.Game),
+		"content":           starlark.String(a.Content),
+		"embed_title":       starlark.String(a.EmbedTitle),
+		"embed_description": starlark.String(a.EmbedDescription),
+	})
+	if err != nil || value == nil {
+		return result, err
+	}
+
+	for key, v := range value {
+		switch key {
+		case "suppress":
+			result.Suppress, err = toBool(a.Hook, key, v)
+		case "content":
+			result.Content, err = toString(a.Hook, key, v)
+		case "embed_title":
+			result.EmbedTitle, err = toString(a.Hook, key, v)
+		case "embed_description":
+			result.EmbedDescription, err = toString(a.Hook, key, v)
+		default:
+			err = fmt.Errorf("%v script returned unknown key %q", a.Hook, key)
+		}
+		if err != nil {
+			return AnnouncementResult{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// Runs the on_command hook for a command the bot does not know. The result is empty if no script implements the hook.
+func RunCommand(c Command) (CommandResult, error) {
+	c.Hook = OnCommand
+	var result CommandResult
+
+	args := make([]starlark.Value, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = starlark.String(arg)
+	}
+	value, err := run(c.Hook, event{
+		"hook":       starlark.String(c.Hook),
+		"guild_id":   starlark.String(c.GuildID),
+		"channel_id": starlark.String(c.ChannelID),
+		"user_id":    starlark.String(c.UserID),
+		"username":   starlark.String(c.Username),
+		"moderator":  starlark.Bool(c.Moderator),
+		"name":       starlark.String(c.Name),
+		"args":       starlark.NewList(args),
+	})
+	if err != nil || value == nil {
+		return result, err
+	}
+
+	for key, v := range value {
+		switch key {
+		case "handled":
+			result.Handled, err = toBool(c.Hook, key, v)
+		case "reply":
+			var reply *string
+			if reply, err = toString(c.Hook, key, v); reply != nil {
+				result.Reply = *reply
+			}
+		default:
+			err = fmt.Errorf("%v script returned unknown key %q", c.Hook, key)
+		}
+		if err != nil {
+			return CommandResult{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// Returns whether a script implements a hook
+func Has(hook string) bool {
+	return find(hook) != nil
+}
+
+// Event passed to a hook, keyed by the names scripts read it with
+type event map[string]starlark.Value
+
+// Calls the function implementing a hook with the event as a dict. Returns the dict it returned, or nil if no
+// script implements the hook or it returned None.
+func run(hook string, e event) (map[string]starlark.Value, error) {
+	fn := find(hook)
+	if fn == nil {
+		return nil, nil
+	}
+
+	dict := starlark.NewDict(len(e))
+	for key, value := range e {
+		if err := dict.SetKey(starlark.String(key), value); err != nil {
+			return nil, err
+		}
+	}
+	dict.Freeze()
+
+	thread, stop := newThread(hook)
+	defer stop()
+
+	value, err := starlark.Call(thread, fn, starlark.Tuple{dict}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%v script failed: %w", hook, err)
+	}
+	if value == starlark.None {
+		return nil, nil
+	}
+
+	returned, ok := value.(*starlark.Dict)
+	if !ok {
+		return nil, fmt.Errorf("%v script returned a %v instead of a dict", hook, value.Type())
+	}
+	result := make(map[string]starlark.Value, returned.Len())
+	for _, item := range returned.Items() {
+		key, ok := starlark.AsString(item[0])
+		if !ok {
+			return nil, fmt.Errorf("%v script returned a dict with a %v key", hook, item[0].Type())
+		}
+		result[key] = item[1]
+	}
+
+	return result, nil
+}
+
+// Returns a thread for running scripts and a function stopping its timeout. Scripts cannot load other files, and are
+// cancelled once they run for ScriptTimeout or take ScriptMaxSteps steps, so a script stuck in a loop cannot hold up
+// announcements.
+func newThread(name string) (*starlark.Thread, func() bool) {
+	thread := &starlark.Thread{
+		Name: name,
+		Print: func(thread *starlark.Thread, msg string) {
+			utils.Log.WithField("script", thread.Name).Info(msg)
+		},
+	}
+	thread.SetMaxExecutionSteps(constants.ScriptMaxSteps)
+
+	timer := time.AfterFunc(constants.ScriptTimeout, func() {
+		thread.Cancel("timed out")
+	})
+
+	return thread, timer.Stop
+}
+
+// Returns the function implementing a hook, or nil if no script does. Scripts are loaded in file name order and the
+// first one defining the hook implements it.
+func find(hook string) starlark.Callable {
+	loaded.Lock()
+	defer loaded.Unlock()
+
+	for _, s := range load() {
+		if fn, ok := s.globals[hook].(starlark.Callable); ok {
+			return fn
+		}
+	}
+
+	return nil
+}
+
+// Returns the scripts in the scripts directory sorted by file name, loading those that are new or changed since
+// they were last loaded. Must be called with loaded held.
+func load() []*script {
+	dir := config.Settings.ScriptsDir
+	if dir != loaded.dir {
+		loaded.dir = dir
+		loaded.scripts = make(map[string]*script)
+	}
+	if dir == "" {
+		return nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	names := []string{}
+	present := make(map[string]bool)
+	for _, entry := range entries {
+		name := entry.Name()
+		info, err := entry.Info()
+		if filepath.Ext(name) != scriptExt || err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		names = append(names, name)
+		present[name] = true
+
+		if s := loaded.scripts[name]; s != nil && s.modTime.Equal(info.ModTime()) && s.size == info.Size() {
+			continue
+		}
+		loaded.scripts[name] = &script{
+			modTime: info.ModTime(),
+			size:    info.Size(),
+			globals: execScript(filepath.Join(dir, name)),
+		}
+	}
+	for name := range loaded.scripts {
+		if !present[name] {
+			delete(loaded.scripts, name)
+		}
+	}
+
+	sort.Strings(names)
+	scripts := make([]*script, len(names))
+	for i, name := range names {
+		scripts[i] = loaded.scripts[name]
+	}
+	return scripts
+}
+
+// Runs a script file and returns its frozen globals, or nil if it failed
+func execScript(path string) starlark.StringDict {
+	thread, stop := newThread(strings.TrimSuffix(filepath.Base(path), scriptExt))
+	defer stop()
+
+	globals, err := starlark.ExecFile(thread, path, nil, nil)
+	if err != nil {
+		utils.Log.WithError(err).WithField("script", path).Error("Failed to load script.")
+		return nil
+	}
+	globals.Freeze()
+
+	return globals
+}
+
+func toBool(hook string, key string, v starlark.Value) (bool, error) {
+	b, ok := v.(starlark.Bool)
+	if !ok {
+		return false, fmt.Errorf("%v script returned a %v for %q instead of a bool", hook, v.Type(), key)
+	}
+	return bool(b), nil
+}
+
+func toString(hook string, key string, v starlark.Value) (*string, error) {
+	s, ok := starlark.AsString(v)
+	if !ok {
+		return nil, fmt.Errorf("%v script returned a %v for %q instead of a string", hook, v.Type(), key)
+	}
+	return &s, nil
+}
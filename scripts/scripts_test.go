@@ -0,0 +1,175 @@
+package scripts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/config"
+)
+
+// Points the scripts directory at a temporary directory with the given scripts, restored when the test ends
+func useScripts(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, src := range files {
+		writeScript(t, dir, name, src)
+	}
+
+	previous := config.Settings.ScriptsDir
+	config.Settings.ScriptsDir = dir
+	t.Cleanup(func() { config.Settings.ScriptsDir = previous })
+	return dir
+}
+
+func writeScript(t *testing.T, dir string, name string, src string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNoScripts(t *testing.T) {
+	useScripts(t, nil)
+
+	if Has(OnLive) {
+		t.Error("hook found without scripts")
+	}
+	result, err := RunAnnouncement(Announcement{Hook: OnLive, Content: "live"})
+	if err != nil || result.Suppress || result.Content != nil {
+		t.Errorf("got %+v, %v", result, err)
+	}
+}
+
+func TestAnnouncementHook(t *testing.T) {
+	useScripts(t, map[string]string{"announcements.star": `
+def on_live(event):
+    if event["game"] == "Chess":
+        return {"suppress": True}
+    return {"content": "Go watch " + event["display_name"] + "!", "embed_title": event["title"].upper()}
+`})
+
+	if !Has(OnLive) || Has(OnOffline) {
+		t.Fatalf("hooks found: on_live %v, on_offline %v", Has(OnLive), Has(OnOffline))
+	}
+
+	result, err := RunAnnouncement(Announcement{Hook: OnLive, DisplayName: "Streamer", Title: "test stream", Game: "Just Chatting"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Suppress || result.Content == nil || *result.Content != "Go watch Streamer!" ||
+		result.EmbedTitle == nil || *result.EmbedTitle != "TEST STREAM" || result.EmbedDescription != nil {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	if result, err := RunAnnouncement(Announcement{Hook: OnLive, Game: "Chess"}); err != nil || !result.Suppress {
+		t.Errorf("announcement not suppressed: %+v, %v", result, err)
+	}
+}
+
+func TestCommandHook(t *testing.T) {
+	useScripts(t, map[string]string{"commands.star": `
+def on_command(event):
+    if event["name"] != "hello":
+        return None
+    return {"handled": True, "reply": "Hello " + " ".join(event["args"])}
+`})
+
+	result, err := RunCommand(Command{Name: "hello", Args: []string{"there", "friend"}})
+	if err != nil || !result.Handled || result.Reply != "Hello there friend" {
+		t.Errorf("got %+v, %v", result, err)
+	}
+
+	result, err = RunCommand(Command{Name: "other"})
+	if err != nil || result.Handled {
+		t.Errorf("unknown command handled: %+v, %v", result, err)
+	}
+}
+
+func TestInvalidResults(t *testing.T) {
+	useScripts(t, map[string]string{"invalid.star": `
+def on_live(event):
+    return "not a dict"
+
+def on_offline(event):
+    return {"colour": "red"}
+
+def on_command(event):
+    return {"handled": "yes"}
+`})
+
+	if _, err := RunAnnouncement(Announcement{Hook: OnLive}); err == nil {
+		t.Error("no error for a string result")
+	}
+	if _, err := RunAnnouncement(Announcement{Hook: OnOffline}); err == nil {
+		t.Error("no error for an unknown key")
+	}
+	if _, err := RunCommand(Command{Name: "hello"}); err == nil {
+		t.Error("no error for a string where a bool is expected")
+	}
+}
+
+func TestScriptsAreSandboxed(t *testing.T) {
+	useScripts(t, map[string]string{
+		"a_load.star": `
+load("other.star", "helper")
+
+def on_live(event):
+    return {"suppress": True}
+`,
+		"b_loop.star": `
+def on_live(event):
+    n = 0
+    for i in range(100000000):
+        n += i
+    return {"content": str(n)}
+
+def on_offline(event):
+    event["content"] = "changed"
+`,
+	})
+
+	// The script loading another file fails to load, so the next one implements the hook
+	start := time.Now()
+	_, err := RunAnnouncement(Announcement{Hook: OnLive})
+	if err == nil || !strings.Contains(err.Error(), "too many steps") {
+		t.Errorf("endless script was not cancelled: %v", err)
+	}
+	if time.Since(start) > time.Second*5 {
+		t.Errorf("cancelling the script took %v", time.Since(start))
+	}
+
+	if _, err := RunAnnouncement(Announcement{Hook: OnOffline}); err == nil {
+		t.Error("script changed the event it was given")
+	}
+}
+
+func TestScriptsReload(t *testing.T) {
+	dir := useScripts(t, map[string]string{"hooks.star": `
+def on_live(event):
+    return {"content": "first"}
+`})
+
+	if result, err := RunAnnouncement(Announcement{Hook: OnLive}); err != nil || result.Content == nil || *result.Content != "first" {
+		t.Fatalf("got %+v, %v", result, err)
+	}
+
+	writeScript(t, dir, "hooks.star", `
+def on_live(event):
+    return {"content": "second, after the script changed"}
+`)
+	if result, err := RunAnnouncement(Announcement{Hook: OnLive}); err != nil || result.Content == nil || *result.Content != "second, after the script changed" {
+		t.Fatalf("changed script was not reloaded: %+v, %v", result, err)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "hooks.star")); err != nil {
+		t.Fatal(err)
+	}
+	if Has(OnLive) {
+		t.Error("removed script still implements its hook")
+	}
+}
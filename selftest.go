@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/harness"
+	"github.com/spf13/cobra"
+)
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Run the register, announce and offline flow against fake Twitch and Discord servers",
+	Long: "Starts the bot against in-process fake Twitch and Discord servers with a temporary data directory, registers a " +
+		"Twitch channel, takes it live and offline, and checks the announcement is sent and then ended. Needs no credentials " +
+		"or network access. Exits with status 1 if any step fails.",
+	Args: cobra.NoArgs,
+	// The harness sets up its own data directory and HTTP client
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+	Run: func(cmd *cobra.Command, args []string) {
+		os.Exit(runSelftest())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(selftestCmd)
+}
+
+// Runs the end to end flow, prints a report and returns the exit code
+func runSelftest() int {
+	const (
+		login     = "harnessstreamer"
+		channelID = "300001"
+		timeout   = time.Second * 10
+	)
+
+	failed := false
+	report := func(name string, err error) bool {
+		if err != nil {
+			failed = true
+			fmt.Printf("[FAIL] %v: %v\n", name, err)
+		} else {
+			fmt.Printf("[ OK ] %v\n", name)
+		}
+		return err == nil
+	}
+
+	dir, err := os.MkdirTemp("", "discordtwitchbot-selftest")
+	if !report("Temporary data directory is created", err) {
+		return 1
+	}
+	defer os.RemoveAll(dir)
+
+	h, err := harness.New(dir)
+	if !report("Bot starts against the fake servers", err) {
+		return 1
+	}
+	defer h.Close()
+
+	h.Twitch.AddUser(login, "HarnessStreamer")
+	if !report("Twitch channel is registered", h.Register(login, channelID)) {
+		return 1
+	}
+
+	h.Twitch.GoLive(login, "Selftest stream", "Just Chatting")
+	announced := report("Live stream is announced", h.WaitFor(func() bool {
+		return len(h.Discord.Messages(channelID)) == 1
+	}, timeout))
+
+	if announced {
+		h.Twitch.GoOffline(login)
+		report("Offline stream ends the live message", h.WaitFor(func() bool {
+			messages := h.Discord.Messages(channelID)
+			return len(messages) == 1 && messages[0].Edits > 0 && messages[0].Embed != nil &&
+				messages[0].Embed.Author != nil && messages[0].Embed.Author.Name != "HarnessStreamer is live!"
+		}, timeout))
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
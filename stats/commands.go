@@ -0,0 +1,203 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Upper bounds of the command latency histogram buckets
+var commandBuckets = []time.Duration{
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+type commandKey struct {
+	command string
+	guildID string
+}
+
+type commandMetrics struct {
+	invocations int
+	failures    int
+	total       time.Duration
+	slowest     time.Duration
+	buckets     []int // Invocations handled within each of commandBuckets
+}
+
+var commands = struct {
+	sync.Mutex
+	metrics map[commandKey]*commandMetrics
+}{metrics: make(map[commandKey]*commandMetrics)}
+
+// Invocations, failures and handling time of a command in every Discord server since the bot started
+type CommandUsage struct {
+	Command     string
+	Invocations int
+	Failures    int
+	Average     time.Duration
+	Slowest     time.Duration
+}
+
+// Records a command handled for a guild, how long handling it took and whether it failed
+func CommandHandled(command string, guildID string, elapsed time.Duration, failed bool) {
+	commands.Lock()
+	defer commands.Unlock()
+
+	key := commandKey{command: command, guildID: guildID}
+	c := commands.metrics[key]
+	if c == nil {
+		c = &commandMetrics{buckets: make([]int, len(commandBuckets))}
+		commands.metrics[key] = c
+	}
+
+	c.invocations++
+	if failed {
+		c.failures++
+	}
+	c.total += elapsed
+	if elapsed > c.slowest {
+		c.slowest = elapsed
+	}
+	for i, bound := range commandBuckets {
+		if elapsed <= bound {
+			c.buckets[i]++
+		}
+	}
+}
+
+// Removes the command metrics of a guild. Returns the number of commands it had metrics of.
+func ForgetGuild(guildID string) int {
+	commands.Lock()
+	defer commands.Unlock()
+
+	removed := 0
+	for key := range commands.metrics {
+		if key.guildID == guildID {
+			delete(commands.metrics, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Returns the usage of every command summed over Discord servers, most used first
+func CommandUsages() []CommandUsage {
+	commands.Lock()
+	defer commands.Unlock()
+
+	byCommand := make(map[string]*CommandUsage)
+	total := make(map[string]time.Duration)
+	for key, c := range commands.metrics {
+		u := byCommand[key.command]
+		if u == nil {
+			u = &CommandUsage{Command: key.command}
+			byCommand[key.command] = u
+		}
+		u.Invocations += c.invocations
+		u.Failures += c.failures
+		total[key.command] += c.total
+		if c.slowest > u.Slowest {
+			u.Slowest = c.slowest
+		}
+	}
+
+	usages := []CommandUsage{}
+	for command, u := range byCommand {
+		u.Average = total[command] / time.Duration(u.Invocations)
+		usages = append(usages, *u)
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].Invocations != usages[j].Invocations {
+			return usages[i].Invocations > usages[j].Invocations
+		}
+		return usages[i].Command < usages[j].Command
+	})
+	return usages
+}
+
+// Escapes a Prometheus label value
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Writes the command metrics in the Prometheus text format
+func WriteMetrics(w io.Writer) error {
+	commands.Lock()
+	keys := make([]commandKey, 0, len(commands.metrics))
+	metrics := make(map[commandKey]commandMetrics, len(commands.metrics))
+	for key, c := range commands.metrics {
+		keys = append(keys, key)
+		metrics[key] = *c
+	}
+	commands.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].command != keys[j].command {
+			return keys[i].command < keys[j].command
+		}
+		return keys[i].guildID < keys[j].guildID
+	})
+
+	var b strings.Builder
+	labels := func(key commandKey) string {
+		return `command="` + labelEscaper.Replace(key.command) + `",guild="` + labelEscaper.Replace(key.guildID) + `"`
+	}
+
+	b.WriteString("# HELP discordtwitchbot_commands_total Commands handled since the bot started.\n")
+	b.WriteString("# TYPE discordtwitchbot_commands_total counter\n")
+	for _, key := range keys {
+		fmt.Fprintf(&b, "discordtwitchbot_commands_total{%v} %v\n", labels(key), metrics[key].invocations)
+	}
+
+	b.WriteString("# HELP discordtwitchbot_command_failures_total Commands that failed since the bot started.\n")
+	b.WriteString("# TYPE discordtwitchbot_command_failures_total counter\n")
+	for _, key := range keys {
+		fmt.Fprintf(&b, "discordtwitchbot_command_failures_total{%v} %v\n", labels(key), metrics[key].failures)
+	}
+
+	b.WriteString("# HELP discordtwitchbot_command_duration_seconds Time taken to handle commands.\n")
+	b.WriteString("# TYPE discordtwitchbot_command_duration_seconds histogram\n")
+	for _, key := range keys {
+		c := metrics[key]
+		for i, bound := range commandBuckets {
+			fmt.Fprintf(&b, "discordtwitchbot_command_duration_seconds_bucket{%v,le=\"%v\"} %v\n", labels(key), bound.Seconds(), c.buckets[i])
+		}
+		fmt.Fprintf(&b, "discordtwitchbot_command_duration_seconds_bucket{%v,le=\"+Inf\"} %v\n", labels(key), c.invocations)
+		fmt.Fprintf(&b, "discordtwitchbot_command_duration_seconds_sum{%v} %v\n", labels(key), c.total.Seconds())
+		fmt.Fprintf(&b, "discordtwitchbot_command_duration_seconds_count{%v} %v\n", labels(key), c.invocations)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Returns the handler serving the command metrics to Prometheus. Requests must carry token as a bearer token if it is set.
+func MetricsHandler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if token != "" && !utils.ValidBearerToken(r, token) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := WriteMetrics(w); err != nil {
+			utils.Log.WithError(err).Error("Failed to write metrics.")
+		}
+	})
+}
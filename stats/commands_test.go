@@ -0,0 +1,134 @@
+package stats
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Forgets the commands recorded by earlier tests
+func resetCommands() {
+	commands.Lock()
+	commands.metrics = make(map[commandKey]*commandMetrics)
+	commands.Unlock()
+}
+
+func TestCommandBuckets(t *testing.T) {
+	resetCommands()
+
+	CommandHandled("add", "guild", 5*time.Millisecond, false)
+	CommandHandled("add", "guild", 300*time.Millisecond, true)
+	CommandHandled("add", "guild", time.Minute, false)
+
+	c := commands.metrics[commandKey{command: "add", guildID: "guild"}]
+	// Buckets are cumulative, a command counts in every bucket its handling time fits in
+	expected := []int{1, 1, 1, 1, 2, 2, 2, 2, 2}
+	for i, count := range expected {
+		if c.buckets[i] != count {
+			t.Errorf("bucket le=%v: got %v, expected %v", commandBuckets[i], c.buckets[i], count)
+		}
+	}
+	if c.invocations != 3 || c.failures != 1 || c.slowest != time.Minute {
+		t.Errorf("got %v invocations, %v failures, slowest %v", c.invocations, c.failures, c.slowest)
+	}
+}
+
+func TestWriteMetrics(t *testing.T) {
+	resetCommands()
+
+	CommandHandled("list", "2", 20*time.Millisecond, false)
+	CommandHandled("add", "1", 2*time.Second, true)
+	CommandHandled(`say "hi"`, "1", time.Millisecond, false)
+
+	var b strings.Builder
+	if err := WriteMetrics(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	for _, line := range []string{
+		"# TYPE discordtwitchbot_commands_total counter",
+		`discordtwitchbot_commands_total{command="add",guild="1"} 1`,
+		`discordtwitchbot_command_failures_total{command="add",guild="1"} 1`,
+		`discordtwitchbot_command_failures_total{command="list",guild="2"} 0`,
+		"# TYPE discordtwitchbot_command_duration_seconds histogram",
+		`discordtwitchbot_command_duration_seconds_bucket{command="add",guild="1",le="1"} 0`,
+		`discordtwitchbot_command_duration_seconds_bucket{command="add",guild="1",le="2.5"} 1`,
+		`discordtwitchbot_command_duration_seconds_bucket{command="add",guild="1",le="+Inf"} 1`,
+		`discordtwitchbot_command_duration_seconds_bucket{command="list",guild="2",le="0.01"} 0`,
+		`discordtwitchbot_command_duration_seconds_bucket{command="list",guild="2",le="0.05"} 1`,
+		`discordtwitchbot_command_duration_seconds_sum{command="add",guild="1"} 2`,
+		`discordtwitchbot_command_duration_seconds_count{command="list",guild="2"} 1`,
+		`discordtwitchbot_commands_total{command="say \"hi\"",guild="1"} 1`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing line %v", line)
+		}
+	}
+
+	// Series are sorted by command, then guild
+	if strings.Index(out, `commands_total{command="add"`) > strings.Index(out, `commands_total{command="list"`) {
+		t.Error("series are not sorted by command")
+	}
+}
+
+func TestCommandUsages(t *testing.T) {
+	resetCommands()
+
+	CommandHandled("add", "1", time.Second, false)
+	CommandHandled("add", "2", 3*time.Second, true)
+	CommandHandled("list", "1", time.Second, false)
+
+	usages := CommandUsages()
+	if len(usages) != 2 {
+		t.Fatalf("expected 2 commands, got %v", usages)
+	}
+	add := usages[0]
+	if add.Command != "add" || add.Invocations != 2 || add.Failures != 1 || add.Average != 2*time.Second || add.Slowest != 3*time.Second {
+		t.Errorf("add summed over guilds as %+v", add)
+	}
+}
+
+func TestForgetGuild(t *testing.T) {
+	resetCommands()
+
+	CommandHandled("add", "1", time.Second, false)
+	CommandHandled("list", "1", time.Second, false)
+	CommandHandled("add", "2", time.Second, false)
+
+	if removed := ForgetGuild("1"); removed != 2 {
+		t.Errorf("removed metrics of %v commands, expected 2", removed)
+	}
+	usages := CommandUsages()
+	if len(usages) != 1 || usages[0].Command != "add" || usages[0].Invocations != 1 {
+		t.Errorf("got usages %+v after forgetting the guild", usages)
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	resetCommands()
+	handler := MetricsHandler("secret")
+
+	for _, tc := range []struct {
+		method string
+		auth   string
+		status int
+	}{
+		{http.MethodGet, "", http.StatusUnauthorized},
+		{http.MethodGet, "Bearer wrong", http.StatusUnauthorized},
+		{http.MethodPost, "Bearer secret", http.StatusMethodNotAllowed},
+		{http.MethodGet, "Bearer secret", http.StatusOK},
+	} {
+		r := httptest.NewRequest(tc.method, "/metrics", nil)
+		if tc.auth != "" {
+			r.Header.Set("Authorization", tc.auth)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != tc.status {
+			t.Errorf("%v with %q: got status %v, expected %v", tc.method, tc.auth, w.Code, tc.status)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package stats
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Number of days announcements are counted for
+const historyDays = 14
+
+// Usage of the Twitch API and Discord deliveries since the bot started
+type Usage struct {
+	TwitchRequests     int // Requests sent to Twitch
+	TwitchErrors       int // Twitch requests that failed or returned an error status
+	TwitchRateLimited  int // Twitch requests rejected for exceeding the rate limit
+	RateLimit          int // Points in the Twitch rate limit bucket, 0 if unknown
+	RateLimitRemaining int // Points remaining after the last Twitch request
+	RateLimitLowest    int // Fewest points remaining after any Twitch request, -1 if unknown
+	DiscordSent        int // Notifications delivered to Discord
+	DiscordFailed      int // Notifications Discord rejected
+	CacheHits          int // Twitch lookups answered from the cache
+	CacheRevalidated   int // Twitch lookups answered from the cache after Twitch confirmed it was unchanged
+	CacheMisses        int // Cacheable Twitch lookups sent to Twitch
+}
+
+// Results of a cacheable Twitch lookup
+const (
+	CacheHit = iota
+	CacheRevalidated
+	CacheMiss
+)
+
+var usage = struct {
+	sync.Mutex
+	Usage
+}{Usage: Usage{RateLimitLowest: -1}}
+
+// Day an announcement was sent and how many were sent that day
+type DailyCount struct {
+	Day   time.Time
+	Count int
+}
+
+// Returns the number of announcements sent on each of the last days, oldest first
+func DailyAnnouncements() []DailyCount {
+	mu.Lock()
+	defer mu.Unlock()
+
+	days := []DailyCount{}
+	for day, count := range counters.DailyAnnouncements {
+		if t, err := time.Parse("2006-01-02", day); err == nil {
+			days = append(days, DailyCount{Day: t, Count: count})
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Day.Before(days[j].Day) })
+
+	return days
+}
+
+// Counts an announcement towards the current day and forgets days older than historyDays
+func countDailyAnnouncement() {
+	if counters.DailyAnnouncements == nil {
+		counters.DailyAnnouncements = make(map[string]int)
+	}
+
+	now := time.Now().UTC()
+	counters.DailyAnnouncements[now.Format("2006-01-02")]++
+
+	oldest := now.AddDate(0, 0, -historyDays).Format("2006-01-02")
+	for day := range counters.DailyAnnouncements {
+		if day <= oldest {
+			delete(counters.DailyAnnouncements, day)
+		}
+	}
+}
+
+// Returns the usage of the Twitch API and Discord deliveries since the bot started
+func GetUsage() Usage {
+	usage.Lock()
+	defer usage.Unlock()
+
+	return usage.Usage
+}
+
+// Records the result of a notification sent to Discord
+func DeliveryResult(failed bool) {
+	usage.Lock()
+	defer usage.Unlock()
+
+	if failed {
+		usage.DiscordFailed++
+	} else {
+		usage.DiscordSent++
+	}
+}
+
+// Records whether a cacheable Twitch lookup was answered from the cache
+func CacheLookup(result int) {
+	usage.Lock()
+	defer usage.Unlock()
+
+	switch result {
+	case CacheHit:
+		usage.CacheHits++
+	case CacheRevalidated:
+		usage.CacheRevalidated++
+	case CacheMiss:
+		usage.CacheMisses++
+	}
+}
+
+// Wraps an HTTP transport to count requests to the Twitch API and track its rate limit
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(r)
+	if !strings.HasSuffix(r.URL.Hostname(), "twitch.tv") {
+		return resp, err
+	}
+
+	usage.Lock()
+	defer usage.Unlock()
+
+	usage.TwitchRequests++
+	if err != nil {
+		usage.TwitchErrors++
+		return resp, err
+	}
+	if resp.StatusCode >= 400 {
+		usage.TwitchErrors++
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		usage.TwitchRateLimited++
+	}
+
+	if limit, err := strconv.Atoi(resp.Header.Get("Ratelimit-Limit")); err == nil {
+		usage.RateLimit = limit
+	}
+	if remaining, err := strconv.Atoi(resp.Header.Get("Ratelimit-Remaining")); err == nil {
+		usage.RateLimitRemaining = remaining
+		if usage.RateLimitLowest < 0 || remaining < usage.RateLimitLowest {
+			usage.RateLimitLowest = remaining
+		}
+	}
+
+	return resp, err
+}
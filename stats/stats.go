@@ -0,0 +1,128 @@
+package stats
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Lifetime counters of the bot persisted between restarts
+type Counters struct {
+	AnnouncementsSent int           // Number of live announcements sent to Discord
+	CommandsProcessed int           // Number of commands received from Discord
+	Restarts          int           // Number of times the bot has started
+	Uptime            time.Duration // Total time the bot has been running
+	PanicsRecovered   int           // Number of panics recovered in handlers and background jobs
+
+	DailyAnnouncements map[string]int // Map of UTC days formatted as 2006-01-02 to the announcements sent that day
+}
+
+var (
+	mu          sync.Mutex
+	counters    Counters
+	priorUptime time.Duration // Uptime accumulated before the current run
+	startTime   = time.Now()
+	dirty       bool // Whether the counters changed since they were last written to disk
+)
+
+// Loads the counters from disk and records a restart
+func Load() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	err := utils.ReadGobFromDisk(utils.DataDir, "stats", &counters)
+	if errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+
+	priorUptime = counters.Uptime
+	counters.Restarts++
+
+	return save()
+}
+
+// Writes the counters to disk
+func Save() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return save()
+}
+
+// Writes the counters to disk if they changed since they were last written
+func Flush() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if !dirty {
+		return
+	}
+	if err := save(); err != nil {
+		utils.Log.WithError(err).Error("Error writing stats to disk.")
+	}
+}
+
+// Returns a copy of the counters with the uptime of the current run included
+func Get() Counters {
+	mu.Lock()
+	defer mu.Unlock()
+
+	c := counters
+	c.DailyAnnouncements = make(map[string]int, len(counters.DailyAnnouncements))
+	for day, count := range counters.DailyAnnouncements {
+		c.DailyAnnouncements[day] = count
+	}
+	c.Uptime = priorUptime + time.Since(startTime)
+
+	return c
+}
+
+// Returns how long the bot has been running since it last started
+func SessionUptime() time.Duration {
+	return time.Since(startTime)
+}
+
+// Records a live announcement sent to Discord
+func AnnouncementSent() {
+	mu.Lock()
+	countDailyAnnouncement()
+	dirty = true
+	mu.Unlock()
+
+	increment(&counters.AnnouncementsSent)
+}
+
+// Records a command received from Discord
+func CommandProcessed() {
+	increment(&counters.CommandsProcessed)
+}
+
+// Records a panic recovered in a handler or background job
+func PanicRecovered() {
+	increment(&counters.PanicsRecovered)
+}
+
+// Counts in memory only, the counters are written to disk by Flush and Save
+func increment(counter *int) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	*counter++
+	dirty = true
+}
+
+func save() error {
+	counters.Uptime = priorUptime + time.Since(startTime)
+
+	if err := utils.WriteGobToDisk(utils.DataDir, "stats", counters); err != nil {
+		return err
+	}
+	dirty = false
+	return nil
+}
@@ -0,0 +1,36 @@
+package stats
+
+import (
+	"os"
+	"testing"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+func TestFlush(t *testing.T) {
+	utils.DataDir = t.TempDir()
+	path := utils.DataDir + "/stats.gob"
+
+	CommandProcessed()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("counters were written to disk before being flushed")
+	}
+
+	Flush()
+	var saved Counters
+	if err := utils.ReadGobFromDisk(utils.DataDir, "stats", &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.CommandsProcessed != Get().CommandsProcessed {
+		t.Fatalf("flushed %v commands, counted %v", saved.CommandsProcessed, Get().CommandsProcessed)
+	}
+
+	// Unchanged counters are not written again
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	Flush()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("unchanged counters were written to disk")
+	}
+}
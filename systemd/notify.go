@@ -0,0 +1,75 @@
+package systemd
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Sends a state to the service manager through the socket in NOTIFY_SOCKET.
+// Does nothing when the bot is not run by systemd with Type=notify.
+func Notify(state string) {
+	path := os.Getenv("NOTIFY_SOCKET")
+	if path == "" {
+		return
+	}
+	// Abstract sockets are written with a leading @
+	if path[0] == '@' {
+		path = "\x00" + path[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		utils.Log.WithError(err).Warn("Failed to notify systemd.")
+		return
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		utils.Log.WithError(err).Warn("Failed to notify systemd.")
+	}
+}
+
+// Reports that the bot finished starting up
+func Ready(status string) {
+	Notify("READY=1\nSTATUS=" + status)
+}
+
+// Reports that the bot is shutting down
+func Stopping() {
+	Notify("STOPPING=1")
+}
+
+// Returns the watchdog interval of the service, or 0 if the watchdog is disabled
+func watchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	// The watchdog may belong to a parent process
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+
+	return time.Duration(usec) * time.Microsecond
+}
+
+// Sends a watchdog heartbeat twice per watchdog interval while healthy returns true. Once it
+// returns false the heartbeats stop and systemd restarts the bot when the interval passes.
+func Watchdog(healthy func() bool) {
+	interval := watchdogInterval()
+	if interval == 0 {
+		return
+	}
+
+	for range time.Tick(interval / 2) {
+		if healthy() {
+			Notify("WATCHDOG=1")
+		} else {
+			utils.Log.Error("Polling has stalled. Watchdog heartbeats are stopped so systemd restarts the bot.")
+		}
+	}
+}
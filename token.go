@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/twitch"
+	"github.com/spf13/cobra"
+)
+
+var tokenScopes []string // Scopes requested for the user token
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Obtain a Twitch user access token for the application",
+	Long: "Authorizes the application with a Twitch account and prints the user access and refresh token. " +
+		"The client ID and secret are read from the environment variables TWITCH_CLIENT_ID and TWITCH_CLIENT_SECRET.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		accessToken, refreshToken, err := twitch.RequestUserToken(os.Getenv("TWITCH_CLIENT_ID"), os.Getenv("TWITCH_CLIENT_SECRET"), tokenScopes,
+			func(dc *twitch.DeviceCode) {
+				fmt.Printf("Open %v and enter the code %v.\n", dc.VerificationURI, dc.UserCode)
+			})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Access token: %v\nRefresh token: %v\n", accessToken, refreshToken)
+		return nil
+	},
+}
+
+func init() {
+	tokenCmd.Flags().StringSliceVar(&tokenScopes, "scopes", nil, "Comma separated scopes to request")
+
+	rootCmd.AddCommand(tokenCmd)
+}
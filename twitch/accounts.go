@@ -0,0 +1,171 @@
+package twitch
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/accounts"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Starts linking a Discord user's Twitch account with a device code. The returned device code must be
+// completed by the user, after which the account is linked in the background and done is called.
+func (t *Session) LinkAccount(discordUserID string, done func(login string, err error)) (*DeviceCode, error) {
+	dc, err := t.requestDeviceCode([]string{})
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		token, err := t.waitForDeviceToken(dc)
+		if err != nil {
+			done("", err)
+			return
+		}
+
+		user, err := t.verifyAccount(token.AccessToken)
+		if err != nil {
+			done("", err)
+			return
+		}
+
+		err = accounts.Link(discordUserID, user.ID, user.Login)
+		done(user.Login, err)
+	}()
+
+	return dc, nil
+}
+
+// Returns a Twitch authorization URL that links a Discord user's account when opened,
+// or false if account linking through the HTTP server is not enabled
+func (t *Session) AccountLinkURL(discordUserID string, done func(login string, err error)) (string, bool, error) {
+	if t.accountRedirect == "" {
+		return "", false, nil
+	}
+
+	state, err := accounts.StartVerification(discordUserID, done)
+	if err != nil {
+		return "", true, err
+	}
+
+	id, _ := t.appCredentials()
+	client, err := helix.NewClient(&helix.Options{
+		HTTPClient:  utils.HTTPClient,
+		ClientID:    id,
+		RedirectURI: t.accountRedirect,
+	})
+	if err != nil {
+		return "", true, err
+	}
+
+	return client.GetAuthorizationURL(&helix.AuthorizationURLParams{
+		ResponseType: "code",
+		Scopes:       []string{},
+		State:        state,
+		ForceVerify:  true,
+	}), true, nil
+}
+
+// Enables linking accounts through the Twitch authorization page. Returns the handler Twitch
+// redirects to, which must be served at redirectURL.
+func (t *Session) EnableAccountLinking(redirectURL string) http.Handler {
+	t.accountRedirect = redirectURL
+
+	return http.HandlerFunc(t.handleAccountRedirect)
+}
+
+// Completes an account link after the user authorized the bot on Twitch
+func (t *Session) handleAccountRedirect(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	state := query.Get("state")
+
+	if query.Get("error") != "" {
+		accounts.FailVerification(state, constants.ErrAuthorizationDenied)
+		http.Error(w, "Authorization was denied. Your Twitch account was not linked.", http.StatusForbidden)
+		return
+	}
+
+	id, secret := t.appCredentials()
+	client, err := helix.NewClient(&helix.Options{
+		HTTPClient:   utils.HTTPClient,
+		ClientID:     id,
+		ClientSecret: secret,
+		RedirectURI:  t.accountRedirect,
+	})
+	if err != nil {
+		http.Error(w, "Your Twitch account could not be linked.", http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := client.RequestUserAccessToken(query.Get("code"))
+	if err != nil || resp.StatusCode != http.StatusOK {
+		accounts.FailVerification(state, constants.ErrTwitchQueryFailed)
+		http.Error(w, "Your Twitch account could not be linked.", http.StatusBadGateway)
+		return
+	}
+
+	user, err := t.verifyAccount(resp.Data.AccessToken)
+	if err != nil {
+		accounts.FailVerification(state, err)
+		http.Error(w, "Your Twitch account could not be linked.", http.StatusBadGateway)
+		return
+	}
+
+	if err := accounts.CompleteVerification(state, user.ID, user.Login); err != nil {
+		http.Error(w, "This link has expired. Start linking your account again from Discord.", http.StatusBadRequest)
+		return
+	}
+
+	fmt.Fprintf(w, "Your Twitch account %v is now linked. You can close this page.", user.Login)
+}
+
+// Returns the Twitch user an access token belongs to and revokes the token,
+// as it is only needed to prove ownership of the account
+func (t *Session) verifyAccount(accessToken string) (*helix.User, error) {
+	client, err := t.userClient(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	defer client.RevokeUserAccessToken(accessToken)
+
+	resp, err := client.GetUsers(&helix.UsersParams{})
+	if err != nil {
+		return nil, err
+	} else if len(resp.Data.Users) == 0 {
+		return nil, constants.ErrTwitchUserDoesNotExist
+	}
+
+	return &resp.Data.Users[0], nil
+}
+
+// Moves accounts linked before they were shared between sessions into the account store
+func (t *Session) migrateAccounts() error {
+	type linkedAccount struct {
+		TwitchUserID string
+		TwitchLogin  string
+		LinkedAt     time.Time
+	}
+
+	legacy := make(map[string]*linkedAccount)
+	err := utils.ReadGobFromDisk(utils.DataDir, t.name+"_accounts", &legacy)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for discordUserID, account := range legacy {
+		if _, ok := accounts.Get(discordUserID); !ok {
+			if err := accounts.Link(discordUserID, account.TwitchUserID, account.TwitchLogin); err != nil {
+				return err
+			}
+		}
+	}
+
+	return os.Remove(utils.DataDir + "/" + t.name + "_accounts.gob")
+}
@@ -0,0 +1,94 @@
+package twitch
+
+import (
+	"strings"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+// Lets a guild refer to a twitch channel by a friendlier name in commands
+func (t *Session) AddAlias(discordGuildID string, alias string, twitchID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	alias, twitchID = strings.ToLower(alias), strings.ToLower(twitchID)
+	if alias == twitchID {
+		return constants.ErrAliasIsChannel
+	}
+
+	gs := t.getGuildSettings(discordGuildID)
+	if _, ok := gs.Aliases[alias]; ok {
+		return constants.ErrAliasExists
+	}
+	if gs.Aliases == nil {
+		gs.Aliases = make(map[string]string)
+	}
+	gs.Aliases[alias] = twitchID
+
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Removes an alias from a guild
+func (t *Session) RemoveAlias(discordGuildID string, alias string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	alias = strings.ToLower(alias)
+	if _, ok := gs.Aliases[alias]; !ok {
+		return constants.ErrAliasDoesNotExist
+	}
+
+	delete(gs.Aliases, alias)
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Returns a copy of the aliases of a guild
+func (t *Session) GetAliases(discordGuildID string) map[string]string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	aliases := make(map[string]string)
+
+	if t.guilds[discordGuildID] != nil {
+		for alias, twitchID := range t.guilds[discordGuildID].Aliases {
+			aliases[alias] = twitchID
+		}
+	}
+
+	return aliases
+}
+
+// Returns the twitch channel a name refers to in a guild, which is the name itself unless it is an alias
+func (t *Session) ResolveAlias(discordGuildID string, name string) string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	name = NormalizeLogin(name)
+
+	if gs := t.guilds[discordGuildID]; gs != nil {
+		if twitchID, ok := gs.Aliases[name]; ok {
+			return twitchID
+		}
+	}
+
+	return name
+}
+
+// Returns the login a pasted channel name refers to, accepting an @ prefix, a display name or a twitch.tv URL
+func NormalizeLogin(name string) string {
+	name = strings.TrimSpace(name)
+	name = strings.TrimPrefix(name, "@")
+
+	lower := strings.ToLower(name)
+	if !strings.Contains(lower, "://") && (strings.HasPrefix(lower, "twitch.tv/") || strings.Contains(lower, ".twitch.tv/")) {
+		name = "https://" + name
+	}
+	if login := twitchLoginFromURL(name); login != "" {
+		return strings.TrimPrefix(login, "@")
+	}
+
+	return strings.ToLower(name)
+}
@@ -0,0 +1,98 @@
+package twitch
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Live status of a guild's channels served by the live status API
+type liveStatus struct {
+	Guild    string        `json:"guild"`
+	Channels []statusEntry `json:"channels"`
+	Time     time.Time     `json:"time"`
+}
+
+// Creates a token for the live status API of a guild, replacing its previous token
+func (t *Session) NewAPIToken(discordGuildID string) (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	gs := t.getGuildSettings(discordGuildID)
+	gs.APIToken = hex.EncodeToString(b)
+
+	t.writeGuildsToDisk()
+	return gs.APIToken, nil
+}
+
+// Revokes the live status API token of a guild
+func (t *Session) RevokeAPIToken(discordGuildID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.APIToken == "" {
+		return constants.ErrAPITokenNotSet
+	}
+
+	gs.APIToken = ""
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Returns the handler serving the live status of the channels monitored by the guild query parameter as JSON.
+// Requests must carry the guild's API token as a bearer token or, for browser sources that cannot set headers,
+// as the token query parameter.
+func (t *Session) LiveStatusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		t.mu.RLock()
+		guildID, ok := t.authorizeAPIRequest(r)
+		var channels []statusEntry
+		if ok {
+			channels = t.statusEntries(guildID)
+		}
+		t.mu.RUnlock()
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Cache-Control", "no-store")
+		if err := json.NewEncoder(w).Encode(liveStatus{
+			Guild:    guildID,
+			Channels: channels,
+			Time:     time.Now().UTC(),
+		}); err != nil {
+			utils.Log.WithError(err).Error("Failed to write live status.")
+		}
+	})
+}
+
+// Returns the guild query parameter of a request and whether the request carries the guild's API token
+func (t *Session) authorizeAPIRequest(r *http.Request) (string, bool) {
+	guildID := r.URL.Query().Get("guild")
+	gs := t.guilds[guildID]
+	if gs == nil || gs.APIToken == "" {
+		return guildID, false
+	}
+
+	given := r.URL.Query().Get("token")
+	return guildID, utils.ValidBearerToken(r, gs.APIToken) || subtle.ConstantTimeCompare([]byte(given), []byte(gs.APIToken)) == 1
+}
@@ -0,0 +1,415 @@
+package twitch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/accounts"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/features"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Settings for mirroring bans between a broadcaster's Twitch channel and a guild
+type banSyncSettings struct {
+	ChannelID string // Discord channel ban confirmations are posted to
+	ToDiscord bool   // Whether Twitch bans of linked accounts are mirrored to Discord
+	ToTwitch  bool   // Whether Discord bans of linked accounts are mirrored to Twitch
+}
+
+// Ban applied by ban sync, kept so it can be reverted
+type BanRecord struct {
+	ID            int       // Number of the ban in its guild
+	Login         string    // Broadcaster whose channel the ban was synced with
+	TwitchUserID  string    // Banned Twitch user ID
+	TwitchLogin   string    // Banned Twitch login
+	DiscordUserID string    // Banned Discord user ID
+	ToDiscord     bool      // Whether a Twitch ban was mirrored to Discord, rather than the other way around
+	Reason        string    // Reason given for the original ban
+	ApprovedBy    string    // Discord username of the moderator who confirmed the ban
+	Time          time.Time // Time the ban was applied
+	Reverted      bool      // Whether the ban was lifted again
+}
+
+// Ban waiting for a moderator to confirm it
+type pendingBan struct {
+	guildID string
+	record  BanRecord
+}
+
+type banConfirmations struct {
+	mu      sync.Mutex
+	pending map[string]*pendingBan // Map of Discord message IDs to the ban they ask to confirm
+}
+
+// Mirrors bans of linked accounts between a broadcaster's Twitch channel and a guild. Confirmations are posted to discordChannelID.
+func (t *Session) SetBanSync(login string, discordGuildID string, discordChannelID string, toDiscord bool, toTwitch bool) error {
+	_, bt, err := t.broadcasterClient(login, discordGuildID)
+	if err != nil {
+		return err
+	}
+
+	if toDiscord {
+		if !features.Enabled(features.EventSub, discordGuildID) {
+			return constants.ErrFeatureDisabled
+		}
+		if err := t.ensureEventSub(helix.EventSubTypeChannelBan, bt.UserID); err != nil {
+			return err
+		}
+		if err := t.ensureEventSub(helix.EventSubTypeChannelUnban, bt.UserID); err != nil {
+			return err
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.BanSync == nil {
+		gs.BanSync = make(map[string]*banSyncSettings)
+	}
+	gs.BanSync[login] = &banSyncSettings{
+		ChannelID: discordChannelID,
+		ToDiscord: toDiscord,
+		ToTwitch:  toTwitch,
+	}
+
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Stops mirroring bans of a broadcaster in a guild
+func (t *Session) DisableBanSync(login string, discordGuildID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.BanSync[login] == nil {
+		return constants.ErrBanSyncNotConfigured
+	}
+
+	delete(gs.BanSync, login)
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Returns the most recent bans applied by ban sync in a guild, newest first
+func (t *Session) GetBanLog(discordGuildID string, limit int) []BanRecord {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+
+	records := []BanRecord{}
+	for i := len(gs.BanLog) - 1; i >= 0 && len(records) < limit; i-- {
+		records = append(records, *gs.BanLog[i])
+	}
+
+	return records
+}
+
+// Lifts a ban applied by ban sync
+func (t *Session) RevertBan(ds *discordgo.Session, discordGuildID string, id int) (BanRecord, error) {
+	t.mu.Lock()
+	record := t.findBan(discordGuildID, id)
+	var ban BanRecord
+	if record != nil {
+		ban = *record
+	}
+	t.mu.Unlock()
+
+	if record == nil {
+		return BanRecord{}, constants.ErrBanDoesNotExist
+	} else if ban.Reverted {
+		return ban, constants.ErrBanReverted
+	}
+
+	if err := t.liftBan(ds, discordGuildID, &ban); err != nil {
+		return ban, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	record.Reverted = true
+	ban.Reverted = true
+	t.writeGuildsToDisk()
+	return ban, nil
+}
+
+// Returns the ban with the given number in a guild's ban log, or nil if there is none
+func (t *Session) findBan(discordGuildID string, id int) *BanRecord {
+	for _, record := range t.getGuildSettings(discordGuildID).BanLog {
+		if record.ID == id {
+			return record
+		}
+	}
+	return nil
+}
+
+// Asks moderators of guilds mirroring Discord bans to confirm banning a linked account on Twitch
+func (t *Session) HandleDiscordBan(ds *discordgo.Session, discordGuildID string, user *discordgo.User) {
+	account, ok := accounts.Get(user.ID)
+	if !ok {
+		return
+	}
+
+	// Confirmations are posted once the lock is released
+	confirmations := make(map[string]string)
+	t.mu.Lock()
+	gs := t.getGuildSettings(discordGuildID)
+	for login, bs := range gs.BanSync {
+		// Discord bans mirrored from Twitch must not be sent back
+		if bs.ToTwitch && !t.activeBan(gs, login, user.ID, true) {
+			confirmations[login] = bs.ChannelID
+		}
+	}
+	t.mu.Unlock()
+
+	for login, channelID := range confirmations {
+		t.requestBanConfirmation(ds, discordGuildID, channelID, BanRecord{
+			Login:         login,
+			TwitchUserID:  account.TwitchUserID,
+			TwitchLogin:   account.TwitchLogin,
+			DiscordUserID: user.ID,
+			Reason:        "Banned in Discord",
+		}, fmt.Sprintf("<@%v> was banned from this Discord server. React %v to ban %v from %v's Twitch channel.",
+			user.ID, constants.ConfirmEmoji, account.TwitchLogin, login))
+	}
+}
+
+// Applies the ban a confirmation message asks for. Returns false if the message is not a ban confirmation.
+func (t *Session) ConfirmBan(ds *discordgo.Session, messageID string, moderator string) (bool, error) {
+	t.bans.mu.Lock()
+	pb := t.bans.pending[messageID]
+	delete(t.bans.pending, messageID)
+	t.bans.mu.Unlock()
+
+	if pb == nil {
+		return false, nil
+	}
+
+	// The record is kept before the ban is applied so the ban event it causes is not mirrored back
+	t.mu.Lock()
+	gs := t.getGuildSettings(pb.guildID)
+	record := &BanRecord{}
+	*record = pb.record
+	record.ID = gs.NextBanID + 1
+	record.ApprovedBy = moderator
+	record.Time = time.Now().UTC()
+	gs.BanLog = append(gs.BanLog, record)
+	gs.NextBanID = record.ID
+	ban := *record
+	t.mu.Unlock()
+
+	ds = discordFor(pb.guildID, ds)
+	err := t.applyBan(ds, pb.guildID, &ban)
+
+	t.mu.Lock()
+	if err != nil {
+		t.forgetBan(gs, record)
+	} else {
+		t.writeGuildsToDisk()
+	}
+	channelID := ""
+	if bs := gs.BanSync[ban.Login]; bs != nil {
+		channelID = bs.ChannelID
+	}
+	t.mu.Unlock()
+
+	if err != nil {
+		return true, err
+	}
+	if channelID != "" {
+		ds.ChannelMessageSend(channelID, fmt.Sprintf("Ban #%v confirmed by %v. Revert it with `%v bans revert %v`.",
+			ban.ID, moderator, constants.CommandPrefix, ban.ID))
+	}
+
+	return true, nil
+}
+
+// Removes a ban that failed to apply from a guild's ban log, giving its number back unless a later ban took one
+func (t *Session) forgetBan(gs *guildSettings, record *BanRecord) {
+	for i, r := range gs.BanLog {
+		if r == record {
+			gs.BanLog = append(gs.BanLog[:i], gs.BanLog[i+1:]...)
+			break
+		}
+	}
+	if gs.NextBanID == record.ID {
+		gs.NextBanID--
+	}
+}
+
+func (t *Session) handleBan(ds *discordgo.Session, event json.RawMessage) {
+	var ban helix.EventSubChannelBanEvent
+	if err := json.Unmarshal(event, &ban); err != nil {
+		utils.Log.WithError(err).Error("Failed to parse channel ban.")
+		return
+	}
+
+	account, ok := accounts.GetByTwitchID(ban.UserID)
+	if !ok {
+		return
+	}
+
+	// Map of guild ID to the channel confirmations are posted to, which are posted once the lock is released
+	confirmations := make(map[string]string)
+	t.mu.RLock()
+	for guildID, gs := range t.guilds {
+		// Twitch bans mirrored from Discord must not be sent back
+		bs := gs.BanSync[ban.BroadcasterUserLogin]
+		if bs != nil && bs.ToDiscord && features.Enabled(features.EventSub, guildID) &&
+			!t.activeBan(gs, ban.BroadcasterUserLogin, account.DiscordUserID, false) {
+			confirmations[guildID] = bs.ChannelID
+		}
+	}
+	t.mu.RUnlock()
+
+	for guildID, channelID := range confirmations {
+		gds := discordFor(guildID, ds)
+		if _, err := gds.GuildMember(guildID, account.DiscordUserID); err != nil {
+			continue
+		}
+
+		kind := "banned"
+		if !ban.IsPermanent {
+			kind = "timed out"
+		}
+
+		t.requestBanConfirmation(gds, guildID, channelID, BanRecord{
+			Login:         ban.BroadcasterUserLogin,
+			TwitchUserID:  ban.UserID,
+			TwitchLogin:   ban.UserLogin,
+			DiscordUserID: account.DiscordUserID,
+			ToDiscord:     true,
+			Reason:        ban.Reason,
+		}, fmt.Sprintf("%v was %v in %v's Twitch channel by %v: %v\nReact %v to ban <@%v> in this Discord server.",
+			ban.UserName, kind, ban.BroadcasterUserName, ban.ModeratorUserName, ban.Reason, constants.ConfirmEmoji, account.DiscordUserID))
+	}
+}
+
+// Lifts Discord bans that were mirrored from a Twitch ban that has been lifted
+func (t *Session) handleUnban(ds *discordgo.Session, event json.RawMessage) {
+	var unban helix.EventSubChannelUnbanEvent
+	if err := json.Unmarshal(event, &unban); err != nil {
+		utils.Log.WithError(err).Error("Failed to parse channel unban.")
+		return
+	}
+
+	// Bans are lifted once the lock is released
+	type mirroredBan struct {
+		guildID   string
+		record    *BanRecord
+		ban       BanRecord
+		channelID string
+	}
+	var lifts []mirroredBan
+	t.mu.RLock()
+	for guildID, gs := range t.guilds {
+		for _, record := range gs.BanLog {
+			if record.Reverted || !record.ToDiscord || record.Login != unban.BroadcasterUserLogin || record.TwitchUserID != unban.UserID {
+				continue
+			}
+
+			lift := mirroredBan{guildID: guildID, record: record, ban: *record}
+			if bs := gs.BanSync[record.Login]; bs != nil {
+				lift.channelID = bs.ChannelID
+			}
+			lifts = append(lifts, lift)
+		}
+	}
+	t.mu.RUnlock()
+
+	for _, lift := range lifts {
+		gds := discordFor(lift.guildID, ds)
+		if err := t.liftBan(gds, lift.guildID, &lift.ban); err != nil {
+			utils.Log.WithError(err).Error("Failed to lift mirrored Discord ban.")
+			continue
+		}
+
+		t.mu.Lock()
+		lift.record.Reverted = true
+		t.writeGuildsToDisk()
+		t.mu.Unlock()
+
+		if lift.channelID != "" {
+			gds.ChannelMessageSendComplex(lift.channelID, &discordgo.MessageSend{
+				Content:         fmt.Sprintf("%v was unbanned on Twitch, so ban #%v was reverted.", utils.SanitizeText(unban.UserName), lift.ban.ID),
+				AllowedMentions: &discordgo.MessageAllowedMentions{},
+			})
+		}
+	}
+}
+
+// Posts a message moderators react to in order to confirm a ban
+func (t *Session) requestBanConfirmation(ds *discordgo.Session, guildID string, channelID string, record BanRecord, content string) {
+	msg, err := ds.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content:         content,
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	if err != nil {
+		utils.Log.WithError(err).Error("Error sending Discord message.")
+		return
+	}
+	ds.MessageReactionAdd(channelID, msg.ID, constants.ConfirmEmoji)
+
+	t.bans.mu.Lock()
+	t.bans.pending[msg.ID] = &pendingBan{guildID: guildID, record: record}
+	t.bans.mu.Unlock()
+}
+
+// Returns whether a synced ban of a Discord user in the given direction is in effect
+func (t *Session) activeBan(gs *guildSettings, login string, discordUserID string, toDiscord bool) bool {
+	for _, record := range gs.BanLog {
+		if !record.Reverted && record.Login == login && record.DiscordUserID == discordUserID && record.ToDiscord == toDiscord {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Session) applyBan(ds *discordgo.Session, guildID string, record *BanRecord) error {
+	if record.ToDiscord {
+		return ds.GuildBanCreateWithReason(guildID, record.DiscordUserID, "Banned on Twitch: "+record.Reason, 0)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"data": map[string]string{"user_id": record.TwitchUserID, "reason": record.Reason},
+	})
+	if err != nil {
+		return err
+	}
+	return t.moderationRequest(http.MethodPost, guildID, record, bytes.NewReader(body))
+}
+
+func (t *Session) liftBan(ds *discordgo.Session, guildID string, record *BanRecord) error {
+	if record.ToDiscord {
+		return ds.GuildBanDelete(guildID, record.DiscordUserID)
+	}
+
+	return t.moderationRequest(http.MethodDelete, guildID, record, nil)
+}
+
+// Bans or unbans a Twitch user in a broadcaster's channel
+func (t *Session) moderationRequest(method string, guildID string, record *BanRecord, body io.Reader) error {
+	_, bt, err := t.broadcasterClient(record.Login, guildID)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{"broadcaster_id": {bt.UserID}, "moderator_id": {bt.UserID}}
+	if method == http.MethodDelete {
+		query.Set("user_id", record.TwitchUserID)
+	}
+
+	return t.helixRequest(method, constants.TwitchBansURL+"?"+query.Encode(), bt.AccessToken, body)
+}
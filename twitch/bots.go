@@ -0,0 +1,85 @@
+package twitch
+
+import (
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Twitch sessions of the connected bots. They are keyed by the bot's Discord user ID rather than its gateway
+// session ID, which changes whenever Discord makes the bot reconnect.
+var activeSessions = struct {
+	sync.RWMutex
+	m map[string]*Session // Map of Discord bot user IDs to twitch sessions
+}{m: make(map[string]*Session)}
+
+// Returns the Discord user ID of a bot, or an empty string before it is ready
+func botID(s *discordgo.Session) string {
+	if s.State == nil || s.State.User == nil {
+		return ""
+	}
+	return s.State.User.ID
+}
+
+// Sets the Twitch session commands of a bot are issued against, or detaches the bot if t is nil
+func setActiveSession(s *discordgo.Session, t *Session) {
+	activeSessions.Lock()
+	defer activeSessions.Unlock()
+
+	if t == nil {
+		delete(activeSessions.m, botID(s))
+	} else {
+		activeSessions.m[botID(s)] = t
+	}
+}
+
+// Discord sessions of the bots connected to each guild, used to route messages when several bots share a Twitch session
+var guildSessions = struct {
+	sync.RWMutex
+	m map[string]*discordgo.Session // Map of guild ID to the Discord session of the bot in it
+}{m: make(map[string]*discordgo.Session)}
+
+// Lets an additional Discord bot issue commands against a Twitch session that is already being monitored.
+// Notifications for the bot's guilds are sent through it.
+func AttachDiscord(t *Session, s *discordgo.Session) {
+	// The reconnect loop attaches the waiting bots under the same lock once the session connects, so a bot
+	// attached while it connects is never left waiting
+	t.reconnection.mu.Lock()
+	defer t.reconnection.mu.Unlock()
+
+	if t.connected() {
+		setActiveSession(s, t)
+	} else {
+		t.reconnection.bots = append(t.reconnection.bots, s)
+	}
+}
+
+// Polls Twitch right away after a bot resumed its gateway connection, so streams that went live
+// while it was disconnected are announced without waiting for the next poll
+func Resume(s *discordgo.Session) {
+	if t := GetSession(s); t != nil {
+		t.PollNow()
+	}
+}
+
+// Returns the Discord session of the bot in a guild, or ds if the guild has not been seen by any bot
+func discordFor(guildID string, ds *discordgo.Session) *discordgo.Session {
+	guildSessions.RLock()
+	defer guildSessions.RUnlock()
+
+	if s := guildSessions.m[guildID]; s != nil {
+		return s
+	}
+	return ds
+}
+
+func setGuildSession(guildID string, s *discordgo.Session) {
+	guildSessions.Lock()
+	defer guildSessions.Unlock()
+
+	if s == nil {
+		delete(guildSessions.m, guildID)
+	} else {
+		guildSessions.m[guildID] = s
+	}
+}
@@ -0,0 +1,39 @@
+package twitch
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Returns a Discord session of a bot with the given user ID, detached from every Twitch session when the test ends
+func newTestBot(t *testing.T, userID string) *discordgo.Session {
+	t.Helper()
+
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: userID}
+	t.Cleanup(func() { setActiveSession(s, nil) })
+	return s
+}
+
+func TestAttachDiscord(t *testing.T) {
+	ts := newTestSession(t)
+
+	// Bots attached while the session is disconnected wait for it to connect
+	ts.setConnected(false)
+	waiting := newTestBot(t, "bot-waiting")
+	AttachDiscord(ts, waiting)
+	if GetSession(waiting) != nil {
+		t.Error("bot attached to a disconnected session")
+	}
+	if len(ts.reconnection.bots) != 1 || ts.reconnection.bots[0] != waiting {
+		t.Errorf("bot is not waiting for the session to connect: %v", ts.reconnection.bots)
+	}
+
+	ts.setConnected(true)
+	attached := newTestBot(t, "bot-attached")
+	AttachDiscord(ts, attached)
+	if GetSession(attached) != ts {
+		t.Error("bot not attached to a connected session")
+	}
+}
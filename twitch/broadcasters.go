@@ -0,0 +1,236 @@
+package twitch
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Scopes a broadcaster grants the bot when linking their account
+var broadcasterScopes = []string{
+	"channel:manage:broadcast",
+	"channel:read:redemptions",
+	"channel:read:polls",
+	"channel:read:predictions",
+	"channel:read:subscriptions",
+	"channel:moderate",
+	"moderator:manage:banned_users",
+	"moderator:manage:shoutouts",
+	"channel:read:goals",
+}
+
+// Authorization a broadcaster granted the bot to act on their channel
+type broadcasterToken struct {
+	UserID        string          // Twitch user ID of the broadcaster
+	Login         string          // Twitch login of the broadcaster
+	DiscordUserID string          // Discord user that linked the broadcaster
+	GuildIDs      map[string]bool // Discord guilds allowed to act on the broadcaster's channel
+	AccessToken   string          // Twitch user access token
+	RefreshToken  string          // Twitch refresh token
+	Scopes        []string        // Scopes granted by the broadcaster
+	Expiry        time.Time       // Time the access token expires
+}
+
+// Starts linking a broadcaster's Twitch account. The returned device code must be completed
+// by the broadcaster, after which the account is linked to the guild in the background
+// and done is called with the linked login or an error.
+func (t *Session) LinkBroadcaster(discordUserID string, discordGuildID string, done func(login string, err error)) (*DeviceCode, error) {
+	dc, err := t.requestDeviceCode(broadcasterScopes)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		token, err := t.waitForDeviceToken(dc)
+		if err != nil {
+			done("", err)
+			return
+		}
+
+		client, err := t.userClient(token.AccessToken)
+		if err != nil {
+			done("", err)
+			return
+		}
+
+		resp, err := client.GetUsers(&helix.UsersParams{})
+		if err != nil {
+			done("", err)
+			return
+		} else if len(resp.Data.Users) == 0 {
+			done("", constants.ErrTwitchUserDoesNotExist)
+			return
+		}
+
+		user := resp.Data.Users[0]
+		t.broadcasterMu.Lock()
+		bt := t.broadcasters[user.Login]
+		if bt == nil {
+			bt = &broadcasterToken{GuildIDs: make(map[string]bool)}
+			t.broadcasters[user.Login] = bt
+		}
+
+		bt.UserID = user.ID
+		bt.Login = user.Login
+		bt.DiscordUserID = discordUserID
+		bt.GuildIDs[discordGuildID] = true
+		bt.AccessToken = token.AccessToken
+		bt.RefreshToken = token.RefreshToken
+		bt.Scopes = token.Scopes
+		bt.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+
+		t.writeBroadcastersToDisk()
+		t.broadcasterMu.Unlock()
+
+		done(user.Login, nil)
+	}()
+
+	return dc, nil
+}
+
+// Unlinks a broadcaster from a guild, forgetting the authorization once no guild uses it
+func (t *Session) UnlinkBroadcaster(login string, discordGuildID string) error {
+	t.broadcasterMu.Lock()
+	defer t.broadcasterMu.Unlock()
+
+	bt := t.broadcasters[login]
+	if bt == nil || !bt.GuildIDs[discordGuildID] {
+		return constants.ErrBroadcasterNotLinked
+	}
+
+	delete(bt.GuildIDs, discordGuildID)
+	if len(bt.GuildIDs) == 0 {
+		delete(t.broadcasters, login)
+	}
+
+	t.writeBroadcastersToDisk()
+	return nil
+}
+
+// Returns the logins of broadcasters linked to a guild
+func (t *Session) GetLinkedBroadcasters(discordGuildID string) []string {
+	t.broadcasterMu.Lock()
+	defer t.broadcasterMu.Unlock()
+
+	logins := []string{}
+
+	for login, bt := range t.broadcasters {
+		if bt.GuildIDs[discordGuildID] {
+			logins = append(logins, login)
+		}
+	}
+	sort.Strings(logins)
+
+	return logins
+}
+
+// Returns a helix client authorized as a broadcaster linked to the guild and a copy of the broadcaster's authorization,
+// refreshing the token if needed
+func (t *Session) broadcasterClient(login string, discordGuildID string) (*helix.Client, *broadcasterToken, error) {
+	t.broadcasterMu.Lock()
+	defer t.broadcasterMu.Unlock()
+
+	bt := t.broadcasters[login]
+	if bt == nil || !bt.GuildIDs[discordGuildID] {
+		return nil, nil, constants.ErrBroadcasterNotLinked
+	}
+
+	if time.Until(bt.Expiry) < time.Minute {
+		resp, err := t.helixClient().RefreshUserAccessToken(bt.RefreshToken)
+		if err != nil {
+			return nil, nil, err
+		} else if resp.Data.AccessToken == "" {
+			return nil, nil, constants.ErrInvalidToken
+		}
+
+		bt.AccessToken = resp.Data.AccessToken
+		bt.RefreshToken = resp.Data.RefreshToken
+		bt.Expiry = time.Now().Add(time.Duration(resp.Data.ExpiresIn) * time.Second)
+		t.writeBroadcastersToDisk()
+	}
+
+	token := *bt
+	client, err := t.userClient(token.AccessToken)
+	return client, &token, err
+}
+
+// Returns a helix client that sends requests with a user access token
+func (t *Session) userClient(accessToken string) (*helix.Client, error) {
+	id, secret := t.appCredentials()
+	return helix.NewClient(&helix.Options{
+		HTTPClient:      utils.HTTPClient,
+		ClientID:        id,
+		ClientSecret:    secret,
+		UserAccessToken: accessToken,
+	})
+}
+
+// Sends a request to a Helix endpoint the helix client does not support with a user access token
+func (t *Session) helixRequest(method string, url string, accessToken string, body io.Reader) error {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	id, _ := t.appCredentials()
+	req.Header.Set("Client-Id", id)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := utils.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		utils.Log.WithField("StatusCode", resp.StatusCode).Errorf("Twitch request to %v failed.\n", req.URL.Path)
+		return constants.ErrTwitchQueryFailed
+	}
+
+	return nil
+}
+
+// Sends a GET request to a Helix endpoint the helix client does not support and decodes the JSON response into v
+func (t *Session) helixGet(url string, accessToken string, v interface{}) (int, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	id, _ := t.appCredentials()
+	req.Header.Set("Client-Id", id)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := utils.HTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil
+	}
+
+	return resp.StatusCode, json.NewDecoder(resp.Body).Decode(v)
+}
+
+func (t *Session) readBroadcastersFromDisk() error {
+	err := utils.ReadGobFromDisk(utils.DataDir, t.name+"_broadcasters", &t.broadcasters)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (t *Session) writeBroadcastersToDisk() {
+	if err := utils.WriteGobToDisk(utils.DataDir, t.name+"_broadcasters", t.broadcasters); err != nil {
+		utils.Log.WithError(err).Error("Error writing broadcaster authorizations to disk.")
+	}
+}
@@ -0,0 +1,182 @@
+package twitch
+
+import (
+	"image"
+	_ "image/jpeg" // Registers the decoders of the image formats Twitch serves avatars in
+	_ "image/png"
+	"net/http"
+	"strings"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// ColorAuto makes a registration use the dominant color of the streamer's avatar
+const ColorAuto = -1
+
+// Sets the live embed color of a registration. ColorAuto samples the streamer's avatar and 0 restores the default.
+func (t *Session) SetChannelColor(twitchID string, discordGuildID string, discordChannelID string, color int) error {
+	t.mu.RLock()
+	var logoURL string
+	sampled := false
+	if idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID); idx >= 0 {
+		tci := t.twitchData[twitchID]
+		logoURL = tci.LogoURL
+		sampled = tci.AvatarColorURL == tci.LogoURL && tci.AvatarColor != 0
+	}
+	t.mu.RUnlock()
+
+	// The avatar is downloaded without the lock
+	avatarColor := 0
+	if color == ColorAuto && !sampled && logoURL != "" {
+		var err error
+		if avatarColor, err = sampleAvatarColor(logoURL); err != nil {
+			return err
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	if idx < 0 {
+		return constants.ErrTwitchUserNotRegistered
+	}
+
+	tci := t.twitchData[twitchID]
+	if avatarColor != 0 && tci.LogoURL == logoURL {
+		tci.AvatarColor = avatarColor
+		tci.AvatarColorURL = logoURL
+	}
+	tci.DiscordChannels[discordGuildID][idx].Color = color
+
+	t.writeDataToDisk()
+
+	return nil
+}
+
+// Sets the live embed color used while a game is played in a guild. A color of 0 removes it.
+func (t *Session) SetGameColor(discordGuildID string, game string, color int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	game = strings.ToLower(game)
+
+	if color == 0 {
+		if _, ok := gs.GameColors[game]; !ok {
+			return constants.ErrGameColorNotSet
+		}
+		delete(gs.GameColors, game)
+	} else {
+		if gs.GameColors == nil {
+			gs.GameColors = make(map[string]int)
+		}
+		gs.GameColors[game] = color
+	}
+
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Returns a copy of the game colors of a guild
+func (t *Session) GetGameColors(discordGuildID string) map[string]int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	colors := make(map[string]int)
+
+	if t.guilds[discordGuildID] != nil {
+		for game, color := range t.guilds[discordGuildID].GameColors {
+			colors[game] = color
+		}
+	}
+
+	return colors
+}
+
+// Returns the color of a registration's live embed.
+// The registration's color takes precedence over the game's color, which takes precedence over the profile's color.
+func (t *Session) embedColor(guildID string, dc *discordChannel, tci *twitchChannelInfo, p *Profile) int {
+	if dc.Color == ColorAuto && tci.AvatarColorURL == tci.LogoURL && tci.AvatarColor != 0 {
+		return tci.AvatarColor
+	} else if dc.Color > 0 {
+		return dc.Color
+	}
+
+	if gs := t.guilds[guildID]; gs != nil && tci.StreamData != nil {
+		if color, ok := gs.GameColors[strings.ToLower(tci.StreamData.GameName)]; ok {
+			return color
+		}
+	}
+
+	return p.color()
+}
+
+// Returns the dominant color of a streamer's avatar, which is cached until the avatar changes
+func sampleAvatarColor(logoURL string) (int, error) {
+	resp, err := utils.HTTPClient.Get(logoURL)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, constants.ErrAvatarUnavailable
+	}
+
+	img, _, err := image.Decode(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	return dominantColor(img), nil
+}
+
+// Returns the average color of the most common group of similar colors in an image.
+// Transparent pixels are ignored and a color of 0 is returned as 1 so it isn't mistaken for no color.
+func dominantColor(img image.Image) int {
+	type bucket struct {
+		count   int
+		r, g, b int
+	}
+	buckets := make(map[int]*bucket)
+	var best *bucket
+
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+			r, g, b = r>>8, g>>8, b>>8
+
+			// Colors are grouped by the upper 3 bits of each channel
+			key := int(r>>5)<<6 | int(g>>5)<<3 | int(b>>5)
+			bk := buckets[key]
+			if bk == nil {
+				bk = &bucket{}
+				buckets[key] = bk
+			}
+			bk.count++
+			bk.r += int(r)
+			bk.g += int(g)
+			bk.b += int(b)
+
+			if best == nil || bk.count > best.count {
+				best = bk
+			}
+		}
+	}
+
+	if best == nil {
+		return constants.DiscordLiveColor
+	}
+
+	color := (best.r/best.count)<<16 | (best.g/best.count)<<8 | best.b/best.count
+	if color == 0 {
+		color = 1
+	}
+	return color
+}
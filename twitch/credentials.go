@@ -0,0 +1,124 @@
+package twitch
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Switches the session to new Twitch app credentials. The new credentials must produce a valid
+// app access token before the old client is replaced, so monitoring continues uninterrupted.
+func (t *Session) RotateCredentials(id string, secret string) error {
+	client, err := helix.NewClient(&helix.Options{
+		HTTPClient:   utils.HTTPClient,
+		ClientID:     id,
+		ClientSecret: secret,
+		RedirectURI:  "http://localhost",
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.RequestAppAccessToken([]string{""})
+	if err != nil {
+		return err
+	} else if resp.Data.AccessToken == "" {
+		return constants.ErrInvalidCredentials
+	}
+	client.SetAppAccessToken(resp.Data.AccessToken)
+
+	if isValid, _, err := client.ValidateToken(resp.Data.AccessToken); err != nil {
+		return err
+	} else if !isValid {
+		return constants.ErrInvalidCredentials
+	}
+
+	// A token refresh of the old client must not finish after the swap and mark the session connected with it
+	t.mu.Lock()
+	t.tokenMu.Lock()
+	t.clientMu.Lock()
+	old := t.client
+	t.client = client
+	t.clientID = id
+	t.clientSecret = secret
+	t.clientMu.Unlock()
+	t.setConnected(true)
+	t.tokenMu.Unlock()
+	t.mu.Unlock()
+
+	if old != nil {
+		if _, err := old.RevokeUserAccessToken(old.GetAppAccessToken()); err != nil {
+			utils.Log.WithError(err).Warn("Failed to revoke the previous Twitch app access token.")
+		}
+	}
+
+	utils.Log.Info("Twitch credentials rotated.")
+	return nil
+}
+
+// Returns the Helix client of the session
+func (t *Session) helixClient() *helix.Client {
+	t.clientMu.RLock()
+	defer t.clientMu.RUnlock()
+
+	return t.client
+}
+
+// Validates the app access token of the session. The helix client sets the token it validates on
+// itself for the request, so the token is validated with a client of its own instead of the shared one.
+func (t *Session) validateToken() (bool, *helix.ValidateTokenResponse, error) {
+	client := t.helixClient()
+	if client == nil {
+		return false, nil, constants.ErrNoTwitchClient
+	}
+
+	token := client.GetAppAccessToken()
+	validator, err := t.userClient(token)
+	if err != nil {
+		return false, nil, err
+	}
+	return validator.ValidateToken(token)
+}
+
+// Returns the client ID and secret of the session's Twitch app
+func (t *Session) appCredentials() (string, string) {
+	t.clientMu.RLock()
+	defer t.clientMu.RUnlock()
+
+	return t.clientID, t.clientSecret
+}
+
+// Returns the handler that rotates Twitch credentials from a JSON body with client_id and client_secret.
+// Requests must carry token as a bearer token.
+func (t *Session) CredentialsHandler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if !utils.ValidBearerToken(r, token) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var body struct {
+			ClientID     string `json:"client_id"`
+			ClientSecret string `json:"client_secret"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, constants.APIRequestMaxBytes)).Decode(&body); err != nil || body.ClientID == "" || body.ClientSecret == "" {
+			http.Error(w, "client_id and client_secret are required.", http.StatusBadRequest)
+			return
+		}
+
+		if err := t.RotateCredentials(body.ClientID, body.ClientSecret); err != nil {
+			utils.Log.WithError(err).Error("Failed to rotate Twitch credentials.")
+			http.Error(w, "The new credentials could not be verified. The previous credentials are still in use.", http.StatusUnprocessableEntity)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
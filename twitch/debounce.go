@@ -0,0 +1,69 @@
+package twitch
+
+import (
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+// Time a stream must stay live or offline before the state machine moves to StateLive or StateOffline
+type debounce struct {
+	live    time.Duration
+	offline time.Duration
+}
+
+// Sets the default time a stream must stay live before it is announced and offline before its message is ended.
+// A value of 0 keeps TwitchStateChangeTime.
+func (t *Session) SetDebounce(live time.Duration, offline time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.debounce = debounce{constants.TwitchStateChangeTime, constants.TwitchStateChangeTime}
+	if live > 0 {
+		t.debounce.live = live
+	}
+	if offline > 0 {
+		t.debounce.offline = offline
+	}
+}
+
+// Overrides the debounce of a single registration. A value of 0 uses the default.
+func (t *Session) SetChannelDebounce(twitchID string, discordGuildID string, discordChannelID string, live time.Duration, offline time.Duration) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if live < 0 || live > constants.MaxDebounce || offline < 0 || offline > constants.MaxDebounce {
+		return constants.ErrInvalidDebounce
+	}
+
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	if idx < 0 {
+		return constants.ErrTwitchUserNotRegistered
+	}
+
+	dc := t.twitchData[twitchID].DiscordChannels[discordGuildID][idx]
+	dc.LiveDebounce = live
+	dc.OfflineDebounce = offline
+
+	t.writeDataToDisk()
+
+	return nil
+}
+
+// Returns whether a stream has been live long enough to be announced in a registration
+func (t *Session) liveConfirmed(dc *discordChannel, tci *twitchChannelInfo) bool {
+	d := t.debounce.live
+	if dc.LiveDebounce > 0 {
+		d = dc.LiveDebounce
+	}
+	return tci.StreamData != nil && time.Since(tci.StartTime) > d
+}
+
+// Returns whether a stream has been offline long enough to end its message in a registration
+func (t *Session) offlineConfirmed(dc *discordChannel, tci *twitchChannelInfo) bool {
+	d := t.debounce.offline
+	if dc.OfflineDebounce > 0 {
+		d = dc.OfflineDebounce
+	}
+	return tci.StreamData == nil && time.Since(tci.EndTime) > d
+}
@@ -0,0 +1,55 @@
+package twitch
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Time Discord took to deliver the live announcements of a guild since the bot started
+type Delivery struct {
+	GuildID       string        // ID of the Discord guild
+	Announcements int           // Number of live announcements delivered
+	Average       time.Duration // Average time Discord took to accept an announcement
+	Slowest       time.Duration // Longest time Discord took to accept an announcement
+}
+
+var deliveries = struct {
+	sync.Mutex
+	guilds map[string]*Delivery // Map of Discord guild IDs to their delivery times
+}{guilds: make(map[string]*Delivery)}
+
+// Records the time Discord took to accept a live announcement of a guild
+func recordDelivery(guildID string, d time.Duration) {
+	deliveries.Lock()
+	defer deliveries.Unlock()
+
+	g := deliveries.guilds[guildID]
+	if g == nil {
+		g = &Delivery{GuildID: guildID}
+		deliveries.guilds[guildID] = g
+	}
+
+	g.Average = (g.Average*time.Duration(g.Announcements) + d) / time.Duration(g.Announcements+1)
+	g.Announcements++
+	if d > g.Slowest {
+		g.Slowest = d
+	}
+}
+
+// Returns the guilds Discord took the longest to deliver announcements to, slowest first
+func (t *Session) SlowestDeliveries(limit int) []Delivery {
+	deliveries.Lock()
+	defer deliveries.Unlock()
+
+	slowest := []Delivery{}
+	for _, g := range deliveries.guilds {
+		slowest = append(slowest, *g)
+	}
+	sort.Slice(slowest, func(i, j int) bool { return slowest[i].Average > slowest[j].Average })
+	if len(slowest) > limit {
+		slowest = slowest[:limit]
+	}
+
+	return slowest
+}
@@ -0,0 +1,110 @@
+package twitch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// DeviceCode is a pending authorization a Twitch user completes at VerificationURI
+type DeviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+type deviceToken struct {
+	AccessToken  string   `json:"access_token"`
+	RefreshToken string   `json:"refresh_token"`
+	ExpiresIn    int      `json:"expires_in"`
+	Scopes       []string `json:"scope"`
+	Message      string   `json:"message"`
+}
+
+// Starts a device code authorization requesting scopes from a Twitch user
+func (t *Session) requestDeviceCode(scopes []string) (*DeviceCode, error) {
+	id, _ := t.appCredentials()
+	resp, err := utils.HTTPClient.PostForm(constants.TwitchDeviceURL, url.Values{
+		"client_id": {id},
+		"scopes":    {strings.Join(scopes, " ")},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, constants.ErrTwitchQueryFailed
+	}
+
+	dc := &DeviceCode{}
+	if err := json.NewDecoder(resp.Body).Decode(dc); err != nil {
+		return nil, err
+	}
+
+	return dc, nil
+}
+
+// Polls Twitch until the user completes the authorization or the device code expires
+func (t *Session) waitForDeviceToken(dc *DeviceCode) (*deviceToken, error) {
+	interval := time.Duration(dc.Interval) * time.Second
+	if interval <= 0 {
+		interval = time.Second * 5
+	}
+	expiry := time.Now().Add(time.Duration(dc.ExpiresIn) * time.Second)
+
+	for time.Now().Before(expiry) {
+		time.Sleep(interval)
+
+		id, secret := t.appCredentials()
+		resp, err := utils.HTTPClient.PostForm(constants.TwitchTokenURL, url.Values{
+			"client_id":     {id},
+			"client_secret": {secret},
+			"device_code":   {dc.DeviceCode},
+			"grant_type":    {"urn:ietf:params:oauth:grant-type:device_code"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		token := &deviceToken{}
+		err = json.NewDecoder(resp.Body).Decode(token)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			return token, nil
+		} else if token.Message != "authorization_pending" && token.Message != "slow_down" {
+			return nil, constants.ErrAuthorizationDenied
+		}
+	}
+
+	return nil, constants.ErrAuthorizationExpired
+}
+
+// Obtains a user access token with scopes for the application, calling prompt with the code the user has to enter
+func RequestUserToken(clientID string, clientSecret string, scopes []string, prompt func(*DeviceCode)) (string, string, error) {
+	t := &Session{clientID: clientID, clientSecret: clientSecret}
+
+	dc, err := t.requestDeviceCode(scopes)
+	if err != nil {
+		return "", "", err
+	}
+	prompt(dc)
+
+	token, err := t.waitForDeviceToken(dc)
+	if err != nil {
+		return "", "", err
+	}
+
+	return token.AccessToken, token.RefreshToken, nil
+}
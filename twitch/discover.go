@@ -0,0 +1,74 @@
+package twitch
+
+import (
+	"fmt"
+
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+// Live stream of a category suggested for a guild to monitor
+type Suggestion struct {
+	Login       string // Twitch login of the streamer
+	DisplayName string // Twitch display name of the streamer
+	Title       string // Title of the stream
+	Viewers     int    // Viewer count of the stream
+}
+
+// Searches the live streams of a Twitch category with at least minViewers viewers, most viewed first.
+// Channels the guild already monitors are left out. Returns the name of the category as Twitch spells it.
+func (t *Session) Discover(discordGuildID string, category string, minViewers int) (string, []Suggestion, error) {
+	if !validateAndRefreshAuthToken(t) {
+		return "", nil, constants.ErrInvalidToken
+	}
+
+	t.limiter.wait()
+	games, err := t.helixClient().GetGames(&helix.GamesParams{Names: []string{category}})
+	if err != nil {
+		return "", nil, err
+	}
+	t.limiter.update(&games.ResponseCommon)
+	if games.StatusCode != 200 {
+		return "", nil, fmt.Errorf("%w: %v %v", constants.ErrTwitchQueryFailed, games.StatusCode, games.ErrorMessage)
+	}
+	if len(games.Data.Games) == 0 {
+		return "", nil, constants.ErrCategoryDoesNotExist
+	}
+	game := games.Data.Games[0]
+
+	// Twitch returns the streams of a category sorted by viewer count
+	t.limiter.wait()
+	streams, err := t.helixClient().GetStreams(&helix.StreamsParams{
+		First:   constants.TwitchQueryBatchSize,
+		GameIDs: []string{game.ID},
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	t.limiter.update(&streams.ResponseCommon)
+	if streams.StatusCode != 200 {
+		return "", nil, fmt.Errorf("%w: %v %v", constants.ErrTwitchQueryFailed, streams.StatusCode, streams.ErrorMessage)
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	suggestions := []Suggestion{}
+	for _, stream := range streams.Data.Streams {
+		if len(suggestions) == constants.DiscoverResults || stream.ViewerCount < minViewers {
+			break
+		}
+		if tci := t.twitchData[NormalizeLogin(stream.UserLogin)]; tci != nil && len(tci.DiscordChannels[discordGuildID]) > 0 {
+			continue
+		}
+
+		suggestions = append(suggestions, Suggestion{
+			Login:       stream.UserLogin,
+			DisplayName: stream.UserName,
+			Title:       stream.Title,
+			Viewers:     stream.ViewerCount,
+		})
+	}
+
+	return game.Name, suggestions, nil
+}
@@ -0,0 +1,93 @@
+package twitch
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Tag Twitch adds to streams with a Drops campaign running
+const dropsTag = "DropsEnabled"
+
+// Only the tags of a stream are read, since the helix client does not decode them
+type streamTags struct {
+	Data []struct {
+		UserLogin string   `json:"user_login"`
+		Tags      []string `json:"tags"`
+	} `json:"data"`
+}
+
+// Sets whether only streams with Drops enabled are announced in a guild
+func (t *Session) SetDropsOnly(discordGuildID string, enabled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	gs.DropsOnly = enabled
+	t.writeGuildsToDisk()
+}
+
+// Returns whether only streams with Drops enabled are announced in a guild
+func (t *Session) GetDropsOnly(discordGuildID string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.dropsOnly(discordGuildID)
+}
+
+func (t *Session) dropsOnly(discordGuildID string) bool {
+	gs := t.guilds[discordGuildID]
+	return gs != nil && gs.DropsOnly
+}
+
+// Looks up the tags of streams that just went live. Returns a map of Twitch channel to the tags of its stream.
+// Twitch is queried without the lock, the tags are applied with setTags once it is taken.
+func (t *Session) queryTags(channels []string) map[string][]string {
+	tags := make(map[string][]string)
+	for start := 0; start < len(channels); start += constants.TwitchQueryBatchSize {
+		end := start + constants.TwitchQueryBatchSize
+		if end > len(channels) {
+			end = len(channels)
+		}
+
+		query := url.Values{"user_login": channels[start:end], "first": {fmt.Sprint(constants.TwitchQueryBatchSize)}}
+
+		var resp streamTags
+		t.limiter.wait()
+		if status, err := t.helixGet(constants.TwitchStreamsURL+"?"+query.Encode(), t.helixClient().GetAppAccessToken(), &resp); err != nil {
+			utils.Log.WithError(err).Error("Failed to query twitch for stream tags.")
+			break
+		} else if status != 200 {
+			utils.Log.WithField("StatusCode", status).Error("HTTP Error returned from twitch.")
+			break
+		}
+
+		for _, stream := range resp.Data {
+			tags[NormalizeLogin(stream.UserLogin)] = stream.Tags
+		}
+	}
+
+	return tags
+}
+
+// Sets the tags of streams looked up with queryTags and whether they have Drops enabled
+func (t *Session) setTags(tags map[string][]string) {
+	for twitchID, streamTags := range tags {
+		if tci := t.twitchData[twitchID]; tci != nil && tci.StreamData != nil {
+			tci.DropsEnabled = hasTag(streamTags, dropsTag)
+			tci.Tags = streamTags
+		}
+	}
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package twitch
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+// Builds a Discord embed, only setting the parts that have a value and truncating text to Discord's limits,
+// since Discord rejects embeds with empty fields or text that is too long
+type embedBuilder struct {
+	embed *discordgo.MessageEmbed
+}
+
+func newEmbed() *embedBuilder {
+	return &embedBuilder{embed: &discordgo.MessageEmbed{}}
+}
+
+func (b *embedBuilder) title(title string, url string) *embedBuilder {
+	b.embed.Title = truncateText(strings.TrimSpace(title), constants.DiscordMaxEmbedTitle)
+	b.embed.URL = url
+	return b
+}
+
+func (b *embedBuilder) description(description string) *embedBuilder {
+	b.embed.Description = truncateText(strings.TrimSpace(description), constants.DiscordMaxEmbedDescription)
+	return b
+}
+
+func (b *embedBuilder) color(color int) *embedBuilder {
+	b.embed.Color = color
+	return b
+}
+
+func (b *embedBuilder) author(name string) *embedBuilder {
+	if name = strings.TrimSpace(name); name != "" {
+		b.embed.Author = &discordgo.MessageEmbedAuthor{Name: truncateText(name, constants.DiscordMaxEmbedAuthor)}
+	}
+	return b
+}
+
+func (b *embedBuilder) thumbnail(url string) *embedBuilder {
+	if url != "" {
+		b.embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: url}
+	}
+	return b
+}
+
+func (b *embedBuilder) image(url string) *embedBuilder {
+	if url != "" {
+		b.embed.Image = &discordgo.MessageEmbedImage{URL: url}
+	}
+	return b
+}
+
+// Adds a field unless its name or value is empty or the embed already has the most fields Discord allows
+func (b *embedBuilder) field(name string, value string, inline bool) *embedBuilder {
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	if name == "" || value == "" || len(b.embed.Fields) >= constants.DiscordMaxEmbedFields {
+		return b
+	}
+
+	b.embed.Fields = append(b.embed.Fields, &discordgo.MessageEmbedField{
+		Name:   truncateText(name, constants.DiscordMaxFieldName),
+		Value:  truncateText(value, constants.DiscordMaxFieldValue),
+		Inline: inline,
+	})
+	return b
+}
+
+func (b *embedBuilder) fields(fields []*discordgo.MessageEmbedField) *embedBuilder {
+	for _, f := range fields {
+		b.field(f.Name, f.Value, f.Inline)
+	}
+	return b
+}
+
+func (b *embedBuilder) footer(text string) *embedBuilder {
+	if text = strings.TrimSpace(text); text != "" {
+		b.embed.Footer = &discordgo.MessageEmbedFooter{Text: truncateText(text, constants.DiscordMaxEmbedFooter)}
+	} else {
+		b.embed.Footer = nil
+	}
+	return b
+}
+
+// Returns the embed. If its text is longer than Discord allows in total, the description is shortened
+// and then trailing fields are dropped.
+func (b *embedBuilder) build() *discordgo.MessageEmbed {
+	if excess := embedLength(b.embed) - constants.DiscordMaxEmbedTotal; excess > 0 {
+		if n := utf8.RuneCountInString(b.embed.Description) - excess; n > 0 {
+			b.embed.Description = truncateText(b.embed.Description, n)
+		} else {
+			b.embed.Description = ""
+		}
+	}
+	for len(b.embed.Fields) > 0 && embedLength(b.embed) > constants.DiscordMaxEmbedTotal {
+		b.embed.Fields = b.embed.Fields[:len(b.embed.Fields)-1]
+	}
+	return b.embed
+}
+
+// Returns the number of characters of an embed counted towards Discord's total limit
+func embedLength(e *discordgo.MessageEmbed) int {
+	n := utf8.RuneCountInString(e.Title) + utf8.RuneCountInString(e.Description)
+	if e.Author != nil {
+		n += utf8.RuneCountInString(e.Author.Name)
+	}
+	if e.Footer != nil {
+		n += utf8.RuneCountInString(e.Footer.Text)
+	}
+	for _, f := range e.Fields {
+		n += utf8.RuneCountInString(f.Name) + utf8.RuneCountInString(f.Value)
+	}
+	return n
+}
+
+// Shortens text to at most max characters, ending it with an ellipsis if it was cut
+func truncateText(text string, max int) string {
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	if max <= 3 {
+		return string([]rune(text)[:max])
+	}
+	return string([]rune(text)[:max-3]) + "..."
+}
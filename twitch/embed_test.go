@@ -0,0 +1,107 @@
+package twitch
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+func TestTruncateText(t *testing.T) {
+	for _, tc := range []struct {
+		text     string
+		max      int
+		expected string
+	}{
+		{"short", 10, "short"},
+		{"exactly", 7, "exactly"},
+		{"too long text", 8, "too l..."},
+		{"ééééé", 4, "é..."},
+		{"abcdef", 2, "ab"},
+	} {
+		if got := truncateText(tc.text, tc.max); got != tc.expected {
+			t.Errorf("truncateText(%q, %v) = %q, expected %q", tc.text, tc.max, got, tc.expected)
+		}
+	}
+}
+
+func TestEmbedTruncatesParts(t *testing.T) {
+	long := strings.Repeat("a", 5000)
+
+	embed := newEmbed().
+		title(long, "https://twitch.tv/a").
+		description(long).
+		author(long).
+		field(long, long, true).
+		footer(long).
+		build()
+
+	for _, tc := range []struct {
+		part string
+		text string
+		max  int
+	}{
+		{"title", embed.Title, constants.DiscordMaxEmbedTitle},
+		{"author", embed.Author.Name, constants.DiscordMaxEmbedAuthor},
+		{"field name", embed.Fields[0].Name, constants.DiscordMaxFieldName},
+		{"field value", embed.Fields[0].Value, constants.DiscordMaxFieldValue},
+		{"footer", embed.Footer.Text, constants.DiscordMaxEmbedFooter},
+	} {
+		if n := utf8.RuneCountInString(tc.text); n != tc.max || !strings.HasSuffix(tc.text, "...") {
+			t.Errorf("%v has %v characters, expected %v ending in an ellipsis", tc.part, n, tc.max)
+		}
+	}
+
+	// The description fits on its own but is shortened further to keep the embed within the total limit
+	if n := utf8.RuneCountInString(embed.Description); n > constants.DiscordMaxEmbedDescription {
+		t.Errorf("description has %v characters", n)
+	}
+	if n := embedLength(embed); n > constants.DiscordMaxEmbedTotal {
+		t.Errorf("embed has %v characters, more than %v", n, constants.DiscordMaxEmbedTotal)
+	}
+}
+
+func TestEmbedDescriptionLimit(t *testing.T) {
+	embed := newEmbed().description(strings.Repeat("a", 5000)).build()
+
+	if n := utf8.RuneCountInString(embed.Description); n != constants.DiscordMaxEmbedDescription {
+		t.Errorf("description has %v characters, expected %v", n, constants.DiscordMaxEmbedDescription)
+	}
+}
+
+func TestEmbedTotalLimitDropsFields(t *testing.T) {
+	b := newEmbed().title("Title", "")
+	for i := 0; i < 10; i++ {
+		b.field(strings.Repeat("n", 100), strings.Repeat("v", 1000), false)
+	}
+	embed := b.build()
+
+	// The embed has no description to shorten, so trailing fields are dropped
+	if n := embedLength(embed); n > constants.DiscordMaxEmbedTotal {
+		t.Errorf("embed has %v characters, more than %v", n, constants.DiscordMaxEmbedTotal)
+	}
+	if len(embed.Fields) != 5 {
+		t.Errorf("expected 5 fields to fit, got %v", len(embed.Fields))
+	}
+}
+
+func TestEmbedFieldCount(t *testing.T) {
+	b := newEmbed()
+	for i := 0; i < constants.DiscordMaxEmbedFields+5; i++ {
+		b.field("Name", "Value", true)
+	}
+	b.field("", "empty name", true).field("empty value", " ", true)
+
+	if n := len(b.build().Fields); n != constants.DiscordMaxEmbedFields {
+		t.Errorf("got %v fields, expected %v", n, constants.DiscordMaxEmbedFields)
+	}
+}
+
+func TestEmbedSkipsEmptyParts(t *testing.T) {
+	embed := newEmbed().author(" ").thumbnail("").image("").footer("").field("", "", false).build()
+
+	if embed.Author != nil || embed.Thumbnail != nil || embed.Image != nil || embed.Footer != nil || len(embed.Fields) != 0 {
+		t.Errorf("empty parts were set: %+v", embed)
+	}
+}
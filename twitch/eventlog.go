@@ -0,0 +1,161 @@
+package twitch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Types of stream events
+const (
+	EventOnline  = "online"
+	EventOffline = "offline"
+	EventTitle   = "title"
+	EventGame    = "game"
+)
+
+// StreamEvent is a change of a stream detected by the monitor, persisted in the session's event log
+type StreamEvent struct {
+	Time      time.Time `json:"time"`
+	Type      string    `json:"type"`
+	Login     string    `json:"login"`
+	StreamID  string    `json:"stream_id,omitempty"`
+	Title     string    `json:"title,omitempty"`
+	Game      string    `json:"game,omitempty"`
+	StartedAt time.Time `json:"started_at,omitempty"`
+}
+
+// Appends the changes between the previous and current state of a stream to the event log
+func (t *Session) logStreamChanges(login string, prev *helix.Stream, cur *helix.Stream) {
+	now := time.Now().UTC()
+
+	switch {
+	case prev == nil && cur != nil:
+		t.logStreamEvent(StreamEvent{Time: now, Type: EventOnline, Login: login, StreamID: cur.ID, Title: cur.Title, Game: cur.GameName, StartedAt: cur.StartedAt})
+	case prev != nil && cur == nil:
+		t.logStreamEvent(StreamEvent{Time: now, Type: EventOffline, Login: login, StreamID: prev.ID})
+	case prev != nil && cur != nil:
+		if prev.Title != cur.Title {
+			t.logStreamEvent(StreamEvent{Time: now, Type: EventTitle, Login: login, StreamID: cur.ID, Title: cur.Title})
+		}
+		if prev.GameName != cur.GameName {
+			t.logStreamEvent(StreamEvent{Time: now, Type: EventGame, Login: login, StreamID: cur.ID, Game: cur.GameName})
+		}
+	}
+}
+
+func (t *Session) logStreamEvent(e StreamEvent) {
+	line, err := json.Marshal(e)
+	if err != nil {
+		utils.Log.WithError(err).Error("Error encoding stream event.")
+		return
+	}
+
+	if err := utils.AppendLineToDisk(utils.DataDir, t.name+"_events", line); err != nil {
+		utils.Log.WithError(err).Error("Error writing stream event to disk.")
+	}
+}
+
+// Returns the events in the event log of a session, oldest first
+func ReadEventLog(session string) ([]StreamEvent, error) {
+	lines, err := utils.ReadLinesFromDisk(utils.DataDir, session+"_events")
+	if errors.Is(err, os.ErrNotExist) {
+		return []StreamEvent{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	events := make([]StreamEvent, 0, len(lines))
+	for i, line := range lines {
+		var e StreamEvent
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, fmt.Errorf("line %v of the event log: %w", i+1, err)
+		}
+		events = append(events, e)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events, nil
+}
+
+// ReplayedChannel is the announcement state of a twitch channel rebuilt from the event log
+type ReplayedChannel struct {
+	Login         string        // Twitch channel
+	Live          bool          // Whether the channel was live after the last event
+	Streams       int           // Number of distinct streams
+	Announcements int           // Number of live announcements the monitor sends for the events
+	LiveTime      time.Duration // Total time spent live
+	Anomalies     []string      // Events that break the expected online and offline order or cause repeated announcements
+}
+
+// Rebuilds the announcement state of every channel from events, following the monitor's rules:
+// a stream is announced when it goes online unless it was offline for less than the state change time.
+func Replay(events []StreamEvent) []ReplayedChannel {
+	type state struct {
+		channel  *ReplayedChannel
+		onlineAt time.Time
+		offAt    time.Time
+		streams  map[string]bool
+	}
+	states := make(map[string]*state)
+
+	for _, e := range events {
+		s := states[e.Login]
+		if s == nil {
+			s = &state{channel: &ReplayedChannel{Login: e.Login}, streams: make(map[string]bool)}
+			states[e.Login] = s
+		}
+		c := s.channel
+		at := e.Time.Format(time.RFC3339)
+
+		switch e.Type {
+		case EventOnline:
+			if c.Live {
+				c.Anomalies = append(c.Anomalies, "online while already live at "+at)
+				continue
+			}
+			c.Live = true
+			s.onlineAt = e.Time
+
+			if !s.offAt.IsZero() && e.Time.Sub(s.offAt) <= constants.TwitchStateChangeTime {
+				c.Anomalies = append(c.Anomalies, fmt.Sprintf("offline for %v before %v, not announced again", e.Time.Sub(s.offAt).Round(time.Second), at))
+			} else {
+				if s.streams[e.StreamID] {
+					c.Anomalies = append(c.Anomalies, "stream "+e.StreamID+" announced again at "+at)
+				}
+				c.Announcements++
+			}
+			if !s.streams[e.StreamID] {
+				s.streams[e.StreamID] = true
+				c.Streams++
+			}
+		case EventOffline:
+			if !c.Live {
+				c.Anomalies = append(c.Anomalies, "offline while not live at "+at)
+				continue
+			}
+			c.Live = false
+			c.LiveTime += e.Time.Sub(s.onlineAt)
+			s.offAt = e.Time
+		default:
+			if !c.Live {
+				c.Anomalies = append(c.Anomalies, e.Type+" change while not live at "+at)
+			}
+		}
+	}
+
+	channels := make([]ReplayedChannel, 0, len(states))
+	for _, s := range states {
+		channels = append(channels, *s.channel)
+	}
+	sort.Slice(channels, func(i, j int) bool { return channels[i].Login < channels[j].Login })
+
+	return channels
+}
@@ -0,0 +1,154 @@
+package twitch
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Types of events sent over event sockets
+const (
+	SocketStatus  = "status"  // Sent once on connecting with every monitored channel
+	SocketLive    = "live"    // A stream went live
+	SocketOffline = "offline" // A stream ended
+	SocketUpdate  = "update"  // The title or game of a live stream changed
+)
+
+// Event sent to the event sockets of the guilds monitoring a channel
+type socketEvent struct {
+	Type     string        `json:"type"`
+	Channel  *statusEntry  `json:"channel,omitempty"`
+	Channels []statusEntry `json:"channels,omitempty"`
+	Time     time.Time     `json:"time"`
+}
+
+// eventSockets keeps the connected event sockets of each guild
+type eventSockets struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan []byte]bool // Map of guild ID to the queues of its connected sockets
+}
+
+// Overlays are browser sources on any origin, so the guild's API token rather than the origin guards the socket
+var socketUpgrader = websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+
+func (e *eventSockets) subscribe(guildID string) chan []byte {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.subscribers == nil {
+		e.subscribers = make(map[string]map[chan []byte]bool)
+	}
+	if e.subscribers[guildID] == nil {
+		e.subscribers[guildID] = make(map[chan []byte]bool)
+	}
+	queue := make(chan []byte, constants.EventSocketBuffer)
+	e.subscribers[guildID][queue] = true
+	return queue
+}
+
+func (e *eventSockets) unsubscribe(guildID string, queue chan []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.subscribers[guildID], queue)
+	if len(e.subscribers[guildID]) == 0 {
+		delete(e.subscribers, guildID)
+	}
+}
+
+// Sends an event about a channel to the sockets of every guild monitoring it.
+// Sockets too slow to keep up miss the event rather than holding up the poll.
+func (t *Session) publishSocketEvent(eventType string, twitchID string, tci *twitchChannelInfo) {
+	t.sockets.mu.Lock()
+	defer t.sockets.mu.Unlock()
+
+	if len(t.sockets.subscribers) == 0 {
+		return
+	}
+
+	entry := statusEntryOf(twitchID, tci)
+	message, err := json.Marshal(socketEvent{Type: eventType, Channel: &entry, Time: time.Now().UTC()})
+	if err != nil {
+		utils.Log.WithError(err).Error("Error encoding socket event.")
+		return
+	}
+
+	for guildID := range tci.DiscordChannels {
+		for queue := range t.sockets.subscribers[guildID] {
+			select {
+			case queue <- message:
+			default:
+			}
+		}
+	}
+}
+
+// Returns the handler streaming the live, offline and update events of the guild query parameter's channels over a WebSocket.
+// It requires the guild's API token like the live status API.
+func (t *Session) EventSocketHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.mu.RLock()
+		guildID, ok := t.authorizeAPIRequest(r)
+		t.mu.RUnlock()
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		conn, err := socketUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		queue := t.sockets.subscribe(guildID)
+		defer t.sockets.unsubscribe(guildID, queue)
+
+		utils.Log.WithFields(logrus.Fields{
+			"server_id": guildID,
+			"remote":    r.RemoteAddr}).Info("Event socket connected.")
+
+		t.mu.RLock()
+		channels := t.statusEntries(guildID)
+		t.mu.RUnlock()
+		status, err := json.Marshal(socketEvent{Type: SocketStatus, Channels: channels, Time: time.Now().UTC()})
+		if err != nil || conn.WriteMessage(websocket.TextMessage, status) != nil {
+			return
+		}
+
+		// Messages from the client are discarded, reading only notices when it disconnects
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		ping := time.NewTicker(constants.EventSocketPingInterval)
+		defer ping.Stop()
+		for {
+			select {
+			case message := <-queue:
+				conn.SetWriteDeadline(time.Now().Add(constants.EventSocketPingInterval))
+				if err := conn.WriteMessage(websocket.TextMessage, message); err != nil {
+					return
+				}
+			case <-ping.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(constants.EventSocketPingInterval)); err != nil {
+					return
+				}
+			case <-closed:
+				return
+			}
+		}
+	})
+}
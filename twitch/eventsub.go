@@ -0,0 +1,197 @@
+package twitch
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Handles the event of an EventSub notification. Listeners take the session's lock themselves.
+type eventSubListener func(ds *discordgo.Session, event json.RawMessage)
+
+type eventSubNotification struct {
+	Challenge    string                     `json:"challenge"`
+	Subscription helix.EventSubSubscription `json:"subscription"`
+	Event        json.RawMessage            `json:"event"`
+}
+
+// State of the EventSub webhook shared by every subscription of the session
+type eventSubState struct {
+	mu            sync.Mutex
+	callback      string                      // Public HTTPS URL Twitch sends notifications to
+	secret        string                      // Secret notifications are signed with
+	subscriptions map[string]bool             // Set of subscription type and broadcaster ID pairs subscribed to
+	listeners     map[string]eventSubListener // Map of subscription type to listener
+	recent        map[string]bool             // IDs of recently handled messages, used to drop retries
+	recentOrder   []string
+}
+
+// Enables EventSub subscriptions delivered to callback and signed with secret.
+// Returns the handler that must be served at callback.
+func (t *Session) EnableEventSub(callback string, secret string, ds *discordgo.Session) http.Handler {
+	t.eventSub.mu.Lock()
+	t.eventSub.callback = callback
+	t.eventSub.secret = secret
+	t.eventSub.mu.Unlock()
+
+	go t.loadEventSubs()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.handleEventSub(w, r, ds)
+	})
+}
+
+// Returns whether EventSub has been enabled for the session
+func (t *Session) eventSubEnabled() bool {
+	t.eventSub.mu.Lock()
+	defer t.eventSub.mu.Unlock()
+
+	return t.eventSub.callback != ""
+}
+
+// Registers the listener called for notifications of a subscription type
+func (t *Session) onEventSub(subType string, listener eventSubListener) {
+	t.eventSub.mu.Lock()
+	defer t.eventSub.mu.Unlock()
+
+	t.eventSub.listeners[subType] = listener
+}
+
+// Subscribes to an event of a broadcaster unless the subscription already exists
+func (t *Session) ensureEventSub(subType string, broadcasterUserID string) error {
+	if !t.eventSubEnabled() {
+		return constants.ErrEventSubDisabled
+	}
+
+	t.eventSub.mu.Lock()
+	key := subType + ":" + broadcasterUserID
+	if t.eventSub.subscriptions[key] {
+		t.eventSub.mu.Unlock()
+		return nil
+	}
+	callback, secret := t.eventSub.callback, t.eventSub.secret
+	t.eventSub.mu.Unlock()
+
+	if !validateAndRefreshAuthToken(t) {
+		return constants.ErrInvalidToken
+	}
+
+	resp, err := t.helixClient().CreateEventSubSubscription(&helix.EventSubSubscription{
+		Type:      subType,
+		Version:   "1",
+		Condition: helix.EventSubCondition{BroadcasterUserID: broadcasterUserID},
+		Transport: helix.EventSubTransport{
+			Method:   "webhook",
+			Callback: callback,
+			Secret:   secret,
+		},
+	})
+	if err != nil {
+		return err
+	} else if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusConflict {
+		utils.Log.WithField("StatusCode", resp.StatusCode).Error("Failed to create EventSub subscription: ", resp.ErrorMessage)
+		return constants.ErrTwitchQueryFailed
+	}
+
+	t.eventSub.mu.Lock()
+	t.eventSub.subscriptions[key] = true
+	t.eventSub.mu.Unlock()
+
+	return nil
+}
+
+// Loads the subscriptions that already exist on Twitch so they aren't created twice
+func (t *Session) loadEventSubs() {
+	if !validateAndRefreshAuthToken(t) {
+		return
+	}
+
+	resp, err := t.helixClient().GetEventSubSubscriptions(&helix.EventSubSubscriptionsParams{})
+	if err != nil {
+		utils.Log.WithError(err).Error("Failed to get EventSub subscriptions.")
+		return
+	}
+
+	t.eventSub.mu.Lock()
+	defer t.eventSub.mu.Unlock()
+
+	for _, sub := range resp.Data.EventSubSubscriptions {
+		if sub.Status == helix.EventSubStatusEnabled || sub.Status == helix.EventSubStatusPending {
+			t.eventSub.subscriptions[sub.Type+":"+sub.Condition.BroadcasterUserID] = true
+		}
+	}
+}
+
+func (t *Session) handleEventSub(w http.ResponseWriter, r *http.Request, ds *discordgo.Session) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, constants.EventSubMaxBytes))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	t.eventSub.mu.Lock()
+	secret := t.eventSub.secret
+	t.eventSub.mu.Unlock()
+
+	if !helix.VerifyEventSubNotification(secret, r.Header, string(body)) {
+		utils.Log.Warn("Received EventSub notification with an invalid signature.")
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	var notification eventSubNotification
+	if err := json.Unmarshal(body, &notification); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch r.Header.Get("Twitch-Eventsub-Message-Type") {
+	case "webhook_callback_verification":
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(notification.Challenge))
+	case "revocation":
+		utils.Log.WithField("status", notification.Subscription.Status).Warnf("EventSub subscription %v for %v was revoked.\n",
+			notification.Subscription.Type, notification.Subscription.Condition.BroadcasterUserID)
+
+		t.eventSub.mu.Lock()
+		delete(t.eventSub.subscriptions, notification.Subscription.Type+":"+notification.Subscription.Condition.BroadcasterUserID)
+		t.eventSub.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	case "notification":
+		w.WriteHeader(http.StatusNoContent)
+
+		t.eventSub.mu.Lock()
+		duplicate := t.markEventSubMessage(r.Header.Get("Twitch-Eventsub-Message-Id"))
+		listener := t.eventSub.listeners[notification.Subscription.Type]
+		t.eventSub.mu.Unlock()
+
+		if !duplicate && listener != nil {
+			go listener(ds, notification.Event)
+		}
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// Records a handled message ID and returns whether it was already handled.
+// Must be called with the EventSub lock held.
+func (t *Session) markEventSubMessage(id string) bool {
+	if t.eventSub.recent[id] {
+		return true
+	}
+
+	t.eventSub.recent[id] = true
+	t.eventSub.recentOrder = append(t.eventSub.recentOrder, id)
+	if len(t.eventSub.recentOrder) > constants.EventSubRecentMessages {
+		delete(t.eventSub.recent, t.eventSub.recentOrder[0])
+		t.eventSub.recentOrder = t.eventSub.recentOrder[1:]
+	}
+
+	return false
+}
@@ -0,0 +1,63 @@
+package twitch
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Returns whether an embed is within Discord's limits. Embeds from the builder always are, but scripts can change them.
+func embedWithinLimits(e *discordgo.MessageEmbed) bool {
+	if utf8.RuneCountInString(e.Title) > constants.DiscordMaxEmbedTitle ||
+		utf8.RuneCountInString(e.Description) > constants.DiscordMaxEmbedDescription ||
+		len(e.Fields) > constants.DiscordMaxEmbedFields ||
+		embedLength(e) > constants.DiscordMaxEmbedTotal {
+		return false
+	}
+	if e.Author != nil && utf8.RuneCountInString(e.Author.Name) > constants.DiscordMaxEmbedAuthor {
+		return false
+	}
+	if e.Footer != nil && utf8.RuneCountInString(e.Footer.Text) > constants.DiscordMaxEmbedFooter {
+		return false
+	}
+	for _, f := range e.Fields {
+		if f.Name == "" || f.Value == "" || utf8.RuneCountInString(f.Name) > constants.DiscordMaxFieldName ||
+			utf8.RuneCountInString(f.Value) > constants.DiscordMaxFieldValue {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns whether Discord rejected a request as malformed, which for announcements means it refused the embed
+func isBadRequest(err error) bool {
+	var restErr *discordgo.RESTError
+	return errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusBadRequest
+}
+
+// Returns the plain text live message sent when Discord refuses the embed. The content is shortened
+// rather than the link so the message always leads to the stream.
+func plainLiveMessage(content string, tci *twitchChannelInfo, names string) string {
+	text := fmt.Sprintf("**%v** is live: %v", utils.SanitizeText(tci.name(names)), utils.SanitizeText(tci.StreamData.Title))
+	text = truncateText(text, constants.DiscordMaxMessageLength-utf8.RuneCountInString(tci.url())-1) + "\n" + tci.url()
+	if content == "" {
+		return text
+	}
+
+	if n := constants.DiscordMaxMessageLength - utf8.RuneCountInString(text) - 1; n > 0 {
+		return truncateText(content, n) + "\n" + text
+	}
+	return text
+}
+
+// Returns the plain text a plain live message is replaced with once the stream ends
+func plainOfflineMessage(tci *twitchChannelInfo, names string) string {
+	return fmt.Sprintf("**%v** was live for %v.\n<%v>", utils.SanitizeText(tci.name(names)),
+		formatDuration(tci.EndTime.Sub(tci.StartTime).Round(time.Second)), tci.url())
+}
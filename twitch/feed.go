@@ -0,0 +1,121 @@
+package twitch
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+// Stream that went live, kept for the live feed
+type liveEvent struct {
+	TwitchChannel string
+	DisplayName   string
+	Title         string
+	GameName      string
+	StartTime     time.Time
+	GuildIDs      map[string]bool // Guilds monitoring the channel when it went live
+}
+
+type liveFeed struct {
+	mu     sync.Mutex
+	events []*liveEvent    // Recent live events, oldest first
+	seen   map[string]bool // Set of twitch channel and start time pairs already recorded
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title   string   `xml:"title"`
+	ID      string   `xml:"id"`
+	Updated string   `xml:"updated"`
+	Link    atomLink `xml:"link"`
+	Summary string   `xml:"summary"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+// Records that a stream went live unless it was already recorded
+func (t *Session) recordLiveEvent(twitchID string, tci *twitchChannelInfo) {
+	t.feed.mu.Lock()
+	defer t.feed.mu.Unlock()
+
+	key := twitchID + ":" + tci.StartTime.String()
+	if t.feed.seen[key] {
+		return
+	}
+	t.feed.seen[key] = true
+
+	event := &liveEvent{
+		TwitchChannel: twitchID,
+		DisplayName:   tci.DisplayName,
+		Title:         tci.StreamData.Title,
+		GameName:      tci.StreamData.GameName,
+		StartTime:     tci.StartTime,
+		GuildIDs:      make(map[string]bool),
+	}
+	for guildID := range tci.DiscordChannels {
+		event.GuildIDs[guildID] = true
+	}
+
+	t.feed.events = append(t.feed.events, event)
+	if len(t.feed.events) > constants.FeedEntries {
+		old := t.feed.events[0]
+		delete(t.feed.seen, old.TwitchChannel+":"+old.StartTime.String())
+		t.feed.events = t.feed.events[1:]
+	}
+}
+
+// Returns the handler serving an Atom feed of recent live events. The guild query parameter
+// limits the feed to channels monitored by a guild.
+func (t *Session) FeedHandler(feedURL string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		guildID := r.URL.Query().Get("guild")
+
+		feed := atomFeed{
+			Title:   "Twitch live notifications",
+			ID:      feedURL,
+			Updated: time.Now().UTC().Format(time.RFC3339),
+			Link:    atomLink{Href: feedURL},
+		}
+		if guildID != "" {
+			feed.ID = feedURL + "?guild=" + guildID
+			feed.Link.Href = feed.ID
+		}
+
+		t.feed.mu.Lock()
+		for i := len(t.feed.events) - 1; i >= 0; i-- {
+			event := t.feed.events[i]
+			if guildID != "" && !event.GuildIDs[guildID] {
+				continue
+			}
+
+			feed.Entries = append(feed.Entries, atomEntry{
+				Title:   fmt.Sprintf("%v is live: %v", event.DisplayName, event.Title),
+				ID:      fmt.Sprintf("%v#%v-%v", feedURL, event.TwitchChannel, event.StartTime.Unix()),
+				Updated: event.StartTime.UTC().Format(time.RFC3339),
+				Link:    atomLink{Href: "https://www.twitch.tv/" + event.TwitchChannel},
+				Summary: "Playing " + event.GameName,
+			})
+		}
+		t.feed.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+		w.Write([]byte(xml.Header))
+		if err := xml.NewEncoder(w).Encode(feed); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package twitch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Announcement of a stream that was already live when it was registered, waiting for a moderator to confirm it
+type pendingAnnouncement struct {
+	twitchID  string
+	guildID   string
+	channelID string
+}
+
+type announcementConfirmations struct {
+	mu      sync.Mutex
+	pending map[string]*pendingAnnouncement // Map of Discord message IDs to the announcement they ask to confirm
+}
+
+// Checks right away whether a newly registered Twitch channel is live. If it is, moderators are asked to confirm
+// announcing the stream now instead of after the next poll and the live debounce. Returns whether they were asked.
+func (t *Session) CheckRegisteredChannel(ds *discordgo.Session, twitchID string, discordGuildID string, discordChannelID string) (bool, error) {
+	t.mu.RLock()
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	confirmed, seen := false, false
+	if idx >= 0 {
+		confirmed = t.twitchData[twitchID].State == StateLive
+		seen = t.twitchData[twitchID].StreamData != nil
+	}
+	t.mu.RUnlock()
+
+	if idx < 0 {
+		return false, constants.ErrTwitchUserNotRegistered
+	}
+	// Channels the monitor loop already confirmed live are announced by the next cycle
+	if confirmed {
+		return false, nil
+	}
+
+	// Streams another registration already saw start are not queried again. Twitch is queried without the lock.
+	var streams []helix.Stream
+	var tags map[string][]string
+	if !seen {
+		var err error
+		if streams, err = t.queryStreams(context.Background(), []string{twitchID}); err != nil {
+			return false, err
+		}
+		if len(streams) > 0 {
+			tags = t.queryTags([]string{twitchID})
+		}
+	}
+
+	t.mu.Lock()
+	announce, err := t.recordRegisteredStream(ds, twitchID, discordGuildID, discordChannelID, streams, tags)
+	displayName, timeout := "", t.debounce.live
+	if announce {
+		displayName = t.twitchData[twitchID].DisplayName
+	}
+	t.mu.Unlock()
+	if err != nil || !announce {
+		return false, err
+	}
+
+	msg, err := ds.ChannelMessageSendComplex(discordChannelID, &discordgo.MessageSend{
+		Content: fmt.Sprintf("%v is live right now. React %v to announce the stream without waiting for it to be confirmed.",
+			utils.SanitizeText(displayName), constants.ConfirmEmoji),
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	if err != nil {
+		return false, err
+	}
+	ds.MessageReactionAdd(discordChannelID, msg.ID, constants.ConfirmEmoji)
+
+	t.announcements.mu.Lock()
+	t.announcements.pending[msg.ID] = &pendingAnnouncement{twitchID: twitchID, guildID: discordGuildID, channelID: discordChannelID}
+	t.announcements.mu.Unlock()
+
+	// Once the confirmation times out the monitor loop announces the stream anyway
+	time.AfterFunc(timeout, func() {
+		t.announcements.mu.Lock()
+		_, waiting := t.announcements.pending[msg.ID]
+		delete(t.announcements.pending, msg.ID)
+		t.announcements.mu.Unlock()
+
+		if waiting {
+			ds.ChannelMessageDelete(discordChannelID, msg.ID)
+		}
+	})
+
+	return true, nil
+}
+
+// Records the stream of a newly registered channel queried by CheckRegisteredChannel, unless another registration
+// already saw it start. Returns whether moderators can be asked to announce it right away.
+func (t *Session) recordRegisteredStream(ds *discordgo.Session, twitchID string, discordGuildID string, discordChannelID string, streams []helix.Stream, tags map[string][]string) (bool, error) {
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	if idx < 0 {
+		return false, constants.ErrTwitchUserNotRegistered
+	}
+
+	tcInfo := t.twitchData[twitchID]
+	if tcInfo.StreamData == nil {
+		if !populateTwitchInfo(twitchID, tcInfo, streams) {
+			return false, nil
+		}
+		t.logStreamChanges(twitchID, nil, tcInfo.StreamData)
+		t.setTags(tags)
+	}
+
+	dc := tcInfo.DiscordChannels[discordGuildID][idx]
+	return t.canAnnounceNow(discordGuildID, dc, tcInfo) && !t.matureFiltered(ds, discordGuildID, discordChannelID, tcInfo), nil
+}
+
+// Announces the stream a confirmation message asks for. Returns false if the message is not an announcement confirmation.
+func (t *Session) ConfirmAnnouncement(ds *discordgo.Session, messageID string) (bool, error) {
+	t.announcements.mu.Lock()
+	pa := t.announcements.pending[messageID]
+	delete(t.announcements.pending, messageID)
+	t.announcements.mu.Unlock()
+
+	if pa == nil {
+		return false, nil
+	}
+
+	ds = discordFor(pa.guildID, ds)
+	if err := ds.ChannelMessageDelete(pa.channelID, messageID); err != nil {
+		utils.Log.WithError(err).Debug("Failed to delete announcement confirmation.")
+	}
+
+	t.mu.Lock()
+	a, err := t.confirmedAnnouncement(ds, pa)
+	t.mu.Unlock()
+
+	if a != nil {
+		a.send(context.Background(), ds, pa.guildID, pa.twitchID)
+	}
+	return true, err
+}
+
+// Prepares the announcement of a confirmed stream. Returns nil if the registration was removed or announced by the
+// monitor loop since the confirmation was posted.
+func (t *Session) confirmedAnnouncement(ds *discordgo.Session, pa *pendingAnnouncement) (*liveAnnouncement, error) {
+	idx := t.getChannelIdx(pa.twitchID, pa.guildID, pa.channelID)
+	if idx < 0 {
+		return nil, constants.ErrTwitchUserNotRegistered
+	}
+	tcInfo := t.twitchData[pa.twitchID]
+	dc := tcInfo.DiscordChannels[pa.guildID][idx]
+	if dc.LiveNotificationSent || tcInfo.StreamData == nil {
+		return nil, nil
+	}
+
+	profile := t.withTagRoles(pa.guildID, tcInfo, t.getProfile(pa.guildID, dc.Profile))
+	if !t.rotationPing(pa.guildID, pa.twitchID, tcInfo) {
+		profile = profile.silenced()
+	}
+	style := embedStyle{t.embedColor(pa.guildID, dc, tcInfo, profile), t.embedLayout(pa.guildID), t.channelInfoFooter(pa.guildID), t.nameStyle(pa.guildID),
+		t.hidesPreview(ds, pa.guildID, pa.channelID, tcInfo), t.hiatusDays(pa.guildID), t.streaks(pa.guildID)}
+
+	// The state machine catches up on a later poll and updates or ends the announcement like any other
+	return t.announce(dc, tcInfo, profile, style), nil
+}
+
+// Returns whether a stream would be announced in a registration once it is confirmed live
+func (t *Session) canAnnounceNow(guildID string, dc *discordChannel, tcInfo *twitchChannelInfo) bool {
+	if dc.LiveNotificationSent || dc.AnnounceDelay > 0 || dc.isPaused() || t.isMuted(guildID) {
+		return false
+	}
+	if t.dropsOnly(guildID) && !tcInfo.DropsEnabled {
+		return false
+	}
+	return t.getProfile(guildID, dc.Profile).allowsGame(tcInfo.StreamData.GameName)
+}
@@ -0,0 +1,75 @@
+package twitch
+
+import (
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Only the total is read from Get Channel Followers
+type channelFollowers struct {
+	Total int `json:"total"`
+}
+
+// Sets whether live embeds in a guild show the channel description and follower count
+func (t *Session) SetChannelInfoFooter(discordGuildID string, enabled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	gs.ChannelInfo = enabled
+	t.writeGuildsToDisk()
+}
+
+// Returns whether live embeds in a guild show the channel description and follower count
+func (t *Session) GetChannelInfoFooter(discordGuildID string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.channelInfoFooter(discordGuildID)
+}
+
+func (t *Session) channelInfoFooter(discordGuildID string) bool {
+	gs := t.guilds[discordGuildID]
+	return gs != nil && gs.ChannelInfo
+}
+
+// Returns whether the description and follower count of a channel should be refreshed before it is announced, and
+// marks them refreshed. Announcements of the same stream in several guilds share one refresh.
+func infoRefreshDue(tci *twitchChannelInfo) bool {
+	if time.Since(tci.InfoUpdated) < constants.ChannelInfoRefreshTime {
+		return false
+	}
+	tci.InfoUpdated = time.Now()
+	return true
+}
+
+// Refreshes the description and follower count of the copy of a channel an announcement is sent from, and writes
+// them back to the channel
+func (t *Session) refreshChannelInfo(discordGuildID string, login string, n notified, tci *twitchChannelInfo) {
+	defer n.update(func(_ *discordChannel, orig *twitchChannelInfo) {
+		orig.Description = tci.Description
+		orig.Followers = tci.Followers
+	})
+
+	if user, err := t.lookupUser(login); err == nil {
+		tci.Description = user.Description
+	}
+
+	// Follower totals need a user token, a linked broadcaster's is used when there is one
+	token := t.helixClient().GetAppAccessToken()
+	if _, bt, err := t.broadcasterClient(login, discordGuildID); err == nil {
+		token = bt.AccessToken
+	}
+
+	var resp channelFollowers
+	t.limiter.wait()
+	status, err := t.helixGet(constants.TwitchFollowersURL+"?broadcaster_id="+tci.StreamData.UserID, token, &resp)
+	if err != nil || status != 200 {
+		utils.Log.WithFields(logrus.Fields{"twitch_channel": login, "StatusCode": status, "error": err}).Debug("Failed to query twitch for the follower count.")
+		return
+	}
+	tci.Followers = resp.Total
+}
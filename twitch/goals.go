@@ -0,0 +1,172 @@
+package twitch
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Settings for posting a broadcaster's creator goals to Discord
+type goalSettings struct {
+	ChannelID string // Discord channel the goal progress is posted to
+	MessageID string // ID of the pinned progress message
+}
+
+type creatorGoal struct {
+	Type          string `json:"type"`
+	Description   string `json:"description"`
+	CurrentAmount int    `json:"current_amount"`
+	TargetAmount  int    `json:"target_amount"`
+}
+
+// Posts the progress of a linked broadcaster's creator goals to a Discord channel
+func (t *Session) SetGoalChannel(login string, discordGuildID string, discordChannelID string) error {
+	if _, _, err := t.broadcasterClient(login, discordGuildID); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.Goals == nil {
+		gs.Goals = make(map[string]*goalSettings)
+	}
+	gs.Goals[login] = &goalSettings{ChannelID: discordChannelID}
+
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Stops posting a broadcaster's goals in a guild
+func (t *Session) DisableGoals(login string, discordGuildID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.Goals[login] == nil {
+		return constants.ErrGoalsNotConfigured
+	}
+
+	delete(gs.Goals, login)
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Periodically updates the goal progress messages of every guild
+func syncGoals(ts *Session, ds *discordgo.Session) {
+	type goalSync struct {
+		guildID string
+		login   string
+		goals   goalSettings
+	}
+	var syncs []goalSync
+	ts.mu.RLock()
+	for guildID, gs := range ts.guilds {
+		for login, goals := range gs.Goals {
+			syncs = append(syncs, goalSync{guildID, login, *goals})
+		}
+	}
+	ts.mu.RUnlock()
+
+	for _, s := range syncs {
+		ts.updateGoals(discordFor(s.guildID, ds), s.guildID, s.login, s.goals)
+	}
+}
+
+// Edits the pinned goal progress message of a broadcaster, sending and pinning a new one if it is missing
+func (t *Session) updateGoals(ds *discordgo.Session, guildID string, login string, goals goalSettings) {
+	_, bt, err := t.broadcasterClient(login, guildID)
+	if err != nil {
+		utils.Log.WithError(err).Error("Failed to authorize as broadcaster.")
+		return
+	}
+
+	var resp struct {
+		Data []creatorGoal `json:"data"`
+	}
+	if status, err := t.helixGet(constants.TwitchGoalsURL+"?broadcaster_id="+url.QueryEscape(bt.UserID), bt.AccessToken, &resp); err != nil {
+		utils.Log.WithError(err).Error("Failed to get creator goals.")
+		return
+	} else if status != http.StatusOK {
+		utils.Log.WithField("StatusCode", status).Error("Failed to get creator goals.")
+		return
+	}
+
+	embed := createDiscordGoalEmbedMessage(login, resp.Data)
+
+	if goals.MessageID != "" {
+		if _, err := ds.ChannelMessageEditEmbed(goals.ChannelID, goals.MessageID, embed); err == nil {
+			return
+		}
+	}
+
+	m, err := ds.ChannelMessageSendEmbed(goals.ChannelID, embed)
+	if err != nil {
+		utils.Log.WithError(err).Error("Error sending Discord message.")
+		return
+	}
+	if err := ds.ChannelMessagePin(goals.ChannelID, m.ID); err != nil {
+		utils.Log.WithError(err).Error("Failed to pin Discord message.")
+	}
+
+	// The goals may have been moved or removed while the message was sent
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if gs := t.guilds[guildID]; gs != nil && gs.Goals[login] != nil && gs.Goals[login].ChannelID == goals.ChannelID {
+		gs.Goals[login].MessageID = m.ID
+		t.writeGuildsToDisk()
+	}
+}
+
+func createDiscordGoalEmbedMessage(login string, goals []creatorGoal) *discordgo.MessageEmbed {
+	fields := []*discordgo.MessageEmbedField{}
+	for _, goal := range goals {
+		name := goal.Description
+		if name == "" {
+			name = goalTypeName(goal.Type) + " goal"
+		}
+
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name: name,
+			Value: fmt.Sprintf("%v %v%%\n%v / %v %v", progressBar(goal.CurrentAmount, goal.TargetAmount),
+				percent(goal.CurrentAmount, goal.TargetAmount), goal.CurrentAmount, goal.TargetAmount, goalTypeName(goal.Type)),
+			Inline: false,
+		})
+	}
+
+	description := ""
+	if len(goals) == 0 {
+		description = "There are no active goals."
+	}
+
+	return &discordgo.MessageEmbed{
+		URL:         "https://www.twitch.tv/" + login,
+		Title:       login + "'s goals",
+		Description: description,
+		Color:       constants.DiscordRewardColor,
+		Fields:      fields,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Updated",
+		},
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// Returns what a goal type counts
+func goalTypeName(goalType string) string {
+	switch goalType {
+	case "follower":
+		return "followers"
+	case "subscription", "subscription_count":
+		return "subscriptions"
+	case "new_subscription", "new_subscription_count":
+		return "new subscriptions"
+	default:
+		return goalType
+	}
+}
@@ -0,0 +1,168 @@
+package twitch
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+// GroupMember describes a registration of a group in a digest
+type GroupMember struct {
+	TwitchChannel    string        // Twitch login of the channel
+	DisplayName      string        // Twitch display name of the channel
+	DiscordChannelID string        // ID of the Discord channel notified
+	Live             bool          // Whether the channel is live
+	Paused           bool          // Whether announcements of the registration are paused
+	Game             string        // Game being played, if live
+	Uptime           time.Duration // Time since the stream started, if live
+	LastLive         time.Time     // Time the last stream ended, if offline
+}
+
+// Tags a registration with a group name, or removes it from its group if group is empty
+func (t *Session) SetChannelGroup(twitchID string, discordGuildID string, discordChannelID string, group string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	if idx < 0 {
+		return constants.ErrTwitchUserNotRegistered
+	}
+
+	t.twitchData[twitchID].DiscordChannels[discordGuildID][idx].Group = strings.ToLower(group)
+
+	t.writeDataToDisk()
+
+	return nil
+}
+
+// Calls fn with every registration of a group in a guild. Returns ErrGroupDoesNotExist if the group has none.
+func (t *Session) forGroup(discordGuildID string, group string, fn func(twitchID string, tci *twitchChannelInfo, dc *discordChannel)) error {
+	group = strings.ToLower(group)
+	found := false
+
+	for twitchID, tci := range t.twitchData {
+		for _, dc := range tci.DiscordChannels[discordGuildID] {
+			if dc.Group == group {
+				found = true
+				fn(twitchID, tci, dc)
+			}
+		}
+	}
+
+	if !found {
+		return constants.ErrGroupDoesNotExist
+	}
+	return nil
+}
+
+// Returns the groups of a guild mapped to the display names of their Twitch channels
+func (t *Session) GetGroups(discordGuildID string) map[string][]string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	groups := make(map[string][]string)
+
+	for _, tci := range t.twitchData {
+		for _, dc := range tci.DiscordChannels[discordGuildID] {
+			if dc.Group != "" {
+				groups[dc.Group] = append(groups[dc.Group], tci.DisplayName)
+			}
+		}
+	}
+	for _, members := range groups {
+		sort.Strings(members)
+	}
+
+	return groups
+}
+
+// Pauses every registration of a group, until resumed if duration is 0
+func (t *Session) PauseGroup(discordGuildID string, group string, duration time.Duration) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if duration < 0 {
+		return constants.ErrInvalidPauseDuration
+	}
+
+	err := t.forGroup(discordGuildID, group, func(twitchID string, tci *twitchChannelInfo, dc *discordChannel) {
+		dc.Paused = true
+		dc.PausedUntil = time.Time{}
+		if duration > 0 {
+			dc.PausedUntil = time.Now().Add(duration)
+		}
+	})
+	if err == nil {
+		t.writeDataToDisk()
+	}
+	return err
+}
+
+// Resumes every registration of a group
+func (t *Session) ResumeGroup(discordGuildID string, group string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	err := t.forGroup(discordGuildID, group, func(twitchID string, tci *twitchChannelInfo, dc *discordChannel) {
+		dc.Paused = false
+		dc.PausedUntil = time.Time{}
+	})
+	if err == nil {
+		t.writeDataToDisk()
+	}
+	return err
+}
+
+// Replaces the templates of every registration of a group with template. An empty template
+// clears them, so the registrations use their profile's template again.
+func (t *Session) SetGroupTemplate(discordGuildID string, group string, template string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	err := t.forGroup(discordGuildID, group, func(twitchID string, tci *twitchChannelInfo, dc *discordChannel) {
+		dc.Templates = nil
+		if template != "" {
+			dc.Templates = []string{template}
+		}
+		dc.NextTemplate = 0
+	})
+	if err == nil {
+		t.writeDataToDisk()
+	}
+	return err
+}
+
+// Returns the registrations of a group, live channels first and then by display name
+func (t *Session) GroupDigest(discordGuildID string, group string) ([]GroupMember, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	members := []GroupMember{}
+
+	err := t.forGroup(discordGuildID, group, func(twitchID string, tci *twitchChannelInfo, dc *discordChannel) {
+		member := GroupMember{
+			TwitchChannel:    twitchID,
+			DisplayName:      tci.DisplayName,
+			DiscordChannelID: dc.ChannelID,
+			Live:             tci.State == StateLive && tci.StreamData != nil,
+			Paused:           dc.isPaused(),
+			LastLive:         tci.EndTime,
+		}
+		if member.Live {
+			member.Game = tci.StreamData.GameName
+			member.Uptime = time.Since(tci.StartTime)
+		}
+		members = append(members, member)
+	})
+
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].Live != members[j].Live {
+			return members[i].Live
+		}
+		return members[i].DisplayName < members[j].DisplayName
+	})
+
+	return members, err
+}
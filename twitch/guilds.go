@@ -0,0 +1,235 @@
+package twitch
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Settings shared by every registration in a Discord guild
+type guildSettings struct {
+	Profiles     map[string]*Profile          // Map of profile name to profile
+	Removed      []*removedChannel            // Registrations removed from the guild that can still be restored
+	Rewards      map[string]*rewardSettings   // Map of twitch channel to its channel point redemption settings
+	PollChannels map[string]string            // Map of twitch channel to the Discord channel its polls and predictions are mirrored to
+	SubRoles     map[string]map[string]string // Map of twitch channel to a map of subscription tier to Discord role ID
+	BanSync      map[string]*banSyncSettings  // Map of twitch channel to how its bans are mirrored
+	BanLog       []*BanRecord                 // Bans applied by ban sync
+	NextBanID    int                          // Last number given to a synced ban
+	MutedUntil   time.Time                    // Time announcements resume after a mute
+	MutePolicy   string                       // Whether announcements are queued or dropped while muted
+	GameColors   map[string]int               // Map of lowercase game name to the color of live embeds while it is played
+	TagRoles     map[string]string            // Map of lowercase stream tag to the role mentioned when a stream has it
+	Squads       map[string][]string          // Map of squad name to the twitch channels announced together
+	Goals        map[string]*goalSettings     // Map of twitch channel to where its creator goals are posted
+	Aliases      map[string]string            // Map of alias to the twitch channel it refers to in commands
+	DropsOnly    bool                         // Whether only streams with Drops enabled are announced
+	EmbedLayout  []string                     // Fields shown in live embeds in order, the default embed is used if empty
+	ChannelInfo  bool                         // Whether live embeds show the channel description and follower count
+	Rotation     *featuredRotation            // Rotation of the featured streamer, every streamer is mentioned if nil
+	LiveRole     string                       // Role given to members while their linked Twitch channel is live, disabled if empty
+	Report       *reportSettings              // Where stream reports are posted, disabled if nil
+	Names        string                       // How Twitch channels are named in messages, NamesDisplay if empty
+	Mature       string                       // How mature streams are announced in channels not marked NSFW, MatureAnywhere if empty
+	APIToken     string                       // Token required by the live status API of the guild, disabled if empty
+	Intake       string                       // Discord channel announcements posted to the announce webhook are sent to, disabled if empty
+	Timezone     string                       // IANA timezone times are shown in and reports are scheduled by, UTC if empty
+	HiatusDays   int                          // Days without streams after which announcements celebrate a return, disabled if 0
+	Streaks      bool                         // Whether announcements celebrate streaks and stream milestones
+}
+
+// Profile is a reusable set of notification settings that can be attached to registrations
+type Profile struct {
+	Template string   // Text sent with the live embed. Supports {name}, {title}, {game} and {url}
+	Mention  string   // Role mention, @everyone or @here prepended to the live message
+	Games    []string // Games that are announced. Every game is announced if empty
+	Color    int      // Color of the live embed. The default color is used if 0
+}
+
+// Returns a copy of a profile that a notification sent in the background can read while commands change the profile
+func (p *Profile) copy() *Profile {
+	if p == nil {
+		return nil
+	}
+
+	c := *p
+	return &c
+}
+
+// Returns the settings of a guild, creating them if they don't exist
+func (t *Session) getGuildSettings(guildID string) *guildSettings {
+	if t.guilds[guildID] == nil {
+		t.guilds[guildID] = &guildSettings{}
+	}
+	if t.guilds[guildID].Profiles == nil {
+		t.guilds[guildID].Profiles = make(map[string]*Profile)
+	}
+
+	return t.guilds[guildID]
+}
+
+// Returns the profile of a guild or nil if it doesn't exist
+func (t *Session) getProfile(guildID string, name string) *Profile {
+	if name == "" || t.guilds[guildID] == nil {
+		return nil
+	}
+
+	return t.guilds[guildID].Profiles[name]
+}
+
+// Creates a named profile in a guild
+func (t *Session) CreateProfile(guildID string, name string, p Profile) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(guildID)
+	if gs.Profiles[name] != nil {
+		return constants.ErrProfileExists
+	}
+
+	gs.Profiles[name] = &p
+	t.writeGuildsToDisk()
+
+	return nil
+}
+
+// Creates a named profile in a guild or replaces its settings if it exists
+func (t *Session) SaveProfile(guildID string, name string, p Profile) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(guildID)
+	if gs.Profiles[name] != nil {
+		*gs.Profiles[name] = p
+	} else {
+		gs.Profiles[name] = &p
+	}
+
+	t.writeGuildsToDisk()
+}
+
+// Deletes a named profile from a guild and detaches it from every registration using it
+func (t *Session) DeleteProfile(guildID string, name string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.getProfile(guildID, name) == nil {
+		return constants.ErrProfileDoesNotExist
+	}
+
+	delete(t.guilds[guildID].Profiles, name)
+
+	for _, tcInfo := range t.twitchData {
+		for _, dc := range tcInfo.DiscordChannels[guildID] {
+			if dc.Profile == name {
+				dc.Profile = ""
+			}
+		}
+	}
+
+	t.writeGuildsToDisk()
+	t.writeDataToDisk()
+
+	return nil
+}
+
+// Returns a copy of the profiles defined in a guild
+func (t *Session) GetProfiles(guildID string) map[string]Profile {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	profiles := make(map[string]Profile)
+
+	if t.guilds[guildID] != nil {
+		for name, p := range t.guilds[guildID].Profiles {
+			profiles[name] = *p
+		}
+	}
+
+	return profiles
+}
+
+// Returns whether a named profile exists in a guild
+func (t *Session) HasProfile(guildID string, name string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.getProfile(guildID, name) != nil
+}
+
+// Attaches a profile to a registration. An empty name detaches the current profile.
+func (t *Session) SetChannelProfile(twitchID string, discordGuildID string, discordChannelID string, name string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if name != "" && t.getProfile(discordGuildID, name) == nil {
+		return constants.ErrProfileDoesNotExist
+	}
+
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	if idx < 0 {
+		return constants.ErrTwitchUserNotRegistered
+	}
+
+	t.twitchData[twitchID].DiscordChannels[discordGuildID][idx].Profile = name
+
+	t.writeDataToDisk()
+
+	return nil
+}
+
+// Returns whether a stream playing game should be announced
+func (p *Profile) allowsGame(game string) bool {
+	if p == nil || len(p.Games) == 0 {
+		return true
+	}
+
+	for _, g := range p.Games {
+		if strings.EqualFold(g, game) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the color of the live embed
+func (p *Profile) color() int {
+	if p == nil || p.Color == 0 {
+		return constants.DiscordLiveColor
+	}
+
+	return p.Color
+}
+
+// Returns the text sent along with the live embed and the mentions it may ping. A non empty template replaces
+// the profile's template. Only mentions in the profile and template ping, never ones in text from Twitch.
+func (p *Profile) content(tci *twitchChannelInfo, template string, names string) (string, *discordgo.MessageAllowedMentions) {
+	if p == nil && template == "" {
+		return "", &discordgo.MessageAllowedMentions{}
+	}
+	if template == "" {
+		template = p.Template
+	}
+	if p != nil && p.Mention != "" {
+		template = strings.TrimSpace(p.Mention + " " + template)
+	}
+
+	content := strings.NewReplacer(
+		"{name}", utils.SanitizeText(tci.name(names)),
+		"{title}", utils.SanitizeText(tci.StreamData.Title),
+		"{game}", utils.SanitizeText(tci.StreamData.GameName),
+		"{url}", tci.url(),
+	).Replace(template)
+
+	return content, utils.AllowedMentions(template)
+}
+
+func (t *Session) writeGuildsToDisk() {
+	if err := utils.WriteGobToDisk(utils.DataDir, t.name+"_guilds", t.guilds); err != nil {
+		utils.Log.WithError(err).Error("Error writing guild settings to disk.")
+	}
+}
@@ -0,0 +1,72 @@
+package twitch
+
+import (
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Sets how many days a Twitch channel must not have streamed for its next announcement to celebrate its return.
+// 0 disables the badge.
+func (t *Session) SetHiatusDays(discordGuildID string, days int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if days < 0 || days > constants.MaxHiatusDays {
+		return constants.ErrInvalidHiatus
+	}
+
+	gs := t.getGuildSettings(discordGuildID)
+	gs.HiatusDays = days
+
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Returns how many days without streams earn a returning streamer the badge in a guild, 0 if it is disabled
+func (t *Session) GetHiatusDays(discordGuildID string) int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.hiatusDays(discordGuildID)
+}
+
+func (t *Session) hiatusDays(discordGuildID string) int {
+	if gs := t.guilds[discordGuildID]; gs != nil {
+		return gs.HiatusDays
+	}
+	return 0
+}
+
+// Records how long a channel went without streaming before the stream it just started.
+// lastEnd is when its previous stream ended, or zero if the monitor did not see it end, in which case the event log is searched.
+func (t *Session) recordHiatus(login string, tci *twitchChannelInfo, lastEnd time.Time) {
+	tci.Hiatus = 0
+
+	if lastEnd.IsZero() {
+		events, err := ReadEventLog(t.name)
+		if err != nil {
+			utils.Log.WithError(err).Error("Error reading event log.")
+			return
+		}
+		for i := len(events) - 1; i >= 0; i-- {
+			if events[i].Login == login && events[i].Type == EventOffline && events[i].Time.Before(tci.StartTime) {
+				lastEnd = events[i].Time
+				break
+			}
+		}
+	}
+
+	if !lastEnd.IsZero() && tci.StartTime.After(lastEnd) {
+		tci.Hiatus = tci.StartTime.Sub(lastEnd)
+	}
+}
+
+// Returns the number of whole days a stream follows a hiatus of at least days days, or 0 if it doesn't
+func hiatusDays(tci *twitchChannelInfo, days int) int {
+	if days <= 0 || tci.Hiatus < time.Duration(days)*24*time.Hour {
+		return 0
+	}
+	return int(tci.Hiatus / (24 * time.Hour))
+}
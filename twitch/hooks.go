@@ -0,0 +1,61 @@
+package twitch
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/scripts"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Lets the script of a hook change the content and embed of an announcement.
+// Returns false if the script suppressed it. A failing script leaves the announcement unchanged.
+func runAnnouncementScript(hook string, guildID string, dc *discordChannel, tci *twitchChannelInfo, content *string, embed *discordgo.MessageEmbed) bool {
+	if !scripts.Has(hook) {
+		return true
+	}
+
+	a := scripts.Announcement{
+		Hook:             hook,
+		GuildID:          guildID,
+		ChannelID:        dc.ChannelID,
+		TwitchLogin:      tci.Login,
+		DisplayName:      tci.DisplayName,
+		EmbedTitle:       embed.Title,
+		EmbedDescription: embed.Description,
+	}
+	if content != nil {
+		a.Content = *content
+	}
+	if tci.StreamData != nil {
+		a.Title = tci.StreamData.Title
+		a.Game = tci.StreamData.GameName
+	} else if len(tci.GameList) > 0 {
+		a.Game = tci.GameList[len(tci.GameList)-1].GameName
+	}
+
+	result, err := scripts.RunAnnouncement(a)
+	if err != nil {
+		utils.Log.WithFields(logrus.Fields{
+			"twitch_channel": tci.DisplayName,
+			"channel_id":     dc.ChannelID,
+			"server_id":      guildID,
+			"error":          err}).Error("Failed to run announcement script.")
+		return true
+	}
+	if result.Suppress {
+		return false
+	}
+
+	if result.Content != nil && content != nil {
+		*content = truncateText(*result.Content, constants.DiscordMaxMessageLength)
+	}
+	if result.EmbedTitle != nil {
+		embed.Title = truncateText(*result.EmbedTitle, constants.DiscordMaxEmbedTitle)
+	}
+	if result.EmbedDescription != nil {
+		embed.Description = truncateText(*result.EmbedDescription, constants.DiscordMaxEmbedDescription)
+	}
+
+	return true
+}
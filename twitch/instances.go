@@ -0,0 +1,61 @@
+package twitch
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Identifies this process when claiming announcements, so two processes accidentally running with the same data
+// directory and bot token do not both announce a stream
+var InstanceID = newInstanceID()
+
+func newInstanceID() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%v-%v-%v", host, os.Getpid(), hex.EncodeToString(b))
+}
+
+// Claims the announcement of a stream in a Discord channel for this instance and returns whether it may be sent.
+// Announcements are sent anyway if the claim cannot be stored, since a duplicate is better than a missed stream.
+func claimAnnouncement(dc *discordChannel, tci *twitchChannelInfo) bool {
+	if tci.StreamData == nil || tci.StreamData.ID == "" {
+		return true
+	}
+
+	holder, err := utils.ClaimLock(utils.DataDir+"/"+constants.AnnounceLockPath, tci.StreamData.ID+"-"+dc.ChannelID, InstanceID)
+	if err != nil {
+		utils.Log.WithError(err).WithField("twitch_channel", tci.Login).Warn("Failed to claim announcement. Sending it anyway.")
+		return true
+	}
+	if holder != InstanceID {
+		utils.Log.WithFields(logrus.Fields{
+			"twitch_channel": tci.Login,
+			"channel_id":     dc.ChannelID,
+			"instance_id":    holder}).Warn("Announcement was claimed by another instance of the bot. Is the bot running twice?")
+		return false
+	}
+	return true
+}
+
+// Removes the announcement claims of streams in a Discord channel. Returns the number of claims removed.
+func forgetAnnouncementClaims(discordChannelID string) (int, error) {
+	return utils.RemoveLocks(utils.DataDir+"/"+constants.AnnounceLockPath, "-"+discordChannelID)
+}
+
+// Removes announcement claims old enough that their streams have ended
+func pruneAnnouncementClaims() {
+	if err := utils.PruneLocks(utils.DataDir+"/"+constants.AnnounceLockPath, constants.AnnounceLockAge); err != nil {
+		utils.Log.WithError(err).Error("Failed to remove old announcement claims.")
+	}
+}
@@ -0,0 +1,199 @@
+package twitch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/crash"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Announcement posted to the announce webhook by an external system
+type intakeRequest struct {
+	Content       string `json:"content"`        // Text sent, a template supporting {name}, {title}, {game} and {url}
+	Profile       string `json:"profile"`        // Profile whose template is used if content is empty and whose mention is prepended
+	TwitchChannel string `json:"twitch_channel"` // Twitch channel the announcement is about, naming and linking it
+	Title         string `json:"title"`          // Title of the embed, no embed is sent if empty
+	Game          string `json:"game"`           // Game shown in the embed
+	Description   string `json:"description"`    // Description of the embed
+	URL           string `json:"url"`            // Link of the embed title, the Twitch channel if empty
+	ImageURL      string `json:"image_url"`      // Image of the embed
+}
+
+// Announcement waiting in the intake queue
+type intakeAnnouncement struct {
+	guildID   string
+	channelID string
+	message   *discordgo.MessageSend
+}
+
+// Sets the Discord channel announcements posted to the announce webhook are sent to. An empty channel disables it.
+func (t *Session) SetIntakeChannel(discordGuildID string, discordChannelID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	gs.Intake = discordChannelID
+	t.writeGuildsToDisk()
+}
+
+// Returns the Discord channel announcements posted to the announce webhook are sent to, empty if disabled
+func (t *Session) GetIntakeChannel(discordGuildID string) string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.intakeChannel(discordGuildID)
+}
+
+func (t *Session) intakeChannel(discordGuildID string) string {
+	if gs := t.guilds[discordGuildID]; gs != nil {
+		return gs.Intake
+	}
+	return ""
+}
+
+// Returns the handler of the announce webhook, where external systems holding a guild's API token post
+// announcements to the guild's intake channel. Announcements are queued and sent one at a time.
+func (t *Session) AnnounceHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		t.mu.RLock()
+		guildID, ok := t.authorizeAPIRequest(r)
+		channelID, muted := t.intakeChannel(guildID), t.isMuted(guildID)
+		t.mu.RUnlock()
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if channelID == "" {
+			http.Error(w, "No intake channel is set for the Discord server.", http.StatusConflict)
+			return
+		}
+		if muted {
+			http.Error(w, "Announcements are muted in the Discord server.", http.StatusConflict)
+			return
+		}
+
+		var req intakeRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, constants.APIRequestMaxBytes)).Decode(&req); err != nil {
+			http.Error(w, "The body must be a JSON announcement.", http.StatusBadRequest)
+			return
+		}
+		t.mu.RLock()
+		message, err := t.intakeMessage(guildID, &req)
+		t.mu.RUnlock()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		select {
+		case t.intake <- intakeAnnouncement{guildID: guildID, channelID: channelID, message: message}:
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			http.Error(w, "Too many announcements are waiting to be sent.", http.StatusTooManyRequests)
+		}
+	})
+}
+
+// Builds the Discord message of an announcement posted to the announce webhook using the guild's templates
+func (t *Session) intakeMessage(guildID string, req *intakeRequest) (*discordgo.MessageSend, error) {
+	var p *Profile
+	if req.Profile != "" {
+		if p = t.getProfile(guildID, req.Profile); p == nil {
+			return nil, constants.ErrProfileDoesNotExist
+		}
+	}
+
+	// The announcement is named and linked like a stream of its Twitch channel
+	login := NormalizeLogin(req.TwitchChannel)
+	tci := &twitchChannelInfo{Login: login, DisplayName: login}
+	if monitored := t.twitchData[login]; monitored != nil {
+		tci.DisplayName = monitored.DisplayName
+		tci.LogoURL = monitored.LogoURL
+	}
+
+	names := t.nameStyle(guildID)
+	url := req.URL
+	if url == "" && login != "" {
+		url = tci.url()
+	}
+
+	// Like live messages, only mentions in the template and profile ping
+	template := req.Content
+	if template == "" && p != nil {
+		template = p.Template
+	}
+	if p != nil && p.Mention != "" {
+		template = strings.TrimSpace(p.Mention + " " + template)
+	}
+	content := strings.TrimSpace(strings.NewReplacer(
+		"{name}", utils.SanitizeText(tci.name(names)),
+		"{title}", utils.SanitizeText(req.Title),
+		"{game}", utils.SanitizeText(req.Game),
+		"{url}", url,
+	).Replace(template))
+
+	message := &discordgo.MessageSend{Content: content, AllowedMentions: utils.AllowedMentions(template)}
+	if req.Title != "" {
+		b := newEmbed().
+			title(req.Title, url).
+			description(req.Description).
+			color(p.color()).
+			thumbnail(tci.LogoURL).
+			image(req.ImageURL)
+		if login != "" {
+			b.author(tci.name(names))
+		}
+		if req.Game != "" {
+			b.field("Game", utils.SanitizeText(req.Game), true)
+		}
+		message.Embed = b.build()
+	}
+
+	if message.Content == "" && message.Embed == nil {
+		return nil, constants.ErrEmptyAnnouncement
+	}
+	if len([]rune(message.Content)) > constants.DiscordMaxMessageLength {
+		return nil, constants.ErrAnnouncementTooLong
+	}
+	return message, nil
+}
+
+// Sends the announcements of the intake queue one at a time, waiting IntakeInterval between them
+// so a burst of posts does not hit Discord's rate limits
+func (t *Session) runIntake(ctx context.Context, ds *discordgo.Session) {
+	for {
+		select {
+		case a := <-t.intake:
+			t.sendIntake(ds, a)
+			time.Sleep(constants.IntakeInterval)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (t *Session) sendIntake(ds *discordgo.Session, a intakeAnnouncement) {
+	defer crash.Recover("send_intake_announcement")
+
+	if connected, _ := getGuildStatus(a.guildID); !connected {
+		return
+	}
+	if _, err := sendRespectingSlowmode(discordFor(a.guildID, ds), a.channelID, a.message); err != nil {
+		utils.Log.WithError(err).WithFields(logrus.Fields{
+			"channel_id": a.channelID,
+			"server_id":  a.guildID}).Error("Error sending announcement from the announce webhook.")
+	}
+}
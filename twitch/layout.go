@@ -0,0 +1,122 @@
+package twitch
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Guild and registration settings a live embed is created with
+type embedStyle struct {
+	color       int      // Color of the embed
+	layout      []string // Fields shown in order, the default fields and uptime footer if empty
+	channelInfo bool     // Whether the footer shows the channel description and follower count
+	names       string   // How the channel is named
+	hidePreview bool     // Whether the stream preview image is left out
+	hiatusDays  int      // Days without streams after which the embed celebrates a return, disabled if 0
+	streaks     bool     // Whether the embed celebrates streaks and stream milestones
+}
+
+// Fields that can be shown in live embeds
+const (
+	FieldGame    = "game"
+	FieldViewers = "viewers"
+	FieldUptime  = "uptime"
+	FieldTags    = "tags"
+	FieldStarted = "started"
+)
+
+// EmbedFields lists every field that can be shown in live embeds
+var EmbedFields = []string{FieldGame, FieldViewers, FieldUptime, FieldTags, FieldStarted}
+
+// Sets which fields live embeds in a guild show and in which order. An empty layout restores the default embed.
+func (t *Session) SetEmbedLayout(discordGuildID string, fields []string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(fields) > constants.DiscordMaxEmbedFields {
+		return constants.ErrTooManyEmbedFields
+	}
+
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		if !isEmbedField(field) {
+			return fmt.Errorf("%w: %v", constants.ErrUnknownEmbedField, field)
+		}
+		if seen[field] {
+			return fmt.Errorf("%w: %v", constants.ErrDuplicateEmbedField, field)
+		}
+		seen[field] = true
+	}
+
+	gs := t.getGuildSettings(discordGuildID)
+	gs.EmbedLayout = append([]string{}, fields...)
+	if len(fields) == 0 {
+		gs.EmbedLayout = nil
+	}
+
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Returns the fields live embeds in a guild show, or nil if the guild uses the default embed
+func (t *Session) GetEmbedLayout(discordGuildID string) []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.embedLayout(discordGuildID)
+}
+
+func (t *Session) embedLayout(discordGuildID string) []string {
+	if gs := t.guilds[discordGuildID]; gs != nil {
+		return append([]string(nil), gs.EmbedLayout...)
+	}
+	return nil
+}
+
+func isEmbedField(field string) bool {
+	for _, f := range EmbedFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Builds the fields of a live embed following a layout. Fields without a value, such as the game
+// of a stream without one, are left out. Values are truncated when the embed is built.
+func layoutFields(t *twitchChannelInfo, layout []string) []*discordgo.MessageEmbedField {
+	fields := []*discordgo.MessageEmbedField{}
+
+	for _, field := range layout {
+		var name, value string
+		switch field {
+		case FieldGame:
+			name, value = "Playing", utils.SanitizeText(t.StreamData.GameName)
+		case FieldViewers:
+			name, value = "Viewers", fmt.Sprint(t.StreamData.ViewerCount)
+		case FieldUptime:
+			name, value = "Uptime", formatDuration(time.Since(t.StartTime).Round(time.Second))
+		case FieldTags:
+			name, value = "Tags", utils.SanitizeText(strings.Join(t.Tags, ", "))
+		case FieldStarted:
+			name, value = "Started", fmt.Sprintf("<t:%d:t>", t.StartTime.Unix())
+		}
+
+		if value == "" {
+			continue
+		}
+
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   name,
+			Value:  value,
+			Inline: true,
+		})
+	}
+
+	return fields
+}
@@ -0,0 +1,103 @@
+package twitch
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/accounts"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Sets the role given to members of a guild while their linked Twitch channel is live, or disables it if roleID is
+// empty. Members who are live right now are moved from the previous role to the new one.
+func (t *Session) SetLiveRole(ds *discordgo.Session, discordGuildID string, roleID string) {
+	t.mu.Lock()
+	gs := t.getGuildSettings(discordGuildID)
+	previous := gs.LiveRole
+	gs.LiveRole = roleID
+	t.writeGuildsToDisk()
+
+	live := []string{}
+	for login, tci := range t.twitchData {
+		if tci.State == StateLive {
+			live = append(live, login)
+		}
+	}
+	t.mu.Unlock()
+
+	for _, login := range live {
+		if previous != "" {
+			t.setMembersRole(discordFor(discordGuildID, ds), discordGuildID, login, previous, false)
+		}
+		if roleID != "" {
+			t.setMembersRole(discordFor(discordGuildID, ds), discordGuildID, login, roleID, true)
+		}
+	}
+}
+
+// Returns the role given to members of a guild while they are live, empty if disabled
+func (t *Session) GetLiveRole(discordGuildID string) string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	gs := t.guilds[discordGuildID]
+	if gs == nil {
+		return ""
+	}
+	return gs.LiveRole
+}
+
+// Grants or removes the live role of every guild to the members who linked a Twitch channel that went live or offline
+func (t *Session) updateLiveRoles(ds *discordgo.Session, login string, live bool) {
+	roles := make(map[string]string)
+	t.mu.RLock()
+	for guildID, gs := range t.guilds {
+		if gs.LiveRole != "" {
+			roles[guildID] = gs.LiveRole
+		}
+	}
+	t.mu.RUnlock()
+
+	for guildID, roleID := range roles {
+		t.setMembersRole(discordFor(guildID, ds), guildID, login, roleID, live)
+	}
+}
+
+// Grants or removes a role of the guild members whose linked Twitch account is login
+// or whose Discord presence shows them streaming it
+func (t *Session) setMembersRole(ds *discordgo.Session, guildID string, login string, roleID string, grant bool) {
+	members := make(map[string]bool)
+	for _, account := range accounts.All() {
+		if strings.EqualFold(account.TwitchLogin, login) {
+			members[account.DiscordUserID] = true
+		}
+	}
+	for _, userID := range streamingMembers(guildID, login) {
+		members[userID] = true
+	}
+
+	for userID := range members {
+		t.setMemberRole(ds, guildID, userID, login, roleID, grant)
+	}
+}
+
+// Grants or removes a role of a guild member streaming login
+func (t *Session) setMemberRole(ds *discordgo.Session, guildID string, userID string, login string, roleID string, grant bool) {
+	member, err := ds.GuildMember(guildID, userID)
+	if err != nil {
+		return
+	}
+
+	if grant && !hasRole(member, roleID) {
+		err = ds.GuildMemberRoleAdd(guildID, userID, roleID)
+	} else if !grant && hasRole(member, roleID) {
+		err = ds.GuildMemberRoleRemove(guildID, userID, roleID)
+	}
+	if err != nil {
+		utils.Log.WithFields(logrus.Fields{
+			"twitch_channel": login,
+			"server_id":      guildID,
+			"error":          err}).Error("Failed to update live role.")
+	}
+}
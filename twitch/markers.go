@@ -0,0 +1,28 @@
+package twitch
+
+import (
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+// Creates a stream marker at the current timestamp of a linked broadcaster's live stream
+func (t *Session) CreateStreamMarker(login string, discordGuildID string, description string) (*helix.CreateStreamMarker, error) {
+	client, bt, err := t.broadcasterClient(login, discordGuildID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.CreateStreamMarker(&helix.CreateStreamMarkerParams{
+		UserID:      bt.UserID,
+		Description: description,
+	})
+	if err != nil {
+		return nil, err
+	} else if resp.StatusCode == 404 {
+		return nil, constants.ErrStreamOffline
+	} else if resp.StatusCode != 200 || len(resp.Data.CreateStreamMarkers) == 0 {
+		return nil, constants.ErrTwitchQueryFailed
+	}
+
+	return &resp.Data.CreateStreamMarkers[0], nil
+}
@@ -0,0 +1,113 @@
+package twitch
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// How streams Twitch marks as mature are announced in Discord channels not marked NSFW
+const (
+	MatureAnywhere  = "anywhere"   // Announced like any other stream
+	MatureNSFWOnly  = "nsfw-only"  // Only announced in NSFW channels
+	MatureNoPreview = "no-preview" // Announced without the stream preview image
+)
+
+// Discord error code of a message rejected because of the channel's slowmode
+const slowmodeErrorCode = 20016
+
+// Sets how mature streams are announced in the channels of a guild not marked NSFW
+func (t *Session) SetMaturePolicy(discordGuildID string, policy string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if policy != MatureAnywhere && policy != MatureNSFWOnly && policy != MatureNoPreview {
+		return constants.ErrInvalidMaturePolicy
+	}
+
+	gs := t.getGuildSettings(discordGuildID)
+	gs.Mature = policy
+	if policy == MatureAnywhere {
+		gs.Mature = ""
+	}
+
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Returns how mature streams are announced in the channels of a guild not marked NSFW
+func (t *Session) GetMaturePolicy(discordGuildID string) string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.maturePolicy(discordGuildID)
+}
+
+func (t *Session) maturePolicy(discordGuildID string) string {
+	if gs := t.guilds[discordGuildID]; gs != nil && gs.Mature != "" {
+		return gs.Mature
+	}
+	return MatureAnywhere
+}
+
+// Returns whether the mature policy of a guild keeps a stream from being announced in a Discord channel
+func (t *Session) matureFiltered(ds *discordgo.Session, guildID string, channelID string, tcInfo *twitchChannelInfo) bool {
+	return tcInfo.StreamData != nil && tcInfo.StreamData.IsMature &&
+		t.maturePolicy(guildID) == MatureNSFWOnly && !isNSFWChannel(ds, channelID)
+}
+
+// Returns whether the live embed of a stream leaves out the preview image in a Discord channel
+func (t *Session) hidesPreview(ds *discordgo.Session, guildID string, channelID string, tcInfo *twitchChannelInfo) bool {
+	return tcInfo.StreamData != nil && tcInfo.StreamData.IsMature &&
+		t.maturePolicy(guildID) == MatureNoPreview && !isNSFWChannel(ds, channelID)
+}
+
+// Returns whether a Discord channel is marked NSFW. Channels that cannot be looked up are treated as not NSFW.
+func isNSFWChannel(ds *discordgo.Session, channelID string) bool {
+	if channel, err := ds.State.Channel(channelID); err == nil {
+		return channel.NSFW
+	}
+	if channel, err := ds.Channel(channelID); err == nil {
+		return channel.NSFW
+	}
+	return false
+}
+
+// Returns whether Discord rejected a message because of the channel's slowmode
+func isSlowmode(err error) bool {
+	var restErr *discordgo.RESTError
+	return errors.As(err, &restErr) && restErr.Message != nil && restErr.Message.Code == slowmodeErrorCode
+}
+
+// Sends a message, waiting out the slowmode of the channel and retrying when Discord rejects it because of slowmode.
+// Channels with a slowmode longer than MaxSlowmodeWait are not retried.
+func sendRespectingSlowmode(ds *discordgo.Session, channelID string, data *discordgo.MessageSend) (*discordgo.Message, error) {
+	m, err := ds.ChannelMessageSendComplex(channelID, data)
+	for retry := 0; retry < constants.SlowmodeRetries && isSlowmode(err); retry++ {
+		wait := time.Second
+		if channel, stateErr := ds.State.Channel(channelID); stateErr == nil && channel.RateLimitPerUser > 0 {
+			wait = time.Duration(channel.RateLimitPerUser) * time.Second
+		}
+		if wait > constants.MaxSlowmodeWait {
+			return m, err
+		}
+
+		utils.Log.WithFields(logrus.Fields{
+			"channel_id": channelID,
+			"retry_in":   wait}).Info("Message rejected by slowmode, retrying.")
+		time.Sleep(wait)
+		// Uploaded files are read again from the start
+		for _, f := range data.Files {
+			if seeker, ok := f.Reader.(io.Seeker); ok {
+				seeker.Seek(0, io.SeekStart)
+			}
+		}
+		m, err = ds.ChannelMessageSendComplex(channelID, data)
+	}
+	return m, err
+}
@@ -0,0 +1,66 @@
+package twitch
+
+import (
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+// What happens to announcements while a guild is muted
+const (
+	MuteQueue = "queue" // Announce streams still live once the mute ends
+	MuteDrop  = "drop"  // Never announce streams that went live during the mute
+)
+
+// Silences announcements in a guild until the duration has passed
+func (t *Session) Mute(discordGuildID string, duration time.Duration, policy string) error {
+	if duration <= 0 || duration > constants.MaxMuteDuration {
+		return constants.ErrInvalidMuteDuration
+	}
+	if policy != MuteQueue && policy != MuteDrop {
+		return constants.ErrInvalidMutePolicy
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	gs := t.getGuildSettings(discordGuildID)
+	gs.MutedUntil = time.Now().UTC().Add(duration)
+	gs.MutePolicy = policy
+
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Ends a guild's mute early
+func (t *Session) Unmute(discordGuildID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	if !t.isMuted(discordGuildID) {
+		return constants.ErrNotMuted
+	}
+
+	gs.MutedUntil = time.Time{}
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Returns when a guild's mute ends and its policy, or a zero time if the guild is not muted
+func (t *Session) GetMute(discordGuildID string) (time.Time, string) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if !t.isMuted(discordGuildID) {
+		return time.Time{}, ""
+	}
+
+	gs := t.guilds[discordGuildID]
+	return gs.MutedUntil, gs.MutePolicy
+}
+
+// Returns whether announcements in a guild are silenced
+func (t *Session) isMuted(discordGuildID string) bool {
+	gs := t.guilds[discordGuildID]
+	return gs != nil && time.Now().Before(gs.MutedUntil)
+}
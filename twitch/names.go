@@ -0,0 +1,83 @@
+package twitch
+
+import (
+	"strings"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+// How Twitch channels are named in the messages of a guild
+const (
+	NamesDisplay = "display" // The display name, which may be localized, e.g. in Japanese characters
+	NamesLogin   = "login"   // The login instead of a localized display name
+	NamesBoth    = "both"    // A localized display name followed by the login
+)
+
+// Sets how Twitch channels are named in the messages of a guild
+func (t *Session) SetNameStyle(discordGuildID string, style string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if style != NamesDisplay && style != NamesLogin && style != NamesBoth {
+		return constants.ErrInvalidNameStyle
+	}
+
+	gs := t.getGuildSettings(discordGuildID)
+	gs.Names = style
+	if style == NamesDisplay {
+		gs.Names = ""
+	}
+
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Returns how Twitch channels are named in the messages of a guild
+func (t *Session) GetNameStyle(discordGuildID string) string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.nameStyle(discordGuildID)
+}
+
+func (t *Session) nameStyle(discordGuildID string) string {
+	if gs := t.guilds[discordGuildID]; gs != nil && gs.Names != "" {
+		return gs.Names
+	}
+	return NamesDisplay
+}
+
+// Returns whether the display name of a channel is localized, rather than its login with different casing
+func (tci *twitchChannelInfo) localized() bool {
+	return tci.Login != "" && !strings.EqualFold(tci.DisplayName, tci.Login)
+}
+
+// Returns the name of a channel shown in messages with the given name style
+func (tci *twitchChannelInfo) name(style string) string {
+	if !tci.localized() {
+		return tci.DisplayName
+	}
+
+	switch style {
+	case NamesLogin:
+		return tci.Login
+	case NamesBoth:
+		return tci.DisplayName + " (" + tci.Login + ")"
+	default:
+		return tci.DisplayName
+	}
+}
+
+// Returns the link to a channel. Links use the login since localized display names are not valid in URLs.
+func (tci *twitchChannelInfo) url() string {
+	return "https://www.twitch.tv/" + tci.Login
+}
+
+// Stores the login of channels registered before it was kept alongside the display name
+func (t *Session) migrateLogins() {
+	for login, tci := range t.twitchData {
+		if tci.Login == "" {
+			tci.Login = login
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package twitch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Returns a session without Twitch or Discord connections whose data is kept in a temporary directory
+func newTestSession(t *testing.T) *Session {
+	t.Helper()
+
+	dataDir := utils.DataDir
+	utils.DataDir = t.TempDir()
+	t.Cleanup(func() { utils.DataDir = dataDir })
+
+	ts, err := New("client-id", "client-secret", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ts
+}
+
+func TestChannelURL(t *testing.T) {
+	for _, tci := range []*twitchChannelInfo{
+		{Login: "shroud", DisplayName: "shroud"},
+		{Login: "shroud", DisplayName: "Shroud"},
+		{Login: "shroud", DisplayName: "슈라우드"},
+	} {
+		if url := tci.url(); url != "https://www.twitch.tv/shroud" {
+			t.Errorf("display name %v links to %v", tci.DisplayName, url)
+		}
+	}
+}
+
+func TestChannelName(t *testing.T) {
+	localized := &twitchChannelInfo{Login: "kato_junichi0817", DisplayName: "加藤純一"}
+	cased := &twitchChannelInfo{Login: "shroud", DisplayName: "Shroud"}
+
+	for _, tc := range []struct {
+		tci      *twitchChannelInfo
+		style    string
+		expected string
+	}{
+		{localized, NamesDisplay, "加藤純一"},
+		{localized, NamesLogin, "kato_junichi0817"},
+		{localized, NamesBoth, "加藤純一 (kato_junichi0817)"},
+		{localized, "", "加藤純一"},
+		// Display names that only differ from the login in casing are always shown as is
+		{cased, NamesDisplay, "Shroud"},
+		{cased, NamesLogin, "Shroud"},
+		{cased, NamesBoth, "Shroud"},
+	} {
+		if name := tc.tci.name(tc.style); name != tc.expected {
+			t.Errorf("%v with style %q: got %q, expected %q", tc.tci.Login, tc.style, name, tc.expected)
+		}
+	}
+
+	if !localized.localized() || cased.localized() {
+		t.Error("localized display names were not told apart from casing")
+	}
+	if (&twitchChannelInfo{DisplayName: "加藤純一"}).localized() {
+		t.Error("channel without a login was treated as localized")
+	}
+}
+
+func TestNameStyle(t *testing.T) {
+	ts := newTestSession(t)
+
+	if style := ts.GetNameStyle("guild"); style != NamesDisplay {
+		t.Errorf("default style is %q", style)
+	}
+	if err := ts.SetNameStyle("guild", NamesBoth); err != nil {
+		t.Fatal(err)
+	}
+	if style := ts.GetNameStyle("guild"); style != NamesBoth {
+		t.Errorf("got style %q after setting %q", style, NamesBoth)
+	}
+	if err := ts.SetNameStyle("guild", "nickname"); !errors.Is(err, constants.ErrInvalidNameStyle) {
+		t.Errorf("invalid style: got %v", err)
+	}
+	if err := ts.SetNameStyle("guild", NamesDisplay); err != nil || ts.guilds["guild"].Names != "" {
+		t.Errorf("default style is not stored as empty: %q, %v", ts.guilds["guild"].Names, err)
+	}
+}
+
+func TestMigrateLogins(t *testing.T) {
+	ts := newTestSession(t)
+	ts.twitchData["shroud"] = &twitchChannelInfo{DisplayName: "Shroud"}
+	ts.twitchData["kato_junichi0817"] = &twitchChannelInfo{Login: "kato_junichi0817", DisplayName: "加藤純一"}
+
+	ts.migrateLogins()
+
+	if login := ts.twitchData["shroud"].Login; login != "shroud" {
+		t.Errorf("login of a channel registered without one is %q", login)
+	}
+	if login := ts.twitchData["kato_junichi0817"].Login; login != "kato_junichi0817" {
+		t.Errorf("stored login was changed to %q", login)
+	}
+}
@@ -0,0 +1,88 @@
+package twitch
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Sets the message replacing the stream summary of a registration once its stream ends, e.g. "Thanks for watching!
+// Next stream Friday". Supports {name} and {duration}. An empty message restores the summary.
+func (t *Session) SetOfflineText(twitchID string, discordGuildID string, discordChannelID string, text string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	if idx < 0 {
+		return constants.ErrTwitchUserNotRegistered
+	}
+	if len([]rune(text)) > constants.MaxOfflineText {
+		return constants.ErrOfflineTextTooLong
+	}
+
+	t.twitchData[twitchID].DiscordChannels[discordGuildID][idx].OfflineText = text
+
+	t.writeDataToDisk()
+
+	return nil
+}
+
+// Sets the image replacing the Twitch offline banner of a registration once its stream ends. An empty URL restores
+// the banner.
+func (t *Session) SetOfflineImage(twitchID string, discordGuildID string, discordChannelID string, imageURL string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	if idx < 0 {
+		return constants.ErrTwitchUserNotRegistered
+	}
+	if imageURL != "" {
+		if u, err := url.Parse(imageURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return constants.ErrInvalidImageURL
+		}
+	}
+
+	t.twitchData[twitchID].DiscordChannels[discordGuildID][idx].OfflineImage = imageURL
+
+	t.writeDataToDisk()
+
+	return nil
+}
+
+// Returns the offline message and image of a registration, empty if the defaults are used
+func (t *Session) GetOffline(twitchID string, discordGuildID string, discordChannelID string) (string, string, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	if idx < 0 {
+		return "", "", constants.ErrTwitchUserNotRegistered
+	}
+
+	dc := t.twitchData[twitchID].DiscordChannels[discordGuildID][idx]
+	return dc.OfflineText, dc.OfflineImage, nil
+}
+
+// Replaces the stream summary and offline banner of an offline embed with those of the registration, if it has its
+// own. Returns the plain summary edited in if Discord refuses the embed.
+func customizeOffline(dc *discordChannel, tci *twitchChannelInfo, names string, embed *discordgo.MessageEmbed) string {
+	if dc.OfflineImage != "" {
+		embed.Image = &discordgo.MessageEmbedImage{URL: dc.OfflineImage}
+	}
+	if dc.OfflineText == "" {
+		return plainOfflineMessage(tci, names)
+	}
+
+	text := strings.NewReplacer(
+		"{name}", utils.SanitizeText(tci.name(names)),
+		"{duration}", formatDuration(tci.EndTime.Sub(tci.StartTime).Round(time.Second)),
+	).Replace(dc.OfflineText)
+	embed.Description = text
+
+	return text + "\n<" + tci.url() + ">"
+}
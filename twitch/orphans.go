@@ -0,0 +1,104 @@
+package twitch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Flags the checked channels whose Twitch account was not found and tells the guilds monitoring them.
+// Channels still missing after OrphanGracePeriod are removed, and accounts found again are no longer flagged.
+func (t *Session) updateOrphans(ds *discordgo.Session, checked []string, found map[string]bool) {
+	changed := false
+	for _, login := range checked {
+		tci := t.twitchData[login]
+		if tci == nil {
+			continue
+		}
+
+		if found[login] {
+			if !tci.OrphanedSince.IsZero() {
+				utils.Log.WithField("twitch_channel", login).Info("Twitch account of orphaned channel was found again.")
+				tci.OrphanedSince = time.Time{}
+				changed = true
+			}
+			continue
+		}
+
+		if tci.OrphanedSince.IsZero() {
+			utils.Log.WithField("twitch_channel", login).Warn("Twitch account of monitored channel was not found.")
+			tci.OrphanedSince = time.Now()
+			changed = true
+
+			t.notifyOrphan(ds, tci, fmt.Sprintf("The Twitch account of **%v** could not be found. It may have been deleted, suspended or renamed. "+
+				"It will no longer be monitored after %v unless it comes back. Use `%v channel remove %v` to stop monitoring it now.",
+				utils.SanitizeText(tci.DisplayName), tci.OrphanedSince.Add(constants.OrphanGracePeriod).Format("Jan 2"), constants.CommandPrefix, login))
+		} else if time.Since(tci.OrphanedSince) >= constants.OrphanGracePeriod {
+			t.removeOrphan(ds, login, tci)
+		}
+	}
+
+	if changed {
+		t.writeDataToDisk()
+	}
+}
+
+// Sends a notice to every Discord channel monitoring an orphaned channel in a connected guild.
+// Notices wait out slowmode in the background, so they go through even in channels with a cooldown.
+func (t *Session) notifyOrphan(ds *discordgo.Session, tci *twitchChannelInfo, content string) {
+	type notice struct {
+		ds        *discordgo.Session
+		channelID string
+	}
+	var notices []notice
+	for guildID, dcs := range tci.DiscordChannels {
+		if connected, _ := getGuildStatus(guildID); !connected {
+			continue
+		}
+
+		notified := make(map[string]bool)
+		for _, dc := range dcs {
+			if notified[dc.ChannelID] {
+				continue
+			}
+			notified[dc.ChannelID] = true
+			notices = append(notices, notice{discordFor(guildID, ds), dc.ChannelID})
+		}
+	}
+
+	go func() {
+		for _, n := range notices {
+			if _, err := sendRespectingSlowmode(n.ds, n.channelID, &discordgo.MessageSend{
+				Content:         content,
+				AllowedMentions: &discordgo.MessageAllowedMentions{},
+			}); err != nil {
+				utils.Log.WithError(err).Error("Error sending Discord message.")
+			}
+		}
+	}()
+}
+
+// Removes every registration of an orphaned channel. Removed registrations can be restored with undo.
+func (t *Session) removeOrphan(ds *discordgo.Session, login string, tci *twitchChannelInfo) {
+	t.notifyOrphan(ds, tci, fmt.Sprintf("The Twitch account of **%v** has not been found for %v days, so it is no longer monitored. Use `%v undo` to restore it.",
+		utils.SanitizeText(tci.DisplayName), int(constants.OrphanGracePeriod.Hours()/24), constants.CommandPrefix))
+
+	type registration struct{ guildID, channelID string }
+	var registrations []registration
+	for guildID, dcs := range tci.DiscordChannels {
+		for _, dc := range dcs {
+			registrations = append(registrations, registration{guildID, dc.ChannelID})
+		}
+	}
+	for _, r := range registrations {
+		t.unregisterChannel(login, r.guildID, r.channelID)
+	}
+
+	utils.Log.WithFields(logrus.Fields{
+		"twitch_channel": login,
+		"registrations":  len(registrations)}).Info("Removed orphaned channel.")
+}
@@ -0,0 +1,39 @@
+package twitch
+
+import (
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Returns whether a poll should be ignored as a suspected Twitch outage. Twitch sometimes answers with no streams
+// during incidents, so a poll without streams while many channels were live is only trusted once it repeats
+// OutageConfirmations times in a row.
+func (t *Session) suspectOutage(streams []helix.Stream) bool {
+	if len(streams) > 0 {
+		t.emptyPolls = 0
+		return false
+	}
+
+	live := 0
+	for _, tci := range t.twitchData {
+		if tci.StreamData != nil {
+			live++
+		}
+	}
+	if live < constants.OutageMinLiveChannels {
+		t.emptyPolls = 0
+		return false
+	}
+
+	t.emptyPolls++
+	if t.emptyPolls < constants.OutageConfirmations {
+		utils.Log.Warnf("Twitch returned no streams while %v channels were live. Ignoring the poll as a suspected outage (%v of %v).\n",
+			live, t.emptyPolls, constants.OutageConfirmations)
+		return true
+	}
+
+	utils.Log.Warnf("Twitch returned no streams %v times in a row. Treating the %v live channels as offline.\n", t.emptyPolls, live)
+	t.emptyPolls = 0
+	return false
+}
@@ -0,0 +1,127 @@
+package twitch
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/stats"
+)
+
+// Kinds of notification an outcome is recorded for
+const (
+	OutcomeLive     = "live"
+	OutcomeUpdate   = "update"
+	OutcomeOffline  = "offline"
+	OutcomeReminder = "reminder"
+	OutcomeSquad    = "squad"
+)
+
+// Results of a notification
+const (
+	ResultSent           = "sent"
+	ResultRateLimited    = "rate limited"
+	ResultFailed         = "failed"
+	ResultGameFiltered   = "skipped, game not in profile"
+	ResultDropsFiltered  = "skipped, drops not enabled"
+	ResultMuted          = "skipped, announcements muted"
+	ResultPaused         = "skipped, registration paused"
+	ResultScripted       = "skipped, suppressed by script"
+	ResultClaimed        = "skipped, announced by another instance"
+	ResultMatureFiltered = "skipped, mature stream in a channel not marked NSFW"
+)
+
+// Outcome of a notification to a Discord channel
+type Outcome struct {
+	Time             time.Time // Time the notification was attempted
+	TwitchChannel    string    // Display name of the Twitch channel
+	DiscordChannelID string    // ID of the Discord channel notified
+	Kind             string    // Kind of notification
+	Result           string    // Result of the notification
+	Error            string    // Error the notification failed with, if any
+}
+
+var outcomes = struct {
+	sync.Mutex
+	guilds map[string][]Outcome // Map of Discord guild IDs to their most recent outcomes, oldest first
+}{guilds: make(map[string][]Outcome)}
+
+// Records the outcome of a notification in a guild. Repeated skips of the same notification are recorded once.
+func recordOutcome(guildID string, dc *discordChannel, tci *twitchChannelInfo, kind string, result string, err error) {
+	o := Outcome{
+		Time:             time.Now().UTC(),
+		TwitchChannel:    tci.DisplayName,
+		DiscordChannelID: dc.ChannelID,
+		Kind:             kind,
+		Result:           result,
+	}
+	if err != nil {
+		o.Error = describeDiscordError(err)
+		if isRateLimited(err) {
+			o.Result = ResultRateLimited
+		}
+	}
+	if result == ResultSent || result == ResultFailed {
+		stats.DeliveryResult(result == ResultFailed)
+	}
+
+	outcomes.Lock()
+	defer outcomes.Unlock()
+
+	log := outcomes.guilds[guildID]
+	for i := len(log) - 1; i >= 0; i-- {
+		if log[i].TwitchChannel == o.TwitchChannel && log[i].DiscordChannelID == o.DiscordChannelID {
+			if err == nil && log[i].Kind == o.Kind && log[i].Result == o.Result && result != ResultSent {
+				return
+			}
+			break
+		}
+	}
+
+	log = append(log, o)
+	if len(log) > constants.GuildOutcomeLogSize {
+		log = log[1:]
+	}
+	outcomes.guilds[guildID] = log
+}
+
+// Returns the most recent notification outcomes of a guild, newest first
+func (t *Session) GetOutcomes(discordGuildID string, limit int) []Outcome {
+	outcomes.Lock()
+	defer outcomes.Unlock()
+
+	log := outcomes.guilds[discordGuildID]
+	recent := []Outcome{}
+	for i := len(log) - 1; i >= 0 && len(recent) < limit; i-- {
+		recent = append(recent, log[i])
+	}
+
+	return recent
+}
+
+func isRateLimited(err error) bool {
+	var restErr *discordgo.RESTError
+	return errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusTooManyRequests
+}
+
+// Explains a Discord error in terms a guild admin can act on
+func describeDiscordError(err error) string {
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil {
+		switch restErr.Response.StatusCode {
+		case http.StatusForbidden:
+			return "the bot is missing permissions in the channel"
+		case http.StatusNotFound:
+			return "the channel or message no longer exists"
+		case http.StatusTooManyRequests:
+			return "Discord rate limited the bot"
+		}
+		if restErr.Message != nil && restErr.Message.Message != "" {
+			return restErr.Message.Message
+		}
+	}
+	return err.Error()
+}
@@ -0,0 +1,71 @@
+package twitch
+
+import (
+	"sort"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// GuildInfo describes a Discord guild the bot has joined
+type GuildInfo struct {
+	ID            string
+	Name          string
+	Connected     bool
+	Registrations int
+}
+
+// Returns every guild seen by the bots sharing the session, sorted by name
+func (t *Session) GetGuilds(ds *discordgo.Session) []GuildInfo {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var guilds []GuildInfo
+
+	for guildID, connected := range guildStatuses() {
+		info := GuildInfo{ID: guildID, Name: guildID, Connected: connected}
+		if guild, err := discordFor(guildID, ds).State.Guild(guildID); err == nil {
+			info.Name = guild.Name
+		}
+		for _, tci := range t.twitchData {
+			info.Registrations += len(tci.DiscordChannels[guildID])
+		}
+		guilds = append(guilds, info)
+	}
+
+	sort.Slice(guilds, func(i, j int) bool {
+		return guilds[i].Name < guilds[j].Name
+	})
+
+	return guilds
+}
+
+// Sends a message to every Discord channel with a registration in a connected guild.
+// Returns the number of channels the message was sent to and the number that failed.
+func (t *Session) Broadcast(ds *discordgo.Session, content string) (int, int) {
+	channels := make(map[string]string)
+	t.mu.RLock()
+	for _, tci := range t.twitchData {
+		for guildID, dcs := range tci.DiscordChannels {
+			if connected, _ := getGuildStatus(guildID); !connected {
+				continue
+			}
+			for _, dc := range dcs {
+				channels[dc.ChannelID] = guildID
+			}
+		}
+	}
+	t.mu.RUnlock()
+
+	sent, failed := 0, 0
+	for channelID, guildID := range channels {
+		if _, err := discordFor(guildID, ds).ChannelMessageSend(channelID, content); err != nil {
+			utils.Log.WithError(err).Error("Error sending Discord message.")
+			failed++
+		} else {
+			sent++
+		}
+	}
+
+	return sent, failed
+}
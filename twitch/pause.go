@@ -0,0 +1,60 @@
+package twitch
+
+import (
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+// Suspends announcements of a registration. A duration of 0 pauses until the registration is resumed.
+func (t *Session) PauseChannel(twitchID string, discordGuildID string, discordChannelID string, duration time.Duration) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if duration < 0 {
+		return constants.ErrInvalidPauseDuration
+	}
+
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	if idx < 0 {
+		return constants.ErrTwitchUserNotRegistered
+	}
+
+	dc := t.twitchData[twitchID].DiscordChannels[discordGuildID][idx]
+	dc.Paused = true
+	dc.PausedUntil = time.Time{}
+	if duration > 0 {
+		dc.PausedUntil = time.Now().Add(duration)
+	}
+
+	t.writeDataToDisk()
+
+	return nil
+}
+
+// Resumes announcements of a paused registration
+func (t *Session) ResumeChannel(twitchID string, discordGuildID string, discordChannelID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	if idx < 0 {
+		return constants.ErrTwitchUserNotRegistered
+	}
+
+	dc := t.twitchData[twitchID].DiscordChannels[discordGuildID][idx]
+	if !dc.isPaused() {
+		return constants.ErrNotPaused
+	}
+	dc.Paused = false
+	dc.PausedUntil = time.Time{}
+
+	t.writeDataToDisk()
+
+	return nil
+}
+
+// Returns whether announcements of a registration are paused. Pauses with a duration end on their own.
+func (dc *discordChannel) isPaused() bool {
+	return dc.Paused && (dc.PausedUntil.IsZero() || time.Now().Before(dc.PausedUntil))
+}
@@ -0,0 +1,128 @@
+package twitch
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/config"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Discord permission the bot needs to announce streams, and why if only some registrations need it
+type permission struct {
+	bit    int64
+	name   string
+	reason string
+}
+
+var (
+	permissionView    = permission{discordgo.PermissionViewChannel, "View Channel", ""}
+	permissionSend    = permission{discordgo.PermissionSendMessages, "Send Messages", ""}
+	permissionEmbed   = permission{discordgo.PermissionEmbedLinks, "Embed Links", ""}
+	permissionAttach  = permission{discordgo.PermissionAttachFiles, "Attach Files", "to upload stream previews"}
+	permissionPin     = permission{discordgo.PermissionManageMessages, "Manage Messages", "to pin live messages"}
+	permissionMention = permission{discordgo.PermissionMentionEveryone, "Mention All Roles", "to ping @everyone, @here or roles that are not mentionable"}
+)
+
+// PermissionProblem describes a Discord channel with registrations where the bot lacks permissions they need
+type PermissionProblem struct {
+	DiscordChannelID string   // ID of the Discord channel
+	TwitchChannels   []string // Display names of the Twitch channels announced in the Discord channel
+	Missing          []string // Permissions the bot lacks, with why they are needed if only some registrations need them
+	Err              error    // Error getting the bot's permissions, e.g. because the Discord channel was deleted
+}
+
+// Checks the bot's permissions in every Discord channel of a guild with registrations. Returns the number of Discord
+// channels checked and those missing permissions, sorted by Discord channel ID.
+func (t *Session) CheckPermissions(ds *discordgo.Session, discordGuildID string) (int, []PermissionProblem, error) {
+	roles, err := ds.GuildRoles(discordGuildID)
+	if err != nil {
+		return 0, nil, err
+	}
+	mentionable := make(map[string]bool)
+	for _, role := range roles {
+		mentionable[role.ID] = role.Mentionable
+	}
+
+	t.mu.RLock()
+	tagMentions := ""
+	for _, roleID := range t.tagRoles(discordGuildID) {
+		tagMentions += " <@&" + roleID + ">"
+	}
+
+	type channelNeeds struct {
+		twitchChannels []string
+		permissions    []permission
+	}
+	channels := make(map[string]*channelNeeds)
+	for _, tci := range t.twitchData {
+		for _, dc := range tci.DiscordChannels[discordGuildID] {
+			needs := channels[dc.ChannelID]
+			if needs == nil {
+				needs = &channelNeeds{permissions: []permission{permissionView, permissionSend, permissionEmbed}}
+				channels[dc.ChannelID] = needs
+			}
+			needs.twitchChannels = append(needs.twitchChannels, tci.DisplayName)
+
+			if config.Settings.UploadPreviews {
+				needs.permissions = append(needs.permissions, permissionAttach)
+			}
+			if dc.Pin {
+				needs.permissions = append(needs.permissions, permissionPin)
+			}
+
+			// Only mentions written by moderators ping, so they are the ones that need permission
+			text := strings.Join(dc.Templates, " ") + tagMentions
+			if p := t.getProfile(discordGuildID, dc.Profile); p != nil {
+				text += " " + p.Mention + " " + p.Template
+			}
+			allowed := utils.AllowedMentions(text)
+			if len(allowed.Parse) > 0 {
+				needs.permissions = append(needs.permissions, permissionMention)
+			}
+			for _, roleID := range allowed.Roles {
+				if !mentionable[roleID] {
+					needs.permissions = append(needs.permissions, permissionMention)
+				}
+			}
+		}
+	}
+
+	t.mu.RUnlock()
+
+	problems := []PermissionProblem{}
+	for channelID, needs := range channels {
+		sort.Strings(needs.twitchChannels)
+		problem := PermissionProblem{DiscordChannelID: channelID, TwitchChannels: needs.twitchChannels}
+
+		granted, err := ds.UserChannelPermissions(ds.State.User.ID, channelID)
+		if err != nil {
+			problem.Err = err
+			problems = append(problems, problem)
+			continue
+		}
+
+		reported := make(map[int64]bool)
+		for _, p := range needs.permissions {
+			if granted&p.bit == p.bit || reported[p.bit] {
+				continue
+			}
+			reported[p.bit] = true
+
+			missing := p.name
+			if p.reason != "" {
+				missing += " (" + p.reason + ")"
+			}
+			problem.Missing = append(problem.Missing, missing)
+		}
+		if len(problem.Missing) > 0 {
+			problems = append(problems, problem)
+		}
+	}
+
+	sort.Slice(problems, func(i, j int) bool {
+		return problems[i].DiscordChannelID < problems[j].DiscordChannelID
+	})
+	return len(channels), problems, nil
+}
@@ -0,0 +1,58 @@
+package twitch
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Sets whether a registration pins its live message while the stream is live
+func (t *Session) SetChannelPin(twitchID string, discordGuildID string, discordChannelID string, pin bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	if idx < 0 {
+		return constants.ErrTwitchUserNotRegistered
+	}
+
+	t.twitchData[twitchID].DiscordChannels[discordGuildID][idx].Pin = pin
+
+	t.writeDataToDisk()
+
+	return nil
+}
+
+// Pins the live message of a registration unless the channel already has the maximum number of pins
+func pinLiveMessage(ds *discordgo.Session, dc *discordChannel) {
+	pinned, err := ds.ChannelMessagesPinned(dc.ChannelID)
+	if err != nil {
+		utils.Log.WithError(err).Error("Failed to get pinned Discord messages.")
+		return
+	} else if len(pinned) >= constants.DiscordMaxPins {
+		utils.Log.WithField("channel_id", dc.ChannelID).Warn("Live message was not pinned because the channel has too many pins.")
+		return
+	}
+
+	if err := ds.ChannelMessagePin(dc.ChannelID, dc.LiveMessageID); err != nil {
+		utils.Log.WithFields(logrus.Fields{
+			"channel_id": dc.ChannelID,
+			"error":      err}).Error("Failed to pin Discord message. The bot may lack the Manage Messages permission.")
+		return
+	}
+
+	dc.Pinned = true
+}
+
+// Unpins the live message of a registration if it was pinned
+func unpinLiveMessage(ds *discordgo.Session, dc *discordChannel) {
+	if !dc.Pinned {
+		return
+	}
+
+	if err := ds.ChannelMessageUnpin(dc.ChannelID, dc.LiveMessageID); err != nil {
+		utils.Log.WithError(err).Error("Failed to unpin Discord message.")
+	}
+	dc.Pinned = false
+}
@@ -0,0 +1,292 @@
+package twitch
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/features"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// EventSub subscription types for polls and predictions
+const (
+	eventSubTypePollBegin          = "channel.poll.begin"
+	eventSubTypePollProgress       = "channel.poll.progress"
+	eventSubTypePollEnd            = "channel.poll.end"
+	eventSubTypePredictionBegin    = "channel.prediction.begin"
+	eventSubTypePredictionProgress = "channel.prediction.progress"
+	eventSubTypePredictionLock     = "channel.prediction.lock"
+	eventSubTypePredictionEnd      = "channel.prediction.end"
+)
+
+var pollEventSubTypes = []string{
+	eventSubTypePollBegin, eventSubTypePollProgress, eventSubTypePollEnd,
+	eventSubTypePredictionBegin, eventSubTypePredictionProgress, eventSubTypePredictionLock, eventSubTypePredictionEnd,
+}
+
+type pollEvent struct {
+	ID                   string `json:"id"`
+	BroadcasterUserLogin string `json:"broadcaster_user_login"`
+	BroadcasterUserName  string `json:"broadcaster_user_name"`
+	Title                string `json:"title"`
+	Choices              []struct {
+		Title              string `json:"title"`
+		Votes              int    `json:"votes"`
+		ChannelPointsVotes int    `json:"channel_points_votes"`
+		BitsVotes          int    `json:"bits_votes"`
+	} `json:"choices"`
+	Status string `json:"status"`
+}
+
+type predictionEvent struct {
+	ID                   string `json:"id"`
+	BroadcasterUserLogin string `json:"broadcaster_user_login"`
+	BroadcasterUserName  string `json:"broadcaster_user_name"`
+	Title                string `json:"title"`
+	WinningOutcomeID     string `json:"winning_outcome_id"`
+	Outcomes             []struct {
+		ID            string `json:"id"`
+		Title         string `json:"title"`
+		Users         int    `json:"users"`
+		ChannelPoints int    `json:"channel_points"`
+	} `json:"outcomes"`
+	Status string `json:"status"`
+}
+
+// A poll or prediction mirrored into Discord channels
+type pollMirror struct {
+	messages   map[string]string // Map of Discord channel ID to the mirrored message ID
+	updateTime time.Time         // Time the mirrored messages were last edited
+}
+
+// Mirrors in progress, keyed by poll or prediction ID
+type pollMirrors struct {
+	mu      sync.Mutex
+	mirrors map[string]*pollMirror
+}
+
+// Mirrors a linked broadcaster's polls and predictions into a Discord channel
+func (t *Session) SetPollChannel(login string, discordGuildID string, discordChannelID string) error {
+	if !features.Enabled(features.EventSub, discordGuildID) {
+		return constants.ErrFeatureDisabled
+	}
+
+	_, bt, err := t.broadcasterClient(login, discordGuildID)
+	if err != nil {
+		return err
+	}
+
+	for _, subType := range pollEventSubTypes {
+		if err := t.ensureEventSub(subType, bt.UserID); err != nil {
+			return err
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.PollChannels == nil {
+		gs.PollChannels = make(map[string]string)
+	}
+	gs.PollChannels[login] = discordChannelID
+
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Stops mirroring a broadcaster's polls and predictions in a guild
+func (t *Session) DisablePolls(login string, discordGuildID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.PollChannels[login] == "" {
+		return constants.ErrPollsNotConfigured
+	}
+
+	delete(gs.PollChannels, login)
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Returns the Discord channels a broadcaster's polls and predictions are mirrored to, mapped to their guild
+func (t *Session) pollChannels(login string) map[string]string {
+	channels := make(map[string]string)
+
+	for guildID, gs := range t.guilds {
+		if channelID := gs.PollChannels[login]; channelID != "" && features.Enabled(features.EventSub, guildID) {
+			channels[channelID] = guildID
+		}
+	}
+
+	return channels
+}
+
+func (t *Session) handlePoll(ds *discordgo.Session, event json.RawMessage, ended bool) {
+	var poll pollEvent
+	if err := json.Unmarshal(event, &poll); err != nil {
+		utils.Log.WithError(err).Error("Failed to parse poll event.")
+		return
+	}
+
+	total := 0
+	for _, choice := range poll.Choices {
+		total += choice.Votes
+	}
+
+	lines := []string{}
+	winner := -1
+	for i, choice := range poll.Choices {
+		lines = append(lines, fmt.Sprintf("**%v**\n%v %v%% (%v votes)", utils.SanitizeText(choice.Title), progressBar(choice.Votes, total), percent(choice.Votes, total), choice.Votes))
+		if winner < 0 || choice.Votes > poll.Choices[winner].Votes {
+			winner = i
+		}
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Author:      &discordgo.MessageEmbedAuthor{Name: poll.BroadcasterUserName + " started a poll"},
+		Title:       utils.SanitizeText(poll.Title),
+		Description: strings.Join(lines, "\n"),
+		Color:       constants.DiscordRewardColor,
+	}
+
+	var results *discordgo.MessageEmbed
+	if ended {
+		embed.Author.Name = poll.BroadcasterUserName + "'s poll has ended"
+		if poll.Status == "completed" && winner >= 0 && total > 0 {
+			results = &discordgo.MessageEmbed{
+				Title:       "Poll results: " + utils.SanitizeText(poll.Title),
+				Description: fmt.Sprintf("**%v** won with %v%% of %v votes.", utils.SanitizeText(poll.Choices[winner].Title), percent(poll.Choices[winner].Votes, total), total),
+				Color:       constants.DiscordRewardColor,
+			}
+		}
+	}
+
+	t.mirrorPoll(ds, poll.ID, poll.BroadcasterUserLogin, embed, results, ended, ended)
+}
+
+func (t *Session) handlePrediction(ds *discordgo.Session, event json.RawMessage, locked bool, ended bool) {
+	var prediction predictionEvent
+	if err := json.Unmarshal(event, &prediction); err != nil {
+		utils.Log.WithError(err).Error("Failed to parse prediction event.")
+		return
+	}
+
+	total := 0
+	for _, outcome := range prediction.Outcomes {
+		total += outcome.ChannelPoints
+	}
+
+	lines := []string{}
+	winner := ""
+	for _, outcome := range prediction.Outcomes {
+		title := utils.SanitizeText(outcome.Title)
+		if outcome.ID == prediction.WinningOutcomeID {
+			winner = title
+			title += " ✅"
+		}
+		lines = append(lines, fmt.Sprintf("**%v**\n%v %v%% (%v users, %v points)", title, progressBar(outcome.ChannelPoints, total), percent(outcome.ChannelPoints, total), outcome.Users, outcome.ChannelPoints))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Author:      &discordgo.MessageEmbedAuthor{Name: prediction.BroadcasterUserName + " started a prediction"},
+		Title:       utils.SanitizeText(prediction.Title),
+		Description: strings.Join(lines, "\n"),
+		Color:       constants.DiscordRewardColor,
+	}
+
+	var results *discordgo.MessageEmbed
+	if ended {
+		embed.Author.Name = prediction.BroadcasterUserName + "'s prediction has ended"
+		if prediction.Status == "resolved" && winner != "" {
+			results = &discordgo.MessageEmbed{
+				Title:       "Prediction results: " + utils.SanitizeText(prediction.Title),
+				Description: fmt.Sprintf("**%v** was the winning outcome. %v channel points were predicted.", winner, total),
+				Color:       constants.DiscordRewardColor,
+			}
+		}
+	} else if locked {
+		embed.Author.Name = prediction.BroadcasterUserName + "'s prediction is locked"
+	}
+
+	t.mirrorPoll(ds, prediction.ID, prediction.BroadcasterUserLogin, embed, results, ended || locked, ended)
+}
+
+// Sends or edits the mirrored message of a poll or prediction. Progress edits are throttled
+// unless force is set. Results are sent as a new message and done ends the mirror.
+func (t *Session) mirrorPoll(ds *discordgo.Session, id string, login string, embed *discordgo.MessageEmbed, results *discordgo.MessageEmbed, force bool, done bool) {
+	t.mu.RLock()
+	channels := t.pollChannels(login)
+	t.mu.RUnlock()
+
+	t.polls.mu.Lock()
+	defer t.polls.mu.Unlock()
+
+	mirror := t.polls.mirrors[id]
+	if mirror == nil {
+		mirror = &pollMirror{messages: make(map[string]string)}
+		t.polls.mirrors[id] = mirror
+	} else if !force && time.Since(mirror.updateTime) < constants.PollUpdateInterval {
+		return
+	}
+	mirror.updateTime = time.Now()
+
+	for channelID, guildID := range channels {
+		ds := discordFor(guildID, ds)
+		if messageID := mirror.messages[channelID]; messageID != "" {
+			if _, err := ds.ChannelMessageEditEmbed(channelID, messageID, embed); err != nil {
+				utils.Log.WithError(err).Error("Error updating Discord message.")
+			}
+		} else if m, err := ds.ChannelMessageSendEmbed(channelID, embed); err != nil {
+			utils.Log.WithError(err).Error("Error sending Discord message.")
+		} else {
+			mirror.messages[channelID] = m.ID
+		}
+
+		if results != nil {
+			if _, err := ds.ChannelMessageSendEmbed(channelID, results); err != nil {
+				utils.Log.WithError(err).Error("Error sending Discord message.")
+			}
+		}
+	}
+
+	if done {
+		delete(t.polls.mirrors, id)
+	}
+}
+
+func (t *Session) registerPollListeners() {
+	t.onEventSub(eventSubTypePollBegin, func(ds *discordgo.Session, e json.RawMessage) { t.handlePoll(ds, e, false) })
+	t.onEventSub(eventSubTypePollProgress, func(ds *discordgo.Session, e json.RawMessage) { t.handlePoll(ds, e, false) })
+	t.onEventSub(eventSubTypePollEnd, func(ds *discordgo.Session, e json.RawMessage) { t.handlePoll(ds, e, true) })
+	t.onEventSub(eventSubTypePredictionBegin, func(ds *discordgo.Session, e json.RawMessage) { t.handlePrediction(ds, e, false, false) })
+	t.onEventSub(eventSubTypePredictionProgress, func(ds *discordgo.Session, e json.RawMessage) { t.handlePrediction(ds, e, false, false) })
+	t.onEventSub(eventSubTypePredictionLock, func(ds *discordgo.Session, e json.RawMessage) { t.handlePrediction(ds, e, true, false) })
+	t.onEventSub(eventSubTypePredictionEnd, func(ds *discordgo.Session, e json.RawMessage) { t.handlePrediction(ds, e, false, true) })
+}
+
+// Returns a text progress bar of value out of total
+func progressBar(value int, total int) string {
+	filled := 0
+	if total > 0 {
+		filled = value * constants.ProgressBarLength / total
+	}
+	// Goals can be exceeded
+	if filled > constants.ProgressBarLength {
+		filled = constants.ProgressBarLength
+	}
+
+	return strings.Repeat("█", filled) + strings.Repeat("░", constants.ProgressBarLength-filled)
+}
+
+func percent(value int, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return value * 100 / total
+}
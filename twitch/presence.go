@@ -0,0 +1,152 @@
+package twitch
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Persisted availability of a guild, so guilds the bot was removed from can be told apart from guilds
+// that did not report in during the current session
+type guildPresence struct {
+	LastSeen  time.Time // Time the guild was last available
+	RemovedAt time.Time // Time the bot was found to be removed from the guild, zero if it is in it
+}
+
+var presence = struct {
+	sync.Mutex
+	loaded bool
+	guilds map[string]*guildPresence // Map of guild ID to its presence
+}{guilds: make(map[string]*guildPresence)}
+
+// Loads the persisted guild presence once per process
+func loadGuildPresence() {
+	presence.Lock()
+	defer presence.Unlock()
+
+	if presence.loaded {
+		return
+	}
+	presence.loaded = true
+
+	if err := utils.ReadGobFromDisk(utils.DataDir, "guild_presence", &presence.guilds); err != nil && !errors.Is(err, os.ErrNotExist) {
+		utils.Log.WithError(err).Error("Error reading guild presence from disk.")
+	}
+}
+
+// Writes the guild presence to disk. The caller must hold the presence lock.
+func writeGuildPresence() {
+	if err := utils.WriteGobToDisk(utils.DataDir, "guild_presence", presence.guilds); err != nil {
+		utils.Log.WithError(err).Error("Error writing guild presence to disk.")
+	}
+}
+
+func getGuildPresence(guildID string) *guildPresence {
+	if presence.guilds[guildID] == nil {
+		presence.guilds[guildID] = &guildPresence{}
+	}
+	return presence.guilds[guildID]
+}
+
+// Records that a guild is available
+func markGuildSeen(guildID string) {
+	presence.Lock()
+	defer presence.Unlock()
+
+	p := getGuildPresence(guildID)
+	removed := !p.RemovedAt.IsZero()
+	p.LastSeen = time.Now().UTC()
+	p.RemovedAt = time.Time{}
+
+	// Only changes of membership are written right away, LastSeen is saved with them or on shutdown
+	if removed {
+		writeGuildPresence()
+	}
+}
+
+// Records that the bot was removed from a guild
+func markGuildRemoved(guildID string) {
+	presence.Lock()
+	defer presence.Unlock()
+
+	if p := getGuildPresence(guildID); p.RemovedAt.IsZero() {
+		p.RemovedAt = time.Now().UTC()
+		writeGuildPresence()
+	}
+}
+
+// Deletes the presence record of a guild. Returns whether it had one.
+func forgetGuildPresence(guildID string) bool {
+	loadGuildPresence()
+	presence.Lock()
+	defer presence.Unlock()
+
+	if presence.guilds[guildID] == nil {
+		return false
+	}
+	delete(presence.guilds, guildID)
+	writeGuildPresence()
+	return true
+}
+
+// Compares the guilds with stored data against the guilds the Discord sessions are in. Guilds none of the
+// bots are in are marked as removed, and their data is purged once they have been removed for GuildRemovalGracePeriod.
+// Every bot sharing the session must be connected, otherwise guilds of a missing bot would be marked as removed.
+func Reconcile(t *Session, sessions ...*discordgo.Session) {
+	present := make(map[string]bool)
+	for _, s := range sessions {
+		for _, guild := range s.State.Guilds {
+			present[guild.ID] = true
+		}
+	}
+
+	known := make(map[string]bool)
+	t.mu.RLock()
+	for _, tci := range t.twitchData {
+		for guildID := range tci.DiscordChannels {
+			known[guildID] = true
+		}
+	}
+	for guildID := range t.guilds {
+		known[guildID] = true
+	}
+	t.mu.RUnlock()
+
+	var expired []string
+
+	presence.Lock()
+	for guildID := range present {
+		p := getGuildPresence(guildID)
+		p.LastSeen = time.Now().UTC()
+		p.RemovedAt = time.Time{}
+	}
+	for guildID := range known {
+		if present[guildID] {
+			continue
+		}
+
+		p := getGuildPresence(guildID)
+		if p.RemovedAt.IsZero() {
+			p.RemovedAt = time.Now().UTC()
+			utils.Log.Infof("The bot is no longer in guild %v. Its data is deleted after %v.\n", guildID, constants.GuildRemovalGracePeriod)
+		} else if time.Since(p.RemovedAt) > constants.GuildRemovalGracePeriod {
+			expired = append(expired, guildID)
+			delete(presence.guilds, guildID)
+		}
+	}
+	writeGuildPresence()
+	presence.Unlock()
+
+	for _, guildID := range expired {
+		if _, err := t.PurgeGuild(guildID); err != nil {
+			utils.Log.WithError(err).Errorf("Error deleting the data of removed guild %v.\n", guildID)
+		} else {
+			utils.Log.Infof("Deleted the data of guild %v, which the bot was removed from.\n", guildID)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package twitch
+
+import (
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+// Returns the live message a registration sends. Sample stream data is used if the channel is offline.
+func (t *Session) PreviewLiveMessage(twitchID string, discordGuildID string, discordChannelID string) (*discordgo.MessageSend, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	if idx < 0 {
+		return nil, constants.ErrTwitchUserNotRegistered
+	}
+
+	tci := *t.twitchData[twitchID]
+	dc := tci.DiscordChannels[discordGuildID][idx]
+	if tci.StreamData == nil {
+		tci.StreamData = &helix.Stream{
+			UserLogin:    twitchID,
+			UserName:     tci.DisplayName,
+			Title:        "Preview of the live message",
+			GameName:     "Just Chatting",
+			ThumbnailURL: constants.TwitchPreviewURL + twitchID + "-{width}x{height}.jpg",
+		}
+		tci.StartTime = time.Now()
+	}
+
+	p := t.getProfile(discordGuildID, dc.Profile)
+	// Previews must not advance the template rotation
+	next := *dc
+
+	// Previews show the mentions without pinging them
+	names := t.nameStyle(discordGuildID)
+	content, _ := t.withTagRoles(discordGuildID, &tci, p).content(&tci, next.nextTemplate(), names)
+	return &discordgo.MessageSend{
+		Content:         content,
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+		Embed:           createDiscordLiveEmbedMessage(&tci, embedStyle{t.embedColor(discordGuildID, dc, &tci, p), t.embedLayout(discordGuildID), t.channelInfoFooter(discordGuildID), names, false, t.hiatusDays(discordGuildID), t.streaks(discordGuildID)}),
+	}, nil
+}
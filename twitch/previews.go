@@ -0,0 +1,137 @@
+package twitch
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/config"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Name of the stream preview attached to live messages when upload_previews is enabled
+const previewFileName = "preview.jpg"
+
+// previewCache keeps downloaded stream previews so a stream announced in many Discord channels is downloaded once
+type previewCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedPreview // Map of preview URL to its download
+}
+
+type cachedPreview struct {
+	data    []byte    // JPEG of the preview
+	fetched time.Time // Time the preview was downloaded
+}
+
+var previews = previewCache{entries: make(map[string]*cachedPreview)}
+
+// Returns the preview at a URL, downloading it unless it was downloaded within TwitchThumbnailUpdateTime
+func (c *previewCache) get(url string) ([]byte, error) {
+	c.mu.Lock()
+	if e := c.entries[url]; e != nil && time.Since(e.fetched) < constants.TwitchThumbnailUpdateTime {
+		c.mu.Unlock()
+		return e.data, nil
+	}
+	c.mu.Unlock()
+
+	data, err := downloadPreview(url)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for u, e := range c.entries {
+		if time.Since(e.fetched) >= constants.TwitchThumbnailUpdateTime {
+			delete(c.entries, u)
+		}
+	}
+	c.entries[url] = &cachedPreview{data: data, fetched: time.Now()}
+
+	return data, nil
+}
+
+func downloadPreview(url string) ([]byte, error) {
+	resp, err := utils.HTTPClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, constants.ErrPreviewUnavailable
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, constants.PreviewMaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > constants.PreviewMaxBytes {
+		return nil, constants.ErrPreviewTooLarge
+	}
+
+	return data, nil
+}
+
+// Replaces the linked preview of a live embed by an attachment when previews are uploaded.
+// Returns the files to send with the message, or nil if the preview stays linked, e.g. because it could not be downloaded.
+func uploadPreview(embed *discordgo.MessageEmbed) []*discordgo.File {
+	if !config.Settings.UploadPreviews || embed.Image == nil || embed.Image.URL == "" {
+		return nil
+	}
+
+	data, err := previews.get(embed.Image.URL)
+	if err != nil {
+		utils.Log.WithFields(logrus.Fields{
+			"url":   embed.Image.URL,
+			"error": err}).Warn("Failed to download stream preview, linking it instead.")
+		return nil
+	}
+
+	embed.Image.URL = "attachment://" + previewFileName
+	return []*discordgo.File{{
+		Name:        previewFileName,
+		ContentType: "image/jpeg",
+		Reader:      bytes.NewReader(data),
+	}}
+}
+
+// Keeps showing the uploaded preview of a live message when its embed is updated, as edits cannot upload a new one
+func keepUploadedPreview(dc *discordChannel, embed *discordgo.MessageEmbed) {
+	if dc.PreviewUploaded && embed.Image != nil {
+		embed.Image.URL = "attachment://" + previewFileName
+	}
+}
+
+// Edits a live message into its offline summary.
+// An uploaded preview is removed so it isn't left below the summary as a loose attachment.
+// Plain live messages, and messages whose summary Discord refuses, are edited into plain text instead.
+func editOfflineMessage(ds *discordgo.Session, dc *discordChannel, embed *discordgo.MessageEmbed, plain string) error {
+	if dc.PlainMessage || !embedWithinLimits(embed) {
+		_, err := ds.ChannelMessageEdit(dc.ChannelID, dc.LiveMessageID, plain)
+		return err
+	}
+
+	var err error
+	if !dc.PreviewUploaded {
+		_, err = ds.ChannelMessageEditEmbed(dc.ChannelID, dc.LiveMessageID, embed)
+	} else {
+		embed.Type = "rich"
+		_, err = ds.RequestWithBucketID("PATCH", discordgo.EndpointChannelMessage(dc.ChannelID, dc.LiveMessageID), map[string]interface{}{
+			"embed":       embed,
+			"attachments": []interface{}{},
+		}, discordgo.EndpointChannelMessage(dc.ChannelID, ""))
+	}
+
+	if isBadRequest(err) {
+		utils.Log.WithError(err).Warn("Discord refused the offline summary. Editing in a plain summary instead.")
+		_, err = ds.ChannelMessageEdit(dc.ChannelID, dc.LiveMessageID, plain)
+	}
+	return err
+}
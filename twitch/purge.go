@@ -0,0 +1,158 @@
+package twitch
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/features"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/stats"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// PurgeReport counts the data deleted by PurgeGuild
+type PurgeReport struct {
+	Registrations      int  // Registrations of Twitch channels to the guild's Discord channels
+	TwitchChannels     int  // Twitch channels no longer monitored by any guild
+	Settings           bool // Whether the guild had settings, e.g. profiles, removed registrations or the ban log
+	Outcomes           int  // Recorded notification outcomes
+	Broadcasters       int  // Broadcaster authorizations linked to the guild
+	PendingBans        int  // Synced bans waiting for confirmation
+	FeedEvents         int  // Live feed entries of Twitch channels only the guild monitored
+	StreamEvents       int  // Event log entries of Twitch channels no longer monitored
+	FeatureOverrides   int  // Feature flag overrides of the guild
+	Presence           bool // Whether the guild's presence record was removed
+	CommandMetrics     int  // Commands with usage metrics recorded in the guild
+	AnnouncementClaims int  // Announcement claim files of the guild's Discord channels
+}
+
+// Irrevocably deletes every piece of stored data related to a guild
+func (t *Session) PurgeGuild(discordGuildID string) (PurgeReport, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var report PurgeReport
+
+	forgotten := make(map[string]bool)
+	discordChannelIDs := make(map[string]bool)
+	for twitchID, tci := range t.twitchData {
+		if dcs, ok := tci.DiscordChannels[discordGuildID]; ok {
+			report.Registrations += len(dcs)
+			for _, dc := range dcs {
+				discordChannelIDs[dc.ChannelID] = true
+			}
+			delete(tci.DiscordChannels, discordGuildID)
+		}
+		if len(tci.DiscordChannels) == 0 {
+			forgotten[twitchID] = true
+			delete(t.twitchData, twitchID)
+		}
+	}
+	report.TwitchChannels = len(forgotten)
+	t.writeDataToDisk()
+
+	if _, ok := t.guilds[discordGuildID]; ok {
+		report.Settings = true
+		delete(t.guilds, discordGuildID)
+		t.writeGuildsToDisk()
+	}
+
+	t.broadcasterMu.Lock()
+	for login, bt := range t.broadcasters {
+		if bt.GuildIDs[discordGuildID] {
+			report.Broadcasters++
+			delete(bt.GuildIDs, discordGuildID)
+			if len(bt.GuildIDs) == 0 {
+				delete(t.broadcasters, login)
+			}
+		}
+	}
+	if report.Broadcasters > 0 {
+		t.writeBroadcastersToDisk()
+	}
+	t.broadcasterMu.Unlock()
+
+	outcomes.Lock()
+	report.Outcomes = len(outcomes.guilds[discordGuildID])
+	delete(outcomes.guilds, discordGuildID)
+	outcomes.Unlock()
+
+	deliveries.Lock()
+	delete(deliveries.guilds, discordGuildID)
+	deliveries.Unlock()
+
+	t.bans.mu.Lock()
+	for messageID, pb := range t.bans.pending {
+		if pb.guildID == discordGuildID {
+			report.PendingBans++
+			delete(t.bans.pending, messageID)
+		}
+	}
+	t.bans.mu.Unlock()
+
+	t.feed.mu.Lock()
+	kept := t.feed.events[:0]
+	for _, event := range t.feed.events {
+		if event.GuildIDs[discordGuildID] {
+			delete(event.GuildIDs, discordGuildID)
+			if len(event.GuildIDs) == 0 {
+				report.FeedEvents++
+				delete(t.feed.seen, event.TwitchChannel+":"+event.StartTime.String())
+				continue
+			}
+		}
+		kept = append(kept, event)
+	}
+	t.feed.events = kept
+	t.feed.mu.Unlock()
+
+	var err error
+	if report.StreamEvents, err = t.forgetStreamEvents(forgotten); err != nil {
+		return report, err
+	}
+
+	if report.FeatureOverrides, err = features.ForgetGuild(discordGuildID); err != nil {
+		return report, err
+	}
+
+	report.CommandMetrics = stats.ForgetGuild(discordGuildID)
+	report.Presence = forgetGuildPresence(discordGuildID)
+
+	for channelID := range discordChannelIDs {
+		removed, err := forgetAnnouncementClaims(channelID)
+		report.AnnouncementClaims += removed
+		if err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// Removes the event log entries of Twitch channels. Returns the number of entries removed.
+func (t *Session) forgetStreamEvents(logins map[string]bool) (int, error) {
+	if len(logins) == 0 {
+		return 0, nil
+	}
+
+	lines, err := utils.ReadLinesFromDisk(utils.DataDir, t.name+"_events")
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	kept := lines[:0]
+	for _, line := range lines {
+		var e StreamEvent
+		if err := json.Unmarshal(line, &e); err == nil && logins[e.Login] {
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	removed := len(lines) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, utils.WriteLinesToDisk(utils.DataDir, t.name+"_events", kept)
+}
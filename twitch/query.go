@@ -0,0 +1,156 @@
+package twitch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/tracing"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// rateLimiter coordinates Twitch rate limit usage between query workers
+type rateLimiter struct {
+	mu        sync.Mutex
+	remaining int       // Points remaining in the current bucket, -1 if unknown
+	reset     time.Time // Time the bucket refills
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{remaining: -1}
+}
+
+// Blocks until the rate limit bucket has enough points for another request
+func (r *rateLimiter) wait() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.remaining >= 0 && r.remaining <= constants.TwitchRateLimitThreshold {
+		if d := time.Until(r.reset); d > 0 {
+			utils.Log.Debugf("Twitch rate limit nearly exhausted. Waiting %v for reset.\n", d)
+			time.Sleep(d)
+		}
+		r.remaining = -1
+	}
+
+	if r.remaining > 0 {
+		r.remaining--
+	}
+}
+
+// Updates the limiter with the rate limit headers of a Twitch response
+func (r *rateLimiter) update(resp *helix.ResponseCommon) {
+	if resp == nil || resp.Header.Get("Ratelimit-Remaining") == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.remaining = resp.GetRateLimitRemaining()
+	r.reset = time.Unix(int64(resp.GetRateLimitReset()), 0)
+}
+
+// Sets the number of GetStreams batches that can be issued concurrently
+func (t *Session) SetQueryWorkers(n int) {
+	if n < 1 {
+		n = 1
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.queryWorkers = n
+}
+
+// Queries the streams of the given channels in batches using a pool of workers.
+// Returns an error if any batch failed, since a partial response cannot be used to decide who is offline.
+func (t *Session) queryStreams(ctx context.Context, channels []string) ([]helix.Stream, error) {
+	var batches [][]string
+	for i := 0; i < len(channels); i += constants.TwitchQueryBatchSize {
+		end := i + constants.TwitchQueryBatchSize
+		if end > len(channels) {
+			end = len(channels)
+		}
+		batches = append(batches, channels[i:end])
+	}
+
+	t.mu.RLock()
+	workers := t.queryWorkers
+	t.mu.RUnlock()
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(batches) {
+		workers = len(batches)
+	}
+
+	jobs := make(chan []string)
+	results := make(chan batchResult, len(batches))
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range jobs {
+				results <- t.queryStreamBatch(ctx, batch)
+			}
+		}()
+	}
+
+	for _, batch := range batches {
+		jobs <- batch
+	}
+	close(jobs)
+	wg.Wait()
+	close(results)
+
+	var (
+		streams []helix.Stream
+		err     error
+	)
+	for r := range results {
+		if r.err != nil {
+			err = r.err
+			continue
+		}
+		streams = append(streams, r.streams...)
+	}
+
+	return streams, err
+}
+
+type batchResult struct {
+	streams []helix.Stream
+	err     error
+}
+
+// Queries the streams of one batch of channels
+func (t *Session) queryStreamBatch(ctx context.Context, batch []string) batchResult {
+	_, span := tracing.Span(ctx, "twitch.get_streams", attribute.Int("twitch.channels", len(batch)))
+	defer span.End()
+
+	t.limiter.wait()
+	resp, err := t.helixClient().GetStreams(&helix.StreamsParams{
+		First:      constants.TwitchQueryBatchSize,
+		UserLogins: batch,
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return batchResult{err: err}
+	}
+	t.limiter.update(&resp.ResponseCommon)
+	t.logResponse("streams", &resp.ResponseCommon, resp.Data)
+	if resp.StatusCode != 200 {
+		err = fmt.Errorf("%w: %v %v", constants.ErrTwitchQueryFailed, resp.StatusCode, resp.ErrorMessage)
+		tracing.RecordError(span, err)
+		return batchResult{err: err}
+	}
+
+	span.SetAttributes(attribute.Int("twitch.live_streams", len(resp.Data.Streams)))
+	return batchResult{streams: resp.Data.Streams}
+}
@@ -0,0 +1,113 @@
+package twitch
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/crash"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Connection retries of a session that could not connect to Twitch
+type reconnection struct {
+	running int32                // 1 while a retry loop runs, accessed atomically
+	closed  int32                // 1 once the session is closed, accessed atomically
+	mu      sync.Mutex           // Guards bots and stop
+	bots    []*discordgo.Session // Additional bots attached while the session was disconnected
+	stop    context.CancelFunc   // Stops the jobs of the current connection, nil while disconnected
+	jobs    sync.WaitGroup       // Jobs of connections still running
+}
+
+// Returns whether the session is connected to Twitch
+func (t *Session) connected() bool {
+	return atomic.LoadInt32(&t.isConnected) == 1
+}
+
+func (t *Session) setConnected(connected bool) {
+	var value int32
+	if connected {
+		value = 1
+	}
+	atomic.StoreInt32(&t.isConnected, value)
+}
+
+// Returns the context of the jobs of a new connection to Twitch, and the function cancelling it once the connection
+// is lost. Jobs of an earlier connection still running are stopped.
+func (t *Session) startConnection() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	t.reconnection.mu.Lock()
+	defer t.reconnection.mu.Unlock()
+
+	if t.reconnection.stop != nil {
+		t.reconnection.stop()
+	}
+	t.reconnection.stop = cancel
+	return ctx, cancel
+}
+
+// Runs job in the background as a job of the current connection
+func (t *Session) goJob(job func()) {
+	t.reconnection.jobs.Add(1)
+	go func() {
+		defer t.reconnection.jobs.Done()
+		job()
+	}()
+}
+
+// Stops the jobs of the current connection
+func (t *Session) stopConnection() {
+	t.reconnection.mu.Lock()
+	defer t.reconnection.mu.Unlock()
+
+	if t.reconnection.stop != nil {
+		t.reconnection.stop()
+		t.reconnection.stop = nil
+	}
+}
+
+// Retries connecting a session to Twitch in the background with a growing delay and starts monitoring once it connects.
+// Until then GetSession returns nil for its Discord sessions and commands reply that Twitch is unavailable.
+func (t *Session) reconnect(s *discordgo.Session) {
+	if !atomic.CompareAndSwapInt32(&t.reconnection.running, 0, 1) {
+		return
+	}
+	defer crash.Recover("twitch_reconnect")
+
+	delay := constants.TwitchReconnectMinDelay
+	for {
+		time.Sleep(delay)
+		if atomic.LoadInt32(&t.reconnection.closed) == 1 {
+			atomic.StoreInt32(&t.reconnection.running, 0)
+			return
+		}
+
+		if err := t.GetAuthToken(); err != nil {
+			delay *= 2
+			if delay > constants.TwitchReconnectMaxDelay {
+				delay = constants.TwitchReconnectMaxDelay
+			}
+			utils.Log.WithError(err).WithField("retry_in", delay).Warn("Could not establish connection to Twitch.")
+			continue
+		}
+
+		utils.Log.Info("Reconnected to Twitch.")
+		utils.NotifyOperator(s, "Reconnected to Twitch. Commands and announcements are available again.")
+
+		// The retry loop is done, so a disconnect of the new monitor loop can start another
+		atomic.StoreInt32(&t.reconnection.running, 0)
+		StartMonitoring(t, s)
+
+		t.reconnection.mu.Lock()
+		for _, bs := range t.reconnection.bots {
+			setActiveSession(bs, t)
+		}
+		t.reconnection.bots = nil
+		t.reconnection.mu.Unlock()
+		return
+	}
+}
@@ -0,0 +1,32 @@
+package twitch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+func TestNewWithoutClientID(t *testing.T) {
+	stored := newTestSession(t)
+	stored.twitchData["shroud"] = &twitchChannelInfo{Login: "shroud", DisplayName: "shroud", DiscordChannels: map[string][]*discordChannel{}}
+	stored.writeDataToDisk()
+
+	// The session runs without Twitch but keeps its data
+	ts, err := New("", "", "test")
+	if err == nil {
+		t.Fatal("expected an error creating the helix client")
+	}
+	if ts.helixClient() != nil {
+		t.Error("expected no helix client")
+	}
+	if ts.twitchData["shroud"] == nil {
+		t.Error("stored registrations were not loaded")
+	}
+	if err := ts.GetAuthToken(); !errors.Is(err, constants.ErrNoTwitchClient) {
+		t.Errorf("GetAuthToken: got %v, expected %v", err, constants.ErrNoTwitchClient)
+	}
+	if ts.connected() {
+		t.Error("session without a client is connected")
+	}
+}
@@ -0,0 +1,174 @@
+package twitch
+
+import (
+	"sort"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Registration describes a Twitch channel monitored by a Discord channel
+type Registration struct {
+	TwitchChannel      string    // Twitch login of the monitored channel
+	DisplayName        string    // Twitch display name of the monitored channel
+	DiscordChannelID   string    // ID of the Discord channel notified
+	DiscordChannelName string    // Name of the Discord channel notified, empty if unknown
+	WatchUntil         time.Time // Time a temporary watch ends, zero if permanent
+}
+
+// LiveChannel describes a Twitch channel registered in a guild that is live
+type LiveChannel struct {
+	TwitchChannel string        // Twitch login of the channel
+	DisplayName   string        // Twitch display name of the channel
+	Title         string        // Title of the stream
+	Game          string        // Game being played
+	Viewers       int           // Current viewer count
+	Uptime        time.Duration // Time since the stream started
+}
+
+// Returns the Twitch channels registered in a guild that are live, longest running stream first
+func (t *Session) GetLiveChannels(discordGuildID string) []LiveChannel {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	live := []LiveChannel{}
+
+	for twitchID, tcInfo := range t.twitchData {
+		if tcInfo.State != StateLive || tcInfo.StreamData == nil || len(tcInfo.DiscordChannels[discordGuildID]) == 0 {
+			continue
+		}
+		live = append(live, LiveChannel{
+			TwitchChannel: twitchID,
+			DisplayName:   tcInfo.DisplayName,
+			Title:         tcInfo.StreamData.Title,
+			Game:          tcInfo.StreamData.GameName,
+			Viewers:       tcInfo.StreamData.ViewerCount,
+			Uptime:        time.Since(tcInfo.StartTime),
+		})
+	}
+
+	sort.Slice(live, func(i, j int) bool { return live[i].Uptime > live[j].Uptime })
+
+	return live
+}
+
+// Returns every registration in a guild sorted by Discord channel name and Twitch channel
+func (t *Session) GetGuildRegistrations(discordGuildID string) []Registration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.guildRegistrations(discordGuildID)
+}
+
+func (t *Session) guildRegistrations(discordGuildID string) []Registration {
+	registrations := []Registration{}
+
+	for twitchID, tcInfo := range t.twitchData {
+		for _, dc := range tcInfo.DiscordChannels[discordGuildID] {
+			registrations = append(registrations, Registration{
+				TwitchChannel:      twitchID,
+				DisplayName:        tcInfo.DisplayName,
+				DiscordChannelID:   dc.ChannelID,
+				DiscordChannelName: dc.ChannelName,
+				WatchUntil:         dc.WatchUntil,
+			})
+		}
+	}
+
+	sort.Slice(registrations, func(i, j int) bool {
+		if registrations[i].DiscordChannelName != registrations[j].DiscordChannelName {
+			return registrations[i].DiscordChannelName < registrations[j].DiscordChannelName
+		}
+		return registrations[i].TwitchChannel < registrations[j].TwitchChannel
+	})
+
+	return registrations
+}
+
+// Updates the stored name of a Discord channel in every registration using it
+func (t *Session) UpdateChannelName(discordGuildID string, discordChannelID string, name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	updated := false
+
+	for _, tcInfo := range t.twitchData {
+		for _, dc := range tcInfo.DiscordChannels[discordGuildID] {
+			if dc.ChannelID == discordChannelID && dc.ChannelName != name {
+				dc.ChannelName = name
+				updated = true
+			}
+		}
+	}
+
+	if updated {
+		utils.Log.Debugf("Updated name of Discord channel %v to %v.\n", discordChannelID, name)
+
+		// Writes the data to the disk in case of crash
+		t.writeDataToDisk()
+	}
+}
+
+// Returns whether a Twitch channel is monitored by any guild
+func (t *Session) IsMonitored(twitchID string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.twitchData[twitchID] != nil
+}
+
+// Registers a Twitch channel to another Discord channel of the guild with the settings of an existing registration.
+// Pauses and the state of the current stream are not copied.
+func (t *Session) CopyRegistration(twitchID string, discordGuildID string, fromChannelID string, toChannelID string, toChannelName string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	idx := t.getChannelIdx(twitchID, discordGuildID, fromChannelID)
+	if idx < 0 {
+		return constants.ErrTwitchUserNotRegistered
+	}
+	if t.getChannelIdx(twitchID, discordGuildID, toChannelID) >= 0 {
+		return constants.ErrTwitchUserRegistered
+	}
+
+	from := t.twitchData[twitchID].DiscordChannels[discordGuildID][idx]
+	dc := &discordChannel{
+		ChannelID:        toChannelID,
+		ChannelName:      toChannelName,
+		Profile:          from.Profile,
+		ReminderInterval: from.ReminderInterval,
+		Color:            from.Color,
+		AnnounceDelay:    from.AnnounceDelay,
+		Pin:              from.Pin,
+		Templates:        append([]string(nil), from.Templates...),
+		TemplateMode:     from.TemplateMode,
+		Group:            from.Group,
+		LiveDebounce:     from.LiveDebounce,
+		OfflineDebounce:  from.OfflineDebounce,
+	}
+	t.twitchData[twitchID].DiscordChannels[discordGuildID] = append(t.twitchData[twitchID].DiscordChannels[discordGuildID], dc)
+
+	// Writes the data to the disk in case of crash
+	t.writeDataToDisk()
+
+	return nil
+}
+
+// Unregisters every Twitch channel from a Discord channel, or from the whole guild if discordChannelID is empty.
+// The registrations can be restored one at a time with undo. Returns the number of registrations removed.
+func (t *Session) ClearRegistrations(discordGuildID string, discordChannelID string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	removed := 0
+	for _, r := range t.guildRegistrations(discordGuildID) {
+		if discordChannelID != "" && r.DiscordChannelID != discordChannelID {
+			continue
+		}
+		if t.unregisterChannel(r.TwitchChannel, discordGuildID, r.DiscordChannelID) {
+			removed++
+		}
+	}
+	return removed
+}
@@ -0,0 +1,106 @@
+package twitch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/crash"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Sets how often a registration reminds its Discord channel that a long stream is still live. Zero disables reminders.
+func (t *Session) SetReminderInterval(twitchID string, discordGuildID string, discordChannelID string, interval time.Duration) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if interval != 0 && interval < constants.MinReminderInterval {
+		return constants.ErrReminderTooFrequent
+	}
+
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	if idx < 0 {
+		return constants.ErrTwitchUserNotRegistered
+	}
+
+	t.twitchData[twitchID].DiscordChannels[discordGuildID][idx].ReminderInterval = interval
+
+	t.writeDataToDisk()
+
+	return nil
+}
+
+// Sets the time after a stream starts before a registration announces it. A delay of 0 announces streams right away.
+func (t *Session) SetAnnounceDelay(twitchID string, discordGuildID string, discordChannelID string, delay time.Duration) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if delay < 0 || delay > constants.MaxAnnounceDelay {
+		return constants.ErrInvalidAnnounceDelay
+	}
+
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	if idx < 0 {
+		return constants.ErrTwitchUserNotRegistered
+	}
+
+	t.twitchData[twitchID].DiscordChannels[discordGuildID][idx].AnnounceDelay = delay
+
+	t.writeDataToDisk()
+
+	return nil
+}
+
+// Returns whether the announcement delay of a registration has passed
+func announceDue(dc *discordChannel, tci *twitchChannelInfo) bool {
+	return time.Since(tci.StartTime) >= dc.AnnounceDelay
+}
+
+// Returns whether a live registration is due for a reminder
+func reminderDue(dc *discordChannel, tci *twitchChannelInfo) bool {
+	if dc.ReminderInterval == 0 || dc.RemindersSent >= constants.MaxStreamReminders {
+		return false
+	}
+
+	last := tci.StartTime
+	if dc.LastReminder.After(last) {
+		last = dc.LastReminder
+	}
+	return time.Since(last) > dc.ReminderInterval
+}
+
+// Posts a reminder that a stream is still live, replacing the previous reminder so only one is shown at a time
+func sendReminder(ds *discordgo.Session, guildID string, n notified, dc *discordChannel, tci *twitchChannelInfo, names string) {
+	defer crash.Recover("send_reminder")
+
+	deleteReminder(ds, dc)
+
+	hours := int(time.Since(tci.StartTime).Hours())
+	content := fmt.Sprintf("**%v** is still live, %vh in, playing %v: <%v>",
+		utils.SanitizeText(tci.name(names)), hours, utils.SanitizeText(tci.StreamData.GameName), tci.url())
+
+	if m, err := ds.ChannelMessageSendComplex(dc.ChannelID, &discordgo.MessageSend{
+		Content:         content,
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	}); err != nil {
+		utils.Log.WithError(err).Error("Error sending Discord message.")
+		recordOutcome(guildID, dc, tci, OutcomeReminder, ResultFailed, err)
+	} else {
+		dc.ReminderMessageID = m.ID
+		recordOutcome(guildID, dc, tci, OutcomeReminder, ResultSent, nil)
+	}
+	n.update(func(orig *discordChannel, _ *twitchChannelInfo) { orig.ReminderMessageID = dc.ReminderMessageID })
+}
+
+// Removes the last reminder of a registration
+func deleteReminder(ds *discordgo.Session, dc *discordChannel) {
+	if dc.ReminderMessageID == "" {
+		return
+	}
+
+	if err := ds.ChannelMessageDelete(dc.ChannelID, dc.ReminderMessageID); err != nil {
+		utils.Log.WithError(err).Warn("Failed to delete still live reminder.")
+	}
+	dc.ReminderMessageID = ""
+}
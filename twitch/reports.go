@@ -0,0 +1,292 @@
+package twitch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// How often stream reports are posted
+const (
+	ReportWeekly  = "weekly"
+	ReportMonthly = "monthly"
+)
+
+// Where and how often a guild's stream reports are posted
+type reportSettings struct {
+	ChannelID string    // Discord channel reports are posted in
+	Period    string    // Whether reports cover a week or a month
+	LastSent  time.Time // Time the last report was posted, or the reports were set up
+}
+
+// Stream activity of a Twitch channel during a report's period
+type channelActivity struct {
+	login    string
+	name     string
+	live     time.Duration
+	sessions int
+	games    map[string]time.Duration
+}
+
+// Posts a summary of the streams of the guild's Twitch channels in a Discord channel every week or month
+func (t *Session) SetReports(discordGuildID string, discordChannelID string, period string) error {
+	if period != ReportWeekly && period != ReportMonthly {
+		return constants.ErrInvalidReportPeriod
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	gs := t.getGuildSettings(discordGuildID)
+	gs.Report = &reportSettings{ChannelID: discordChannelID, Period: period, LastSent: time.Now().UTC()}
+
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Stops posting stream reports in a guild
+func (t *Session) DisableReports(discordGuildID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.Report == nil {
+		return constants.ErrReportsNotConfigured
+	}
+
+	gs.Report = nil
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Returns the Discord channel and period of a guild's stream reports, empty if they are disabled
+func (t *Session) GetReports(discordGuildID string) (string, string) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	gs := t.guilds[discordGuildID]
+	if gs == nil || gs.Report == nil {
+		return "", ""
+	}
+	return gs.Report.ChannelID, gs.Report.Period
+}
+
+// Posts the report of the period so far in a Discord channel
+func (t *Session) PostReport(ds *discordgo.Session, discordGuildID string, discordChannelID string, period string) error {
+	if period != ReportWeekly && period != ReportMonthly {
+		return constants.ErrInvalidReportPeriod
+	}
+
+	t.mu.RLock()
+	now := time.Now().In(t.timezone(discordGuildID))
+	embed, err := t.createReportEmbed(discordGuildID, period, periodBoundary(period, now), now)
+	t.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	_, err = ds.ChannelMessageSendEmbed(discordChannelID, embed)
+	return err
+}
+
+// Posts the reports of every guild whose period ended since its last report
+func sendReports(ts *Session, ds *discordgo.Session) {
+	type report struct {
+		guildID   string
+		channelID string
+		embed     *discordgo.MessageEmbed
+		err       error
+	}
+	var reports []report
+	now := time.Now().UTC()
+
+	ts.mu.Lock()
+	for guildID, gs := range ts.guilds {
+		if gs.Report == nil {
+			continue
+		}
+		if connected, available := getGuildStatus(guildID); !available || !connected {
+			continue
+		}
+
+		end := periodBoundary(gs.Report.Period, now.In(ts.timezone(guildID)))
+		if !gs.Report.LastSent.Before(end) {
+			continue
+		}
+		start := periodBoundary(gs.Report.Period, end.Add(-time.Second))
+
+		embed, err := ts.createReportEmbed(guildID, gs.Report.Period, start, end)
+		reports = append(reports, report{guildID, gs.Report.ChannelID, embed, err})
+
+		// A failed report is not retried so a deleted channel doesn't cause an error every hour
+		gs.Report.LastSent = now
+		ts.writeGuildsToDisk()
+	}
+	ts.mu.Unlock()
+
+	for _, r := range reports {
+		err := r.err
+		if err == nil {
+			_, err = discordFor(r.guildID, ds).ChannelMessageSendEmbed(r.channelID, r.embed)
+		}
+		if err != nil {
+			utils.Log.WithFields(logrus.Fields{
+				"channel_id": r.channelID,
+				"server_id":  r.guildID,
+				"error":      err}).Error("Failed to post stream report.")
+		}
+	}
+}
+
+// Returns the start of the week, beginning on Monday, or of the month that now is in, in the timezone of now
+func periodBoundary(period string, now time.Time) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	if period == ReportMonthly {
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// Creates the report of the streams of a guild's Twitch channels between start and end from the event log
+func (t *Session) createReportEmbed(discordGuildID string, period string, start time.Time, end time.Time) (*discordgo.MessageEmbed, error) {
+	events, err := ReadEventLog(t.name)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := make(map[string]*channelActivity)
+	for login, tci := range t.twitchData {
+		if len(tci.DiscordChannels[discordGuildID]) > 0 {
+			activity[login] = &channelActivity{login: login, name: tci.DisplayName, games: make(map[string]time.Duration)}
+		}
+	}
+
+	summarizeEvents(events, activity, start, end)
+
+	channels := []*channelActivity{}
+	var total time.Duration
+	sessions := 0
+	for _, a := range activity {
+		if a.sessions > 0 {
+			channels = append(channels, a)
+			total += a.live
+			sessions += a.sessions
+		}
+	}
+	sort.Slice(channels, func(i, j int) bool { return channels[i].live > channels[j].live })
+
+	title := "Weekly stream report"
+	if period == ReportMonthly {
+		title = "Monthly stream report"
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: title,
+		Description: fmt.Sprintf("%v to %v\n%v streams, %v live in total", start.Format("Jan 2"), end.Add(-time.Second).Format("Jan 2, 2006"),
+			sessions, formatHours(total)),
+		Color: constants.DiscordLiveColor,
+	}
+	if len(channels) == 0 {
+		embed.Description += "\nNo Twitch channel of this Discord server streamed."
+	}
+
+	for i, a := range channels {
+		if i == constants.DiscordMaxEmbedFields {
+			break
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   utils.SanitizeText(a.name),
+			Value:  fmt.Sprintf("%v over %v streams\n%v", formatHours(a.live), a.sessions, topGames(a.games)),
+			Inline: true,
+		})
+	}
+
+	return embed, nil
+}
+
+// Adds up the time live, streams and time per game of the channels in activity between start and end
+func summarizeEvents(events []StreamEvent, activity map[string]*channelActivity, start time.Time, end time.Time) {
+	type liveStream struct {
+		since time.Time // Start of the current game, or of the stream
+		game  string
+	}
+	live := make(map[string]*liveStream)
+
+	// Time of a live stream spent in [start, end) from since to until
+	add := func(a *channelActivity, s *liveStream, until time.Time) {
+		from := s.since
+		if from.Before(start) {
+			from = start
+		}
+		if until.After(end) {
+			until = end
+		}
+		if until.After(from) {
+			a.live += until.Sub(from)
+			if s.game != "" {
+				a.games[s.game] += until.Sub(from)
+			}
+		}
+	}
+
+	for _, e := range events {
+		a := activity[e.Login]
+		if a == nil || !e.Time.Before(end) {
+			continue
+		}
+
+		switch e.Type {
+		case EventOnline:
+			live[e.Login] = &liveStream{since: e.Time, game: e.Game}
+			if !e.Time.Before(start) {
+				a.sessions++
+			}
+		case EventGame:
+			if s := live[e.Login]; s != nil {
+				add(a, s, e.Time)
+				s.since, s.game = e.Time, e.Game
+			}
+		case EventOffline:
+			if s := live[e.Login]; s != nil {
+				add(a, s, e.Time)
+				delete(live, e.Login)
+			}
+		}
+	}
+
+	// Streams still live at the end of the period
+	for login, s := range live {
+		add(activity[login], s, end)
+	}
+}
+
+// Returns the three games played the longest
+func topGames(games map[string]time.Duration) string {
+	names := make([]string, 0, len(games))
+	for name := range games {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return games[names[i]] > games[names[j]] })
+
+	top := []string{}
+	for i, name := range names {
+		if i == constants.ReportTopGames {
+			break
+		}
+		top = append(top, utils.SanitizeText(name)+" ("+formatHours(games[name])+")")
+	}
+	return strings.Join(top, ", ")
+}
+
+// Formats a duration as hours and minutes like 12h 5m
+func formatHours(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h := d / time.Hour
+	return fmt.Sprintf("%dh %dm", h, (d-h*time.Hour)/time.Minute)
+}
@@ -0,0 +1,108 @@
+package twitch
+
+import (
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/config"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Value redacted fields and headers are replaced with
+const redacted = "[redacted]"
+
+// Headers and fields that are always redacted from logged Twitch responses
+var alwaysRedacted = []string{"authorization", "client-id", "cookie", "set-cookie", "access_token", "refresh_token", "ratelimit-limit", "ratelimit-remaining", "ratelimit-reset"}
+
+// Decides whether the Twitch responses of the next poll are logged. Only 1 in twitch_response_log.sample polls is logged.
+func (t *Session) sampleResponseLog() {
+	settings := config.Settings.TwitchResponseLog
+	sampled := false
+	if settings.Enabled {
+		sample := int64(settings.Sample)
+		if sample < 1 {
+			sample = 1
+		}
+		sampled = atomic.AddInt64(&t.responseLog.polls, 1)%sample == 0
+	}
+
+	var flag int32
+	if sampled {
+		flag = 1
+	}
+	atomic.StoreInt32(&t.responseLog.sampled, flag)
+}
+
+// Logs a Twitch response of a sampled poll with sensitive headers and fields redacted and its size limited
+func (t *Session) logResponse(endpoint string, resp *helix.ResponseCommon, data interface{}) {
+	if atomic.LoadInt32(&t.responseLog.sampled) == 0 {
+		return
+	}
+	settings := config.Settings.TwitchResponseLog
+
+	keys := make(map[string]bool)
+	for _, key := range append(alwaysRedacted, settings.Redact...) {
+		keys[strings.ToLower(key)] = true
+	}
+
+	headers := make(map[string]string)
+	for name := range resp.Header {
+		if keys[strings.ToLower(name)] {
+			headers[name] = redacted
+		} else {
+			headers[name] = resp.Header.Get(name)
+		}
+	}
+
+	// The data is decoded generically so fields can be redacted whatever its type
+	var generic interface{}
+	raw, err := json.Marshal(data)
+	if err == nil {
+		err = json.Unmarshal(raw, &generic)
+	}
+	if err == nil {
+		raw, err = json.Marshal(redactFields(generic, keys))
+	}
+	if err != nil {
+		utils.Log.WithError(err).Error("Failed to encode Twitch response for logging.")
+		return
+	}
+
+	maxBytes := settings.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = constants.ResponseLogMaxBytes
+	}
+	body := string(raw)
+	if len(body) > maxBytes {
+		body = body[:maxBytes] + "… (truncated)"
+	}
+
+	utils.Log.WithFields(logrus.Fields{
+		"endpoint": endpoint,
+		"status":   resp.StatusCode,
+		"headers":  headers,
+		"body":     body}).Info("Twitch response.")
+}
+
+// Replaces the values of fields whose lowercase name is in keys, at any depth
+func redactFields(v interface{}, keys map[string]bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if keys[strings.ToLower(key)] {
+				v[key] = redacted
+			} else {
+				v[key] = redactFields(value, keys)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactFields(value, keys)
+		}
+	}
+	return v
+}
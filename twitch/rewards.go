@@ -0,0 +1,132 @@
+package twitch
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/features"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Settings for posting a broadcaster's channel point redemptions to Discord
+type rewardSettings struct {
+	ChannelID string          // Discord channel redemptions are posted to
+	Rewards   map[string]bool // Map of lowercase reward title to whether its redemptions are posted
+}
+
+// Posts redemptions of a linked broadcaster's enabled rewards to a Discord channel
+func (t *Session) SetRewardChannel(login string, discordGuildID string, discordChannelID string) error {
+	if !features.Enabled(features.EventSub, discordGuildID) {
+		return constants.ErrFeatureDisabled
+	}
+
+	_, bt, err := t.broadcasterClient(login, discordGuildID)
+	if err != nil {
+		return err
+	}
+
+	if err := t.ensureEventSub(helix.EventSubTypeChannelPointsCustomRewardRedemptionAdd, bt.UserID); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.Rewards == nil {
+		gs.Rewards = make(map[string]*rewardSettings)
+	}
+	if gs.Rewards[login] == nil {
+		gs.Rewards[login] = &rewardSettings{Rewards: make(map[string]bool)}
+	}
+	gs.Rewards[login].ChannelID = discordChannelID
+
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Stops posting a broadcaster's redemptions in a guild
+func (t *Session) DisableRewards(login string, discordGuildID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.Rewards[login] == nil {
+		return constants.ErrRewardsNotConfigured
+	}
+
+	delete(gs.Rewards, login)
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Toggles whether redemptions of a reward are posted
+func (t *Session) SetRewardEnabled(login string, discordGuildID string, reward string, enabled bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.Rewards[login] == nil {
+		return constants.ErrRewardsNotConfigured
+	}
+
+	gs.Rewards[login].Rewards[strings.ToLower(reward)] = enabled
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Returns the Discord channel a broadcaster's redemptions are posted to and the reward toggles
+func (t *Session) GetRewardSettings(login string, discordGuildID string) (string, map[string]bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.Rewards[login] == nil {
+		return "", nil, constants.ErrRewardsNotConfigured
+	}
+
+	rewards := make(map[string]bool)
+	for reward, enabled := range gs.Rewards[login].Rewards {
+		rewards[reward] = enabled
+	}
+
+	return gs.Rewards[login].ChannelID, rewards, nil
+}
+
+func (t *Session) handleRedemption(ds *discordgo.Session, event json.RawMessage) {
+	var redemption helix.EventSubChannelPointsCustomRewardRedemptionEvent
+	if err := json.Unmarshal(event, &redemption); err != nil {
+		utils.Log.WithError(err).Error("Failed to parse channel points redemption.")
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       utils.SanitizeText(redemption.UserName + " redeemed " + redemption.Reward.Title),
+		Description: utils.SanitizeText(redemption.UserInput),
+		Color:       constants.DiscordRewardColor,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprint(redemption.Reward.Cost) + " channel points on " + redemption.BroadcasterUserName + "'s channel",
+		},
+	}
+
+	// Maps the Discord channels the redemption is posted to to their guild
+	channels := make(map[string]string)
+	t.mu.RLock()
+	for guildID, gs := range t.guilds {
+		rs := gs.Rewards[redemption.BroadcasterUserLogin]
+		if rs == nil || !rs.Rewards[strings.ToLower(redemption.Reward.Title)] || !features.Enabled(features.EventSub, guildID) {
+			continue
+		}
+		channels[rs.ChannelID] = guildID
+	}
+	t.mu.RUnlock()
+
+	for channelID, guildID := range channels {
+		if _, err := discordFor(guildID, ds).ChannelMessageSendEmbed(channelID, embed); err != nil {
+			utils.Log.WithError(err).Error("Error sending Discord message.")
+		}
+	}
+}
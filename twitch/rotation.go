@@ -0,0 +1,169 @@
+package twitch
+
+import (
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+// How often the featured streamer of a rotation changes
+const (
+	RotationDaily  = "daily"
+	RotationWeekly = "weekly"
+)
+
+// Rotation of a guild's featured streamer. Only the featured streamer's announcements mention
+// their profile's role, once per period. Everyone else is announced without a mention.
+type featuredRotation struct {
+	Channels     []string  // Twitch channels featured in turn
+	Schedule     string    // Whether the featured streamer changes daily or weekly
+	Start        time.Time // Start of the first period, when the rotation was created
+	PingedPeriod int       // Last period the featured streamer was mentioned in
+	PingedStream string    // ID of the stream the featured streamer was last mentioned for
+}
+
+// Adds a Twitch channel to the end of a guild's featured rotation, creating a daily rotation if there is none
+func (t *Session) AddFeatured(discordGuildID string, twitchID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.twitchData[twitchID] == nil || len(t.twitchData[twitchID].DiscordChannels[discordGuildID]) == 0 {
+		return constants.ErrTwitchUserNotRegistered
+	}
+
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.Rotation == nil {
+		gs.Rotation = &featuredRotation{Schedule: RotationDaily, Start: periodStart(time.Now().UTC()), PingedPeriod: -1}
+	}
+	for _, featured := range gs.Rotation.Channels {
+		if featured == twitchID {
+			return constants.ErrAlreadyFeatured
+		}
+	}
+	gs.Rotation.Channels = append(gs.Rotation.Channels, twitchID)
+
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Removes a Twitch channel from a guild's featured rotation. The rotation ends when it is empty.
+func (t *Session) RemoveFeatured(discordGuildID string, twitchID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.Rotation == nil {
+		return constants.ErrNotFeatured
+	}
+
+	for i, featured := range gs.Rotation.Channels {
+		if featured == twitchID {
+			gs.Rotation.Channels = append(gs.Rotation.Channels[:i], gs.Rotation.Channels[i+1:]...)
+			if len(gs.Rotation.Channels) == 0 {
+				gs.Rotation = nil
+			}
+			t.writeGuildsToDisk()
+			return nil
+		}
+	}
+
+	return constants.ErrNotFeatured
+}
+
+// Sets whether the featured streamer of a guild changes daily or weekly
+func (t *Session) SetRotationSchedule(discordGuildID string, schedule string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if schedule != RotationDaily && schedule != RotationWeekly {
+		return constants.ErrInvalidRotationSchedule
+	}
+
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.Rotation == nil {
+		return constants.ErrNoRotation
+	}
+	gs.Rotation.Schedule = schedule
+
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Ends a guild's featured rotation, every streamer is announced with their mention again
+func (t *Session) ClearRotation(discordGuildID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.Rotation == nil {
+		return constants.ErrNoRotation
+	}
+
+	gs.Rotation = nil
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Returns the channels of a guild's featured rotation in order, its schedule and the current featured streamer
+func (t *Session) GetRotation(discordGuildID string) ([]string, string, string) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	gs := t.guilds[discordGuildID]
+	if gs == nil || gs.Rotation == nil {
+		return nil, "", ""
+	}
+
+	r := gs.Rotation
+	return append([]string{}, r.Channels...), r.Schedule, r.Channels[r.period(time.Now().UTC())%len(r.Channels)]
+}
+
+// Returns whether an announcement in a guild may mention its profile's role. Without a rotation every
+// announcement may. Otherwise only the featured streamer's first stream of the period does.
+func (t *Session) rotationPing(discordGuildID string, twitchID string, tci *twitchChannelInfo) bool {
+	gs := t.guilds[discordGuildID]
+	if gs == nil || gs.Rotation == nil {
+		return true
+	}
+
+	r := gs.Rotation
+	period := r.period(time.Now().UTC())
+	if r.Channels[period%len(r.Channels)] != twitchID {
+		return false
+	}
+	// Every registration of the same stream gets the mention
+	if r.PingedPeriod == period {
+		return r.PingedStream == tci.StreamData.ID
+	}
+
+	r.PingedPeriod = period
+	r.PingedStream = tci.StreamData.ID
+	t.writeGuildsToDisk()
+	return true
+}
+
+// Returns the number of whole periods since the rotation started
+func (r *featuredRotation) period(now time.Time) int {
+	length := constants.RotationDay
+	if r.Schedule == RotationWeekly {
+		length *= 7
+	}
+
+	return int(now.Sub(r.Start) / length)
+}
+
+// Returns the start of the UTC day of a time
+func periodStart(now time.Time) time.Time {
+	return now.Truncate(constants.RotationDay)
+}
+
+// Returns a copy of a profile without its mention
+func (p *Profile) silenced() *Profile {
+	if p == nil {
+		return nil
+	}
+
+	silent := *p
+	silent.Mention = ""
+	return &silent
+}
@@ -0,0 +1,169 @@
+package twitch
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Scheduled stream of a broadcaster
+type scheduleSegment struct {
+	ID            string     `json:"id"`
+	StartTime     time.Time  `json:"start_time"`
+	EndTime       time.Time  `json:"end_time"`
+	Title         string     `json:"title"`
+	CanceledUntil *time.Time `json:"canceled_until"`
+	Category      *struct {
+		Name string `json:"name"`
+	} `json:"category"`
+}
+
+type scheduleResponse struct {
+	Data struct {
+		Segments        []scheduleSegment `json:"segments"`
+		BroadcasterName string            `json:"broadcaster_name"`
+	} `json:"data"`
+}
+
+type cachedSchedule struct {
+	segments  []scheduleSegment
+	fetchedAt time.Time
+}
+
+type scheduleCache struct {
+	mu        sync.Mutex
+	schedules map[string]*cachedSchedule // Map of twitch channel to its last fetched schedule
+}
+
+// Returns the upcoming scheduled streams of a twitch channel, fetched at most once per cache period
+func (t *Session) getSchedule(twitchID string, broadcasterID string) ([]scheduleSegment, error) {
+	t.schedules.mu.Lock()
+	cached := t.schedules.schedules[twitchID]
+	t.schedules.mu.Unlock()
+
+	if cached != nil && time.Since(cached.fetchedAt) < constants.ScheduleCacheTime {
+		return cached.segments, nil
+	}
+
+	if !validateAndRefreshAuthToken(t) {
+		return nil, constants.ErrInvalidToken
+	}
+
+	var resp scheduleResponse
+	status, err := t.helixGet(constants.TwitchScheduleURL+"?"+url.Values{"broadcaster_id": {broadcasterID}}.Encode(),
+		t.helixClient().GetAppAccessToken(), &resp)
+	if err != nil {
+		return nil, err
+	} else if status != http.StatusOK && status != http.StatusNotFound {
+		return nil, constants.ErrTwitchQueryFailed
+	}
+
+	// Channels without a schedule respond with not found
+	t.schedules.mu.Lock()
+	t.schedules.schedules[twitchID] = &cachedSchedule{segments: resp.Data.Segments, fetchedAt: time.Now()}
+	t.schedules.mu.Unlock()
+
+	return resp.Data.Segments, nil
+}
+
+// Returns the handler serving an ICS calendar of the scheduled streams of every channel monitored by the guild query parameter
+func (t *Session) CalendarHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		guildID := r.URL.Query().Get("guild")
+		if guildID == "" {
+			http.Error(w, "The guild query parameter is required.", http.StatusBadRequest)
+			return
+		}
+
+		logins := []string{}
+		t.mu.RLock()
+		for twitchID, tcInfo := range t.twitchData {
+			if len(tcInfo.DiscordChannels[guildID]) > 0 {
+				logins = append(logins, twitchID)
+			}
+		}
+		timezone := t.timezone(guildID)
+		t.mu.RUnlock()
+
+		var calendar strings.Builder
+		calendar.WriteString("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//DiscordTwitchBot//Twitch schedules//EN\r\n")
+		calendar.WriteString("X-WR-CALNAME:Twitch streams\r\n")
+		fmt.Fprintf(&calendar, "X-WR-TIMEZONE:%v\r\n", timezone)
+
+		for i := 0; i < len(logins); i += constants.TwitchQueryBatchSize {
+			end := i + constants.TwitchQueryBatchSize
+			if end > len(logins) {
+				end = len(logins)
+			}
+
+			if !validateAndRefreshAuthToken(t) {
+				http.Error(w, "Twitch is unavailable.", http.StatusBadGateway)
+				return
+			}
+			users, err := t.helixClient().GetUsers(&helix.UsersParams{Logins: logins[i:end]})
+			if err != nil {
+				http.Error(w, "Twitch is unavailable.", http.StatusBadGateway)
+				return
+			}
+
+			for _, user := range users.Data.Users {
+				segments, err := t.getSchedule(user.Login, user.ID)
+				if err != nil {
+					utils.Log.WithError(err).Errorf("Failed to get schedule of %v.\n", user.Login)
+					continue
+				}
+
+				for _, segment := range segments {
+					writeCalendarEvent(&calendar, user.Login, user.DisplayName, segment)
+				}
+			}
+		}
+
+		calendar.WriteString("END:VCALENDAR\r\n")
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Write([]byte(calendar.String()))
+	})
+}
+
+// Writes a scheduled stream as an ICS event
+func writeCalendarEvent(calendar *strings.Builder, login string, displayName string, segment scheduleSegment) {
+	if segment.CanceledUntil != nil {
+		return
+	}
+
+	end := segment.EndTime
+	if end.IsZero() {
+		end = segment.StartTime.Add(time.Hour)
+	}
+
+	summary := displayName
+	if segment.Title != "" {
+		summary += ": " + segment.Title
+	}
+	description := ""
+	if segment.Category != nil {
+		description = "Playing " + segment.Category.Name
+	}
+
+	fmt.Fprintf(calendar, "BEGIN:VEVENT\r\nUID:%v@twitch.tv\r\nDTSTAMP:%v\r\nDTSTART:%v\r\nDTEND:%v\r\n",
+		segment.ID, icsTime(time.Now()), icsTime(segment.StartTime), icsTime(end))
+	fmt.Fprintf(calendar, "SUMMARY:%v\r\nDESCRIPTION:%v\r\nURL:https://www.twitch.tv/%v\r\nEND:VEVENT\r\n",
+		icsEscape(summary), icsEscape(description), login)
+}
+
+func icsTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// Escapes text for an ICS property value
+func icsEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(text)
+}
@@ -0,0 +1,133 @@
+package twitch
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/crash"
+)
+
+// Coordinates the poll cycles of every monitoring session, so sessions with their own credentials or
+// Discord bots do not query Twitch and send to Discord at the same time
+type pollScheduler struct {
+	mu       sync.Mutex
+	sessions []*Session // Monitoring sessions in the order they were given their slot
+}
+
+var pollSchedule = &pollScheduler{}
+
+// Gives a session a slot in the poll interval
+func (p *pollScheduler) join(t *Session) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sessions = append(p.sessions, t)
+}
+
+// Frees the slot of a session, spreading the remaining sessions over the interval
+func (p *pollScheduler) leave(t *Session) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, s := range p.sessions {
+		if s == t {
+			p.sessions = append(p.sessions[:i], p.sessions[i+1:]...)
+			return
+		}
+	}
+}
+
+// Returns the time until the next poll of a session. Each session polls once per interval, offset by its
+// share of the interval and a random jitter within that share.
+func (p *pollScheduler) untilNext(t *Session, interval time.Duration, now time.Time) time.Duration {
+	p.mu.Lock()
+	idx, n := -1, len(p.sessions)
+	for i, s := range p.sessions {
+		if s == t {
+			idx = i
+		}
+	}
+	p.mu.Unlock()
+
+	if idx < 0 {
+		return interval
+	}
+
+	slot := interval / time.Duration(n)
+	next := now.Truncate(interval).Add(slot * time.Duration(idx))
+	for !next.After(now) {
+		next = next.Add(interval)
+	}
+	return next.Sub(now) + jitter(slot)
+}
+
+// Returns a random delay of up to PollJitter of d
+func jitter(d time.Duration) time.Duration {
+	max := int64(float64(d) * constants.PollJitter)
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(max))
+}
+
+// Runs a periodic job of the session every interval until ctx, the context of the connection it was started for,
+// is done. Jitter keeps the jobs of several sessions from running in lockstep.
+func (t *Session) every(ctx context.Context, interval time.Duration, job func()) {
+	for ctx.Err() == nil {
+		runJob(job)
+		select {
+		case <-time.After(interval + jitter(interval)):
+		case <-ctx.Done():
+		}
+	}
+}
+
+// Runs the poll job of the session in its slot of the shared poll schedule, and also as soon as wake receives.
+// Stops once the session disconnects or ctx is done.
+func (t *Session) everyPollOrWake(ctx context.Context, interval time.Duration, wake <-chan struct{}, job func()) {
+	pollSchedule.join(t)
+	defer pollSchedule.leave(t)
+
+	for ctx.Err() == nil && t.connected() {
+		runJob(job)
+		select {
+		case <-time.After(pollSchedule.untilNext(t, interval, time.Now())):
+		case <-wake:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// Returns job wrapped to run with the session's data locked
+func (t *Session) locked(job func()) func() {
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		job()
+	}
+}
+
+// Runs one iteration of a periodic job. A panic is recovered so the job runs again next interval.
+func runJob(job func()) {
+	defer crash.Recover("scheduled_job")
+	job()
+}
+
+// Polls Twitch for every monitored channel without waiting for the next interval.
+// Requests made while a poll is already pending are merged into it.
+func (t *Session) PollNow() {
+	select {
+	case t.pollNow <- struct{}{}:
+	default:
+	}
+}
+
+// Returns whether the monitor loop finished a poll cycle recently. A session that never
+// started monitoring is not polling.
+func (t *Session) Polling() bool {
+	lastPoll := atomic.LoadInt64(&t.lastPoll)
+	return lastPoll != 0 && time.Since(time.Unix(0, lastPoll)) < constants.PollStallTimeout
+}
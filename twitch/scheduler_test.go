@@ -0,0 +1,67 @@
+package twitch
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Waits for every to return after its context is cancelled
+func runEvery(t *Session, ctx context.Context, runs *int32) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		t.every(ctx, time.Millisecond, func() { atomic.AddInt32(runs, 1) })
+	}()
+	return done
+}
+
+func TestEveryStopsWithItsConnection(t *testing.T) {
+	ts := newTestSession(t)
+
+	var first, second int32
+	ctx, _ := ts.startConnection()
+	firstDone := runEvery(ts, ctx, &first)
+
+	// A new connection stops the jobs of the previous one
+	ctx, cancel := ts.startConnection()
+	secondDone := runEvery(ts, ctx, &second)
+	select {
+	case <-firstDone:
+	case <-time.After(time.Second):
+		t.Fatal("job of the previous connection still runs")
+	}
+
+	time.Sleep(time.Millisecond * 20)
+	if atomic.LoadInt32(&second) == 0 {
+		t.Error("job of the new connection did not run")
+	}
+
+	cancel()
+	select {
+	case <-secondDone:
+	case <-time.After(time.Second):
+		t.Fatal("job still runs after its connection was lost")
+	}
+}
+
+func TestCloseStopsJobs(t *testing.T) {
+	ts := newTestSession(t)
+
+	var runs int32
+	ctx, _ := ts.startConnection()
+	done := runEvery(ts, ctx, &runs)
+
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job still runs after the session was closed")
+	}
+	if ts.connected() {
+		t.Error("closed session is connected")
+	}
+}
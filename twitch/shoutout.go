@@ -0,0 +1,76 @@
+package twitch
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+// Public profile of a Twitch channel
+type ChannelProfile struct {
+	Login           string // Twitch login
+	DisplayName     string // Twitch display name
+	Description     string // Channel description
+	ProfileImageURL string // Avatar URL
+	GameName        string // Game last streamed
+	Title           string // Last stream title
+}
+
+// Returns the public profile of any Twitch channel
+func (t *Session) GetChannelProfile(login string) (*ChannelProfile, error) {
+	if !validateAndRefreshAuthToken(t) {
+		return nil, constants.ErrInvalidToken
+	}
+
+	users, err := t.helixClient().GetUsers(&helix.UsersParams{Logins: []string{login}})
+	if err != nil {
+		return nil, err
+	} else if users.StatusCode != http.StatusOK {
+		return nil, constants.ErrTwitchQueryFailed
+	} else if len(users.Data.Users) == 0 {
+		return nil, constants.ErrTwitchUserDoesNotExist
+	}
+	user := users.Data.Users[0]
+
+	profile := &ChannelProfile{
+		Login:           user.Login,
+		DisplayName:     user.DisplayName,
+		Description:     user.Description,
+		ProfileImageURL: user.ProfileImageURL,
+	}
+
+	channels, err := t.helixClient().GetChannelInformation(&helix.GetChannelInformationParams{BroadcasterID: user.ID})
+	if err == nil && len(channels.Data.Channels) > 0 {
+		profile.GameName = channels.Data.Channels[0].GameName
+		profile.Title = channels.Data.Channels[0].Title
+	}
+
+	return profile, nil
+}
+
+// Sends a Twitch shoutout for a channel from a linked broadcaster's live stream
+func (t *Session) SendShoutout(fromLogin string, discordGuildID string, toLogin string) error {
+	_, bt, err := t.broadcasterClient(fromLogin, discordGuildID)
+	if err != nil {
+		return err
+	}
+
+	if !validateAndRefreshAuthToken(t) {
+		return constants.ErrInvalidToken
+	}
+	users, err := t.helixClient().GetUsers(&helix.UsersParams{Logins: []string{toLogin}})
+	if err != nil {
+		return err
+	} else if len(users.Data.Users) == 0 {
+		return constants.ErrTwitchUserDoesNotExist
+	}
+
+	query := url.Values{
+		"from_broadcaster_id": {bt.UserID},
+		"to_broadcaster_id":   {users.Data.Users[0].ID},
+		"moderator_id":        {bt.UserID},
+	}
+	return t.helixRequest(http.MethodPost, constants.TwitchShoutoutsURL+"?"+query.Encode(), bt.AccessToken, nil)
+}
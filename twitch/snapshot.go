@@ -0,0 +1,192 @@
+package twitch
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Snapshot of the session's internal state for debugging. It contains no tokens or secrets.
+type Snapshot struct {
+	Session      string                   `json:"session"`
+	Connected    bool                     `json:"connected"`
+	QueryWorkers int                      `json:"query_workers"`
+	RateLimit    rateLimitSnapshot        `json:"rate_limit"`
+	Queues       queueSnapshot            `json:"queues"`
+	Guilds       map[string]guildSnapshot `json:"guilds"`
+	Channels     []channelSnapshot        `json:"channels"`
+	Broadcasters []string                 `json:"broadcasters"`
+	Time         time.Time                `json:"time"`
+}
+
+type rateLimitSnapshot struct {
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+type queueSnapshot struct {
+	EventSubSubscriptions int `json:"eventsub_subscriptions"`
+	PendingBans           int `json:"pending_bans"`
+	PollMirrors           int `json:"poll_mirrors"`
+	FeedEvents            int `json:"feed_events"`
+	CachedSchedules       int `json:"cached_schedules"`
+}
+
+type guildSnapshot struct {
+	Status     string    `json:"status"`
+	MutedUntil time.Time `json:"muted_until,omitempty"`
+	MutePolicy string    `json:"mute_policy,omitempty"`
+	Profiles   int       `json:"profiles"`
+	Squads     int       `json:"squads"`
+}
+
+type channelSnapshot struct {
+	Login         string                 `json:"login"`
+	DisplayName   string                 `json:"display_name"`
+	Live          bool                   `json:"live"`
+	State         string                 `json:"state"`
+	Title         string                 `json:"title,omitempty"`
+	Game          string                 `json:"game,omitempty"`
+	StartTime     time.Time              `json:"start_time"`
+	EndTime       time.Time              `json:"end_time"`
+	Registrations []registrationSnapshot `json:"registrations"`
+}
+
+type registrationSnapshot struct {
+	GuildID              string    `json:"guild_id"`
+	ChannelID            string    `json:"channel_id"`
+	Profile              string    `json:"profile,omitempty"`
+	LiveNotificationSent bool      `json:"live_notification_sent"`
+	LiveMessageID        string    `json:"live_message_id,omitempty"`
+	SquadMessageID       string    `json:"squad_message_id,omitempty"`
+	UpdateTime           time.Time `json:"update_time"`
+	RemindersSent        int       `json:"reminders_sent"`
+	LastReminder         time.Time `json:"last_reminder"`
+}
+
+// Returns a snapshot of the session's internal state
+func (t *Session) Snapshot() Snapshot {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	s := Snapshot{
+		Session:      t.name,
+		Connected:    t.connected(),
+		QueryWorkers: t.queryWorkers,
+		Guilds:       make(map[string]guildSnapshot),
+		Channels:     []channelSnapshot{},
+		Broadcasters: []string{},
+		Time:         time.Now().UTC(),
+	}
+
+	t.limiter.mu.Lock()
+	s.RateLimit = rateLimitSnapshot{Remaining: t.limiter.remaining, Reset: t.limiter.reset}
+	t.limiter.mu.Unlock()
+
+	t.eventSub.mu.Lock()
+	s.Queues.EventSubSubscriptions = len(t.eventSub.subscriptions)
+	t.eventSub.mu.Unlock()
+	t.bans.mu.Lock()
+	s.Queues.PendingBans = len(t.bans.pending)
+	t.bans.mu.Unlock()
+	t.polls.mu.Lock()
+	s.Queues.PollMirrors = len(t.polls.mirrors)
+	t.polls.mu.Unlock()
+	t.feed.mu.Lock()
+	s.Queues.FeedEvents = len(t.feed.events)
+	t.feed.mu.Unlock()
+	t.schedules.mu.Lock()
+	s.Queues.CachedSchedules = len(t.schedules.schedules)
+	t.schedules.mu.Unlock()
+
+	for guildID, connected := range guildStatuses() {
+		status := "removed"
+		if connected {
+			status = "connected"
+		}
+		s.Guilds[guildID] = guildSnapshot{Status: status}
+	}
+	for guildID, gs := range t.guilds {
+		g, ok := s.Guilds[guildID]
+		if !ok {
+			g.Status = "unavailable"
+		}
+		if gs.MutedUntil.After(time.Now()) {
+			g.MutedUntil = gs.MutedUntil
+			g.MutePolicy = gs.MutePolicy
+		}
+		g.Profiles = len(gs.Profiles)
+		g.Squads = len(gs.Squads)
+		s.Guilds[guildID] = g
+	}
+
+	for login, tci := range t.twitchData {
+		c := channelSnapshot{
+			Login:         login,
+			DisplayName:   tci.DisplayName,
+			Live:          tci.StreamData != nil,
+			State:         tci.State.String(),
+			StartTime:     tci.StartTime,
+			EndTime:       tci.EndTime,
+			Registrations: []registrationSnapshot{},
+		}
+		if tci.StreamData != nil {
+			c.Title = tci.StreamData.Title
+			c.Game = tci.StreamData.GameName
+		}
+
+		for guildID, dcs := range tci.DiscordChannels {
+			for _, dc := range dcs {
+				c.Registrations = append(c.Registrations, registrationSnapshot{
+					GuildID:              guildID,
+					ChannelID:            dc.ChannelID,
+					Profile:              dc.Profile,
+					LiveNotificationSent: dc.LiveNotificationSent,
+					LiveMessageID:        dc.LiveMessageID,
+					SquadMessageID:       dc.SquadMessageID,
+					UpdateTime:           dc.UpdateTime,
+					RemindersSent:        dc.RemindersSent,
+					LastReminder:         dc.LastReminder,
+				})
+			}
+		}
+
+		s.Channels = append(s.Channels, c)
+	}
+	sort.Slice(s.Channels, func(i, j int) bool { return s.Channels[i].Login < s.Channels[j].Login })
+
+	t.broadcasterMu.Lock()
+	for login := range t.broadcasters {
+		s.Broadcasters = append(s.Broadcasters, login)
+	}
+	t.broadcasterMu.Unlock()
+	sort.Strings(s.Broadcasters)
+
+	return s
+}
+
+// Returns the handler serving a snapshot of the session's state as JSON to requests carrying token as a bearer token
+func (t *Session) StateHandler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if !utils.ValidBearerToken(r, token) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		snapshot := t.Snapshot()
+
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(snapshot); err != nil {
+			utils.Log.WithError(err).Error("Failed to write state snapshot.")
+		}
+	})
+}
@@ -0,0 +1,209 @@
+package twitch
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/stats"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/tracing"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// A live squad member registered in a Discord channel
+type squadMember struct {
+	twitchID string
+	tci      *twitchChannelInfo
+	dc       *discordChannel
+}
+
+// Groups Twitch channels of a guild that are announced together when they stream the same game
+func (t *Session) SetSquad(discordGuildID string, name string, twitchIDs []string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(twitchIDs) < 2 {
+		return constants.ErrSquadTooSmall
+	}
+	for _, twitchID := range twitchIDs {
+		if t.twitchData[twitchID] == nil || len(t.twitchData[twitchID].DiscordChannels[discordGuildID]) == 0 {
+			return constants.ErrTwitchUserNotRegistered
+		}
+	}
+
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.Squads == nil {
+		gs.Squads = make(map[string][]string)
+	}
+	gs.Squads[name] = twitchIDs
+
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Removes a squad from a guild
+func (t *Session) RemoveSquad(discordGuildID string, name string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.Squads[name] == nil {
+		return constants.ErrSquadDoesNotExist
+	}
+
+	delete(gs.Squads, name)
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Returns a copy of the squads of a guild
+func (t *Session) GetSquads(discordGuildID string) map[string][]string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	squads := make(map[string][]string)
+
+	if t.guilds[discordGuildID] != nil {
+		for name, members := range t.guilds[discordGuildID].Squads {
+			squads[name] = append([]string{}, members...)
+		}
+	}
+
+	return squads
+}
+
+// Announces squad members that are live in the same game and registered in the same Discord channel with one message.
+// Called before the individual announcements, which skip the registrations announced here.
+func announceSquads(ctx context.Context, ts *Session, ds *discordgo.Session) {
+	for guildID, gs := range ts.guilds {
+		if connected, available := getGuildStatus(guildID); !available || !connected || len(gs.Squads) == 0 || ts.isMuted(guildID) {
+			continue
+		}
+
+		for _, members := range gs.Squads {
+			// Map of Discord channel and game to the live members announced there
+			groups := make(map[string][]squadMember)
+			for _, twitchID := range members {
+				tci := ts.twitchData[twitchID]
+				if tci == nil || tci.State != StateLive {
+					continue
+				}
+
+				for _, dc := range tci.DiscordChannels[guildID] {
+					// Members already announced on their own keep their message
+					if dc.LiveNotificationSent && dc.SquadMessageID == "" {
+						continue
+					}
+					if !dc.LiveNotificationSent && (!announceDue(dc, tci) || dc.isPaused()) {
+						continue
+					}
+					if !ts.getProfile(guildID, dc.Profile).allowsGame(tci.StreamData.GameName) {
+						continue
+					}
+
+					key := dc.ChannelID + "/" + strings.ToLower(tci.StreamData.GameName)
+					groups[key] = append(groups[key], squadMember{twitchID, tci, dc})
+				}
+			}
+
+			for _, group := range groups {
+				if len(group) >= 2 {
+					sendSquadNotification(ctx, discordFor(guildID, ds), guildID, group)
+				}
+			}
+		}
+	}
+}
+
+// Sends or edits the message of a squad if a member has not been announced yet
+func sendSquadNotification(ctx context.Context, ds *discordgo.Session, guildID string, group []squadMember) {
+	messageID := ""
+	pending := false
+	for _, m := range group {
+		if m.dc.SquadMessageID != "" {
+			messageID = m.dc.SquadMessageID
+		}
+		if !m.dc.LiveNotificationSent {
+			pending = true
+		}
+	}
+	if !pending {
+		return
+	}
+
+	_, span := tracing.Span(ctx, "discord.send_squad_notification",
+		attribute.Int("squad.members", len(group)),
+		attribute.String("discord.channel_id", group[0].dc.ChannelID))
+	defer span.End()
+
+	channelID := group[0].dc.ChannelID
+	embed := createDiscordSquadEmbedMessage(group)
+
+	var (
+		m   *discordgo.Message
+		err error
+	)
+	if messageID != "" {
+		m, err = ds.ChannelMessageEditEmbed(channelID, messageID, embed)
+	} else {
+		m, err = ds.ChannelMessageSendEmbed(channelID, embed)
+	}
+
+	for _, member := range group {
+		if err != nil {
+			if !member.dc.LiveNotificationSent {
+				recordOutcome(guildID, member.dc, member.tci, OutcomeSquad, ResultFailed, err)
+			}
+			continue
+		}
+
+		if !member.dc.LiveNotificationSent {
+			recordOutcome(guildID, member.dc, member.tci, OutcomeSquad, ResultSent, nil)
+		}
+		member.dc.LiveNotificationSent = true
+		member.dc.SquadMessageID = m.ID
+	}
+
+	if err != nil {
+		utils.Log.WithError(err).Error("Error sending Discord message.")
+		tracing.RecordError(span, err)
+	} else if messageID == "" {
+		stats.AnnouncementSent()
+	}
+}
+
+func createDiscordSquadEmbedMessage(group []squadMember) *discordgo.MessageEmbed {
+	sort.Slice(group, func(i, j int) bool { return group[i].twitchID < group[j].twitchID })
+
+	names := make([]string, 0, len(group))
+	logins := make([]string, 0, len(group))
+	description := ""
+	for _, m := range group {
+		names = append(names, m.tci.DisplayName)
+		logins = append(logins, m.twitchID)
+		description += "**[" + utils.SanitizeText(m.tci.DisplayName) + "](https://www.twitch.tv/" + m.twitchID + ")** " + utils.SanitizeText(m.tci.StreamData.Title) + "\n"
+	}
+
+	return &discordgo.MessageEmbed{
+		URL:         constants.MultiTwitchURL + strings.Join(logins, "/"),
+		Title:       "Watch everyone at once",
+		Description: description,
+		Color:       constants.DiscordLiveColor,
+		Thumbnail: &discordgo.MessageEmbedThumbnail{
+			URL: group[0].tci.LogoURL,
+		},
+		Author: &discordgo.MessageEmbedAuthor{
+			Name: strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1] + " are streaming together!",
+		},
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "Playing",
+				Value:  utils.SanitizeText(group[0].tci.StreamData.GameName) + " ",
+				Inline: true,
+			},
+		},
+	}
+}
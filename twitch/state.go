@@ -0,0 +1,67 @@
+package twitch
+
+import (
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// State of a monitored Twitch channel. Pending states debounce streams that briefly go live or offline,
+// so notifications are only sent in StateLive and StateOffline unless a registration overrides the debounce.
+type streamState int
+
+const (
+	StateOffline        streamState = iota // No stream for longer than the offline debounce
+	StatePendingLive                       // A stream started less than the live debounce ago
+	StateLive                              // A stream has been live for longer than the live debounce
+	StatePendingOffline                    // The stream ended less than the offline debounce ago
+)
+
+func (s streamState) String() string {
+	switch s {
+	case StatePendingLive:
+		return "pending live"
+	case StateLive:
+		return "live"
+	case StatePendingOffline:
+		return "pending offline"
+	default:
+		return "offline"
+	}
+}
+
+// Returns the state a channel is in at time now, given its refreshed stream data
+func (tci *twitchChannelInfo) nextState(now time.Time, d debounce) streamState {
+	if tci.StreamData != nil {
+		if now.Sub(tci.StartTime) > d.live {
+			return StateLive
+		}
+		return StatePendingLive
+	}
+
+	if now.Sub(tci.EndTime) > d.offline {
+		return StateOffline
+	}
+	return StatePendingOffline
+}
+
+// Moves the state machine of a channel along after its stream data was refreshed. Returns the previous state.
+func (tci *twitchChannelInfo) advance(twitchID string, now time.Time, d debounce) streamState {
+	prev := tci.State
+	next := tci.nextState(now, d)
+	if next != prev {
+		utils.Log.Debugf("%v changed from %v to %v.\n", twitchID, prev, next)
+		tci.State = next
+	}
+	return prev
+}
+
+// Returns whether a channel just went live, as opposed to resuming a stream that was briefly offline
+func (tci *twitchChannelInfo) wentLive(prev streamState) bool {
+	return tci.State == StateLive && (prev == StateOffline || prev == StatePendingLive)
+}
+
+// Returns whether a channel's stream just ended for longer than a brief interruption
+func (tci *twitchChannelInfo) wentOffline(prev streamState) bool {
+	return tci.State == StateOffline && prev != StateOffline
+}
@@ -0,0 +1,146 @@
+package twitch
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+func TestStateTransitions(t *testing.T) {
+	now := time.Now()
+	d := debounce{live: 90 * time.Second, offline: 90 * time.Second}
+	recent, old := now.Add(-time.Minute), now.Add(-time.Hour)
+
+	for _, tc := range []struct {
+		name        string
+		prev        streamState
+		live        bool
+		since       time.Time // Start time of a live stream or end time of an offline one
+		next        streamState
+		wentLive    bool
+		wentOffline bool
+	}{
+		{"offline stays offline", StateOffline, false, old, StateOffline, false, false},
+		{"stream starts", StateOffline, true, recent, StatePendingLive, false, false},
+		{"stream already live for the debounce", StateOffline, true, old, StateLive, true, false},
+		{"pending stream waits", StatePendingLive, true, recent, StatePendingLive, false, false},
+		{"pending stream is confirmed", StatePendingLive, true, old, StateLive, true, false},
+		{"pending stream ends briefly", StatePendingLive, false, recent, StatePendingOffline, false, false},
+		{"pending stream ends for good", StatePendingLive, false, old, StateOffline, false, true},
+		{"live stays live", StateLive, true, old, StateLive, false, false},
+		{"live stream ends", StateLive, false, recent, StatePendingOffline, false, false},
+		{"live stream ended for the debounce", StateLive, false, old, StateOffline, false, true},
+		{"ending stream waits", StatePendingOffline, false, recent, StatePendingOffline, false, false},
+		{"ending stream is confirmed", StatePendingOffline, false, old, StateOffline, false, true},
+		{"ending stream resumes", StatePendingOffline, true, old, StateLive, false, false},
+		{"ending stream restarts", StatePendingOffline, true, recent, StatePendingLive, false, false},
+	} {
+		tci := &twitchChannelInfo{State: tc.prev}
+		if tc.live {
+			tci.StreamData = &helix.Stream{}
+			tci.StartTime = tc.since
+		} else {
+			tci.EndTime = tc.since
+		}
+
+		prev := tci.advance("shroud", now, d)
+		if prev != tc.prev || tci.State != tc.next {
+			t.Errorf("%v: went from %v to %v, expected %v to %v", tc.name, prev, tci.State, tc.prev, tc.next)
+		}
+		if tci.wentLive(prev) != tc.wentLive || tci.wentOffline(prev) != tc.wentOffline {
+			t.Errorf("%v: went live %v and offline %v, expected %v and %v",
+				tc.name, tci.wentLive(prev), tci.wentOffline(prev), tc.wentLive, tc.wentOffline)
+		}
+	}
+}
+
+func TestStateDebounce(t *testing.T) {
+	now := time.Now()
+	tci := &twitchChannelInfo{StreamData: &helix.Stream{}, StartTime: now.Add(-time.Minute)}
+
+	// A shorter debounce confirms the same stream sooner
+	if next := tci.nextState(now, debounce{live: 2 * time.Minute}); next != StatePendingLive {
+		t.Errorf("stream live for a minute with a 2 minute debounce is %v", next)
+	}
+	if next := tci.nextState(now, debounce{live: 30 * time.Second}); next != StateLive {
+		t.Errorf("stream live for a minute with a 30 second debounce is %v", next)
+	}
+
+	tci = &twitchChannelInfo{EndTime: now.Add(-time.Minute)}
+	if next := tci.nextState(now, debounce{offline: 2 * time.Minute}); next != StatePendingOffline {
+		t.Errorf("stream offline for a minute with a 2 minute debounce is %v", next)
+	}
+	if next := tci.nextState(now, debounce{offline: 30 * time.Second}); next != StateOffline {
+		t.Errorf("stream offline for a minute with a 30 second debounce is %v", next)
+	}
+}
+
+func TestSetDebounce(t *testing.T) {
+	ts := &Session{}
+
+	ts.SetDebounce(0, 0)
+	if ts.debounce.live != constants.TwitchStateChangeTime || ts.debounce.offline != constants.TwitchStateChangeTime {
+		t.Errorf("default debounce is %+v", ts.debounce)
+	}
+	ts.SetDebounce(time.Minute, 5*time.Minute)
+	if ts.debounce.live != time.Minute || ts.debounce.offline != 5*time.Minute {
+		t.Errorf("debounce is %+v after setting 1m and 5m", ts.debounce)
+	}
+}
+
+func TestRegistrationDebounce(t *testing.T) {
+	ts := &Session{}
+	ts.SetDebounce(2*time.Minute, 2*time.Minute)
+
+	defaults := &discordChannel{}
+	short := &discordChannel{LiveDebounce: 30 * time.Second, OfflineDebounce: 30 * time.Second}
+
+	live := &twitchChannelInfo{StreamData: &helix.Stream{}, StartTime: time.Now().Add(-time.Minute)}
+	if ts.liveConfirmed(defaults, live) {
+		t.Error("stream live for a minute was confirmed with the 2 minute default")
+	}
+	if !ts.liveConfirmed(short, live) {
+		t.Error("stream live for a minute was not confirmed with a 30 second override")
+	}
+	if ts.offlineConfirmed(short, live) {
+		t.Error("live stream was confirmed offline")
+	}
+
+	offline := &twitchChannelInfo{EndTime: time.Now().Add(-time.Minute)}
+	if ts.offlineConfirmed(defaults, offline) {
+		t.Error("stream offline for a minute was confirmed with the 2 minute default")
+	}
+	if !ts.offlineConfirmed(short, offline) {
+		t.Error("stream offline for a minute was not confirmed with a 30 second override")
+	}
+	if ts.liveConfirmed(short, offline) {
+		t.Error("offline stream was confirmed live")
+	}
+}
+
+func TestSetChannelDebounce(t *testing.T) {
+	ts := newTestSession(t)
+	ts.twitchData["shroud"] = &twitchChannelInfo{
+		Login:           "shroud",
+		DiscordChannels: map[string][]*discordChannel{"guild": {{ChannelID: "channel"}}},
+	}
+
+	if err := ts.SetChannelDebounce("shroud", "guild", "channel", time.Minute, 0); err != nil {
+		t.Fatal(err)
+	}
+	if dc := ts.twitchData["shroud"].DiscordChannels["guild"][0]; dc.LiveDebounce != time.Minute || dc.OfflineDebounce != 0 {
+		t.Errorf("registration debounce is %v and %v", dc.LiveDebounce, dc.OfflineDebounce)
+	}
+	if err := ts.SetChannelDebounce("shroud", "guild", "channel", constants.MaxDebounce+time.Second, 0); !errors.Is(err, constants.ErrInvalidDebounce) {
+		t.Errorf("too long debounce: got %v", err)
+	}
+	if err := ts.SetChannelDebounce("shroud", "guild", "channel", -time.Second, 0); !errors.Is(err, constants.ErrInvalidDebounce) {
+		t.Errorf("negative debounce: got %v", err)
+	}
+	if err := ts.SetChannelDebounce("shroud", "guild", "other", time.Minute, 0); !errors.Is(err, constants.ErrTwitchUserNotRegistered) {
+		t.Errorf("unregistered channel: got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package twitch
+
+import (
+	"bytes"
+	"html/template"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Monitored channel shown on the status page
+type statusEntry struct {
+	Login       string     `json:"login"`
+	DisplayName string     `json:"display_name"`
+	URL         string     `json:"url"`
+	LogoURL     string     `json:"logo_url"`
+	Live        bool       `json:"live"`
+	Title       string     `json:"title,omitempty"`
+	Game        string     `json:"game,omitempty"`
+	Viewers     int        `json:"viewers"`
+	StartTime   *time.Time `json:"started_at,omitempty"`
+}
+
+var statusPageTemplate = template.Must(template.New("status").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="60">
+<title>Who's live</title>
+<style>
+body { font-family: sans-serif; margin: 0; padding: 8px; background: transparent; }
+.channel { display: flex; align-items: center; padding: 4px 0; }
+.channel img { width: 36px; height: 36px; border-radius: 50%; margin-right: 8px; }
+.channel a { color: inherit; font-weight: bold; text-decoration: none; }
+.offline { opacity: 0.5; }
+.live-badge { color: #fff; background: #e91916; border-radius: 4px; padding: 0 4px; margin-left: 6px; font-size: 0.75em; }
+.details { font-size: 0.85em; }
+</style>
+</head>
+<body>
+{{range .}}<div class="channel{{if not .Live}} offline{{end}}">
+{{if .LogoURL}}<img src="{{.LogoURL}}" alt="">{{end}}
+<div>
+<a href="{{.URL}}" target="_blank" rel="noopener">{{.DisplayName}}</a>{{if .Live}}<span class="live-badge">LIVE</span>{{end}}
+{{if .Live}}<div class="details">{{.Title}}{{if .Game}} · {{.Game}}{{end}} · {{.Viewers}} viewers</div>{{end}}
+</div>
+</div>
+{{else}}<p>No channels are monitored.</p>
+{{end}}</body>
+</html>
+`))
+
+// Returns the channels monitored by a guild, or by every guild if guildID is empty, with live channels first
+func (t *Session) statusEntries(guildID string) []statusEntry {
+	entries := []statusEntry{}
+	for login, tci := range t.twitchData {
+		if guildID != "" && len(tci.DiscordChannels[guildID]) == 0 {
+			continue
+		}
+
+		entries = append(entries, statusEntryOf(login, tci))
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Live != entries[j].Live {
+			return entries[i].Live
+		}
+		return strings.ToLower(entries[i].DisplayName) < strings.ToLower(entries[j].DisplayName)
+	})
+	return entries
+}
+
+func statusEntryOf(login string, tci *twitchChannelInfo) statusEntry {
+	e := statusEntry{
+		Login:       login,
+		DisplayName: tci.DisplayName,
+		URL:         tci.url(),
+		LogoURL:     tci.LogoURL,
+		Live:        tci.StreamData != nil,
+	}
+	if tci.StreamData != nil {
+		e.Title = tci.StreamData.Title
+		e.Game = tci.StreamData.GameName
+		e.Viewers = tci.StreamData.ViewerCount
+		startTime := tci.StartTime
+		e.StartTime = &startTime
+	}
+	return e
+}
+
+// Returns the handler serving an HTML page of monitored channels and whether they are live, meant to be embedded in an iframe.
+// The guild query parameter limits the page to channels monitored by a guild.
+func (t *Session) StatusPageHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		t.mu.RLock()
+		entries := t.statusEntries(r.URL.Query().Get("guild"))
+		t.mu.RUnlock()
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := statusPageTemplate.Execute(w, entries); err != nil {
+			utils.Log.WithError(err).Error("Failed to write status page.")
+		}
+	})
+}
+
+// Writes the status page of every monitored channel to a file, replacing it at once so web servers never serve a partial page
+func (t *Session) exportStatusPage(path string) {
+	t.mu.RLock()
+	entries := t.statusEntries("")
+	t.mu.RUnlock()
+
+	var page bytes.Buffer
+	if err := statusPageTemplate.Execute(&page, entries); err != nil {
+		utils.Log.WithError(err).Error("Failed to render status page.")
+		return
+	}
+
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, page.Bytes(), 0644); err != nil {
+		utils.Log.WithError(err).Error("Failed to export status page.")
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		utils.Log.WithError(err).Error("Failed to export status page.")
+	}
+}
@@ -0,0 +1,122 @@
+package twitch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Numbers of streams celebrated in announcements when streaks are enabled
+var streamMilestones = map[int]bool{10: true, 25: true, 50: true, 100: true, 250: true, 500: true, 1000: true}
+
+// Sets whether announcements in a guild celebrate streaks and stream milestones
+func (t *Session) SetStreaks(discordGuildID string, enabled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	gs.Streaks = enabled
+
+	t.writeGuildsToDisk()
+}
+
+// Returns whether announcements in a guild celebrate streaks and stream milestones
+func (t *Session) GetStreaks(discordGuildID string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.streaks(discordGuildID)
+}
+
+func (t *Session) streaks(discordGuildID string) bool {
+	if gs := t.guilds[discordGuildID]; gs != nil {
+		return gs.Streaks
+	}
+	return false
+}
+
+// Counts the stream a channel just started towards its streaks. The first stream counted for a channel also counts
+// the streams in the event log before it, so channels monitored before streaks were tracked start with their history.
+func (t *Session) recordStreak(login string, tci *twitchChannelInfo) {
+	if tci.Streams == 0 {
+		events, err := ReadEventLog(t.name)
+		if err != nil {
+			utils.Log.WithError(err).Error("Error reading event log.")
+		}
+
+		seen := make(map[string]bool)
+		for _, e := range events {
+			if e.Login != login || e.Type != EventOnline || seen[e.StreamID] || !e.StartedAt.Before(tci.StartTime) {
+				continue
+			}
+			seen[e.StreamID] = true
+			tci.advanceStreak(e.StartedAt)
+		}
+	}
+
+	tci.advanceStreak(tci.StartTime)
+}
+
+// Counts a stream started at start. Days and weeks are counted in UTC, weeks starting on Monday.
+func (tci *twitchChannelInfo) advanceStreak(start time.Time) {
+	day := utcDay(start)
+	if tci.LastStreamStart.IsZero() {
+		tci.StreakDays, tci.StreakWeeks = 1, 1
+	} else {
+		last := utcDay(tci.LastStreamStart)
+		switch day.Sub(last) {
+		case 0:
+		case 24 * time.Hour:
+			tci.StreakDays++
+		default:
+			tci.StreakDays = 1
+		}
+
+		switch periodBoundary(ReportWeekly, day).Sub(periodBoundary(ReportWeekly, last)) {
+		case 0:
+		case 7 * 24 * time.Hour:
+			tci.StreakWeeks++
+		default:
+			tci.StreakWeeks = 1
+		}
+	}
+
+	tci.Streams++
+	tci.LastStreamStart = start
+}
+
+func utcDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Returns the badge celebrating the current stream's milestone or streak, empty if there is nothing to celebrate
+func streakBadge(tci *twitchChannelInfo) string {
+	switch {
+	case streamMilestones[tci.Streams]:
+		return fmt.Sprintf("🏆 %v stream announced by this bot!", ordinal(tci.Streams))
+	case tci.StreakDays >= constants.StreakMinDays:
+		return fmt.Sprintf("🔥 %v day streak", tci.StreakDays)
+	case tci.StreakWeeks >= constants.StreakMinWeeks:
+		return fmt.Sprintf("🔥 %v week streak", tci.StreakWeeks)
+	}
+	return ""
+}
+
+// Formats a number as an English ordinal such as 1st, 22nd or 100th
+func ordinal(n int) string {
+	if n%100 >= 11 && n%100 <= 13 {
+		return fmt.Sprintf("%vth", n)
+	}
+	switch n % 10 {
+	case 1:
+		return fmt.Sprintf("%vst", n)
+	case 2:
+		return fmt.Sprintf("%vnd", n)
+	case 3:
+		return fmt.Sprintf("%vrd", n)
+	}
+	return fmt.Sprintf("%vth", n)
+}
@@ -0,0 +1,98 @@
+package twitch
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Members whose Discord presence shows them streaming a monitored Twitch channel
+type streamingPresences struct {
+	sync.Mutex
+	members map[string]map[string]string // Map of guild ID to a map of Discord user ID to the Twitch channel streamed
+}
+
+var streaming = streamingPresences{members: make(map[string]map[string]string)}
+
+// Tracks whether a guild member's Discord presence shows a Streaming activity pointing at a monitored Twitch channel.
+// Such members get the guild's live role like members who linked their account, and a channel that is not live yet
+// is polled right away to confirm the stream.
+func (t *Session) HandlePresence(ds *discordgo.Session, guildID string, userID string, activities []*discordgo.Activity) {
+	login, live := "", false
+	t.mu.RLock()
+	for _, activity := range activities {
+		if activity.Type != discordgo.ActivityTypeStreaming {
+			continue
+		}
+		if l := twitchLoginFromURL(activity.URL); l != "" && t.twitchData[l] != nil {
+			login, live = l, t.twitchData[l].State == StateLive
+			break
+		}
+	}
+	t.mu.RUnlock()
+
+	streaming.Lock()
+	previous := streaming.members[guildID][userID]
+	if login == previous {
+		streaming.Unlock()
+		return
+	}
+	if login == "" {
+		delete(streaming.members[guildID], userID)
+	} else {
+		if streaming.members[guildID] == nil {
+			streaming.members[guildID] = make(map[string]string)
+		}
+		streaming.members[guildID][userID] = login
+	}
+	streaming.Unlock()
+
+	if login != "" && !live {
+		t.PollNow()
+	}
+
+	roleID := t.GetLiveRole(guildID)
+	if roleID == "" {
+		return
+	}
+	ds = discordFor(guildID, ds)
+	if previous != "" {
+		t.setMemberRole(ds, guildID, userID, previous, roleID, false)
+	}
+	if login != "" {
+		t.setMemberRole(ds, guildID, userID, login, roleID, true)
+	}
+}
+
+// Returns the members of a guild whose presence shows them streaming a Twitch channel
+func streamingMembers(guildID string, login string) []string {
+	streaming.Lock()
+	defer streaming.Unlock()
+
+	var members []string
+	for userID, streamed := range streaming.members[guildID] {
+		if streamed == login {
+			members = append(members, userID)
+		}
+	}
+	return members
+}
+
+// Returns the login of a Twitch channel URL such as https://www.twitch.tv/<login>, or an empty string
+func twitchLoginFromURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	host = strings.TrimPrefix(host, "m.")
+	path := strings.Trim(u.Path, "/")
+	if host != "twitch.tv" || path == "" || strings.Contains(path, "/") {
+		return ""
+	}
+
+	return strings.ToLower(path)
+}
@@ -0,0 +1,176 @@
+package twitch
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/accounts"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+// Subscription tiers roles can be granted for. TierAny matches every tier.
+const (
+	Tier1   = "1000"
+	Tier2   = "2000"
+	Tier3   = "3000"
+	TierAny = "any"
+)
+
+// Sets the Discord role granted to linked subscribers of a broadcaster at a tier
+func (t *Session) SetSubRole(login string, discordGuildID string, tier string, roleID string) error {
+	if _, _, err := t.broadcasterClient(login, discordGuildID); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.SubRoles == nil {
+		gs.SubRoles = make(map[string]map[string]string)
+	}
+	if gs.SubRoles[login] == nil {
+		gs.SubRoles[login] = make(map[string]string)
+	}
+	gs.SubRoles[login][tier] = roleID
+
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Stops granting a role to subscribers of a broadcaster at a tier
+func (t *Session) RemoveSubRole(login string, discordGuildID string, tier string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.SubRoles[login][tier] == "" {
+		return constants.ErrSubRoleNotConfigured
+	}
+
+	delete(gs.SubRoles[login], tier)
+	if len(gs.SubRoles[login]) == 0 {
+		delete(gs.SubRoles, login)
+	}
+
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Returns a copy of the subscriber roles of a guild, keyed by broadcaster and tier
+func (t *Session) GetSubRoles(discordGuildID string) map[string]map[string]string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	roles := make(map[string]map[string]string)
+
+	for login, tiers := range t.getGuildSettings(discordGuildID).SubRoles {
+		roles[login] = make(map[string]string)
+		for tier, roleID := range tiers {
+			roles[login][tier] = roleID
+		}
+	}
+
+	return roles
+}
+
+// Periodically grants and removes subscriber roles of every guild
+func syncSubRoles(ts *Session, ds *discordgo.Session) {
+	// The roles are copied so they can be synced without holding up the poll
+	type subRoles struct {
+		guildID string
+		login   string
+		tiers   map[string]string
+	}
+	var syncs []subRoles
+	ts.mu.RLock()
+	for guildID, gs := range ts.guilds {
+		for login, tiers := range gs.SubRoles {
+			copied := make(map[string]string, len(tiers))
+			for tier, roleID := range tiers {
+				copied[tier] = roleID
+			}
+			syncs = append(syncs, subRoles{guildID, login, copied})
+		}
+	}
+	ts.mu.RUnlock()
+
+	for _, s := range syncs {
+		ts.syncGuildSubRoles(discordFor(s.guildID, ds), s.guildID, s.login, s.tiers)
+	}
+}
+
+// Grants the configured roles to guild members subscribed to a broadcaster and removes them from everyone else
+func (t *Session) syncGuildSubRoles(ds *discordgo.Session, guildID string, login string, tiers map[string]string) {
+	client, bt, err := t.broadcasterClient(login, guildID)
+	if err != nil {
+		utils.Log.WithError(err).Warnf("Skipping subscriber roles of %v in guild %v.\n", login, guildID)
+		return
+	}
+
+	// Find the linked accounts that are members of the guild
+	members := make(map[string]*discordgo.Member)
+	twitchIDs := make(map[string]string)
+	for _, account := range accounts.All() {
+		member, err := ds.GuildMember(guildID, account.DiscordUserID)
+		if err != nil {
+			continue
+		}
+		members[account.DiscordUserID] = member
+		twitchIDs[account.TwitchUserID] = account.DiscordUserID
+	}
+
+	// Look up the subscription tier of every linked member in batches
+	subTiers := make(map[string]string)
+	ids := make([]string, 0, len(twitchIDs))
+	for id := range twitchIDs {
+		ids = append(ids, id)
+	}
+	for i := 0; i < len(ids); i += constants.TwitchQueryBatchSize {
+		end := i + constants.TwitchQueryBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		resp, err := client.GetSubscriptions(&helix.SubscriptionsParams{
+			BroadcasterID: bt.UserID,
+			UserID:        ids[i:end],
+			First:         constants.TwitchQueryBatchSize,
+		})
+		if err != nil || resp.StatusCode != 200 {
+			utils.Log.WithError(err).Errorf("Failed to get subscriptions of %v.\n", login)
+			return
+		}
+
+		for _, sub := range resp.Data.Subscriptions {
+			subTiers[twitchIDs[sub.UserID]] = sub.Tier
+		}
+	}
+
+	for discordUserID, member := range members {
+		tier, subscribed := subTiers[discordUserID]
+
+		for roleTier, roleID := range tiers {
+			wanted := subscribed && (roleTier == TierAny || roleTier == tier)
+			has := hasRole(member, roleID)
+
+			if wanted && !has {
+				if err := ds.GuildMemberRoleAdd(guildID, discordUserID, roleID); err != nil {
+					utils.Log.WithError(err).Error("Failed to grant subscriber role.")
+				}
+			} else if !wanted && has {
+				if err := ds.GuildMemberRoleRemove(guildID, discordUserID, roleID); err != nil {
+					utils.Log.WithError(err).Error("Failed to remove subscriber role.")
+				}
+			}
+		}
+	}
+}
+
+func hasRole(member *discordgo.Member, roleID string) bool {
+	for _, role := range member.Roles {
+		if role == roleID {
+			return true
+		}
+	}
+	return false
+}
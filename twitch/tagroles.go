@@ -0,0 +1,99 @@
+package twitch
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+// Sets the role mentioned when a stream in a guild has a tag
+func (t *Session) SetTagRole(discordGuildID string, tag string, roleID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+	if tag == "" || strings.ContainsAny(tag, " \t") {
+		return constants.ErrInvalidTag
+	}
+
+	gs := t.getGuildSettings(discordGuildID)
+	if gs.TagRoles == nil {
+		gs.TagRoles = make(map[string]string)
+	}
+	gs.TagRoles[tag] = roleID
+
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Removes the role mentioned when a stream in a guild has a tag
+func (t *Session) RemoveTagRole(discordGuildID string, tag string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+	gs := t.guilds[discordGuildID]
+	if gs == nil || gs.TagRoles[tag] == "" {
+		return constants.ErrTagRoleNotSet
+	}
+
+	delete(gs.TagRoles, tag)
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Returns a copy of the tag roles of a guild
+func (t *Session) GetTagRoles(discordGuildID string) map[string]string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.tagRoles(discordGuildID)
+}
+
+func (t *Session) tagRoles(discordGuildID string) map[string]string {
+	roles := make(map[string]string)
+
+	if t.guilds[discordGuildID] != nil {
+		for tag, roleID := range t.guilds[discordGuildID].TagRoles {
+			roles[tag] = roleID
+		}
+	}
+
+	return roles
+}
+
+// Returns a copy of a profile that also mentions the roles of the stream's tags in a guild.
+// The profile is returned as is when no tag has a role.
+func (t *Session) withTagRoles(discordGuildID string, tci *twitchChannelInfo, p *Profile) *Profile {
+	gs := t.guilds[discordGuildID]
+	if gs == nil || len(gs.TagRoles) == 0 {
+		return p
+	}
+
+	mentions := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range tci.Tags {
+		roleID := gs.TagRoles[strings.ToLower(tag)]
+		if roleID == "" || seen[roleID] {
+			continue
+		}
+		seen[roleID] = true
+
+		mention := "<@&" + roleID + ">"
+		if p == nil || !strings.Contains(p.Mention, mention) {
+			mentions = append(mentions, mention)
+		}
+	}
+	if len(mentions) == 0 {
+		return p
+	}
+	sort.Strings(mentions)
+
+	tagged := &Profile{}
+	if p != nil {
+		*tagged = *p
+	}
+	tagged.Mention = strings.TrimSpace(tagged.Mention + " " + strings.Join(mentions, " "))
+	return tagged
+}
@@ -0,0 +1,122 @@
+package twitch
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+// How a registration picks among its templates
+const (
+	TemplateRotate = "rotate" // Use the templates in order
+	TemplateRandom = "random" // Pick a template at random, never the same one twice in a row
+)
+
+func init() {
+	rand.Seed(time.Now().UnixNano())
+}
+
+// Adds a template to the templates a registration picks from for each announcement.
+// Registration templates replace the template of the registration's profile.
+func (t *Session) AddTemplate(twitchID string, discordGuildID string, discordChannelID string, template string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	if idx < 0 {
+		return constants.ErrTwitchUserNotRegistered
+	}
+
+	dc := t.twitchData[twitchID].DiscordChannels[discordGuildID][idx]
+	if len(dc.Templates) >= constants.MaxTemplates {
+		return constants.ErrTooManyTemplates
+	}
+	dc.Templates = append(dc.Templates, template)
+
+	t.writeDataToDisk()
+
+	return nil
+}
+
+// Removes the nth template of a registration, counting from 1
+func (t *Session) RemoveTemplate(twitchID string, discordGuildID string, discordChannelID string, n int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	if idx < 0 {
+		return constants.ErrTwitchUserNotRegistered
+	}
+
+	dc := t.twitchData[twitchID].DiscordChannels[discordGuildID][idx]
+	if n < 1 || n > len(dc.Templates) {
+		return constants.ErrTemplateDoesNotExist
+	}
+	dc.Templates = append(dc.Templates[:n-1], dc.Templates[n:]...)
+
+	t.writeDataToDisk()
+
+	return nil
+}
+
+// Sets whether a registration rotates through its templates or picks them at random
+func (t *Session) SetTemplateMode(twitchID string, discordGuildID string, discordChannelID string, mode string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if mode != TemplateRotate && mode != TemplateRandom {
+		return constants.ErrInvalidTemplateMode
+	}
+
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	if idx < 0 {
+		return constants.ErrTwitchUserNotRegistered
+	}
+
+	t.twitchData[twitchID].DiscordChannels[discordGuildID][idx].TemplateMode = mode
+
+	t.writeDataToDisk()
+
+	return nil
+}
+
+// Returns the templates of a registration and how it picks among them
+func (t *Session) GetTemplates(twitchID string, discordGuildID string, discordChannelID string) ([]string, string, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	if idx < 0 {
+		return nil, "", constants.ErrTwitchUserNotRegistered
+	}
+
+	dc := t.twitchData[twitchID].DiscordChannels[discordGuildID][idx]
+	mode := dc.TemplateMode
+	if mode == "" {
+		mode = TemplateRotate
+	}
+
+	return append([]string{}, dc.Templates...), mode, nil
+}
+
+// Returns the template of the registration's next announcement and moves on to the following one.
+// Returns an empty string if the registration has no templates of its own.
+func (dc *discordChannel) nextTemplate() string {
+	n := len(dc.Templates)
+	if n == 0 {
+		return ""
+	}
+
+	i := dc.NextTemplate % n
+	if dc.TemplateMode == TemplateRandom {
+		i = rand.Intn(n)
+		// The template of the last announcement is skipped so no template is used twice in a row
+		if last := dc.NextTemplate - 1; n > 1 && last >= 0 && i == last%n {
+			i = (i + 1 + rand.Intn(n-1)) % n
+		}
+	}
+	dc.NextTemplate = i + 1
+
+	return dc.Templates[i]
+}
@@ -0,0 +1,46 @@
+package twitch
+
+import (
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+// Sets the IANA timezone, e.g. Europe/Paris, that times are shown in and reports are scheduled by in a guild.
+// An empty timezone restores UTC.
+func (t *Session) SetTimezone(discordGuildID string, timezone string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		// LoadLocation also accepts "Local", which depends on the host the bot runs on
+		if err != nil || timezone == "Local" {
+			return constants.ErrInvalidTimezone
+		}
+		timezone = loc.String()
+	}
+
+	gs := t.getGuildSettings(discordGuildID)
+	gs.Timezone = timezone
+
+	t.writeGuildsToDisk()
+	return nil
+}
+
+// Returns the timezone of a guild, UTC if it has none
+func (t *Session) GetTimezone(discordGuildID string) *time.Location {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.timezone(discordGuildID)
+}
+
+func (t *Session) timezone(discordGuildID string) *time.Location {
+	if gs := t.guilds[discordGuildID]; gs != nil && gs.Timezone != "" {
+		if loc, err := time.LoadLocation(gs.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
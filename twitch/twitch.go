@@ -1,25 +1,58 @@
 package twitch
 
 import (
-	"encoding/gob"
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/config"
 	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/crash"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/plugins"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/push"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/scripts"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/stats"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/tracing"
 	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type discordChannel struct {
-	ChannelID            string    // ID of discord channel
-	LiveMessageID        string    // ID of LiveMessage
-	UpdateTime           time.Time // Time the message was last updated
-	LiveNotificationSent bool      // Whether or not a channel was notified of being live
+	ChannelID            string        // ID of discord channel
+	LiveMessageID        string        // ID of LiveMessage
+	UpdateTime           time.Time     // Time the message was last updated
+	LiveNotificationSent bool          // Whether or not a channel was notified of being live
+	Profile              string        // Name of the guild profile applied to the channel
+	ChannelName          string        // Name of discord channel, kept up to date by ChannelUpdate events
+	ReminderInterval     time.Duration // Time between still live reminders, zero if disabled
+	ReminderMessageID    string        // ID of the last still live reminder
+	LastReminder         time.Time     // Time the last still live reminder was sent
+	RemindersSent        int           // Number of still live reminders sent during the current stream
+	Color                int           // Color of the live embed, ColorAuto to sample the avatar or 0 for the default
+	SquadMessageID       string        // ID of the squad message the channel was announced in instead of a LiveMessage
+	AnnounceDelay        time.Duration // Time after the stream starts before it is announced
+	Pin                  bool          // Whether the LiveMessage is pinned while the stream is live
+	Pinned               bool          // Whether the current LiveMessage is pinned
+	Paused               bool          // Whether announcements are suspended
+	PausedUntil          time.Time     // Time a pause ends, zero if it lasts until resumed
+	Templates            []string      // Templates picked from for each announcement instead of the profile's template
+	TemplateMode         string        // Whether templates are rotated or picked at random
+	NextTemplate         int           // Index after the template of the last announcement
+	Group                string        // Lowercase name of the group the registration is tagged with, empty if none
+	LiveDebounce         time.Duration // Time a stream must be live before it is announced, zero for the session default
+	OfflineDebounce      time.Duration // Time a stream must be offline before its message is ended, zero for the session default
+	PreviewUploaded      bool          // Whether the LiveMessage shows an uploaded stream preview instead of linking it
+	PlainMessage         bool          // Whether the LiveMessage is plain text because Discord refused its embed
+	WatchUntil           time.Time     // Time a temporary watch ends and the registration is removed, zero if permanent
+	OfflineText          string        // Message replacing the stream summary once the stream ends, empty for the summary
+	OfflineImage         string        // Image replacing the Twitch offline banner once the stream ends, empty for the banner
 }
 
 type gameInfo struct {
@@ -29,54 +62,140 @@ type gameInfo struct {
 }
 
 type twitchChannelInfo struct {
-	DisplayName     string                       // Twitch display name
+	Login           string                       // Twitch login, which channel links use
+	DisplayName     string                       // Twitch display name, which may be localized
 	LogoURL         string                       // URL of Twitch logo
+	OfflineImageURL string                       // URL of the Twitch offline banner, empty if the channel has none
+	Description     string                       // Twitch channel description
+	Followers       int                          // Follower count of the Twitch channel, 0 if unknown
+	InfoUpdated     time.Time                    // Time the description and follower count were last refreshed for an announcement
 	StreamData      *helix.Stream                // Stream response sent by
 	GameList        []*gameInfo                  // List of games played by streamer
 	StartTime       time.Time                    // Start time of stream
+	State           streamState                  // State of the channel's state machine
 	EndTime         time.Time                    // End time of stream
 	DiscordChannels map[string][]*discordChannel // Map of Discord guild IDs to discordChannel
+	AvatarColor     int                          // Dominant color of the Twitch logo, sampled for auto colored embeds
+	AvatarColorURL  string                       // URL of the Twitch logo AvatarColor was sampled from
+	DropsEnabled    bool                         // Whether the current stream has Drops enabled
+	Tags            []string                     // Tags of the current stream
+	Hiatus          time.Duration                // Time between the end of the previous stream and the start of the current one, 0 if unknown
+	Streams         int                          // Number of streams the bot has seen, including the current one
+	StreakDays      int                          // Consecutive UTC days with a stream up to the current or last stream
+	StreakWeeks     int                          // Consecutive weeks with a stream up to the current or last stream
+	LastStreamStart time.Time                    // Start time of the current or last stream counted towards the streaks
+	OrphanedSince   time.Time                    // Time the Twitch account was first found missing, zero if it exists
 }
 
+// Session of the bot with Twitch. Exported methods take its lock themselves and release it before calling Twitch or
+// Discord. Unexported methods expect their caller to hold it, unless they run as a background job.
 type Session struct {
-	name        string                        // Name of the Twitch session
-	client      *helix.Client                 // Helix client for sending HTTP requests to twitch
-	isConnected bool                          // Status of Helix client connection to twitch
-	twitchData  map[string]*twitchChannelInfo // Map of twitch channel to its info
+	mu              sync.RWMutex                  // Guards twitchData and guilds
+	name            string                        // Name of the Twitch session
+	clientMu        sync.RWMutex                  // Guards clientID, clientSecret and client, which credential rotation replaces
+	clientID        string                        // Twitch app client ID
+	clientSecret    string                        // Twitch app client secret
+	client          *helix.Client                 // Helix client for sending HTTP requests to twitch
+	isConnected     int32                         // 1 while the Helix client is connected to Twitch, accessed atomically
+	tokenMu         sync.Mutex                    // Keeps the poll and background jobs from refreshing the token at once
+	twitchData      map[string]*twitchChannelInfo // Map of twitch channel to its info
+	guilds          map[string]*guildSettings     // Map of Discord guild IDs to guild settings
+	broadcasterMu   sync.Mutex                    // Guards broadcasters, held while a broadcaster's token is refreshed
+	broadcasters    map[string]*broadcasterToken  // Map of twitch channel to its linked broadcaster authorization
+	eventSub        eventSubState                 // State of EventSub subscriptions
+	polls           pollMirrors                   // Polls and predictions being mirrored to Discord
+	bans            banConfirmations              // Synced bans waiting for a moderator to confirm them
+	announcements   announcementConfirmations     // Streams live when registered waiting for a moderator to confirm announcing them
+	feed            liveFeed                      // Recent live events served as an Atom feed
+	schedules       scheduleCache                 // Recently fetched stream schedules
+	accountRedirect string                        // URL Twitch redirects to after a user authorizes an account link
+	queryWorkers    int                           // Number of GetStreams batches issued concurrently
+	limiter         *rateLimiter                  // Coordinates rate limit usage between query workers
+	users           *userLookups                  // Rate limits and caches lookups of single Twitch users
+	emptyPolls      int                           // Consecutive polls without streams while channels were live
+	pollNow         chan struct{}                 // Wakes the monitor loop to poll before its interval has passed
+	lastPoll        int64                         // Unix nanoseconds the last poll cycle finished, read atomically
+	debounce        debounce                      // Default time streams must stay live or offline before notifications change
+	reconnection    reconnection                  // Background retries while the session cannot connect to Twitch
+	responseLog     responseLog                   // Sampling of Twitch responses logged for debugging
+	sockets         eventSockets                  // Connected event sockets of overlays and other apps
+	intake          chan intakeAnnouncement       // Announcements posted to the announce webhook waiting to be sent
 }
 
-var (
-	activeSessions map[string]*Session // Map of Discord sessions to twitch sessions
-	guildStatus    map[string]bool     // Map of Guild ID to status of guild connection
-)
+// Sampling state of logged Twitch responses
+type responseLog struct {
+	polls   int64 // Polls since the bot started, accessed atomically
+	sampled int32 // 1 while the responses of the current poll are logged, accessed atomically
+}
+
+var guildStatus = struct {
+	sync.RWMutex
+	m map[string]bool // Map of Guild ID to status of guild connection
+}{m: make(map[string]bool)}
+
+// Returns whether the bot is connected to a guild and whether the guild's status is known
+func getGuildStatus(guildID string) (bool, bool) {
+	guildStatus.RLock()
+	defer guildStatus.RUnlock()
 
-func init() {
-	activeSessions = make(map[string]*Session)
-	guildStatus = make(map[string]bool)
+	connected, available := guildStatus.m[guildID]
+	return connected, available
 }
 
-func (t *Session) Close() error {
-	t.isConnected = false
+// Returns a copy of the connection status of every guild with a known status
+func guildStatuses() map[string]bool {
+	guildStatus.RLock()
+	defer guildStatus.RUnlock()
 
-	for _, tcInfo := range t.twitchData {
-		for gID, status := range guildStatus {
-			if !status {
-				delete(tcInfo.DiscordChannels, gID)
-			}
-		}
+	statuses := make(map[string]bool, len(guildStatus.m))
+	for guildID, connected := range guildStatus.m {
+		statuses[guildID] = connected
 	}
+	return statuses
+}
+
+func setGuildStatus(guildID string, connected bool) {
+	guildStatus.Lock()
+	defer guildStatus.Unlock()
 
-	return utils.WriteGobToDisk(constants.DataPath, t.name, t.twitchData)
+	guildStatus.m[guildID] = connected
+}
+
+// Stops the session and saves its data once the jobs of its connection have returned
+func (t *Session) Close() error {
+	t.setConnected(false)
+	atomic.StoreInt32(&t.reconnection.closed, 1)
+	t.stopConnection()
+
+	// Jobs take the lock, so they are waited for before it is held
+	t.reconnection.jobs.Wait()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Data of guilds the bot was removed from is kept until Reconcile finds the grace period has passed
+	presence.Lock()
+	writeGuildPresence()
+	presence.Unlock()
+
+	t.writeGuildsToDisk()
+
+	return utils.WriteGobToDisk(utils.DataDir, t.name, t.twitchData)
 }
 
 // Returns twitch channels being monitored by discord channel
 func (s *Session) GetMonitoredChannels(channelID string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	channels := []string{}
 
 	for tc, tcInfo := range s.twitchData {
 		for _, discordChannels := range tcInfo.DiscordChannels {
 			for _, discordChannel := range discordChannels {
-				if discordChannel.ChannelID == channelID {
+				if discordChannel.ChannelID == channelID && !discordChannel.WatchUntil.IsZero() {
+					channels = append(channels, fmt.Sprintf("%v (watched until <t:%d:f>)", s.twitchData[tc].DisplayName, discordChannel.WatchUntil.Unix()))
+				} else if discordChannel.ChannelID == channelID {
 					channels = append(channels, s.twitchData[tc].DisplayName)
 				}
 			}
@@ -87,73 +206,191 @@ func (s *Session) GetMonitoredChannels(channelID string) []string {
 }
 
 func GetSession(s *discordgo.Session) *Session {
-	return activeSessions[s.State.SessionID]
+	activeSessions.RLock()
+	defer activeSessions.RUnlock()
+
+	return activeSessions.m[botID(s)]
 }
 
 func New(id string, secret string, name string) (t *Session, err error) {
-	t = &Session{}
-	t.name = name
+	t, err = Load(name)
+	t.clientID = id
+	t.clientSecret = secret
 
-	t.client, err = helix.NewClient(&helix.Options{
+	// Without a client, e.g. when no client ID is set, the session runs on its stored data without Twitch
+	client, errClient := helix.NewClient(&helix.Options{
+		HTTPClient:   utils.HTTPClient,
 		ClientID:     id,
 		ClientSecret: secret,
 		RedirectURI:  "http://localhost",
 	})
+	if errClient == nil {
+		t.client = client
+	}
+
 	if err != nil {
 		return t, err
 	}
+	return t, errClient
+}
+
+// Loads the stored data of a session without a Twitch client, e.g. to convert it to the current format offline.
+// The returned session is never nil, even if the data could not be read.
+func Load(name string) (t *Session, err error) {
+	t = &Session{}
+	t.name = name
+	t.queryWorkers = constants.TwitchQueryWorkers
+	t.limiter = newRateLimiter()
+	t.users = newUserLookups()
+	t.pollNow = make(chan struct{}, 1)
+	t.SetDebounce(0, 0)
+	loadGuildPresence()
 
 	t.twitchData = make(map[string]*twitchChannelInfo)
 
-	err = readGobFromDisk(constants.DataPath, t.name, &t.twitchData)
+	t.guilds = make(map[string]*guildSettings)
+	t.broadcasters = make(map[string]*broadcasterToken)
+	t.eventSub.subscriptions = make(map[string]bool)
+	t.eventSub.listeners = make(map[string]eventSubListener)
+	t.eventSub.recent = make(map[string]bool)
+
+	t.polls.mirrors = make(map[string]*pollMirror)
+
+	t.onEventSub(helix.EventSubTypeChannelPointsCustomRewardRedemptionAdd, t.handleRedemption)
+	t.registerPollListeners()
+	t.bans.pending = make(map[string]*pendingBan)
+	t.announcements.pending = make(map[string]*pendingAnnouncement)
+	t.feed.seen = make(map[string]bool)
+	t.schedules.schedules = make(map[string]*cachedSchedule)
+	t.intake = make(chan intakeAnnouncement, constants.IntakeQueueSize)
+	t.onEventSub(helix.EventSubTypeChannelBan, t.handleBan)
+	t.onEventSub(helix.EventSubTypeChannelUnban, t.handleUnban)
+
+	err = utils.ReadGobFromDisk(utils.DataDir, t.name, &t.twitchData)
 	if errors.Is(err, os.ErrNotExist) {
 		utils.Log.Warn("Twitch session info does not exist on disk. Will be created on shutdown.")
 		err = nil
 	}
+	if err != nil {
+		return t, err
+	}
+	t.migrateLogins()
+
+	err = utils.ReadGobFromDisk(utils.DataDir, t.name+"_guilds", &t.guilds)
+	if errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+	if err != nil {
+		return t, err
+	}
 
-	return t, err
+	if err = t.readBroadcastersFromDisk(); err != nil {
+		return t, err
+	}
+
+	return t, t.migrateAccounts()
 }
 
 // Attempts to use client ID and secret to get Auth token from twitch.
 // If successful then set the session state to connected.
 func (t *Session) GetAuthToken() error {
-	resp, err := t.client.RequestAppAccessToken([]string{""})
+	t.tokenMu.Lock()
+	defer t.tokenMu.Unlock()
+
+	return t.requestAuthToken()
+}
+
+// Gets a new app access token and replaces the helix client with one using it, since queries may be
+// sending requests with the current client. Expects tokenMu to be held.
+func (t *Session) requestAuthToken() error {
+	client := t.helixClient()
+	if client == nil {
+		return constants.ErrNoTwitchClient
+	}
+
+	resp, err := client.RequestAppAccessToken([]string{""})
 	if err != nil {
 		return err
 	} else if resp.Data.AccessToken == "" {
 		return constants.ErrEmptyAccessToken
 	}
-	t.client.SetAppAccessToken(resp.Data.AccessToken)
-	t.isConnected = true
+
+	id, secret := t.appCredentials()
+	refreshed, err := helix.NewClient(&helix.Options{
+		HTTPClient:     utils.HTTPClient,
+		ClientID:       id,
+		ClientSecret:   secret,
+		AppAccessToken: resp.Data.AccessToken,
+		RedirectURI:    "http://localhost",
+	})
+	if err != nil {
+		return err
+	}
+
+	t.clientMu.Lock()
+	t.client = refreshed
+	t.clientMu.Unlock()
+	t.setConnected(true)
 
 	return nil
 }
 
 // Registers a Discord Channel to monitor the live state of a twitch channel
-func (t *Session) RegisterChannel(twitchID string, discordGuildID string, discordChannelID string) (registered error) {
-	// if twitch channel doesn't exist, register as new channel
-	if t.twitchData[twitchID] == nil {
+func (t *Session) RegisterChannel(twitchID string, discordGuildID string, discordChannelID string, discordChannelName string) (registered error) {
+	twitchID = NormalizeLogin(twitchID)
+	user, err := t.lookupNewChannel(twitchID)
+	if err != nil {
+		return err
+	}
 
-		// we need to obtain the profile picture url and display name for the twitch channel
-		if validateAndRefreshAuthToken(t) {
-			resp, err := t.client.GetUsers(&helix.UsersParams{Logins: []string{twitchID}})
-			if err != nil {
-				utils.Log.WithError(err).Error("Failed to query twitch.")
-			}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.registerChannel(twitchID, discordGuildID, discordChannelID, discordChannelName, user)
+}
 
-			if len(resp.Data.Users) == 0 {
-				return constants.ErrTwitchUserDoesNotExist
-			}
+// Looks up a twitch channel that is not monitored yet, so it can be registered without holding the lock
+// during the query. Returns nil if the channel is monitored already.
+func (t *Session) lookupNewChannel(twitchID string) (*helix.User, error) {
+	t.mu.RLock()
+	monitored := t.twitchData[twitchID] != nil
+	t.mu.RUnlock()
+	if monitored {
+		return nil, nil
+	}
 
-			// register the twitch information channel
-			t.twitchData[twitchID] = &twitchChannelInfo{
-				DisplayName:     resp.Data.Users[0].DisplayName,
-				LogoURL:         resp.Data.Users[0].ProfileImageURL,
-				DiscordChannels: make(map[string][]*discordChannel),
-			}
-		} else {
-			return constants.ErrInvalidToken
+	// we need to obtain the profile picture url and display name for the twitch channel
+	if !validateAndRefreshAuthToken(t) {
+		return nil, constants.ErrInvalidToken
+	}
+	user, err := t.lookupUser(twitchID)
+	if err != nil {
+		if !errors.Is(err, constants.ErrTwitchUserDoesNotExist) {
+			utils.Log.WithError(err).Error("Failed to query twitch.")
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// Registers a Discord Channel with the user looked up by lookupNewChannel. A channel that stopped being monitored
+// since the lookup is registered with its login as display name until the metadata refresh fills it in.
+func (t *Session) registerChannel(twitchID string, discordGuildID string, discordChannelID string, discordChannelName string, user *helix.User) error {
+	// if twitch channel doesn't exist, register as new channel
+	if t.twitchData[twitchID] == nil {
+		tci := &twitchChannelInfo{
+			Login:           twitchID,
+			DisplayName:     twitchID,
+			DiscordChannels: make(map[string][]*discordChannel),
 		}
+		if user != nil {
+			tci.DisplayName = user.DisplayName
+			tci.LogoURL = user.ProfileImageURL
+			tci.OfflineImageURL = user.OfflineImageURL
+			tci.Description = user.Description
+		}
+
+		// register the twitch information channel
+		t.twitchData[twitchID] = tci
 	}
 
 	// check if twitch session contains discord oracle, register otherwise
@@ -161,47 +398,88 @@ func (t *Session) RegisterChannel(twitchID string, discordGuildID string, discor
 		dc := &discordChannel{
 			ChannelID:            discordChannelID,
 			LiveNotificationSent: false,
+			ChannelName:          discordChannelName,
 		}
 		t.twitchData[twitchID].DiscordChannels[discordGuildID] = append(t.twitchData[twitchID].DiscordChannels[discordGuildID], dc)
 
 		// Writes the data to the disk in case of crash
-		if err := utils.WriteGobToDisk(constants.DataPath, t.name, t.twitchData); err != nil {
-			utils.Log.WithError(err).Error("Error writing data to disk.")
-		}
+		t.writeDataToDisk()
 
 		return nil
 	}
 
+	// Adding a channel that is temporarily watched keeps it for good
+	if dc := t.twitchData[twitchID].DiscordChannels[discordGuildID][t.getChannelIdx(twitchID, discordGuildID, discordChannelID)]; !dc.WatchUntil.IsZero() {
+		dc.WatchUntil = time.Time{}
+		t.writeDataToDisk()
+		return nil
+	}
+
 	return constants.ErrTwitchUserRegistered
 }
 
-// Sets the current guild as active
-func SetGuildActive(guildID string) {
-	guildStatus[guildID] = true
+// Sets the current guild as active and routes its messages through the Discord session s
+func SetGuildActive(s *discordgo.Session, guildID string) {
+	setGuildStatus(guildID, true)
+	setGuildSession(guildID, s)
+	markGuildSeen(guildID)
 }
 
 // Sets the current guild as inactive
 func SetGuildInactive(guildID string) {
-	guildStatus[guildID] = false
+	setGuildStatus(guildID, false)
+	setGuildSession(guildID, nil)
+	markGuildRemoved(guildID)
 }
 
 // Sets current guild as unavailable
 func SetGuildUnavailable(guildID string) {
-	delete(guildStatus, guildID)
+	guildStatus.Lock()
+	defer guildStatus.Unlock()
+
+	delete(guildStatus.m, guildID)
 }
 
-// Adds session to activeSessions if it is connected to Twitch and begins to monitor Twitch
+// Adds session to the active sessions if it is connected to Twitch and begins to monitor Twitch.
+// Otherwise it keeps trying to connect in the background.
 func StartMonitoring(t *Session, s *discordgo.Session) {
-	if t.isConnected {
-		activeSessions[s.State.SessionID] = t
-
-		go monitorChannels(t, s)
+	if !t.connected() {
+		go t.reconnect(s)
+	} else {
+		setActiveSession(s, t)
+		atomic.StoreInt64(&t.lastPoll, time.Now().UnixNano())
+
+		// Jobs run until the connection they were started for is lost, so a reconnect does not run them twice
+		ctx, cancel := t.startConnection()
+		t.goJob(func() { t.every(ctx, constants.MetadataRefreshInterval, func() { t.refreshMetadata(s) }) })
+		t.goJob(func() { monitorChannels(ctx, cancel, t, s) })
+		t.goJob(func() { t.every(ctx, constants.SubRoleSyncInterval, func() { syncSubRoles(t, s) }) })
+		t.goJob(func() { t.every(ctx, constants.GoalUpdateInterval, func() { syncGoals(t, s) }) })
+		t.goJob(func() { t.every(ctx, constants.ReportCheckInterval, func() { sendReports(t, s) }) })
+		t.goJob(func() { t.runIntake(ctx, s) })
+		t.goJob(func() { t.every(ctx, constants.WatchExpiryInterval, t.locked(t.expireWatches)) })
+		t.goJob(func() { t.every(ctx, constants.AnnounceLockAge/4, pruneAnnouncementClaims) })
+		t.goJob(func() { t.every(ctx, constants.StatsFlushInterval, stats.Flush) })
+		if path := config.Settings.StatusPageFile; path != "" {
+			t.goJob(func() { t.every(ctx, constants.StatusPageExportInterval, func() { t.exportStatusPage(path) }) })
+		}
 	}
 }
 
 // Unregisters a Discord Channel from monitor the live state of a Twitch channel
 func (t *Session) UnregisterChannel(twitchID string, discordGuildID string, discordChannelID string) (unregistered bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.unregisterChannel(twitchID, discordGuildID, discordChannelID)
+}
+
+func (t *Session) unregisterChannel(twitchID string, discordGuildID string, discordChannelID string) bool {
+	twitchID = NormalizeLogin(twitchID)
 	if channelIdx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID); channelIdx >= 0 {
+		// Keep the registration around so it can be restored with undo
+		t.softDelete(twitchID, discordGuildID, t.twitchData[twitchID].DiscordChannels[discordGuildID][channelIdx])
+
 		t.twitchData[twitchID].DiscordChannels[discordGuildID] = remove(t.twitchData[twitchID].DiscordChannels[discordGuildID], channelIdx)
 
 		// Check if no more channels in Discord server are monitoring for Twitch channel and if so delete from map
@@ -216,90 +494,82 @@ func (t *Session) UnregisterChannel(twitchID string, discordGuildID string, disc
 		}
 
 		// Writes the data to the disk in case of crash
-		if err := utils.WriteGobToDisk(constants.DataPath, t.name, t.twitchData); err != nil {
-			utils.Log.WithError(err).Error("Error writing data to disk.")
-		}
+		t.writeDataToDisk()
 
 		return true
 	}
 	return false
 }
 
-func createDiscordLiveEmbedMessage(t *twitchChannelInfo) *discordgo.MessageEmbed {
-	var fields []*discordgo.MessageEmbedField
-	if t.StreamData.GameName != "" {
-		fields = []*discordgo.MessageEmbedField{
-			{
-				Name:   "Playing",
-				Value:  t.StreamData.GameName + " ",
-				Inline: true,
-			},
-			{
-				Name:   "Viewers",
-				Value:  fmt.Sprint(t.StreamData.ViewerCount) + " ",
-				Inline: true,
-			},
-		}
+// Creates the embed of a live message. A layout replaces the default fields and uptime footer.
+func createDiscordLiveEmbedMessage(t *twitchChannelInfo, style embedStyle) *discordgo.MessageEmbed {
+	b := newEmbed().
+		title(utils.SanitizeText(t.StreamData.Title), t.url()).
+		color(style.color).
+		author(t.name(style.names) + " is live!").
+		thumbnail(t.LogoURL)
+	if !style.hidePreview {
+		b.image(strings.Replace(strings.Replace(t.StreamData.ThumbnailURL+"?"+
+			fmt.Sprint(time.Now().Round(constants.TwitchThumbnailUpdateTime).Unix()),
+			"{width}", "1920", -1), "{height}", "1080", -1))
+	}
+
+	var footer []string
+	if len(style.layout) > 0 {
+		b.fields(layoutFields(t, style.layout))
 	} else {
-		fields = []*discordgo.MessageEmbedField{
-			{
-				Name:   "Viewers",
-				Value:  fmt.Sprint(t.StreamData.ViewerCount) + " ",
-				Inline: true,
-			},
-		}
+		b.field("Playing", utils.SanitizeText(t.StreamData.GameName), true).
+			field("Viewers", fmt.Sprint(t.StreamData.ViewerCount), true)
+		footer = append(footer, "Streaming for "+formatDuration(time.Since(t.StartTime).Round(time.Second)))
+	}
+	if style.channelInfo && t.Followers > 0 {
+		footer = append([]string{fmt.Sprintf("%v followers", t.Followers)}, footer...)
+	}
+	footerText := strings.Join(footer, " • ")
+	if style.channelInfo && t.Description != "" {
+		footerText = truncateText(t.Description, constants.FooterDescriptionLength) + "\n" + footerText
 	}
+	b.footer(footerText)
 
-	embed := &discordgo.MessageEmbed{
-		URL:   "https://www.twitch.tv/" + t.DisplayName,
-		Title: t.StreamData.Title,
-		Color: 0x00ff00,
-		Footer: &discordgo.MessageEmbedFooter{
-			Text: "Streaming for " + formatDuration(time.Since(t.StartTime).Round(time.Second)),
-		},
-		Image: &discordgo.MessageEmbedImage{
-			URL: strings.Replace(strings.Replace(t.StreamData.ThumbnailURL+"?"+
-				fmt.Sprint(time.Now().Round(constants.TwitchThumbnailUpdateTime).Unix()),
-				"{width}", "1920", -1), "{height}", "1080", -1),
-		},
-		Thumbnail: &discordgo.MessageEmbedThumbnail{
-			URL: t.LogoURL,
-		},
-		Author: &discordgo.MessageEmbedAuthor{
-			Name: t.DisplayName + " is live!",
-		},
-		Fields: fields,
+	var badges []string
+	if days := hiatusDays(t, style.hiatusDays); days > 0 {
+		badges = append(badges, fmt.Sprintf("🎉 First stream in %v days!", days))
+	}
+	if badge := streakBadge(t); style.streaks && badge != "" {
+		badges = append(badges, badge)
+	}
+	if t.DropsEnabled {
+		badges = append(badges, "🎁 Drops enabled")
+	}
+	if len(badges) > 0 {
+		b.description(strings.Join(badges, "\n"))
 	}
 
-	return embed
+	return b.build()
 }
 
-func createDiscordOfflineEmbedMessage(t *twitchChannelInfo) *discordgo.MessageEmbed {
+func createDiscordOfflineEmbedMessage(t *twitchChannelInfo, names string, loc *time.Location) *discordgo.MessageEmbed {
 	games := ""
 
 	for i, game := range t.GameList {
 		if game.GameName != "" {
-			games += fmt.Sprint(i+1) + ". " + game.GameName + " for " + formatDuration(game.EndTime.Sub(game.StartTime).Round(time.Second)) + "\n"
+			games += fmt.Sprint(i+1) + ". " + utils.SanitizeText(game.GameName) + " for " + formatDuration(game.EndTime.Sub(game.StartTime).Round(time.Second)) + "\n"
 		} else {
 			games += fmt.Sprint(i+1) + ". Nothing for " + formatDuration(game.EndTime.Sub(game.StartTime).Round(time.Second)) + "\n"
 		}
 	}
 
-	embed := &discordgo.MessageEmbed{
-		Description: "**Started at:** " + t.StartTime.Format("01/02/2006 15:04 MST") + "\n" +
-			"__**Ended at:** " + t.EndTime.Format("01/02/2006 15:04 MST") + "__\n" +
+	// The stream thumbnail is replaced by the offline banner once the stream ends
+	return newEmbed().
+		description("**Started at:** " + t.StartTime.In(loc).Format("01/02/2006 15:04 MST") + "\n" +
+			"__**Ended at:** " + t.EndTime.In(loc).Format("01/02/2006 15:04 MST") + "__\n" +
 			"**Total time streamed:** " + formatDuration(t.EndTime.Sub(t.StartTime).Round(time.Second)) + "\n\n" +
-			"**Games Played**\n" + games,
-		Color: 0xff0000,
-		Thumbnail: &discordgo.MessageEmbedThumbnail{
-			URL: t.LogoURL,
-		},
-		Author: &discordgo.MessageEmbedAuthor{
-			Name: t.DisplayName + " was online.",
-		},
-	}
-
-	return embed
+			"**Games Played**\n" + games).
+		color(constants.DiscordOfflineColor).
+		thumbnail(t.LogoURL).
+		author(t.name(names) + " was online.").
+		image(t.OfflineImageURL).
+		build()
 }
 
 func formatDuration(d time.Duration) string {
@@ -325,53 +595,120 @@ func (t *Session) getChannelIdx(twitchID string, discordGuildID string, discordC
 	return -1
 }
 
-func monitorChannels(ts *Session, ds *discordgo.Session) {
-	for ts.isConnected {
-		if validateAndRefreshAuthToken(ts) {
-			var queryChannels []string
+// Monitors the Twitch channels of a session until it disconnects
+func monitorChannels(ctx context.Context, cancel context.CancelFunc, ts *Session, ds *discordgo.Session) {
+	ts.everyPollOrWake(ctx, constants.TwitchQueryInterval, ts.pollNow, func() {
+		monitorCycle(ts, ds)
+		atomic.StoreInt64(&ts.lastPoll, time.Now().UnixNano())
+	})
 
-			for twitchChannel := range ts.twitchData {
-				queryChannels = append(queryChannels, twitchChannel)
-			}
+	// The other jobs of the connection stop with the poll
+	cancel()
+	setActiveSession(ds, nil)
 
-			resp, err := ts.client.GetStreams(&helix.StreamsParams{
-				UserLogins: queryChannels,
-			})
-			if err != nil {
-				utils.Log.WithError(err).Error("Failed to query twitch.")
-			}
+	// The session lost its connection to Twitch rather than being closed
+	go ts.reconnect(ds)
+}
 
-			if constants.DebugTwitchResponse {
-				empJSON, err := json.MarshalIndent(resp, "", "  ")
-				if err != nil {
-					utils.Log.WithError(err).Debug("Error marshaling Twitch JSON response.")
-				} else {
-					utils.Log.Debugf("Twitch getStreams request Response: %+v\n", string(empJSON))
-				}
-			}
+// Refreshes the streams of every monitored channel, advances their state machines and sends the resulting notifications
+func monitorCycle(ts *Session, ds *discordgo.Session) {
+	if !validateAndRefreshAuthToken(ts) {
+		return
+	}
 
-			// Populates twitch info. If stream not found then set end time.
-			for twitchChannel, tcInfo := range ts.twitchData {
-				if !populateTwitchInfo(twitchChannel, tcInfo, resp) {
-					tcInfo.StreamData = nil
-					if tcInfo.EndTime.IsZero() {
-						tcInfo.EndTime = time.Now().UTC()
-					}
-				}
-			}
+	var queryChannels []string
+	ts.mu.RLock()
+	for twitchChannel := range ts.twitchData {
+		queryChannels = append(queryChannels, twitchChannel)
+	}
+	ts.mu.RUnlock()
+
+	ctx, span := tracing.Span(context.Background(), "monitor.cycle", attribute.Int("twitch.channels", len(queryChannels)))
+	defer span.End()
 
-			sendNotifications(ts, ds)
+	ts.sampleResponseLog()
+	streams, err := ts.queryStreams(ctx, queryChannels)
+	if err != nil {
+		utils.Log.WithError(err).Error("Failed to query twitch.")
+		tracing.RecordError(span, err)
+		return
+	}
+
+	// The tags of streams that went live are looked up before the lock is taken as well
+	var started []string
+	ts.mu.RLock()
+	for _, stream := range streams {
+		if tci := ts.twitchData[NormalizeLogin(stream.UserLogin)]; tci != nil && tci.StreamData == nil && stream.Type == "live" {
+			started = append(started, NormalizeLogin(stream.UserLogin))
 		}
+	}
+	ts.mu.RUnlock()
+	tags := ts.queryTags(started)
+
+	// Twitch is queried without the lock so commands and HTTP requests are not held up by it
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.suspectOutage(streams) {
+		span.SetAttributes(attribute.Bool("twitch.suspected_outage", true))
+		return
+	}
 
-		time.Sleep(constants.TwitchQueryInterval)
+	// Populates twitch info. If stream not found then set end time.
+	now := time.Now()
+	for twitchChannel, tcInfo := range ts.twitchData {
+		prev := tcInfo.StreamData
+		lastEnd := tcInfo.EndTime
+		if !populateTwitchInfo(twitchChannel, tcInfo, streams) {
+			tcInfo.StreamData = nil
+			tcInfo.DropsEnabled = false
+			tcInfo.Tags = nil
+			if tcInfo.EndTime.IsZero() {
+				tcInfo.EndTime = time.Now().UTC()
+			}
+		} else if prev == nil {
+			// A stream resumed within the offline debounce continues the stream before it
+			if tcInfo.State == StateOffline {
+				ts.recordHiatus(twitchChannel, tcInfo, lastEnd)
+				ts.recordStreak(twitchChannel, tcInfo)
+			}
+		}
+		ts.logStreamChanges(twitchChannel, prev, tcInfo.StreamData)
+		if prev != nil && tcInfo.StreamData != nil && (prev.Title != tcInfo.StreamData.Title || prev.GameName != tcInfo.StreamData.GameName) {
+			ts.publishSocketEvent(SocketUpdate, twitchChannel, tcInfo)
+		}
+		if prevState := tcInfo.advance(twitchChannel, now, ts.debounce); tcInfo.wentLive(prevState) {
+			go push.NotifyLive(twitchChannel, tcInfo.DisplayName, tcInfo.StreamData.Title, tcInfo.StreamData.GameName)
+			go ts.updateLiveRoles(ds, twitchChannel, true)
+			ts.publishSocketEvent(SocketLive, twitchChannel, tcInfo)
+			plugins.Live(plugins.LiveEvent{
+				TwitchID:    twitchChannel,
+				Login:       tcInfo.Login,
+				DisplayName: tcInfo.DisplayName,
+				Title:       tcInfo.StreamData.Title,
+				Game:        tcInfo.StreamData.GameName,
+				StartTime:   tcInfo.StartTime,
+			})
+		} else if tcInfo.wentOffline(prevState) {
+			go ts.updateLiveRoles(ds, twitchChannel, false)
+			ts.publishSocketEvent(SocketOffline, twitchChannel, tcInfo)
+			plugins.Offline(plugins.OfflineEvent{
+				TwitchID:    twitchChannel,
+				Login:       tcInfo.Login,
+				DisplayName: tcInfo.DisplayName,
+				StartTime:   tcInfo.StartTime,
+				EndTime:     tcInfo.EndTime,
+			})
+		}
 	}
+	ts.setTags(tags)
 
-	delete(activeSessions, ds.State.SessionID)
+	sendNotifications(ctx, ts, ds)
 }
 
-func populateTwitchInfo(twitchChannel string, tcInfo *twitchChannelInfo, resp *helix.StreamsResponse) bool {
-	for _, streams := range resp.Data.Streams {
-		if streams.UserLogin == twitchChannel && streams.Type == "live" {
+func populateTwitchInfo(twitchChannel string, tcInfo *twitchChannelInfo, streamList []helix.Stream) bool {
+	for _, streams := range streamList {
+		if NormalizeLogin(streams.UserLogin) == twitchChannel && streams.Type == "live" {
 			tcInfo.StreamData = &streams
 			tcInfo.StartTime = streams.StartedAt
 			tcInfo.EndTime = time.Time{}
@@ -402,41 +739,106 @@ func populateTwitchInfo(twitchChannel string, tcInfo *twitchChannelInfo, resp *h
 	return false
 }
 
-func readGobFromDisk(path string, name string, o *map[string]*twitchChannelInfo) error {
-	if file, err := os.Open(path + "/" + name + ".gob"); err != nil {
-		return err
-	} else {
-		return gob.NewDecoder(file).Decode(o)
-	}
-}
-
 func remove(s []*discordChannel, i int) []*discordChannel {
 	s[len(s)-1], s[i] = s[i], s[len(s)-1]
 	return s[:len(s)-1]
 }
 
-func sendNotifications(ts *Session, ds *discordgo.Session) {
-	for _, tcInfo := range ts.twitchData {
-		if tcInfo.StreamData != nil && time.Since(tcInfo.StartTime) > constants.TwitchStateChangeTime {
+func sendNotifications(ctx context.Context, ts *Session, ds *discordgo.Session) {
+	announceSquads(ctx, ts, ds)
+
+	for twitchID, tcInfo := range ts.twitchData {
+		switch tcInfo.State {
+		case StateLive, StatePendingLive:
+			if tcInfo.State == StateLive {
+				ts.recordLiveEvent(twitchID, tcInfo)
+			}
 			for guild, discordChannels := range tcInfo.DiscordChannels {
-				if connected, available := guildStatus[guild]; available && connected {
+				if connected, available := getGuildStatus(guild); available && connected {
+					gds := discordFor(guild, ds)
+					layout := ts.embedLayout(guild)
+					channelInfo := ts.channelInfoFooter(guild)
+					names := ts.nameStyle(guild)
 					for _, discordChannel := range discordChannels {
+						profile := ts.getProfile(guild, discordChannel.Profile)
+						style := embedStyle{ts.embedColor(guild, discordChannel, tcInfo, profile), layout, channelInfo, names,
+							ts.hidesPreview(gds, guild, discordChannel.ChannelID, tcInfo), ts.hiatusDays(guild), ts.streaks(guild)}
+						muted := ts.isMuted(guild)
 						if !discordChannel.LiveNotificationSent {
-							discordChannel.LiveNotificationSent = true
-							go sendLiveNotification(ds, discordChannel, tcInfo)
+							// Registrations with a shorter debounce are announced while the stream is pending
+							if !ts.liveConfirmed(discordChannel, tcInfo) {
+								continue
+							}
+							// Delayed announcements are picked up by a later cycle
+							if !announceDue(discordChannel, tcInfo) {
+								continue
+							}
+							// Streams still live when a pause ends are announced then
+							if discordChannel.isPaused() {
+								recordOutcome(guild, discordChannel, tcInfo, OutcomeLive, ResultPaused, nil)
+								continue
+							}
+							if !profile.allowsGame(tcInfo.StreamData.GameName) {
+								recordOutcome(guild, discordChannel, tcInfo, OutcomeLive, ResultGameFiltered, nil)
+								continue
+							}
+							if ts.dropsOnly(guild) && !tcInfo.DropsEnabled {
+								recordOutcome(guild, discordChannel, tcInfo, OutcomeLive, ResultDropsFiltered, nil)
+								continue
+							}
+							if ts.matureFiltered(gds, guild, discordChannel.ChannelID, tcInfo) {
+								recordOutcome(guild, discordChannel, tcInfo, OutcomeLive, ResultMatureFiltered, nil)
+								continue
+							}
+							// Queued announcements are sent once the mute ends if the stream is still live
+							if muted {
+								recordOutcome(guild, discordChannel, tcInfo, OutcomeLive, ResultMuted, nil)
+								if ts.guilds[guild].MutePolicy == MuteDrop {
+									discordChannel.LiveNotificationSent = true
+								}
+								continue
+							}
+							profile = ts.withTagRoles(guild, tcInfo, profile)
+							// Only the featured streamer of a rotation is announced with a mention
+							if !ts.rotationPing(guild, twitchID, tcInfo) {
+								profile = profile.silenced()
+							}
+							a, guild, twitchID := ts.announce(discordChannel, tcInfo, profile, style), guild, twitchID
+							ts.goJob(func() { a.send(ctx, gds, guild, twitchID) })
 						} else if discordChannel.LiveMessageID != "" && time.Since(discordChannel.UpdateTime) > constants.TwitchLiveMessageUpdateTime {
-							go updateLiveNotification(ds, discordChannel, tcInfo)
+							n, dc, tci := ts.notify(discordChannel, tcInfo)
+							guild := guild
+							ts.goJob(func() { updateLiveNotification(ctx, gds, guild, n, dc, tci, style) })
+						} else if discordChannel.LiveMessageID != "" && !muted && reminderDue(discordChannel, tcInfo) {
+							// The reminder is counted right away so the next cycle does not send it again
+							discordChannel.LastReminder = time.Now().UTC()
+							discordChannel.RemindersSent++
+							n, dc, tci := ts.notify(discordChannel, tcInfo)
+							guild := guild
+							ts.goJob(func() { sendReminder(gds, guild, n, dc, tci, names) })
 						}
 					}
 				}
 			}
-		} else if tcInfo.StreamData == nil && time.Since(tcInfo.EndTime) > constants.TwitchStateChangeTime {
+		case StateOffline, StatePendingOffline:
 			for guild, discordChannels := range tcInfo.DiscordChannels {
-				if connected, available := guildStatus[guild]; available && connected {
+				if connected, available := getGuildStatus(guild); available && connected {
+					gds := discordFor(guild, ds)
+					names := ts.nameStyle(guild)
 					for _, discordChannel := range discordChannels {
+						// Registrations with a longer debounce keep their live message until it passes
+						if !ts.offlineConfirmed(discordChannel, tcInfo) {
+							continue
+						}
 						if discordChannel.LiveNotificationSent && discordChannel.LiveMessageID != "" {
 							discordChannel.LiveNotificationSent = false
-							go sendOfflineNotification(ds, discordChannel, tcInfo)
+							n, dc, tci := ts.notify(discordChannel, tcInfo)
+							guild, timezone := guild, ts.timezone(guild)
+							ts.goJob(func() { sendOfflineNotification(ctx, gds, guild, n, dc, tci, names, timezone) })
+						} else if discordChannel.LiveNotificationSent {
+							// Streams announced in a squad message or dropped during a mute have no live message to update
+							discordChannel.LiveNotificationSent = false
+							discordChannel.SquadMessageID = ""
 						}
 					}
 				}
@@ -445,52 +847,228 @@ func sendNotifications(ts *Session, ds *discordgo.Session) {
 	}
 }
 
-func sendLiveNotification(ds *discordgo.Session, dc *discordChannel, tci *twitchChannelInfo) {
-	if m, err := ds.ChannelMessageSendEmbed(dc.ChannelID, createDiscordLiveEmbedMessage(tci)); err != nil {
+// Registration a notification is sent for in the background. Notifications read copies of the registration and its
+// Twitch channel, since the poll keeps changing them, and write their results back with update.
+type notified struct {
+	ts  *Session
+	dc  *discordChannel
+	tci *twitchChannelInfo
+}
+
+// Returns copies of a registration and its Twitch channel for a notification sent in the background
+func (t *Session) notify(dc *discordChannel, tci *twitchChannelInfo) (notified, *discordChannel, *twitchChannelInfo) {
+	dcCopy, tciCopy := *dc, *tci
+	dcCopy.Templates = append([]string(nil), dc.Templates...)
+	tciCopy.DiscordChannels = nil
+	tciCopy.Tags = append([]string(nil), tci.Tags...)
+	tciCopy.GameList = make([]*gameInfo, len(tci.GameList))
+	for i, game := range tci.GameList {
+		g := *game
+		tciCopy.GameList[i] = &g
+	}
+
+	return notified{t, dc, tci}, &dcCopy, &tciCopy
+}
+
+// Live notification of a registration, sent in the background once it was prepared under the lock
+type liveAnnouncement struct {
+	n       notified
+	dc      *discordChannel
+	tci     *twitchChannelInfo
+	profile *Profile
+	style   embedStyle
+	refresh bool // Whether the description and follower count are refreshed before it is sent
+}
+
+// Marks a registration announced and prepares its live notification
+func (t *Session) announce(dc *discordChannel, tci *twitchChannelInfo, p *Profile, style embedStyle) *liveAnnouncement {
+	dc.LiveNotificationSent = true
+
+	// The description and follower count are refreshed for each announcement
+	a := &liveAnnouncement{profile: p.copy(), style: style, refresh: style.channelInfo && infoRefreshDue(tci)}
+	a.n, a.dc, a.tci = t.notify(dc, tci)
+	return a
+}
+
+// Sends a live notification prepared by announce
+func (a *liveAnnouncement) send(ctx context.Context, ds *discordgo.Session, guildID string, twitchID string) {
+	if a.refresh {
+		a.n.ts.refreshChannelInfo(guildID, twitchID, a.n, a.tci)
+	}
+	sendLiveNotification(ctx, ds, guildID, a.n, a.dc, a.tci, a.profile, a.style)
+}
+
+// Writes the results of a notification back to its registration and Twitch channel under the lock
+func (n notified) update(change func(dc *discordChannel, tci *twitchChannelInfo)) {
+	n.ts.mu.Lock()
+	defer n.ts.mu.Unlock()
+
+	change(n.dc, n.tci)
+}
+
+func sendLiveNotification(ctx context.Context, ds *discordgo.Session, guildID string, n notified, dc *discordChannel, tci *twitchChannelInfo, p *Profile, style embedStyle) {
+	defer crash.Recover("send_live_notification")
+
+	_, span := tracing.Span(ctx, "discord.send_live_notification",
+		attribute.String("twitch.channel", tci.DisplayName),
+		attribute.String("discord.channel_id", dc.ChannelID),
+		attribute.Int64("announcement.delay_ms", time.Since(tci.StartTime).Milliseconds()))
+	defer span.End()
+
+	if !claimAnnouncement(dc, tci) {
+		recordOutcome(guildID, dc, tci, OutcomeLive, ResultClaimed, nil)
+		return
+	}
+
+	content, allowedMentions := p.content(tci, dc.nextTemplate(), style.names)
+	n.update(func(orig *discordChannel, _ *twitchChannelInfo) { orig.NextTemplate = dc.NextTemplate })
+	embed := createDiscordLiveEmbedMessage(tci, style)
+	if !runAnnouncementScript(scripts.OnLive, guildID, dc, tci, &content, embed) {
+		recordOutcome(guildID, dc, tci, OutcomeLive, ResultScripted, nil)
+		return
+	}
+
+	sent := time.Now()
+	message := &discordgo.MessageSend{Content: content, Embed: embed, AllowedMentions: allowedMentions}
+	if embedWithinLimits(embed) {
+		message.Files = uploadPreview(embed)
+	} else {
+		utils.Log.WithField("twitch_channel", tci.Login).Warn("Live embed exceeds Discord's limits. Sending a plain message instead.")
+		message = &discordgo.MessageSend{Content: plainLiveMessage(content, tci, style.names), AllowedMentions: allowedMentions}
+	}
+
+	// The stream is still announced if Discord refuses the embed or the text sent with it
+	m, err := sendRespectingSlowmode(ds, dc.ChannelID, message)
+	if isBadRequest(err) && message.Embed != nil {
+		utils.Log.WithError(err).WithField("twitch_channel", tci.Login).Warn("Discord refused the live message. Sending a plain message instead.")
+		message = &discordgo.MessageSend{Content: plainLiveMessage(content, tci, style.names), AllowedMentions: allowedMentions}
+		m, err = sendRespectingSlowmode(ds, dc.ChannelID, message)
+	}
+
+	if err != nil {
 		utils.Log.WithError(err).Error("Error sending Discord message.")
+		tracing.RecordError(span, err)
+		recordOutcome(guildID, dc, tci, OutcomeLive, ResultFailed, err)
 	} else {
 		dc.LiveMessageID = m.ID
 		dc.UpdateTime = time.Now()
+		dc.PreviewUploaded = len(message.Files) > 0
+		dc.PlainMessage = message.Embed == nil
+		if dc.Pin {
+			pinLiveMessage(ds, dc)
+		}
+		n.update(func(orig *discordChannel, _ *twitchChannelInfo) {
+			orig.LiveMessageID = dc.LiveMessageID
+			orig.UpdateTime = dc.UpdateTime
+			orig.PreviewUploaded = dc.PreviewUploaded
+			orig.PlainMessage = dc.PlainMessage
+			orig.Pinned = dc.Pinned
+		})
+		recordDelivery(guildID, time.Since(sent))
+		stats.AnnouncementSent()
+		recordOutcome(guildID, dc, tci, OutcomeLive, ResultSent, nil)
 	}
 }
 
-func sendOfflineNotification(ds *discordgo.Session, dc *discordChannel, tci *twitchChannelInfo) {
-	tci.GameList[len(tci.GameList)-1].EndTime = tci.EndTime
+func sendOfflineNotification(ctx context.Context, ds *discordgo.Session, guildID string, n notified, dc *discordChannel, tci *twitchChannelInfo, names string, loc *time.Location) {
+	defer crash.Recover("send_offline_notification")
+
+	_, span := tracing.Span(ctx, "discord.send_offline_notification",
+		attribute.String("twitch.channel", tci.DisplayName),
+		attribute.String("discord.channel_id", dc.ChannelID))
+	defer span.End()
 
-	if _, err := ds.ChannelMessageEditEmbed(dc.ChannelID, dc.LiveMessageID, createDiscordOfflineEmbedMessage(tci)); err != nil {
+	if len(tci.GameList) > 0 {
+		tci.GameList[len(tci.GameList)-1].EndTime = tci.EndTime
+	}
+
+	// A suppressed offline summary leaves the live message as it is
+	embed := createDiscordOfflineEmbedMessage(tci, names, loc)
+	plain := customizeOffline(dc, tci, names, embed)
+	if !runAnnouncementScript(scripts.OnOffline, guildID, dc, tci, nil, embed) {
+		recordOutcome(guildID, dc, tci, OutcomeOffline, ResultScripted, nil)
+	} else if err := editOfflineMessage(ds, dc, embed, plain); err != nil {
 		utils.Log.WithError(err).Error("Error updating Discord message.")
+		tracing.RecordError(span, err)
+		recordOutcome(guildID, dc, tci, OutcomeOffline, ResultFailed, err)
+	} else {
+		recordOutcome(guildID, dc, tci, OutcomeOffline, ResultSent, nil)
 	}
 
-	dc.LiveMessageID = ""
-	dc.UpdateTime = time.Time{}
-	tci.GameList = nil
+	deleteReminder(ds, dc)
+	unpinLiveMessage(ds, dc)
+
+	n.update(func(dc *discordChannel, tci *twitchChannelInfo) {
+		dc.ReminderMessageID = ""
+		dc.RemindersSent = 0
+		dc.Pinned = false
+		dc.LiveMessageID = ""
+		dc.UpdateTime = time.Time{}
+		dc.PreviewUploaded = false
+		dc.PlainMessage = false
+		tci.GameList = nil
+	})
 }
 
-func updateLiveNotification(ds *discordgo.Session, dc *discordChannel, tci *twitchChannelInfo) {
-	if m, err := ds.ChannelMessageEditEmbed(dc.ChannelID, dc.LiveMessageID, createDiscordLiveEmbedMessage(tci)); err != nil {
-		dc.LiveNotificationSent = false
+func updateLiveNotification(ctx context.Context, ds *discordgo.Session, guildID string, n notified, dc *discordChannel, tci *twitchChannelInfo, style embedStyle) {
+	defer crash.Recover("update_live_notification")
+
+	_, span := tracing.Span(ctx, "discord.update_live_notification",
+		attribute.String("twitch.channel", tci.DisplayName),
+		attribute.String("discord.channel_id", dc.ChannelID))
+	defer span.End()
+
+	// Plain messages sent because Discord refused the embed are left as they are
+	if dc.PlainMessage {
+		n.update(func(dc *discordChannel, _ *twitchChannelInfo) { dc.UpdateTime = time.Now().UTC() })
+		return
+	}
+
+	embed := createDiscordLiveEmbedMessage(tci, style)
+	keepUploadedPreview(dc, embed)
+	if m, err := ds.ChannelMessageEditEmbed(dc.ChannelID, dc.LiveMessageID, embed); isBadRequest(err) {
+		utils.Log.WithError(err).WithField("twitch_channel", tci.Login).Warn("Discord refused the updated live embed. The live message is no longer updated.")
+		n.update(func(dc *discordChannel, _ *twitchChannelInfo) {
+			dc.PlainMessage = true
+			dc.UpdateTime = time.Now().UTC()
+		})
+	} else if err != nil {
 		utils.Log.WithError(err).Error("Error updating Discord message.")
+		tracing.RecordError(span, err)
+		recordOutcome(guildID, dc, tci, OutcomeUpdate, ResultFailed, err)
+		n.update(func(dc *discordChannel, _ *twitchChannelInfo) { dc.LiveNotificationSent = false })
 	} else {
-		dc.LiveMessageID = m.ID
-		dc.UpdateTime = time.Now().UTC()
+		n.update(func(dc *discordChannel, _ *twitchChannelInfo) {
+			dc.LiveMessageID = m.ID
+			dc.UpdateTime = time.Now().UTC()
+		})
+	}
+}
+
+func (t *Session) writeDataToDisk() {
+	if err := utils.WriteGobToDisk(utils.DataDir, t.name, t.twitchData); err != nil {
+		utils.Log.WithError(err).Error("Error writing data to disk.")
 	}
 }
 
 func validateAndRefreshAuthToken(ts *Session) bool {
+	ts.tokenMu.Lock()
+	defer ts.tokenMu.Unlock()
+
 	// Validate and refresh Twitch authorization token, if token valid
-	if isValid, resp, err := ts.client.ValidateToken(ts.client.GetAppAccessToken()); err != nil {
+	if isValid, resp, err := ts.validateToken(); err != nil {
 		utils.Log.WithError(err).Error("Failed to validate Twitch authorization token.")
 	} else if !isValid {
-		ts.isConnected = false
-		for !ts.isConnected {
+		ts.setConnected(false)
+		for !ts.connected() {
 			utils.Log.Debug("Attempting to get new Twitch authentication token.")
-			if ts.GetAuthToken() != nil {
+			if ts.requestAuthToken() != nil {
 				utils.Log.WithError(err).Error("Failed to get new Twitch authorization token.")
 				break
 			}
 		}
 
-		if ts.isConnected {
+		if ts.connected() {
 			utils.Log.Debug("Successfully got new Twitch authentication token.")
 			return true
 		}
@@ -502,3 +1080,66 @@ func validateAndRefreshAuthToken(ts *Session) bool {
 
 	return false
 }
+
+// Looks up the display name, logo, offline banner and description of every monitored channel,
+// since streamers change them after registration. Channels whose account was not found are flagged as orphaned.
+func (t *Session) refreshMetadata(ds *discordgo.Session) {
+	var logins []string
+	t.mu.RLock()
+	for twitchID := range t.twitchData {
+		logins = append(logins, twitchID)
+	}
+	t.mu.RUnlock()
+	if len(logins) == 0 || !validateAndRefreshAuthToken(t) {
+		return
+	}
+
+	changed := false
+	var checked []string
+	found := make(map[string]bool)
+	for start := 0; start < len(logins); start += constants.TwitchQueryBatchSize {
+		end := start + constants.TwitchQueryBatchSize
+		if end > len(logins) {
+			end = len(logins)
+		}
+
+		t.limiter.wait()
+		resp, err := t.helixClient().GetUsers(&helix.UsersParams{Logins: logins[start:end]})
+		if err != nil {
+			utils.Log.WithError(err).Error("Failed to query twitch.")
+			break
+		}
+		t.limiter.update(&resp.ResponseCommon)
+		if resp.StatusCode != 200 {
+			utils.Log.WithField("StatusCode", resp.StatusCode).Error("HTTP Error returned from twitch.")
+			break
+		}
+		checked = append(checked, logins[start:end]...)
+
+		t.mu.Lock()
+		for _, user := range resp.Data.Users {
+			tci := t.twitchData[user.Login]
+			if tci == nil {
+				continue
+			}
+			found[user.Login] = true
+			if tci.DisplayName != user.DisplayName || tci.LogoURL != user.ProfileImageURL ||
+				tci.OfflineImageURL != user.OfflineImageURL || tci.Description != user.Description {
+				tci.DisplayName = user.DisplayName
+				tci.LogoURL = user.ProfileImageURL
+				tci.OfflineImageURL = user.OfflineImageURL
+				tci.Description = user.Description
+				changed = true
+			}
+		}
+		t.mu.Unlock()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if changed {
+		t.writeDataToDisk()
+	}
+	t.updateOrphans(ds, checked, found)
+}
@@ -0,0 +1,88 @@
+package twitch
+
+import (
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+// A registration removed from a Discord channel that can be restored until UnregisterUndoTime passes
+type removedChannel struct {
+	TwitchID     string          // Twitch channel the registration monitored
+	DisplayName  string          // Twitch display name at the time of removal
+	LogoURL      string          // URL of Twitch logo at the time of removal
+	OfflineImage string          // URL of the Twitch offline banner at the time of removal
+	Channel      *discordChannel // The removed registration
+	RemovedAt    time.Time       // Time the registration was removed
+}
+
+// Records a registration that is being removed so it can be restored later
+func (t *Session) softDelete(twitchID string, discordGuildID string, dc *discordChannel) {
+	gs := t.getGuildSettings(discordGuildID)
+	gs.Removed = append(pruneRemoved(gs.Removed), &removedChannel{
+		TwitchID:     twitchID,
+		DisplayName:  t.twitchData[twitchID].DisplayName,
+		LogoURL:      t.twitchData[twitchID].LogoURL,
+		OfflineImage: t.twitchData[twitchID].OfflineImageURL,
+		Channel:      dc,
+		RemovedAt:    time.Now().UTC(),
+	})
+
+	t.writeGuildsToDisk()
+}
+
+// Restores the most recently removed registration of a guild.
+// Returns the Twitch channel and Discord channel of the restored registration.
+func (t *Session) UndoUnregister(discordGuildID string) (twitchID string, discordChannelID string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gs := t.getGuildSettings(discordGuildID)
+	gs.Removed = pruneRemoved(gs.Removed)
+
+	if len(gs.Removed) == 0 {
+		return "", "", constants.ErrNothingToUndo
+	}
+
+	rc := gs.Removed[len(gs.Removed)-1]
+	gs.Removed = gs.Removed[:len(gs.Removed)-1]
+	t.writeGuildsToDisk()
+
+	if t.getChannelIdx(rc.TwitchID, discordGuildID, rc.Channel.ChannelID) >= 0 {
+		return rc.TwitchID, rc.Channel.ChannelID, constants.ErrTwitchUserRegistered
+	}
+
+	if t.twitchData[rc.TwitchID] == nil {
+		t.twitchData[rc.TwitchID] = &twitchChannelInfo{
+			Login:           rc.TwitchID,
+			DisplayName:     rc.DisplayName,
+			LogoURL:         rc.LogoURL,
+			OfflineImageURL: rc.OfflineImage,
+			DiscordChannels: make(map[string][]*discordChannel),
+		}
+	}
+
+	// The stream may have changed state since removal so start from a clean notification state
+	rc.Channel.LiveMessageID = ""
+	rc.Channel.UpdateTime = time.Time{}
+	rc.Channel.LiveNotificationSent = false
+
+	t.twitchData[rc.TwitchID].DiscordChannels[discordGuildID] = append(t.twitchData[rc.TwitchID].DiscordChannels[discordGuildID], rc.Channel)
+
+	// Writes the data to the disk in case of crash
+	t.writeDataToDisk()
+
+	return rc.TwitchID, rc.Channel.ChannelID, nil
+}
+
+// Drops removed registrations that can no longer be restored
+func pruneRemoved(removed []*removedChannel) []*removedChannel {
+	kept := removed[:0]
+	for _, rc := range removed {
+		if time.Since(rc.RemovedAt) < constants.UnregisterUndoTime {
+			kept = append(kept, rc)
+		}
+	}
+
+	return kept
+}
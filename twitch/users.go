@@ -0,0 +1,196 @@
+package twitch
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nicklaw5/helix"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/helixcache"
+)
+
+// userLookups rate limits and caches the GetUsers lookups of single channels, e.g. of channels being registered,
+// so bulk or repeated registrations stay cheap
+type userLookups struct {
+	mu     sync.Mutex
+	tokens float64                // Lookups that can be issued right away
+	refill time.Time              // Time tokens were last refilled
+	cache  map[string]*cachedUser // Map of twitch channel to its recently looked up user
+}
+
+type cachedUser struct {
+	user    *helix.User // Looked up user, nil if no user has the login
+	fetched time.Time   // Time the user was looked up
+}
+
+func newUserLookups() *userLookups {
+	return &userLookups{
+		tokens: constants.UserLookupBurst,
+		refill: time.Now(),
+		cache:  make(map[string]*cachedUser),
+	}
+}
+
+// Returns the cached lookup of a login, or nil if it was not looked up recently
+func (u *userLookups) cached(login string) *cachedUser {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if c := u.cache[login]; c != nil && time.Since(c.fetched) < constants.UserLookupCacheTime {
+		return c
+	}
+	delete(u.cache, login)
+	return nil
+}
+
+func (u *userLookups) forget(login string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	delete(u.cache, login)
+}
+
+func (u *userLookups) store(login string, user *helix.User) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.cache[login] = &cachedUser{user: user, fetched: time.Now()}
+}
+
+// Takes a token from the bucket, waiting for one to refill.
+// Gives up if the wait would exceed UserLookupMaxWait.
+func (u *userLookups) take() error {
+	u.mu.Lock()
+	now := time.Now()
+	u.tokens += float64(now.Sub(u.refill)) / float64(constants.UserLookupInterval)
+	if u.tokens > constants.UserLookupBurst {
+		u.tokens = constants.UserLookupBurst
+	}
+	u.refill = now
+
+	// Waiting callers already own the tokens that refill before them
+	u.tokens--
+	wait := time.Duration(-u.tokens * float64(constants.UserLookupInterval))
+	if wait > constants.UserLookupMaxWait {
+		u.tokens++
+		u.mu.Unlock()
+		return constants.ErrLookupRateLimited
+	}
+	u.mu.Unlock()
+
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+	return nil
+}
+
+// Looks up a Twitch user by login. Returns ErrTwitchUserDoesNotExist if no user has the login.
+func (t *Session) lookupUser(login string) (*helix.User, error) {
+	return t.queryUser(login, &helix.UsersParams{Logins: []string{login}})
+}
+
+// Looks up a Twitch user by numeric user ID. Returns ErrTwitchUserDoesNotExist if no user has the ID.
+func (t *Session) lookupUserID(userID string) (*helix.User, error) {
+	return t.queryUser("id:"+userID, &helix.UsersParams{IDs: []string{userID}})
+}
+
+func (t *Session) queryUser(key string, params *helix.UsersParams) (*helix.User, error) {
+	if c := t.users.cached(key); c != nil {
+		if c.user == nil {
+			return nil, constants.ErrTwitchUserDoesNotExist
+		}
+		return c.user, nil
+	}
+
+	if err := t.users.take(); err != nil {
+		return nil, err
+	}
+
+	t.limiter.wait()
+	resp, err := t.helixClient().GetUsers(params)
+	if err != nil {
+		return nil, err
+	}
+	t.limiter.update(&resp.ResponseCommon)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("%w: %v %v", constants.ErrTwitchQueryFailed, resp.StatusCode, resp.ErrorMessage)
+	}
+
+	if len(resp.Data.Users) == 0 {
+		t.users.store(key, nil)
+		return nil, constants.ErrTwitchUserDoesNotExist
+	}
+
+	user := resp.Data.Users[0]
+	t.users.store(key, &user)
+	return &user, nil
+}
+
+// Looks up the display name, logo, offline banner and description of a monitored channel again, e.g. after a rebrand.
+// Returns the refreshed display name.
+func (t *Session) RefreshChannel(twitchID string) (string, error) {
+	t.mu.RLock()
+	registered := t.twitchData[twitchID] != nil
+	t.mu.RUnlock()
+	if !registered {
+		return "", constants.ErrTwitchUserNotRegistered
+	}
+	if !validateAndRefreshAuthToken(t) {
+		return "", constants.ErrInvalidToken
+	}
+
+	t.users.forget(twitchID)
+	helixcache.Forget("login", twitchID)
+	user, err := t.lookupUser(twitchID)
+	if err != nil {
+		return "", err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tci := t.twitchData[twitchID]
+	if tci == nil {
+		return "", constants.ErrTwitchUserNotRegistered
+	}
+	tci.DisplayName = user.DisplayName
+	tci.LogoURL = user.ProfileImageURL
+	tci.OfflineImageURL = user.OfflineImageURL
+	tci.Description = user.Description
+
+	t.writeDataToDisk()
+
+	return tci.DisplayName, nil
+}
+
+var validLogin = regexp.MustCompile(`^[a-z0-9_]{1,25}$`)
+
+// Returns the login of a twitch channel given as a login, display name, twitch.tv URL or numeric user ID.
+// Numbers are looked up as user IDs unless a channel with that login is registered already or no user has the ID.
+func (t *Session) ResolveChannel(name string) (string, error) {
+	login := NormalizeLogin(name)
+	if !validLogin.MatchString(login) {
+		return "", constants.ErrInvalidTwitchChannel
+	}
+	t.mu.RLock()
+	registered := t.twitchData[login] != nil
+	t.mu.RUnlock()
+	if strings.Trim(login, "0123456789") != "" || registered {
+		return login, nil
+	}
+
+	if !validateAndRefreshAuthToken(t) {
+		return "", constants.ErrInvalidToken
+	}
+	user, err := t.lookupUserID(login)
+	if errors.Is(err, constants.ErrTwitchUserDoesNotExist) {
+		return login, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return strings.ToLower(user.Login), nil
+}
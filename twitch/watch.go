@@ -0,0 +1,71 @@
+package twitch
+
+import (
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Registers a Twitch channel to a Discord channel until the duration has passed, for one-off events such as a game
+// night. Watching a channel that is already watched extends the watch, while permanent registrations are left alone.
+// Returns when the watch ends.
+func (t *Session) WatchChannel(twitchID string, discordGuildID string, discordChannelID string, discordChannelName string, duration time.Duration) (time.Time, error) {
+	if duration <= 0 || duration > constants.MaxWatchDuration {
+		return time.Time{}, constants.ErrInvalidWatchTime
+	}
+
+	twitchID = NormalizeLogin(twitchID)
+	user, err := t.lookupNewChannel(twitchID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	idx := t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	if idx >= 0 {
+		if t.twitchData[twitchID].DiscordChannels[discordGuildID][idx].WatchUntil.IsZero() {
+			return time.Time{}, constants.ErrTwitchUserRegistered
+		}
+	} else {
+		if err := t.registerChannel(twitchID, discordGuildID, discordChannelID, discordChannelName, user); err != nil {
+			return time.Time{}, err
+		}
+		idx = t.getChannelIdx(twitchID, discordGuildID, discordChannelID)
+	}
+
+	dc := t.twitchData[twitchID].DiscordChannels[discordGuildID][idx]
+	dc.WatchUntil = time.Now().Add(duration)
+
+	t.writeDataToDisk()
+	return dc.WatchUntil, nil
+}
+
+// Removes the registrations whose watch has ended. A registration announcing a stream is kept until the stream ends,
+// so its live message is ended like any other. Removed registrations can be restored with undo.
+func (t *Session) expireWatches() {
+	type registration struct{ login, guildID, channelID string }
+	var expired []registration
+
+	now := time.Now()
+	for login, tci := range t.twitchData {
+		for guildID, dcs := range tci.DiscordChannels {
+			for _, dc := range dcs {
+				if !dc.WatchUntil.IsZero() && now.After(dc.WatchUntil) && !dc.LiveNotificationSent {
+					expired = append(expired, registration{login, guildID, dc.ChannelID})
+				}
+			}
+		}
+	}
+
+	for _, r := range expired {
+		if t.unregisterChannel(r.login, r.guildID, r.channelID) {
+			utils.Log.WithFields(logrus.Fields{
+				"twitch_channel": r.login,
+				"channel_id":     r.channelID,
+				"server_id":      r.guildID}).Info("Removed registration whose watch ended.")
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+	"sync"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Prefix identifying files written with encryption enabled, followed by the salt the key was derived with
+var encryptedHeader = []byte("DTBENC2\n")
+
+// Prefix of files encrypted with an unsalted SHA-256 of the passphrase, which are still read but no longer written
+var legacyEncryptedHeader = []byte("DTBENC1\n")
+
+// scrypt parameters deriving AES-256 keys from the passphrase
+const (
+	saltSize = 16
+	scryptN  = 1 << 15
+	scryptR  = 8
+	scryptP  = 1
+)
+
+var encryption struct {
+	mu         sync.Mutex
+	passphrase string            // Passphrase persisted data is encrypted with, empty if encryption is disabled
+	salt       []byte            // Salt of the key new data is encrypted with
+	keys       map[string][]byte // Map of salts to the keys derived from the passphrase with them
+}
+
+// Enables AES-GCM encryption of persisted data using keys derived from passphrase with scrypt and a random salt
+// stored in each file. An empty passphrase disables encryption.
+func SetEncryptionKey(passphrase string) {
+	encryption.mu.Lock()
+	defer encryption.mu.Unlock()
+
+	encryption.passphrase = passphrase
+	encryption.salt = nil
+	encryption.keys = make(map[string][]byte)
+}
+
+// Returns whether persisted data is encrypted
+func encryptionEnabled() bool {
+	encryption.mu.Lock()
+	defer encryption.mu.Unlock()
+
+	return encryption.passphrase != ""
+}
+
+func encrypt(plaintext []byte) ([]byte, error) {
+	encryption.mu.Lock()
+	if encryption.passphrase == "" {
+		encryption.mu.Unlock()
+		return plaintext, nil
+	}
+	// Every file written in a run shares one salt so the key is only derived once
+	if encryption.salt == nil {
+		salt := make([]byte, saltSize)
+		if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+			encryption.mu.Unlock()
+			return nil, err
+		}
+		encryption.salt = salt
+	}
+	salt := encryption.salt
+	key, err := deriveKey(salt)
+	encryption.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	out := append([]byte{}, encryptedHeader...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, plaintext, nil), nil
+}
+
+// Decrypts data written by encrypt. Data without the encrypted header is returned as is
+// so files written before encryption was enabled can still be read.
+func decrypt(data []byte) ([]byte, error) {
+	legacy := bytes.HasPrefix(data, legacyEncryptedHeader)
+	if !legacy && !bytes.HasPrefix(data, encryptedHeader) {
+		return data, nil
+	}
+
+	encryption.mu.Lock()
+	if encryption.passphrase == "" {
+		encryption.mu.Unlock()
+		return nil, constants.ErrMissingEncryptionKey
+	}
+
+	var key []byte
+	var err error
+	data = data[len(encryptedHeader):]
+	if legacy {
+		sum := sha256.Sum256([]byte(encryption.passphrase))
+		key = sum[:]
+	} else if len(data) < saltSize {
+		encryption.mu.Unlock()
+		return nil, constants.ErrCorruptedData
+	} else {
+		key, err = deriveKey(data[:saltSize])
+		data = data[saltSize:]
+	}
+	encryption.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, constants.ErrCorruptedData
+	}
+
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, constants.ErrCorruptedData
+	}
+
+	return plaintext, nil
+}
+
+// Returns the key derived from the passphrase with salt. Must be called with encryption.mu held.
+func deriveKey(salt []byte) ([]byte, error) {
+	if key, ok := encryption.keys[string(salt)]; ok {
+		return key, nil
+	}
+
+	key, err := scrypt.Key([]byte(encryption.passphrase), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+	encryption.keys[string(salt)] = key
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+func TestEncryptRoundTrip(t *testing.T) {
+	defer SetEncryptionKey("")
+	SetEncryptionKey("passphrase")
+
+	data, err := encrypt([]byte("twitch data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, encryptedHeader) || bytes.Contains(data, []byte("twitch data")) {
+		t.Fatal("data was not encrypted")
+	}
+
+	plaintext, err := decrypt(data)
+	if err != nil || string(plaintext) != "twitch data" {
+		t.Fatalf("got %q, %v", plaintext, err)
+	}
+
+	// A new key has a new salt, yet reads data encrypted with the old one
+	SetEncryptionKey("passphrase")
+	again, err := encrypt([]byte("twitch data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	salt := func(b []byte) []byte { return b[len(encryptedHeader) : len(encryptedHeader)+saltSize] }
+	if bytes.Equal(salt(data), salt(again)) {
+		t.Error("salt was reused after the key was set again")
+	}
+	if plaintext, err := decrypt(data); err != nil || string(plaintext) != "twitch data" {
+		t.Errorf("got %q, %v", plaintext, err)
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	defer SetEncryptionKey("")
+	SetEncryptionKey("passphrase")
+	data, err := encrypt([]byte("twitch data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SetEncryptionKey("wrong")
+	if _, err := decrypt(data); !errors.Is(err, constants.ErrCorruptedData) {
+		t.Errorf("wrong key: got %v", err)
+	}
+	SetEncryptionKey("")
+	if _, err := decrypt(data); !errors.Is(err, constants.ErrMissingEncryptionKey) {
+		t.Errorf("missing key: got %v", err)
+	}
+	SetEncryptionKey("passphrase")
+	if _, err := decrypt(data[:len(encryptedHeader)+4]); !errors.Is(err, constants.ErrCorruptedData) {
+		t.Errorf("truncated data: got %v", err)
+	}
+
+	// Unencrypted data is read as is
+	if plaintext, err := decrypt([]byte("plain")); err != nil || string(plaintext) != "plain" {
+		t.Errorf("unencrypted data: got %q, %v", plaintext, err)
+	}
+}
+
+func TestDecryptLegacy(t *testing.T) {
+	defer SetEncryptionKey("")
+	SetEncryptionKey("passphrase")
+
+	// Written the way files were encrypted before keys were salted
+	key := sha256.Sum256([]byte("passphrase"))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	data := gcm.Seal(append(append([]byte{}, legacyEncryptedHeader...), nonce...), nonce, []byte("old data"), nil)
+
+	if plaintext, err := decrypt(data); err != nil || string(plaintext) != "old data" {
+		t.Fatalf("got %q, %v", plaintext, err)
+	}
+}
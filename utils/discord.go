@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/config"
+)
+
+var (
+	inviteRegex        = regexp.MustCompile(`(?i)(https?://)?(www\.)?(discord\.(gg|io|me|li)|discord(app)?\.com/invite)/\S+`)
+	userMentionRegex   = regexp.MustCompile(`<@!?(\d+)>`)
+	roleMentionRegex   = regexp.MustCompile(`<@&(\d+)>`)
+	markdownEscaper    = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "~", `\~`, "`", "\\`", "|", `\|`, ">", `\>`, "#", `\#`, "[", `\[`, "]", `\]`)
+	mentionNeutralizer = strings.NewReplacer("@everyone", "@\u200beveryone", "@here", "@\u200bhere", "<@", "<@\u200b", "<#", "<#\u200b")
+)
+
+// Sends a notice to the operator channel set in the config file, if there is one
+func NotifyOperator(s *discordgo.Session, message string) {
+	if config.Settings.OperatorChannelID == "" {
+		return
+	}
+
+	if _, err := s.ChannelMessageSend(config.Settings.OperatorChannelID, message); err != nil {
+		Log.WithError(err).Error("Failed to send operator notice to Discord.")
+	}
+}
+
+// Makes text from Twitch, such as stream titles, safe to put in a Discord message. Markdown is escaped so the
+// text is shown as written, mentions are broken so they cannot ping and invite links are removed.
+func SanitizeText(text string) string {
+	text = inviteRegex.ReplaceAllString(text, "(invite removed)")
+	text = markdownEscaper.Replace(text)
+	return mentionNeutralizer.Replace(text)
+}
+
+// Returns the mentions a message may ping: only those written in text, which must not contain text from Twitch
+func AllowedMentions(text string) *discordgo.MessageAllowedMentions {
+	allowed := &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}}
+	if strings.Contains(text, "@everyone") || strings.Contains(text, "@here") {
+		allowed.Parse = append(allowed.Parse, discordgo.AllowedMentionTypeEveryone)
+	}
+	for _, m := range roleMentionRegex.FindAllStringSubmatch(text, -1) {
+		allowed.Roles = append(allowed.Roles, m[1])
+	}
+	for _, m := range userMentionRegex.FindAllStringSubmatch(text, -1) {
+		allowed.Users = append(allowed.Users, m[1])
+	}
+	return allowed
+}
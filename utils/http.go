@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Client used for every outgoing HTTP request of the bot
+var HTTPClient = &http.Client{Timeout: time.Second * 30}
+
+// Sets a default User-Agent on requests that don't set one
+type userAgentTransport struct {
+	base      http.RoundTripper
+	userAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Header.Get("User-Agent") == "" {
+		r = r.Clone(r.Context())
+		r.Header.Set("User-Agent", t.userAgent)
+	}
+	return t.base.RoundTrip(r)
+}
+
+// Configures the shared HTTP client. An empty proxy falls back to the HTTP_PROXY and HTTPS_PROXY environment
+// variables and caFile adds a PEM certificate authority trusted next to the system ones.
+func ConfigureHTTPClient(timeout time.Duration, proxy string, caFile string, userAgent string) error {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			return err
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return err
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.New(caFile + " contains no PEM certificates")
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+
+	HTTPClient = &http.Client{
+		Timeout:   timeout,
+		Transport: &userAgentTransport{base: transport, userAgent: userAgent},
+	}
+	return nil
+}
+
+// Returns whether a request carries token as its bearer token. An empty token never matches.
+func ValidBearerToken(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+
+	given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
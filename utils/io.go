@@ -1,24 +1,304 @@
 package utils
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/gob"
 	"errors"
+	"fmt"
 	"os"
+	"strings"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-func WriteGobToDisk(path string, name string, o interface{}) error {
+// Directory persisted data is read from and written to
+var DataDir = constants.DataPath
+
+// Sets the directory persisted data is stored in, creating it if it doesn't exist.
+// Returns an error if the directory can't be written to.
+func SetDataDir(path string) error {
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return fmt.Errorf("data directory %v could not be created: %w", path, err)
+	}
+
+	file, err := os.CreateTemp(path, ".writecheck")
+	if err != nil {
+		return fmt.Errorf("data directory %v is not writable: %w", path, err)
+	}
+	file.Close()
+	os.Remove(file.Name())
+
+	DataDir = path
+	return nil
+}
+
+func ReadGobFromDisk(path string, name string, o interface{}) (err error) {
+	_, span := tracing.Span(context.Background(), "storage.read", attribute.String("storage.file", name))
+	defer func() {
+		if !errors.Is(err, os.ErrNotExist) {
+			tracing.RecordError(span, err)
+		}
+		span.End()
+	}()
+
+	data, err := ReadFileFromDisk(path, name)
+	if err != nil {
+		return err
+	}
+
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(o)
+}
+
+// Returns an error if a persisted file exists but can't be read or decrypted
+func VerifyFileOnDisk(path string, name string) error {
+	if _, err := ReadFileFromDisk(path, name); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Returns the names of every persisted file in path, without the .gob extension
+func ListGobFiles(path string) ([]string, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".gob") {
+			names = append(names, strings.TrimSuffix(entry.Name(), ".gob"))
+		}
+	}
+	return names, nil
+}
+
+// Returns the names of every append-only log file in path, without the .log extension
+func ListLogFiles(path string) ([]string, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".log") {
+			names = append(names, strings.TrimSuffix(entry.Name(), ".log"))
+		}
+	}
+	return names, nil
+}
+
+// Returns the decrypted contents of a persisted file
+func ReadFileFromDisk(path string, name string) ([]byte, error) {
+	data, err := os.ReadFile(path + "/" + name + ".gob")
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err = decrypt(data); err != nil {
+		return nil, fmt.Errorf("%v/%v.gob: %w", path, name, err)
+	}
+	return data, nil
+}
+
+// Encrypts data if encryption is enabled and writes it to a persisted file
+func WriteFileToDisk(path string, name string, data []byte) error {
+	data, err := encrypt(data)
+	if err != nil {
+		return err
+	}
+
+	// Written to a temporary file first so a crash never leaves a partial file behind
+	tmp := path + "/" + name + ".gob.tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path+"/"+name+".gob")
+}
+
+func WriteGobToDisk(path string, name string, o interface{}) (err error) {
+	_, span := tracing.Span(context.Background(), "storage.write", attribute.String("storage.file", name))
+	defer func() {
+		recordWrite(err)
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
 	//check if file exists and if not creates a directory for it
 	if _, err := os.Stat(path + "/" + name + ".gob"); errors.Is(err, os.ErrNotExist) {
-		if err := os.MkdirAll(path, 0755); err != nil {
+		if err := os.MkdirAll(path, 0700); err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(o); err != nil {
+		return err
+	}
+
+	return WriteFileToDisk(path, name, buf.Bytes())
+}
+
+// Appends a line to an append-only log file. Lines are encrypted and base64 encoded if encryption is enabled.
+func AppendLineToDisk(path string, name string, line []byte) error {
+	if encryptionEnabled() {
+		data, err := encrypt(line)
+		if err != nil {
 			return err
 		}
+		line = []byte(base64.StdEncoding.EncodeToString(data))
 	}
 
-	file, err := os.Create(path + "/" + name + ".gob")
+	file, err := os.OpenFile(path+"/"+name+".log", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
 	if err != nil {
 		return err
 	}
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Replaces the lines of an append-only log file, e.g. to delete some of them
+func WriteLinesToDisk(path string, name string, lines [][]byte) error {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		if encryptionEnabled() {
+			data, err := encrypt(line)
+			if err != nil {
+				return err
+			}
+			line = []byte(base64.StdEncoding.EncodeToString(data))
+		}
+		buf.Write(append(line, '\n'))
+	}
+
+	// Written to a temporary file first so a crash never leaves a partial log behind
+	tmp := path + "/" + name + ".log.tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path+"/"+name+".log")
+}
+
+// Returns the decrypted lines of an append-only log file
+func ReadLinesFromDisk(path string, name string) ([][]byte, error) {
+	file, err := os.Open(path + "/" + name + ".log")
+	if err != nil {
+		return nil, err
+	}
 	defer file.Close()
 
-	return gob.NewEncoder(file).Encode(o)
+	lines := [][]byte{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		// Lines written without encryption are stored as is
+		if line[0] != '{' {
+			data, err := base64.StdEncoding.DecodeString(string(line))
+			if err != nil {
+				return nil, fmt.Errorf("%v/%v.log: %w", path, name, constants.ErrCorruptedData)
+			}
+			if line, err = decrypt(data); err != nil {
+				return nil, fmt.Errorf("%v/%v.log: %w", path, name, err)
+			}
+		}
+
+		lines = append(lines, append([]byte{}, line...))
+	}
+
+	return lines, scanner.Err()
+}
+
+// Claims the lock file name in path for owner and returns the owner holding it, which is owner if the lock was free or
+// already held by owner. The owner is written to a temporary file that is then linked into place, so a lock file
+// never exists without its owner and only one of several processes sharing the directory claims a lock.
+func ClaimLock(path string, name string, owner string) (string, error) {
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(path, "."+name+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(owner); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	// Linking fails if the lock exists, unlike renaming, which would replace it
+	err = os.Link(tmp.Name(), path+"/"+name+".lock")
+	if err == nil {
+		return owner, nil
+	}
+	if !errors.Is(err, os.ErrExist) {
+		return "", err
+	}
+
+	holder, err := os.ReadFile(path + "/" + name + ".lock")
+	return string(holder), err
+}
+
+// Removes the lock files in path whose name without the .lock extension ends with suffix.
+// Returns the number of lock files removed.
+func RemoveLocks(path string, suffix string) (int, error) {
+	entries, err := os.ReadDir(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), suffix+".lock") {
+			continue
+		}
+		if err := os.Remove(path + "/" + entry.Name()); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// Removes the lock files in path claimed longer than maxAge ago, along with temporary files left by interrupted claims
+func PruneLocks(path string, maxAge time.Duration) error {
+	entries, err := os.ReadDir(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || (!strings.HasSuffix(entry.Name(), ".lock") && !strings.HasSuffix(entry.Name(), ".tmp")) {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && time.Since(info.ModTime()) > maxAge {
+			if err := os.Remove(path + "/" + entry.Name()); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	return nil
 }
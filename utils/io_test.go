@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClaimLock(t *testing.T) {
+	dir := t.TempDir()
+
+	if holder, err := ClaimLock(dir, "stream-channel", "first"); err != nil || holder != "first" {
+		t.Fatalf("free lock: got %q, %v", holder, err)
+	}
+	if holder, err := ClaimLock(dir, "stream-channel", "first"); err != nil || holder != "first" {
+		t.Fatalf("lock claimed again by its owner: got %q, %v", holder, err)
+	}
+	if holder, err := ClaimLock(dir, "stream-channel", "second"); err != nil || holder != "first" {
+		t.Fatalf("lock held by another owner: got %q, %v", holder, err)
+	}
+}
+
+func TestClaimLockConcurrent(t *testing.T) {
+	dir := t.TempDir()
+
+	const owners = 20
+	holders := make([]string, owners)
+	var wg sync.WaitGroup
+	for i := 0; i < owners; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			holder, err := ClaimLock(dir, "stream-channel", fmt.Sprint("owner", i))
+			if err != nil {
+				t.Error(err)
+			}
+			holders[i] = holder
+		}(i)
+	}
+	wg.Wait()
+
+	// Every owner must see the same holder, never an empty one
+	for i, holder := range holders {
+		if holder == "" || holder != holders[0] {
+			t.Fatalf("owner %v saw holder %q, owner 0 saw %q", i, holder, holders[0])
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected only the lock file to remain, found %v files", len(entries))
+	}
+}
+
+func TestPruneLocks(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := ClaimLock(dir, "old", "owner"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ClaimLock(dir, "new", "owner"); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(dir+"/old.lock", past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := PruneLocks(dir, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir + "/old.lock"); !os.IsNotExist(err) {
+		t.Error("old lock was not pruned")
+	}
+	if _, err := os.Stat(dir + "/new.lock"); err != nil {
+		t.Error("recent lock was pruned")
+	}
+	if err := PruneLocks(dir+"/missing", time.Minute); err != nil {
+		t.Errorf("pruning a missing directory: %v", err)
+	}
+}
+
+func TestRemoveLocks(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{"1-100", "2-100", "1-200"} {
+		if _, err := ClaimLock(dir, name, "owner"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := RemoveLocks(dir, "-100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 2 {
+		t.Errorf("removed %v locks, expected 2", removed)
+	}
+	if _, err := os.Stat(dir + "/1-200.lock"); err != nil {
+		t.Error("lock of another channel was removed")
+	}
+	if removed, err := RemoveLocks(dir+"/missing", "-100"); err != nil || removed != 0 {
+		t.Errorf("removing from a missing directory: %v, %v", removed, err)
+	}
+}
+
+func TestWriteGobToDisk(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := WriteGobToDisk(dir, "data", map[string]int{"a": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteGobToDisk(dir, "data", map[string]int{"b": 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]int
+	if err := ReadGobFromDisk(dir, "data", &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got["b"] != 2 {
+		t.Fatalf("expected the second write to replace the first, got %v", got)
+	}
+	if _, err := os.Stat(dir + "/data.gob.tmp"); !os.IsNotExist(err) {
+		t.Error("temporary file was left behind")
+	}
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"sync"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+)
+
+// Health of writes to the data directory
+var storageHealth = struct {
+	sync.Mutex
+	failures int                           // Consecutive writes that failed
+	onChange func(healthy bool, err error) // Called when writes start failing repeatedly or recover
+}{}
+
+// Sets a function called when writes start failing repeatedly, with the last error, and when they recover
+func OnStorageHealthChange(f func(healthy bool, err error)) {
+	storageHealth.Lock()
+	defer storageHealth.Unlock()
+
+	storageHealth.onChange = f
+}
+
+// Returns whether persisted data is being saved. It is not once StorageFailureThreshold writes in a row have failed.
+func StorageHealthy() bool {
+	storageHealth.Lock()
+	defer storageHealth.Unlock()
+
+	return storageHealth.failures < constants.StorageFailureThreshold
+}
+
+// Records the result of a write to the data directory
+func recordWrite(err error) {
+	storageHealth.Lock()
+	wasHealthy := storageHealth.failures < constants.StorageFailureThreshold
+	if err != nil {
+		storageHealth.failures++
+	} else {
+		storageHealth.failures = 0
+	}
+	healthy := storageHealth.failures < constants.StorageFailureThreshold
+	onChange := storageHealth.onChange
+	storageHealth.Unlock()
+
+	if healthy == wasHealthy {
+		return
+	}
+	if healthy {
+		Log.Info("Writes to the data directory recovered.")
+	} else {
+		Log.WithError(err).Error("Writes to the data directory keep failing. Settings may not persist.")
+	}
+	if onChange != nil {
+		go onChange(healthy, err)
+	}
+}
@@ -0,0 +1,115 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+var (
+	mu            sync.Mutex
+	latestRelease string // Tag of the latest GitHub release, empty until checked
+)
+
+// Returns the latest release if it is newer than the running version, otherwise an empty string
+func NewerRelease() string {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if isNewer(latestRelease, Version) {
+		return latestRelease
+	}
+	return ""
+}
+
+// Periodically checks GitHub for a newer release and calls notify once for every newer release found
+func WatchReleases(notify func(release string)) {
+	notified := ""
+
+	for {
+		release, err := fetchLatestRelease()
+		if err != nil {
+			utils.Log.WithError(err).Warn("Failed to check GitHub for a newer release.")
+		} else {
+			mu.Lock()
+			latestRelease = release
+			mu.Unlock()
+
+			if isNewer(release, Version) && release != notified {
+				utils.Log.Infof("A newer release %v is available.\n", release)
+				notified = release
+				notify(release)
+			}
+		}
+
+		time.Sleep(constants.UpdateCheckInterval)
+	}
+}
+
+func fetchLatestRelease() (string, error) {
+	resp, err := utils.HTTPClient.Get(constants.GitHubLatestReleaseURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("github returned status %v", resp.StatusCode)
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", err
+	}
+
+	return release.TagName, nil
+}
+
+// Returns whether version a is newer than version b. Versions that aren't of the form v1.2.3 are never newer.
+func isNewer(a string, b string) bool {
+	av, okA := parseVersion(a)
+	bv, okB := parseVersion(b)
+	if !okA || !okB {
+		return false
+	}
+
+	for i := range av {
+		if av[i] != bv[i] {
+			return av[i] > bv[i]
+		}
+	}
+	return false
+}
+
+func parseVersion(v string) ([3]int, bool) {
+	var parsed [3]int
+
+	parts := strings.SplitN(strings.TrimPrefix(v, "v"), ".", 3)
+	if len(parts) != 3 {
+		return parsed, false
+	}
+
+	for i, part := range parts {
+		// Ignore pre-release and build suffixes
+		if end := strings.IndexAny(part, "-+"); end >= 0 {
+			part = part[:end]
+		}
+
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return parsed, false
+		}
+		parsed[i] = n
+	}
+
+	return parsed, true
+}
@@ -0,0 +1,29 @@
+package version
+
+import "runtime/debug"
+
+// Set at build time with
+// -ldflags "-X github.com/samuel-mokhtar/DiscordTwitchBot/version.Version=<version> -X github.com/samuel-mokhtar/DiscordTwitchBot/version.Commit=<commit>"
+var (
+	Version = "dev"
+	Commit  = "unknown"
+)
+
+// Returns the version of a module the bot was built with or "unknown" if it can't be determined
+func ModuleVersion(path string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path == path {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,35 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/samuel-mokhtar/DiscordTwitchBot/constants"
+	"github.com/samuel-mokhtar/DiscordTwitchBot/utils"
+)
+
+var mux = http.NewServeMux()
+
+// Registers a handler for an HTTP path served by the bot
+func Handle(pattern string, handler http.Handler) {
+	mux.Handle(pattern, handler)
+}
+
+// Serves the registered handlers on addr in the background. Slow clients are timed out so they can't hold
+// connections open, event sockets are unaffected since their connections are hijacked.
+func Start(addr string) {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: constants.HTTPReadHeaderTimeout,
+		ReadTimeout:       constants.HTTPReadTimeout,
+		WriteTimeout:      constants.HTTPWriteTimeout,
+		IdleTimeout:       constants.HTTPIdleTimeout,
+	}
+
+	go func() {
+		utils.Log.Infof("HTTP server listening on %v.\n", addr)
+		if err := server.ListenAndServe(); err != nil {
+			utils.Log.WithError(err).Error("HTTP server stopped.")
+		}
+	}()
+}